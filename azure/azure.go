@@ -0,0 +1,234 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/octocov/internal"
+)
+
+const (
+	apiVersion         = "7.0"
+	defaultServiceHost = "https://dev.azure.com"
+)
+
+// Status of the pull request thread. Closed threads do not block the policy requiring the comments to be resolved.
+const threadStatusClosed = 4
+
+type Azure struct {
+	client *http.Client
+	token  string
+}
+
+// New returns an Azure DevOps REST API client using the token of env SYSTEM_ACCESSTOKEN.
+func New() (*Azure, error) {
+	token := os.Getenv("SYSTEM_ACCESSTOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("env %s is not set", "SYSTEM_ACCESSTOKEN")
+	}
+	return &Azure{
+		client: &http.Client{Timeout: 10 * time.Second},
+		token:  token,
+	}, nil
+}
+
+// OrganizationURL returns the URL of the organization (collection) of Azure DevOps Services.
+func OrganizationURL(organization string) string {
+	return fmt.Sprintf("%s/%s", defaultServiceHost, url.PathEscape(organization))
+}
+
+type AzurePipelinesEnv struct {
+	CollectionURL string
+	Project       string
+	Repository    string
+	PullRequestID int
+	CommitSHA     string
+}
+
+// DecodeAzurePipelinesEnv detects the project, the repository and the pull request of the current Azure Pipelines run.
+func DecodeAzurePipelinesEnv() (*AzurePipelinesEnv, error) {
+	e := &AzurePipelinesEnv{
+		CollectionURL: os.Getenv("SYSTEM_COLLECTIONURI"),
+		Project:       os.Getenv("SYSTEM_TEAMPROJECT"),
+		Repository:    os.Getenv("BUILD_REPOSITORY_NAME"),
+		CommitSHA:     os.Getenv("SYSTEM_PULLREQUEST_SOURCECOMMITID"),
+	}
+	if e.CommitSHA == "" {
+		e.CommitSHA = os.Getenv("BUILD_SOURCEVERSION")
+	}
+	id := os.Getenv("SYSTEM_PULLREQUEST_PULLREQUESTID")
+	if id == "" {
+		return e, fmt.Errorf("env %s is not set", "SYSTEM_PULLREQUEST_PULLREQUESTID")
+	}
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return e, fmt.Errorf("invalid env %s: %w", "SYSTEM_PULLREQUEST_PULLREQUESTID", err)
+	}
+	e.PullRequestID = n
+	return e, nil
+}
+
+type comment struct {
+	ID              int    `json:"id,omitempty"`
+	ParentCommentID int    `json:"parentCommentId"`
+	Content         string `json:"content"`
+	CommentType     int    `json:"commentType"`
+}
+
+type thread struct {
+	ID       int        `json:"id,omitempty"`
+	Comments []*comment `json:"comments"`
+	Status   int        `json:"status,omitempty"`
+}
+
+// PutComment edits the latest previous comment with the signature of the key in place, or creates a new thread on the pull request if there is none.
+func (a *Azure) PutComment(ctx context.Context, collectionURL, project, repo string, id int, content, key string) error {
+	sig := internal.CommentSig(key)
+	c := strings.Join([]string{content, sig}, "\n")
+	p := pullRequestPath(collectionURL, project, repo, id) + "/threads"
+	th, cm, err := a.findLatestComment(ctx, p, sig)
+	if err != nil {
+		return err
+	}
+	if cm == nil {
+		in := &thread{
+			Comments: []*comment{{ParentCommentID: 0, Content: c, CommentType: 1}},
+			Status:   threadStatusClosed,
+		}
+		return a.do(ctx, http.MethodPost, p, in, nil)
+	}
+	return a.do(ctx, http.MethodPatch, fmt.Sprintf("%s/%d/comments/%d", p, th.ID, cm.ID), map[string]string{"content": c}, nil)
+}
+
+type PullRequestFile struct {
+	Filename string
+	BlobURL  string
+}
+
+// FetchPullRequestFiles returns the files changed in the latest iteration of the pull request. BlobURL is set if sha is not empty.
+func (a *Azure) FetchPullRequestFiles(ctx context.Context, collectionURL, project, repo string, id int, sha string) ([]*PullRequestFile, error) {
+	p := pullRequestPath(collectionURL, project, repo, id)
+	var iterations struct {
+		Value []struct {
+			ID int `json:"id"`
+		} `json:"value"`
+	}
+	if err := a.do(ctx, http.MethodGet, p+"/iterations", nil, &iterations); err != nil {
+		return nil, err
+	}
+	if len(iterations.Value) == 0 {
+		return nil, nil
+	}
+	latest := iterations.Value[len(iterations.Value)-1].ID
+	var files []*PullRequestFile
+	skip := 0
+	for {
+		var changes struct {
+			ChangeEntries []struct {
+				ChangeType string `json:"changeType"`
+				Item       struct {
+					Path          string `json:"path"`
+					GitObjectType string `json:"gitObjectType"`
+				} `json:"item"`
+			} `json:"changeEntries"`
+			NextSkip int `json:"nextSkip"`
+		}
+		if err := a.do(ctx, http.MethodGet, fmt.Sprintf("%s/iterations/%d/changes?$top=2000&$skip=%d", p, latest, skip), nil, &changes); err != nil {
+			return nil, err
+		}
+		for _, c := range changes.ChangeEntries {
+			if c.Item.GitObjectType != "" && c.Item.GitObjectType != "blob" {
+				continue
+			}
+			if strings.Contains(c.ChangeType, "delete") {
+				continue
+			}
+			name := strings.TrimPrefix(c.Item.Path, "/")
+			f := &PullRequestFile{Filename: name}
+			if sha != "" {
+				f.BlobURL = fmt.Sprintf("%s/%s/_git/%s?path=/%s&version=GC%s", strings.TrimSuffix(collectionURL, "/"), url.PathEscape(project), url.PathEscape(repo), name, sha)
+			}
+			files = append(files, f)
+		}
+		if changes.NextSkip == 0 {
+			break
+		}
+		skip = changes.NextSkip
+	}
+	return files, nil
+}
+
+func (a *Azure) findLatestComment(ctx context.Context, p, sig string) (*thread, *comment, error) {
+	var threads struct {
+		Value []*thread `json:"value"`
+	}
+	if err := a.do(ctx, http.MethodGet, p, nil, &threads); err != nil {
+		return nil, nil, err
+	}
+	var (
+		latestThread  *thread
+		latestComment *comment
+	)
+	for _, th := range threads.Value {
+		for _, c := range th.Comments {
+			if strings.Contains(c.Content, sig) {
+				latestThread = th
+				latestComment = c
+			}
+		}
+	}
+	return latestThread, latestComment, nil
+}
+
+func pullRequestPath(collectionURL, project, repo string, id int) string {
+	return fmt.Sprintf("%s/%s/_apis/git/repositories/%s/pullRequests/%d", strings.TrimSuffix(collectionURL, "/"), url.PathEscape(project), url.PathEscape(repo), id)
+}
+
+// do sends the request to the Azure DevOps REST API.
+func (a *Azure) do(ctx context.Context, method, u string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	q := req.URL.Query()
+	q.Set("api-version", apiVersion)
+	req.URL.RawQuery = q.Encode()
+	req.Header.Set("Authorization", "Bearer "+a.token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("%s %s: %s: %s", method, req.URL.Path, res.Status, strings.TrimSpace(string(b)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecodeAzurePipelinesEnv(t *testing.T) {
+	tests := []struct {
+		id      string
+		want    int
+		wantErr bool
+	}{
+		{"", 0, true},
+		{"x", 0, true},
+		{"12", 12, false},
+	}
+	for _, tt := range tests {
+		t.Setenv("SYSTEM_TEAMPROJECT", "project")
+		t.Setenv("SYSTEM_PULLREQUEST_SOURCECOMMITID", "")
+		t.Setenv("BUILD_SOURCEVERSION", "abcdef")
+		t.Setenv("SYSTEM_PULLREQUEST_PULLREQUESTID", tt.id)
+		got, err := DecodeAzurePipelinesEnv()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwant error %v", err, tt.wantErr)
+			continue
+		}
+		if got.Project != "project" {
+			t.Errorf("got %v\nwant %v", got.Project, "project")
+		}
+		if got.CommitSHA != "abcdef" {
+			t.Errorf("got %v\nwant %v", got.CommitSHA, "abcdef")
+		}
+		if got.PullRequestID != tt.want {
+			t.Errorf("got %v\nwant %v", got.PullRequestID, tt.want)
+		}
+	}
+}
+
+func TestPutComment(t *testing.T) {
+	tests := []struct {
+		threads    []*thread
+		wantMethod string
+		wantPath   string
+	}{
+		{
+			[]*thread{{ID: 1, Comments: []*comment{{ID: 1, Content: "LGTM"}}}},
+			http.MethodPost,
+			"/org/project/_apis/git/repositories/repo/pullRequests/2/threads",
+		},
+		{
+			[]*thread{
+				{ID: 1, Comments: []*comment{{ID: 1, Content: "old\n<!-- octocov:key -->"}}},
+				{ID: 3, Comments: []*comment{{ID: 1, Content: "LGTM"}}},
+				{ID: 4, Comments: []*comment{{ID: 1, Content: "old\n<!-- octocov:key -->"}}},
+			},
+			http.MethodPatch,
+			"/org/project/_apis/git/repositories/repo/pullRequests/2/threads/4/comments/1",
+		},
+	}
+	for _, tt := range tests {
+		var gotMethod, gotPath, gotBody string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if r.URL.Query().Get("api-version") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if r.Method == http.MethodGet {
+				_ = json.NewEncoder(w).Encode(map[string]any{"value": tt.threads})
+				return
+			}
+			gotMethod = r.Method
+			gotPath = r.URL.Path
+			if r.Method == http.MethodPost {
+				th := &thread{}
+				_ = json.NewDecoder(r.Body).Decode(th)
+				if len(th.Comments) > 0 {
+					gotBody = th.Comments[0].Content
+				}
+			} else {
+				b := map[string]string{}
+				_ = json.NewDecoder(r.Body).Decode(&b)
+				gotBody = b["content"]
+			}
+			_, _ = fmt.Fprint(w, "{}")
+		}))
+		t.Setenv("SYSTEM_ACCESSTOKEN", "token")
+		a, err := New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := a.PutComment(context.Background(), ts.URL+"/org/", "project", "repo", 2, "report", "key"); err != nil {
+			t.Fatal(err)
+		}
+		ts.Close()
+		if gotMethod != tt.wantMethod {
+			t.Errorf("got %v\nwant %v", gotMethod, tt.wantMethod)
+		}
+		if gotPath != tt.wantPath {
+			t.Errorf("got %v\nwant %v", gotPath, tt.wantPath)
+		}
+		if want := "report\n<!-- octocov:key -->"; gotBody != want {
+			t.Errorf("got %v\nwant %v", gotBody, want)
+		}
+	}
+}
+
+func TestFetchPullRequestFiles(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/org/project/_apis/git/repositories/repo/pullRequests/2/iterations":
+			_, _ = fmt.Fprint(w, `{"value":[{"id":1},{"id":2}]}`)
+		case "/org/project/_apis/git/repositories/repo/pullRequests/2/iterations/2/changes":
+			if r.URL.Query().Get("$skip") == "0" {
+				_, _ = fmt.Fprint(w, `{"changeEntries":[{"changeType":"edit","item":{"path":"/a.go","gitObjectType":"blob"}},{"changeType":"delete","item":{"path":"/b.go","gitObjectType":"blob"}},{"changeType":"add","item":{"path":"/pkg","gitObjectType":"tree"}}],"nextSkip":3}`)
+				return
+			}
+			_, _ = fmt.Fprint(w, `{"changeEntries":[{"changeType":"add","item":{"path":"/pkg/c.go","gitObjectType":"blob"}}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	t.Setenv("SYSTEM_ACCESSTOKEN", "token")
+	a, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := a.FetchPullRequestFiles(context.Background(), ts.URL+"/org", "project", "repo", 2, "abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*PullRequestFile{
+		{Filename: "a.go", BlobURL: ts.URL + "/org/project/_git/repo?path=/a.go&version=GCabcdef"},
+		{Filename: "pkg/c.go", BlobURL: ts.URL + "/org/project/_git/repo?path=/pkg/c.go&version=GCabcdef"},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestOrganizationURL(t *testing.T) {
+	if got, want := OrganizationURL("my org"), "https://dev.azure.com/my%20org"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	_ "image/jpeg"
 	_ "image/png"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -31,12 +32,23 @@ const dpi = 72
 
 var rgbRe = regexp.MustCompile(`^[0-9A-F]{6}$`)
 
+// Badge styles (https://shields.io/badges).
+const (
+	StyleFlat        = "flat"
+	StyleFlatSquare  = "flat-square"
+	StylePlastic     = "plastic"
+	StyleForTheBadge = "for-the-badge"
+)
+
+var Styles = []string{StyleFlat, StyleFlatSquare, StylePlastic, StyleForTheBadge}
+
 type Badge struct {
 	Label        string
 	Message      string
 	LabelColor   string
 	MessageColor string
 	Icon         []byte
+	Style        string
 	drawer       *font.Drawer
 }
 
@@ -59,6 +71,7 @@ func New(l, m string) *Badge {
 		Message:      m,
 		LabelColor:   defaultLabelColor,
 		MessageColor: defaultMessageColor,
+		Style:        StyleFlat,
 		drawer: &font.Drawer{
 			Face: truetype.NewFace(ttf, &truetype.Options{
 				Size:    fontSize,
@@ -102,6 +115,42 @@ func (b *Badge) SetMessageColor(c any) error {
 	return nil
 }
 
+func (b *Badge) SetStyle(style string) error {
+	if style == "" {
+		b.Style = StyleFlat
+		return nil
+	}
+	for _, s := range Styles {
+		if s == style {
+			b.Style = style
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid badge style: %s", style)
+}
+
+// Trend arrows appended to the message.
+const (
+	TrendUp   = "↑"
+	TrendDown = "↓"
+	TrendFlat = "→"
+)
+
+// trendTolerance is half of the precision of the displayed percentage, so a change that rounds to 0.0 is flat.
+const trendTolerance = 0.05
+
+// Trend returns the arrow of the change (delta) from the previous value.
+func Trend(delta float64) string {
+	switch {
+	case delta > trendTolerance:
+		return TrendUp
+	case delta < -trendTolerance:
+		return TrendDown
+	default:
+		return TrendFlat
+	}
+}
+
 func castColor(c any) (string, error) {
 	switch v := c.(type) {
 	case string:
@@ -119,9 +168,54 @@ func castColor(c any) (string, error) {
 func (b *Badge) Render(wr io.Writer) error {
 	tmpl := template.Must(template.New("badge").Parse(string(badgeTmpl)))
 
+	label := b.Label
+	message := b.Message
+	st := map[string]any{
+		"Height":        20,
+		"Radius":        3,
+		"GradientStops": []string{`<stop offset="0" stop-color="#bbb" stop-opacity=".1"/>`, `<stop offset="1" stop-opacity=".1"/>`},
+		"FontSize":      110,
+		"Bold":          false,
+		"Shadow":        true,
+		"ShadowY":       150,
+		"TextY":         140,
+		"IconY":         3,
+	}
+	switch b.Style {
+	case StyleFlat, "":
+	case StyleFlatSquare:
+		st["Radius"] = 0
+		st["GradientStops"] = nil
+	case StylePlastic:
+		st["Height"] = 18
+		st["Radius"] = 4
+		st["GradientStops"] = []string{`<stop offset="0" stop-color="#fff" stop-opacity=".7"/>`, `<stop offset=".1" stop-color="#aaa" stop-opacity=".1"/>`, `<stop offset=".9" stop-opacity=".3"/>`, `<stop offset="1" stop-opacity=".5"/>`}
+		st["ShadowY"] = 140
+		st["TextY"] = 130
+		st["IconY"] = 2
+	case StyleForTheBadge:
+		label = strings.ToUpper(label)
+		message = strings.ToUpper(message)
+		st["Height"] = 28
+		st["Radius"] = 0
+		st["GradientStops"] = nil
+		st["FontSize"] = 100
+		st["Bold"] = true
+		st["Shadow"] = false
+		st["TextY"] = 175
+		st["IconY"] = 7
+	default:
+		return fmt.Errorf("invalid badge style: %s", b.Style)
+	}
+
 	// https://github.com/badges/shields/tree/master/spec
-	lw := 6 + b.stringWidth(b.Label) + 4
-	mw := 4 + b.stringWidth(b.Message) + 6
+	lw := 6 + b.stringWidth(label) + 4
+	mw := 4 + b.stringWidth(message) + 6
+	if b.Style == StyleForTheBadge {
+		// bold and letter-spaced text is wider than normal text
+		lw = 12 + math.Round(b.stringWidth(label)*1.2) + 12
+		mw = 12 + math.Round(b.stringWidth(message)*1.2) + 12
+	}
 	lx := lw * 10 / 2
 	mx := (lw * 10) + (mw * 10 / 2)
 	iw := 0.0
@@ -145,8 +239,8 @@ func (b *Badge) Render(wr io.Writer) error {
 	}
 
 	d := map[string]any{
-		"Label":        b.Label,
-		"Message":      b.Message,
+		"Label":        label,
+		"Message":      message,
 		"LabelColor":   b.LabelColor,
 		"MessageColor": b.MessageColor,
 		"Width":        lw + mw + iw,
@@ -156,6 +250,9 @@ func (b *Badge) Render(wr io.Writer) error {
 		"MessageX":     mx + (iw * 10),
 		"Icon":         icon,
 	}
+	for k, v := range st {
+		d[k] = v
+	}
 	if err := tmpl.Execute(wr, d); err != nil {
 		return err
 	}
@@ -40,6 +40,47 @@ func TestRender(t *testing.T) {
 	}
 }
 
+func TestRenderStyle(t *testing.T) {
+	flag.Parse()
+
+	tests := []struct {
+		style    string
+		filename string
+		wantErr  bool
+	}{
+		{StyleFlatSquare, "style_flat_square", false},
+		{StylePlastic, "style_plastic", false},
+		{StyleForTheBadge, "style_for_the_badge", false},
+		{"unknown", "", true},
+	}
+	for _, tt := range tests {
+		b := New("coverage", "10%")
+		if err := b.SetStyle(tt.style); err != nil {
+			if !tt.wantErr {
+				t.Error(err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("want err: %s", tt.style)
+			continue
+		}
+		got := new(bytes.Buffer)
+		if err := b.Render(got); err != nil {
+			t.Fatal(err)
+		}
+
+		if os.Getenv("UPDATE_GOLDEN") != "" {
+			golden.Update(t, testdataDir(t), tt.filename, got)
+			continue
+		}
+
+		if diff := golden.Diff(t, testdataDir(t), tt.filename, got); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
 func TestAddIconFile(t *testing.T) {
 	b := New("with", "icon")
 	if err := b.AddIconFile(filepath.Join(testdataDir(t), "icon.svg")); err != nil {
@@ -119,3 +160,23 @@ func testdataDir(t *testing.T) string {
 	}
 	return dir
 }
+
+func TestTrend(t *testing.T) {
+	tests := []struct {
+		delta float64
+		want  string
+	}{
+		{1.2, TrendUp},
+		{0.06, TrendUp},
+		{0.05, TrendFlat},
+		{0, TrendFlat},
+		{-0.04, TrendFlat},
+		{-0.06, TrendDown},
+		{-3.0, TrendDown},
+	}
+	for _, tt := range tests {
+		if got := Trend(tt.delta); got != tt.want {
+			t.Errorf("Trend(%v) = %v, want %v", tt.delta, got, tt.want)
+		}
+	}
+}
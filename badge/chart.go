@@ -0,0 +1,182 @@
+package badge
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	chartWidth      = 600
+	chartHeight     = 220
+	chartLeft       = 44
+	chartRight      = 16
+	chartTop        = 28
+	chartBottom     = 28
+	chartGridColor  = "#E1E4E8"
+	chartTextColor  = "#586069"
+	chartDateLayout = "2006-01-02"
+)
+
+//go:embed chart.svg.tmpl
+var chartTmpl []byte
+
+// Chart is a line chart of percentages (0-100) over time.
+// Points are placed by their timestamps, so sparse or irregular points keep their actual intervals.
+type Chart struct {
+	Label     string
+	LineColor string
+	points    []chartPoint
+	target    *chartPoint
+}
+
+type chartPoint struct {
+	t     time.Time
+	value float64
+	color string
+}
+
+type chartXY struct {
+	X     float64
+	Y     float64
+	Color string
+	Title string
+}
+
+type chartTick struct {
+	X      float64
+	Y      float64
+	TextY  float64
+	Text   string
+	Anchor string
+}
+
+// NewChart returns *Chart.
+func NewChart(label string) *Chart {
+	return &Chart{
+		Label:     label,
+		LineColor: defaultMessageColor,
+	}
+}
+
+// Add adds the percentage at t colored by color.
+func (c *Chart) Add(t time.Time, value float64, color string) error {
+	rgb, err := castColor(color)
+	if err != nil {
+		return err
+	}
+	c.points = append(c.points, chartPoint{t: t, value: value, color: rgb})
+	return nil
+}
+
+// SetTarget sets the target percentage drawn as a dashed line.
+func (c *Chart) SetTarget(value float64, color string) error {
+	rgb, err := castColor(color)
+	if err != nil {
+		return err
+	}
+	c.target = &chartPoint{value: value, color: rgb}
+	return nil
+}
+
+// Render chart.
+func (c *Chart) Render(wr io.Writer) error {
+	tmpl := template.Must(template.New("chart").Parse(string(chartTmpl)))
+	points := make([]chartPoint, len(c.points))
+	copy(points, c.points)
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].t.Before(points[j].t)
+	})
+
+	left := float64(chartLeft)
+	right := float64(chartWidth - chartRight)
+	top := float64(chartTop)
+	bottom := float64(chartHeight - chartBottom)
+	y := func(v float64) float64 {
+		v = min(max(v, 0), 100)
+		return roundTenth(bottom - (bottom-top)*v/100)
+	}
+	var x func(t time.Time) float64
+	if len(points) > 0 && points[len(points)-1].t.After(points[0].t) {
+		start := points[0].t
+		span := float64(points[len(points)-1].t.Sub(start))
+		x = func(t time.Time) float64 {
+			return roundTenth(left + (right-left)*float64(t.Sub(start))/span)
+		}
+	} else {
+		// a single point (or points at the same time) is placed at the center
+		x = func(t time.Time) float64 {
+			return roundTenth((left + right) / 2)
+		}
+	}
+
+	var yTicks []chartTick
+	for _, v := range []float64{0, 25, 50, 75, 100} {
+		yTicks = append(yTicks, chartTick{Y: y(v), TextY: y(v) + 4, Text: fmt.Sprintf("%.0f%%", v)})
+	}
+	var (
+		xTicks []chartTick
+		xys    []chartXY
+		line   []string
+	)
+	for _, p := range points {
+		xy := chartXY{
+			X:     x(p.t),
+			Y:     y(p.value),
+			Color: p.color,
+			Title: fmt.Sprintf("%s: %.1f%%", p.t.UTC().Format(time.RFC3339), p.value),
+		}
+		xys = append(xys, xy)
+		line = append(line, fmt.Sprintf("%g,%g", xy.X, xy.Y))
+	}
+	switch {
+	case len(points) == 0:
+	case x(points[0].t) == x(points[len(points)-1].t):
+		xTicks = append(xTicks, chartTick{X: x(points[0].t), Text: points[0].t.UTC().Format(chartDateLayout), Anchor: "middle"})
+	default:
+		xTicks = append(xTicks,
+			chartTick{X: left, Text: points[0].t.UTC().Format(chartDateLayout), Anchor: "start"},
+			chartTick{X: right, Text: points[len(points)-1].t.UTC().Format(chartDateLayout), Anchor: "end"},
+		)
+	}
+	d := map[string]any{
+		"Width":     chartWidth,
+		"Height":    chartHeight,
+		"FontSize":  fontSize,
+		"TextColor": chartTextColor,
+		"GridColor": chartGridColor,
+		"Label":     c.Label,
+		"LabelY":    top - 12,
+		"Left":      left,
+		"Right":     right,
+		"TickX":     left - 6,
+		"XTickY":    bottom + 18,
+		"YTicks":    yTicks,
+		"XTicks":    xTicks,
+		"NoData":    len(points) == 0,
+		"CenterX":   roundTenth((left + right) / 2),
+		"CenterY":   roundTenth((top + bottom) / 2),
+		"LineColor": c.LineColor,
+		"Points":    xys,
+	}
+	if len(line) > 1 {
+		d["Line"] = strings.Join(line, " ")
+	}
+	if c.target != nil {
+		d["Target"] = chartXY{
+			Y:     y(c.target.value),
+			Color: c.target.color,
+			Title: fmt.Sprintf("target: %.1f%%", c.target.value),
+		}
+	}
+	return tmpl.Execute(wr, d)
+}
+
+func roundTenth(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,57 @@
+package badge
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tenntenn/golden"
+)
+
+func TestRenderChart(t *testing.T) {
+	flag.Parse()
+
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		points   []float64
+		days     []int
+		target   float64
+		filename string
+	}{
+		// irregular intervals
+		{[]float64{62.5, 70.1, 68.0, 81.2}, []int{0, 1, 7, 30}, 80, "chart"},
+		{[]float64{62.5}, []int{0}, 0, "chart_single"},
+		{nil, nil, 0, "chart_no_data"},
+	}
+	for _, tt := range tests {
+		c := NewChart("coverage")
+		for i, v := range tt.points {
+			if err := c.Add(base.AddDate(0, 0, tt.days[i]), v, "#97CA00"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if tt.target > 0 {
+			if err := c.SetTarget(tt.target, "#97CA00"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got := new(bytes.Buffer)
+		if err := c.Render(got); err != nil {
+			t.Fatal(err)
+		}
+		if n := strings.Count(got.String(), "<circle "); n != len(tt.points) {
+			t.Errorf("got %v\nwant %v", n, len(tt.points))
+		}
+
+		if os.Getenv("UPDATE_GOLDEN") != "" {
+			golden.Update(t, testdataDir(t), tt.filename, got)
+			continue
+		}
+		if diff := golden.Diff(t, testdataDir(t), tt.filename, got); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
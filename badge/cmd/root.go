@@ -34,6 +34,7 @@ var (
 	labelColor   string
 	messageColor string
 	icon         string
+	style        string
 )
 
 // rootCmd represents the base command when called without any subcommands.
@@ -53,6 +54,9 @@ var rootCmd = &cobra.Command{
 				return err
 			}
 		}
+		if err := b.SetStyle(style); err != nil {
+			return err
+		}
 		if icon != "" {
 			if err := b.AddIconFile(icon); err != nil {
 				return err
@@ -78,4 +82,5 @@ func init() {
 	rootCmd.Flags().StringVarP(&labelColor, "label-color", "L", "", "color of label background")
 	rootCmd.Flags().StringVarP(&messageColor, "label-message", "M", "", "color of message background")
 	rootCmd.Flags().StringVarP(&icon, "icon", "i", "", "icon of badge")
+	rootCmd.Flags().StringVarP(&style, "style", "s", badge.StyleFlat, "style of badge (flat, flat-square, plastic, for-the-badge)")
 }
@@ -0,0 +1,51 @@
+package badge
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// FormatSVG and FormatEndpoint are the output formats of the badge.
+const (
+	FormatSVG      = "svg"
+	FormatEndpoint = "json"
+)
+
+// Endpoint is the JSON response of the shields.io endpoint badge (https://shields.io/badges/endpoint-badge).
+type Endpoint struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// FormatOf returns the output format of the badge inferred from the extension of the path. It defaults to SVG.
+func FormatOf(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return FormatEndpoint
+	}
+	return FormatSVG
+}
+
+// RenderEndpoint renders the badge as the JSON of the shields.io endpoint badge.
+func (b *Badge) RenderEndpoint(wr io.Writer) error {
+	e := &Endpoint{
+		SchemaVersion: 1,
+		Label:         b.Label,
+		Message:       b.Message,
+		Color:         strings.TrimPrefix(b.MessageColor, "#"),
+	}
+	enc := json.NewEncoder(wr)
+	enc.SetIndent("", "  ")
+	return enc.Encode(e)
+}
+
+// RenderAs renders the badge in the format.
+func (b *Badge) RenderAs(format string, wr io.Writer) error {
+	if format == FormatEndpoint {
+		return b.RenderEndpoint(wr)
+	}
+	return b.Render(wr)
+}
@@ -0,0 +1,42 @@
+package badge
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRenderEndpoint(t *testing.T) {
+	b := New("coverage", "80.0%")
+	b.MessageColor = "#4C1"
+	got := new(bytes.Buffer)
+	if err := b.RenderEndpoint(got); err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "schemaVersion": 1,
+  "label": "coverage",
+  "message": "80.0%",
+  "color": "4C1"
+}
+`
+	if got.String() != want {
+		t.Errorf("got %v\nwant %v", got.String(), want)
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"docs/coverage.svg", FormatSVG},
+		{"docs/coverage.json", FormatEndpoint},
+		{"docs/coverage.JSON", FormatEndpoint},
+		{"docs/coverage", FormatSVG},
+	}
+	for _, tt := range tests {
+		if got := FormatOf(tt.path); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package badge
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"math"
+	"text/template"
+	"time"
+)
+
+const (
+	heatmapCellSize   = 10
+	heatmapCellGap    = 3
+	heatmapPadding    = 4
+	heatmapLabelSize  = 20
+	heatmapEmptyColor = "#EBEDF0"
+	heatmapDateLayout = "2006-01-02"
+)
+
+//go:embed heatmap.svg.tmpl
+var heatmapTmpl []byte
+
+// Heatmap is a calendar heatmap of daily values (like GitHub contributions).
+// Columns are weeks (starting on Sunday) and rows are days of the week.
+type Heatmap struct {
+	Label      string
+	LabelColor string
+	days       int
+	end        time.Time
+	values     map[string]heatmapValue
+}
+
+type heatmapValue struct {
+	message string
+	color   string
+}
+
+type heatmapCell struct {
+	X     int
+	Y     int
+	Color string
+	Title string
+}
+
+// NewHeatmap returns *Heatmap of the recent days ending with end (UTC).
+func NewHeatmap(label string, days int, end time.Time) *Heatmap {
+	if days < 1 {
+		days = 1
+	}
+	return &Heatmap{
+		Label:      label,
+		LabelColor: defaultLabelColor,
+		days:       days,
+		end:        truncateDay(end),
+		values:     map[string]heatmapValue{},
+	}
+}
+
+// Set sets the message and color of the day of t. It overwrites the value already set for the same day.
+// Days out of the window of the heatmap are ignored.
+func (h *Heatmap) Set(t time.Time, message, color string) error {
+	rgb, err := castColor(color)
+	if err != nil {
+		return err
+	}
+	d := truncateDay(t)
+	if d.After(h.end) || d.Before(h.start()) {
+		return nil
+	}
+	h.values[d.Format(heatmapDateLayout)] = heatmapValue{
+		message: message,
+		color:   rgb,
+	}
+	return nil
+}
+
+// Render heatmap.
+func (h *Heatmap) Render(wr io.Writer) error {
+	tmpl := template.Must(template.New("heatmap").Parse(string(heatmapTmpl)))
+	start := h.start()
+	offset := int(start.Weekday())
+	step := heatmapCellSize + heatmapCellGap
+	var cells []heatmapCell
+	for i := 0; i < h.days; i++ {
+		d := start.AddDate(0, 0, i)
+		date := d.Format(heatmapDateLayout)
+		cell := heatmapCell{
+			X:     heatmapPadding + ((offset+i)/7)*step,
+			Y:     heatmapLabelSize + int(d.Weekday())*step,
+			Color: heatmapEmptyColor,
+			Title: fmt.Sprintf("%s: no data", date),
+		}
+		if v, ok := h.values[date]; ok {
+			cell.Color = v.color
+			cell.Title = fmt.Sprintf("%s: %s", date, v.message)
+		}
+		cells = append(cells, cell)
+	}
+	weeks := (offset+h.days-1)/7 + 1
+	w := heatmapPadding*2 + weeks*step - heatmapCellGap
+	// keep the label inside the heatmap even when the window is short
+	if lw := heatmapPadding*2 + int(math.Ceil(New(h.Label, "").stringWidth(h.Label))); lw > w {
+		w = lw
+	}
+	d := map[string]any{
+		"Width":      w,
+		"Height":     heatmapLabelSize + 7*step - heatmapCellGap + heatmapPadding,
+		"Padding":    heatmapPadding,
+		"LabelY":     heatmapLabelSize - 6,
+		"FontSize":   fontSize,
+		"CellSize":   heatmapCellSize,
+		"Label":      h.Label,
+		"LabelColor": h.LabelColor,
+		"Cells":      cells,
+	}
+	return tmpl.Execute(wr, d)
+}
+
+func (h *Heatmap) start() time.Time {
+	return h.end.AddDate(0, 0, -(h.days - 1))
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
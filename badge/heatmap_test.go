@@ -0,0 +1,50 @@
+package badge
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tenntenn/golden"
+)
+
+func TestRenderHeatmap(t *testing.T) {
+	flag.Parse()
+
+	end := time.Date(2024, 3, 13, 12, 0, 0, 0, time.UTC) // Wednesday
+	h := NewHeatmap("coverage", 14, end)
+	if err := h.Set(end, "80.1%", "#97CA00"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Set(end.AddDate(0, 0, -3), "55.0%", "#DFB317"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Set(end.AddDate(0, 0, -30), "10.0%", "#E05D44"); err != nil { // out of the window
+		t.Fatal(err)
+	}
+	got := new(bytes.Buffer)
+	if err := h.Render(got); err != nil {
+		t.Fatal(err)
+	}
+	if c := strings.Count(got.String(), "<rect "); c != 14 {
+		t.Errorf("got %v\nwant %v", c, 14)
+	}
+
+	if os.Getenv("UPDATE_GOLDEN") != "" {
+		golden.Update(t, testdataDir(t), "heatmap", got)
+		return
+	}
+	if diff := golden.Diff(t, testdataDir(t), "heatmap", got); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestHeatmapSetInvalidColor(t *testing.T) {
+	h := NewHeatmap("coverage", 7, time.Now())
+	if err := h.Set(time.Now(), "80%", "green"); err == nil {
+		t.Error("want error")
+	}
+}
@@ -0,0 +1,58 @@
+package badge
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// Manifest is the list of generated badges.
+type Manifest struct {
+	Badges []*ManifestEntry `json:"badges"`
+}
+
+type ManifestEntry struct {
+	Metric  string  `json:"metric"`
+	Path    string  `json:"path"`
+	Label   string  `json:"label"`
+	Message string  `json:"message"`
+	Color   string  `json:"color"`
+	Value   float64 `json:"value"`
+}
+
+func NewManifest() *Manifest {
+	return &Manifest{
+		Badges: []*ManifestEntry{},
+	}
+}
+
+// Add adds the badge written to path that represents the value of the metric.
+func (m *Manifest) Add(metric, path string, value float64, b *Badge) {
+	m.Badges = append(m.Badges, &ManifestEntry{
+		Metric:  metric,
+		Path:    path,
+		Label:   b.Label,
+		Message: b.Message,
+		Color:   b.MessageColor,
+		Value:   value,
+	})
+}
+
+// AddImage adds the image other than a badge (e.g. heatmap) written to path that represents the value of the metric.
+func (m *Manifest) AddImage(metric, path string, value float64, label, message, color string) {
+	m.Badges = append(m.Badges, &ManifestEntry{
+		Metric:  metric,
+		Path:    path,
+		Label:   label,
+		Message: message,
+		Color:   color,
+		Value:   value,
+	})
+}
+
+func (m *Manifest) Write(path string) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0644) // #nosec
+}
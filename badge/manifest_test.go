@@ -0,0 +1,44 @@
+package badge
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestManifest(t *testing.T) {
+	m := NewManifest()
+	b := New("coverage", "80.0%")
+	b.MessageColor = "#97CA00"
+	m.Add("coverage", "docs/coverage.svg", 80.0, b)
+	r := New("code to test ratio", "1:1.2")
+	r.MessageColor = "#A4A61D"
+	m.Add("code_to_test_ratio", "docs/ratio.svg", 1.2, r)
+	m.AddImage("coverage_heatmap", "docs/heatmap.svg", 80.0, "coverage", "80.0%", "#97CA00")
+
+	p := filepath.Join(t.TempDir(), "badges.json")
+	if err := m.Write(p); err != nil {
+		t.Fatal(err)
+	}
+	b2, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := &Manifest{}
+	if err := json.Unmarshal(b2, got); err != nil {
+		t.Fatal(err)
+	}
+	want := &Manifest{
+		Badges: []*ManifestEntry{
+			{Metric: "coverage", Path: "docs/coverage.svg", Label: "coverage", Message: "80.0%", Color: "#97CA00", Value: 80.0},
+			{Metric: "code_to_test_ratio", Path: "docs/ratio.svg", Label: "code to test ratio", Message: "1:1.2", Color: "#A4A61D", Value: 1.2},
+			{Metric: "coverage_heatmap", Path: "docs/heatmap.svg", Label: "coverage", Message: "80.0%", Color: "#97CA00", Value: 80.0},
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,61 @@
+package central
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ Cache = (*FileCache)(nil)
+
+// Cache is the cache of the reports read from the report datastores.
+// The key contains the version of the report (e.g. the blob SHA), so a changed report is never read from the cache.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Put(key string, b []byte) error
+}
+
+// FileCache is an on-disk Cache whose entries expire after the TTL.
+type FileCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewFileCache returns a FileCache storing entries in dir. Entries never expire if ttl is 0.
+func NewFileCache(dir string, ttl time.Duration) *FileCache {
+	return &FileCache{
+		dir: dir,
+		ttl: ttl,
+	}
+}
+
+func (c *FileCache) Get(key string) ([]byte, bool) {
+	p := c.path(key)
+	fi, err := os.Stat(p)
+	if err != nil {
+		return nil, false
+	}
+	if c.ttl > 0 && time.Since(fi.ModTime()) > c.ttl {
+		_ = os.Remove(p)
+		return nil, false
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+func (c *FileCache) Put(key string, b []byte) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil { // #nosec
+		return err
+	}
+	return os.WriteFile(c.path(key), b, 0600)
+}
+
+func (c *FileCache) path(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(h[:])+".json")
+}
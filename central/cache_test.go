@@ -0,0 +1,98 @@
+package central
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/datastore"
+	"github.com/k1LoW/octocov/datastore/local"
+)
+
+func TestFileCache(t *testing.T) {
+	c := NewFileCache(filepath.Join(t.TempDir(), "cache"), time.Hour)
+	if _, ok := c.Get("owner/repo/report.json@abc"); ok {
+		t.Error("got cache hit\nwant miss")
+	}
+	if err := c.Put("owner/repo/report.json@abc", []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	got, ok := c.Get("owner/repo/report.json@abc")
+	if !ok {
+		t.Fatal("got cache miss\nwant hit")
+	}
+	if string(got) != "{}" {
+		t.Errorf("got %s\nwant %s", got, "{}")
+	}
+
+	// Expired entries are not used
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(c.path("owner/repo/report.json@abc"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("owner/repo/report.json@abc"); ok {
+		t.Error("got cache hit\nwant miss")
+	}
+}
+
+type versionedLocal struct {
+	*local.Local
+	versions map[string]string
+}
+
+func (v *versionedLocal) Versions(_ context.Context) (map[string]string, error) {
+	return v.versions, nil
+}
+
+func TestCollectReportsWithCache(t *testing.T) {
+	c := config.New()
+	dir := t.TempDir()
+	p := filepath.Join(dir, "owner", "repo", "report.json")
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(covered int) {
+		b := []byte(fmt.Sprintf(`{"repository":"owner/repo","ref":"refs/heads/main","commit":"1234567","coverage":{"type":"loc","format":"LCOV","total":10,"covered":%d,"files":[]},"timestamp":"2026-01-01T00:00:00Z"}`, covered))
+		if err := os.WriteFile(p, b, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l, err := local.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rd := &versionedLocal{Local: l, versions: map[string]string{"owner/repo/report.json": "sha1"}}
+	ctr := New(&Config{
+		Reports:       []datastore.Datastore{rd},
+		CoverageColor: c.CoverageColor,
+		Cache:         NewFileCache(filepath.Join(t.TempDir(), "cache"), time.Hour),
+	})
+
+	tests := []struct {
+		covered int
+		version string
+		want    float64
+	}{
+		{5, "sha1", 50.0},
+		{8, "sha1", 50.0}, // cached
+		{8, "sha2", 80.0}, // invalidated by the version
+	}
+	for _, tt := range tests {
+		write(tt.covered)
+		rd.versions["owner/repo/report.json"] = tt.version
+		got, err := ctr.CollectReports()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 {
+			t.Fatalf("got %v\nwant %v", len(got), 1)
+		}
+		if got := got[0].CoveragePercent(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
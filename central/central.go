@@ -8,10 +8,12 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"log"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
@@ -31,15 +33,88 @@ type Central struct {
 	reports []*report.Report
 }
 
+// Averages of the code coverage across the repositories (Config.SummaryAverage).
+const (
+	AverageWeighted = "weighted"
+	AverageSimple   = "simple"
+)
+
+// summaryBadgePath is the path of the badge of the average code coverage across the repositories in the badge datastores.
+const summaryBadgePath = "coverage.svg"
+
 type Config struct {
 	Repository             string
 	Wd                     string
 	Index                  string
 	Badges                 []datastore.Datastore
 	Reports                []datastore.Datastore
+	JSON                   string
+	Acceptable             func(r *report.Report) error
 	CoverageColor          func(cover float64) string
 	CodeToTestRatioColor   func(ratio float64) string
 	TestExecutionTimeColor func(d time.Duration) string
+	DocCoverageColor       func(cover float64) string
+	SortByCoverage         bool
+	// Cache is the cache of the reports read from Reports. It is disabled if nil.
+	Cache Cache
+	// RepositoryFilter reports whether the reports of the repository (owner/repo) are aggregated. All repositories are aggregated if nil.
+	RepositoryFilter func(repo string) bool
+	// SummaryAverage is the averaging (AverageWeighted or AverageSimple) of the code coverage across the repositories for the summary badge and index. The summary is disabled if empty.
+	SummaryAverage string
+	// Concurrency is the maximum number of the reports read simultaneously from each datastore of Reports. The reports are read one by one if it is less than 1.
+	Concurrency int
+}
+
+type summary struct {
+	Average      string  `json:"average"`
+	Coverage     float64 `json:"coverage"`
+	Repositories int     `json:"repositories"`
+}
+
+// AverageCoverage returns the average code coverage of the reports in which the code coverage is measured, and the number of them.
+// If weighted is true, the code coverage is weighted by the total lines (i.e. the covered lines divided by the total lines of all the reports).
+// Otherwise, it is the simple average of the code coverage of the reports.
+func AverageCoverage(rs []*report.Report, weighted bool) (float64, int) {
+	var (
+		n              int
+		sum            float64
+		total, covered int
+	)
+	for _, r := range rs {
+		if !r.IsMeasuredCoverage() {
+			continue
+		}
+		n++
+		sum += r.CoveragePercent()
+		total += r.Coverage.Total
+		covered += r.Coverage.Covered
+	}
+	if n == 0 {
+		return 0, 0
+	}
+	if !weighted {
+		return sum / float64(n), n
+	}
+	if total == 0 {
+		return 0, n
+	}
+	return float64(covered) / float64(total) * 100, n
+}
+
+// summary returns the average code coverage across the collected reports. It returns nil if the summary is disabled or no code coverage is measured.
+func (c *Central) summary() *summary {
+	if c.config.SummaryAverage == "" {
+		return nil
+	}
+	cover, n := AverageCoverage(c.reports, c.config.SummaryAverage == AverageWeighted)
+	if n == 0 {
+		return nil
+	}
+	return &summary{
+		Average:      c.config.SummaryAverage,
+		Coverage:     cover,
+		Repositories: n,
+	}
 }
 
 func New(c *Config) *Central {
@@ -71,13 +146,45 @@ func (c *Central) Generate(ctx context.Context) ([]string, error) {
 		return nil, err
 	}
 	if err := c.renderIndex(i); err != nil {
+		_ = i.Close()
+		return nil, err
+	}
+	if err := i.Close(); err != nil {
 		return nil, err
 	}
 	paths = append(paths, p)
 
+	// render JSON
+	if c.config.JSON != "" {
+		if err := os.MkdirAll(filepath.Dir(c.config.JSON), 0755); err != nil { // #nosec
+			return nil, err
+		}
+		j, err := os.OpenFile(c.config.JSON, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec
+		if err != nil {
+			return nil, err
+		}
+		if err := c.renderJSON(j); err != nil {
+			_ = j.Close()
+			return nil, err
+		}
+		if err := j.Close(); err != nil {
+			return nil, err
+		}
+		paths = append(paths, c.config.JSON)
+	}
+
 	return paths, nil
 }
 
+// CollectReports collects the latest report of each repository from the report datastores.
+func (c *Central) CollectReports() ([]*report.Report, error) {
+	c.reports = nil
+	if err := c.collectReports(); err != nil {
+		return nil, err
+	}
+	return c.reports, nil
+}
+
 func (c *Central) CollectedReports() []*report.Report {
 	return c.reports
 }
@@ -87,29 +194,47 @@ func (c *Central) collectReports() error {
 
 	// collect reports
 	for _, d := range c.config.Reports {
+		versions := c.versions(d)
 		fsys, err := d.FS()
 		if err != nil {
 			return err
 		}
+		var paths []string
 		if err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
 			if err != nil {
 				return err
 			}
-			if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
+			if d.IsDir() {
+				// Skip the directories of the repositories (owner/repo) not to be aggregated before reading the reports in them
+				if path != "." && strings.Count(path, "/") == 1 && !c.repositoryMatch(path) {
+					return fs.SkipDir
+				}
+				// Skip the reports keyed by the ref (owner/repo/refs/heads/... and owner/repo/refs/tags/...) not to be collected as the latest report of the repository
+				if strings.Count(path, "/") == 2 && d.Name() == "refs" {
+					return fs.SkipDir
+				}
 				return nil
 			}
-			r := &report.Report{}
-			f, err := fsys.Open(path)
-			if err != nil {
+			if !strings.HasSuffix(d.Name(), ".json") && !strings.HasSuffix(d.Name(), ".json"+internal.GzipExt) {
 				return nil
 			}
-			defer f.Close()
-			b, err := io.ReadAll(f)
-			if err != nil {
-				return nil
+			paths = append(paths, path)
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		rs, errs := c.readReports(fsys, paths, versions)
+		for i, r := range rs {
+			if errs[i] != nil {
+				// A broken report of a repository does not abort the aggregation of the others
+				if _, err := fmt.Fprintf(os.Stderr, "Skip report %s: %v\n", paths[i], errs[i]); err != nil {
+					return err
+				}
+				continue
 			}
-			if err := json.Unmarshal(b, r); err != nil {
-				return nil
+			if !c.repositoryMatch(r.Repository) {
+				continue
 			}
 			current, ok := rsMap[r.Repository]
 			if !ok {
@@ -117,14 +242,11 @@ func (c *Central) collectReports() error {
 					return err
 				}
 				rsMap[r.Repository] = r
-				return nil
+				continue
 			}
 			if current.Timestamp.UnixNano() < r.Timestamp.UnixNano() {
 				rsMap[r.Repository] = r
 			}
-			return nil
-		}); err != nil {
-			return err
 		}
 	}
 
@@ -135,6 +257,93 @@ func (c *Central) collectReports() error {
 	return nil
 }
 
+// readReports reads the reports of paths in fsys, running at most Config.Concurrency reads at a time.
+// The reports and the errors are returned in the order of paths.
+func (c *Central) readReports(fsys fs.FS, paths []string, versions map[string]string) ([]*report.Report, []error) {
+	rs := make([]*report.Report, len(paths))
+	errs := make([]error, len(paths))
+	n := c.config.Concurrency
+	if n < 1 {
+		n = 1
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, n)
+	for i, path := range paths {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, path string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			b, err := c.readReport(fsys, path, versions[path])
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			r := &report.Report{}
+			if err := json.Unmarshal(b, r); err != nil {
+				errs[i] = err
+				return
+			}
+			rs[i] = r
+		}(i, path)
+	}
+	wg.Wait()
+	return rs, errs
+}
+
+func (c *Central) repositoryMatch(repo string) bool {
+	if c.config.RepositoryFilter == nil {
+		return true
+	}
+	return c.config.RepositoryFilter(repo)
+}
+
+// versions returns the versions of the reports in the datastore to look up the cache.
+// It returns nil if the cache is disabled or the datastore does not support versions.
+func (c *Central) versions(d datastore.Datastore) map[string]string {
+	if c.config.Cache == nil {
+		return nil
+	}
+	vd, ok := d.(datastore.Versioned)
+	if !ok {
+		return nil
+	}
+	versions, err := vd.Versions(context.Background())
+	if err != nil {
+		log.Printf("failed to get versions of reports to use the cache: %v", err)
+		return nil
+	}
+	return versions
+}
+
+// readReport reads the report at path in fsys, using the cache if the version of the report is known.
+func (c *Central) readReport(fsys fs.FS, path, version string) ([]byte, error) {
+	var key string
+	if c.config.Cache != nil && version != "" {
+		key = fmt.Sprintf("%s@%s", path, version)
+		if b, ok := c.config.Cache.Get(key); ok {
+			return b, nil
+		}
+	}
+	f, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	if key != "" {
+		if err := c.config.Cache.Put(key, b); err != nil {
+			log.Printf("failed to store the report to the cache: %v", err)
+		}
+	}
+	return b, nil
+}
+
 func (c *Central) generateBadges() ([]string, error) {
 	ctx := context.Background()
 	badges := map[string][]byte{}
@@ -183,6 +392,34 @@ func (c *Central) generateBadges() ([]string, error) {
 			}
 			badges[bp] = out.Bytes()
 		}
+
+		// Doc Coverage
+		if r.DocCoverage != nil && c.config.DocCoverageColor != nil {
+			dp := r.DocCoveragePercent()
+			bp := filepath.Join(r.Repository, "doc.svg")
+			out := new(bytes.Buffer)
+			b := badge.New("doc coverage", fmt.Sprintf("%.1f%%", dp))
+			b.MessageColor = c.config.DocCoverageColor(dp)
+			if err := b.AddIcon(internal.Icon); err != nil {
+				return nil, err
+			}
+			if err := b.Render(out); err != nil {
+				return nil, err
+			}
+			badges[bp] = out.Bytes()
+		}
+	}
+	if sm := c.summary(); sm != nil {
+		out := new(bytes.Buffer)
+		b := badge.New("coverage", fmt.Sprintf("%.1f%%", sm.Coverage))
+		b.MessageColor = c.config.CoverageColor(sm.Coverage)
+		if err := b.AddIcon(internal.Icon); err != nil {
+			return nil, err
+		}
+		if err := b.Render(out); err != nil {
+			return nil, err
+		}
+		badges[summaryBadgePath] = out.Bytes()
 	}
 	var generatedPaths []string
 	for _, d := range c.config.Badges {
@@ -265,7 +502,8 @@ func (c *Central) renderIndex(wr io.Writer) error {
 
 	d := map[string]any{
 		"Host":          host,
-		"Reports":       c.reports,
+		"Reports":       c.indexReports(),
+		"Summary":       c.summary(),
 		"BadgesLinkRel": filepath.ToSlash(badgesLinkRel),
 		"BadgesURLRel":  filepath.ToSlash(badgesURLRel),
 		"RootURL":       rootURL,
@@ -279,6 +517,74 @@ func (c *Central) renderIndex(wr io.Writer) error {
 	return nil
 }
 
+// indexReports returns the collected reports in the order of the index.
+func (c *Central) indexReports() []*report.Report {
+	if !c.config.SortByCoverage {
+		return c.reports
+	}
+	rs := make([]*report.Report, len(c.reports))
+	copy(rs, c.reports)
+	sort.SliceStable(rs, func(i, j int) bool {
+		if rs[i].IsMeasuredCoverage() != rs[j].IsMeasuredCoverage() {
+			return rs[i].IsMeasuredCoverage()
+		}
+		return rs[i].CoveragePercent() > rs[j].CoveragePercent()
+	})
+	return rs
+}
+
+type jsonReport struct {
+	Repository        string    `json:"repository"`
+	Ref               string    `json:"ref"`
+	Commit            string    `json:"commit"`
+	Coverage          *float64  `json:"coverage,omitempty"`
+	CodeToTestRatio   *float64  `json:"code_to_test_ratio,omitempty"`
+	TestExecutionTime *float64  `json:"test_execution_time,omitempty"`
+	Timestamp         time.Time `json:"timestamp"`
+	BelowThreshold    bool      `json:"below_threshold"`
+}
+
+func (c *Central) renderJSON(wr io.Writer) error {
+	rs := []*jsonReport{}
+	for _, r := range c.reports {
+		jr := &jsonReport{
+			Repository:        r.Repository,
+			Ref:               r.Ref,
+			Commit:            r.Commit,
+			TestExecutionTime: r.TestExecutionTime,
+			Timestamp:         r.Timestamp,
+		}
+		if r.IsMeasuredCoverage() {
+			cp := r.CoveragePercent()
+			jr.Coverage = &cp
+		}
+		if r.IsMeasuredCodeToTestRatio() {
+			tr := r.CodeToTestRatioRatio()
+			jr.CodeToTestRatio = &tr
+		}
+		if c.config.Acceptable != nil {
+			if err := c.config.Acceptable(r); err != nil {
+				jr.BelowThreshold = true
+			}
+		}
+		rs = append(rs, jr)
+	}
+	v := map[string]any{
+		"reports": rs,
+	}
+	if sm := c.summary(); sm != nil {
+		v["summary"] = sm
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := wr.Write(b); err != nil {
+		return err
+	}
+	return nil
+}
+
 func funcs() map[string]any {
 	return template.FuncMap{
 		"coverage": func(r *report.Report) string {
@@ -2,13 +2,19 @@ package central
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/coverage"
 	"github.com/k1LoW/octocov/datastore"
 	"github.com/k1LoW/octocov/datastore/local"
+	"github.com/k1LoW/octocov/report"
 )
 
 func TestCollectReports(t *testing.T) {
@@ -42,6 +48,135 @@ func TestCollectReports(t *testing.T) {
 	}
 }
 
+func TestCollectReportsWithRepositoryFilter(t *testing.T) {
+	tests := []struct {
+		include []string
+		exclude []string
+		want    []string
+	}{
+		{nil, nil, []string{"k1LoW/awpsec", "k1LoW/tbls", "sebastianbergmann/phpunit", "tiangolo/fastapi", "winebarrel/ridgepole"}},
+		{[]string{"k1LoW/*"}, nil, []string{"k1LoW/awpsec", "k1LoW/tbls"}},
+		{[]string{"k1LoW/*"}, []string{"k1LoW/tbls"}, []string{"k1LoW/awpsec"}},
+		{nil, []string{"k1LoW/*", "tiangolo/fastapi"}, []string{"sebastianbergmann/phpunit", "winebarrel/ridgepole"}},
+	}
+	for _, tt := range tests {
+		c := config.New()
+		c.Central = &config.Central{
+			Repositories: &config.CentralRepositories{
+				Include: tt.include,
+				Exclude: tt.exclude,
+			},
+		}
+		rd, err := local.New(filepath.Join(testdataDir(t), "reports"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctr := New(&Config{
+			Repository:       "owner/repo",
+			Index:            ".",
+			Wd:               c.Wd(),
+			Reports:          []datastore.Datastore{rd},
+			RepositoryFilter: c.CentralRepositoryMatch,
+		})
+		if err := ctr.collectReports(); err != nil {
+			t.Fatal(err)
+		}
+		got := []string{}
+		for _, r := range ctr.reports {
+			got = append(got, r.Repository)
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestCollectReportsConcurrently(t *testing.T) {
+	want := []string{"k1LoW/awpsec", "k1LoW/tbls", "sebastianbergmann/phpunit", "tiangolo/fastapi", "winebarrel/ridgepole"}
+	bdir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(bdir, "owner", "broken"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bdir, "owner", "broken", "report.json"), []byte("{broken"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, concurrency := range []int{0, 1, 3, 10} {
+		rd, err := local.New(filepath.Join(testdataDir(t), "reports"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		bd, err := local.New(bdir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctr := New(&Config{
+			Repository:  "owner/repo",
+			Index:       ".",
+			Reports:     []datastore.Datastore{rd, bd},
+			Concurrency: concurrency,
+		})
+		if err := ctr.collectReports(); err != nil {
+			t.Fatalf("concurrency %d: %v", concurrency, err)
+		}
+		got := []string{}
+		for _, r := range ctr.reports {
+			got = append(got, r.Repository)
+		}
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Errorf("concurrency %d: %s", concurrency, diff)
+		}
+	}
+}
+
+func TestCollectReportsSkipRefReports(t *testing.T) {
+	dir := t.TempDir()
+	b, err := os.ReadFile(filepath.Join(testdataDir(t), "reports", "k1LoW", "tbls", "report.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "k1LoW", "tbls"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "k1LoW", "tbls", "report.json"), b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	r := &report.Report{}
+	if err := json.Unmarshal(b, r); err != nil {
+		t.Fatal(err)
+	}
+	want := r.Timestamp
+	// The report of the tag is newer than the report of the default branch
+	r.Timestamp = r.Timestamp.Add(time.Hour)
+	rb, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "k1LoW", "tbls", "refs", "tags", "v1.0.0"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "k1LoW", "tbls", "refs", "tags", "v1.0.0", "report.json"), rb, 0600); err != nil {
+		t.Fatal(err)
+	}
+	rd, err := local.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctr := New(&Config{
+		Repository: "owner/repo",
+		Index:      ".",
+		Reports:    []datastore.Datastore{rd},
+	})
+	if err := ctr.collectReports(); err != nil {
+		t.Fatal(err)
+	}
+	if len(ctr.reports) != 1 {
+		t.Fatalf("got %v\nwant %v", len(ctr.reports), 1)
+	}
+	if got := ctr.reports[0].Timestamp; !got.Equal(want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
 func TestGenerateBadges(t *testing.T) {
 	c := config.New()
 	rd, err := local.New(filepath.Join(testdataDir(t), "reports"))
@@ -94,6 +229,69 @@ func TestGenerateBadges(t *testing.T) {
 	}
 }
 
+func TestGenerateBadgesWithSummary(t *testing.T) {
+	c := config.New()
+	rd, err := local.New(filepath.Join(testdataDir(t), "reports"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	td := t.TempDir()
+	bd, err := local.New(td)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctr := New(&Config{
+		Repository:             "owner/repo",
+		Index:                  ".",
+		Wd:                     c.Wd(),
+		Badges:                 []datastore.Datastore{bd},
+		Reports:                []datastore.Datastore{rd},
+		CoverageColor:          c.CoverageColor,
+		CodeToTestRatioColor:   c.CodeToTestRatioColor,
+		TestExecutionTimeColor: c.TestExecutionTimeColor,
+		SummaryAverage:         AverageWeighted,
+	})
+	if err := ctr.collectReports(); err != nil {
+		t.Fatal(err)
+	}
+	paths, err := ctr.generateBadges()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 11; len(paths) != want {
+		t.Errorf("got %v\nwant %v", len(paths), want)
+	}
+	if _, err := os.Stat(filepath.Join(td, "coverage.svg")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAverageCoverage(t *testing.T) {
+	rs := []*report.Report{
+		{Repository: "owner/a", Coverage: &coverage.Coverage{Total: 100, Covered: 90}},
+		{Repository: "owner/b", Coverage: &coverage.Coverage{Total: 900, Covered: 450}},
+		{Repository: "owner/c"},
+	}
+	tests := []struct {
+		rs       []*report.Report
+		weighted bool
+		want     float64
+		wantN    int
+	}{
+		{rs, true, 54.0, 2},
+		{rs, false, 70.0, 2},
+		{rs[2:], true, 0, 0},
+		{nil, false, 0, 0},
+		{[]*report.Report{{Coverage: &coverage.Coverage{}}}, true, 0, 1},
+	}
+	for _, tt := range tests {
+		got, gotN := AverageCoverage(tt.rs, tt.weighted)
+		if got != tt.want || gotN != tt.wantN {
+			t.Errorf("got %v, %v\nwant %v, %v", got, gotN, tt.want, tt.wantN)
+		}
+	}
+}
+
 func TestRenderIndex(t *testing.T) {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -150,6 +348,92 @@ func TestRenderIndex(t *testing.T) {
 	}
 }
 
+func TestRenderJSON(t *testing.T) {
+	c := config.New()
+	rd, err := local.New(filepath.Join(testdataDir(t), "reports"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bd, err := local.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctr := New(&Config{
+		Repository: "owner/repo",
+		Index:      ".",
+		Wd:         c.Wd(),
+		Badges:     []datastore.Datastore{bd},
+		Reports:    []datastore.Datastore{rd},
+		Acceptable: func(r *report.Report) error {
+			if r.CoveragePercent() < 50.0 {
+				return errors.New("below threshold")
+			}
+			return nil
+		},
+		CoverageColor:          c.CoverageColor,
+		CodeToTestRatioColor:   c.CodeToTestRatioColor,
+		TestExecutionTimeColor: c.TestExecutionTimeColor,
+	})
+	if err := ctr.collectReports(); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := ctr.renderJSON(buf); err != nil {
+		t.Fatal(err)
+	}
+	got := struct {
+		Reports []*jsonReport `json:"reports"`
+	}{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := 5; len(got.Reports) != want {
+		t.Fatalf("got %v\nwant %v", len(got.Reports), want)
+	}
+	for i, jr := range got.Reports {
+		r := ctr.reports[i]
+		if jr.Repository != r.Repository {
+			t.Errorf("got %v\nwant %v", jr.Repository, r.Repository)
+		}
+		if want := r.CoveragePercent() < 50.0; jr.BelowThreshold != want {
+			t.Errorf("%s: got %v\nwant %v", jr.Repository, jr.BelowThreshold, want)
+		}
+	}
+}
+
+func TestIndexReports(t *testing.T) {
+	c := config.New()
+	rd, err := local.New(filepath.Join(testdataDir(t), "reports"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctr := New(&Config{
+		Repository:     "owner/repo",
+		Index:          ".",
+		Wd:             c.Wd(),
+		Reports:        []datastore.Datastore{rd},
+		SortByCoverage: true,
+	})
+	if err := ctr.collectReports(); err != nil {
+		t.Fatal(err)
+	}
+	got := ctr.indexReports()
+	if len(got) != len(ctr.reports) {
+		t.Fatalf("got %v\nwant %v", len(got), len(ctr.reports))
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i-1].CoveragePercent() < got[i].CoveragePercent() {
+			t.Errorf("%s (%v) should be after %s (%v)", got[i-1].Repository, got[i-1].CoveragePercent(), got[i].Repository, got[i].CoveragePercent())
+		}
+	}
+	for i := 1; i < len(ctr.reports); i++ {
+		if ctr.reports[i-1].Repository > ctr.reports[i].Repository {
+			t.Error("collected reports should remain sorted by repository")
+		}
+	}
+}
+
 func testdataDir(t *testing.T) string {
 	t.Helper()
 	wd, err := os.Getwd()
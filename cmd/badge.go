@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -40,8 +41,12 @@ const (
 	badgeCoverage = "coverage"
 	badgeRatio    = "ratio"
 	badgeTime     = "time"
+	badgeDoc      = "doc"
 )
 
+// naColor is the message color of badges whose metric is not applicable.
+const naColor = "#9F9F9F"
+
 var outPath string
 
 // badgeCmd represents the badge command.
@@ -50,7 +55,7 @@ var badgeCmd = &cobra.Command{
 	Short:     "generate badge",
 	Long:      `generate badge.`,
 	Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
-	ValidArgs: []string{badgeCoverage, badgeRatio, badgeTime},
+	ValidArgs: []string{badgeCoverage, badgeRatio, badgeTime, badgeDoc},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := context.Background()
 		c := config.New()
@@ -58,8 +63,12 @@ var badgeCmd = &cobra.Command{
 			return err
 		}
 		c.Build()
+		c, err := selectCoverageConfig(c)
+		if err != nil {
+			return err
+		}
 
-		r, err := report.New(c.Repository)
+		r, err := report.New(c.Repository, report.CaseInsensitive(c.Coverage.CaseInsensitive), report.ParserCommand(c.CoverageParserCommand()), report.CoverageFormat(c.Coverage.Format))
 		if err != nil {
 			return err
 		}
@@ -88,10 +97,8 @@ var badgeCmd = &cobra.Command{
 			if err := r.MeasureCoverage(c.Coverage.Paths, c.Coverage.Exclude); err != nil {
 				return err
 			}
-			cp := r.CoveragePercent()
-			b := badge.New("coverage", fmt.Sprintf("%.1f%%", cp))
-			b.MessageColor = c.CoverageColor(cp)
-			if err := b.AddIcon(internal.Icon); err != nil {
+			b, err := coverageBadge(c, r.CoveragePercent(), nil)
+			if err != nil {
 				return err
 			}
 			if err := b.Render(out); err != nil {
@@ -104,16 +111,14 @@ var badgeCmd = &cobra.Command{
 			if err := c.CodeToTestRatioConfigReady(); err != nil {
 				return err
 			}
-			if err := r.MeasureCodeToTestRatio(c.Root(), c.CodeToTestRatio.Code, c.CodeToTestRatio.Test); err != nil {
+			if err := r.MeasureCodeToTestRatioByConfig(c); err != nil {
 				return err
 			}
-			tr := r.CodeToTestRatioRatio()
-			b := badge.New("code to test ratio", fmt.Sprintf("1:%.1f", tr))
-			b.MessageColor = c.CodeToTestRatioColor(tr)
-			if err := b.AddIcon(internal.Icon); err != nil {
+			b, err := codeToTestRatioBadge(c, r)
+			if err != nil {
 				return err
 			}
-			if err := b.Render(out); err != nil {
+			if err := b.RenderAs(badge.FormatOf(outPath), out); err != nil {
 				return err
 			}
 		case badgeTime:
@@ -130,6 +135,28 @@ var badgeCmd = &cobra.Command{
 			d := time.Duration(r.TestExecutionTimeNano())
 			b := badge.New("test execution time", d.String())
 			b.MessageColor = c.TestExecutionTimeColor(d)
+			if err := b.SetStyle(c.TestExecutionTime.Badge.Style); err != nil {
+				return err
+			}
+			if err := b.AddIcon(internal.Icon); err != nil {
+				return err
+			}
+			if err := b.Render(out); err != nil {
+				return err
+			}
+		case badgeDoc:
+			if err := c.DocCoverageConfigReady(); err != nil {
+				return err
+			}
+			if err := r.MeasureDocCoverage(c.Root(), c.DocCoverage.Include, c.DocCoverage.Exclude); err != nil {
+				return err
+			}
+			dp := r.DocCoveragePercent()
+			b := badge.New("doc coverage", fmt.Sprintf("%.1f%%", dp))
+			b.MessageColor = c.DocCoverageColor(dp)
+			if err := b.SetStyle(c.DocCoverage.Badge.Style); err != nil {
+				return err
+			}
 			if err := b.AddIcon(internal.Icon); err != nil {
 				return err
 			}
@@ -145,5 +172,51 @@ var badgeCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(badgeCmd)
 	badgeCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
+	badgeCmd.Flags().StringVarP(&coverageName, "coverage-name", "", "", "name of the coverage config to be used when coverage: is a list of named coverage configs")
 	badgeCmd.Flags().StringVarP(&outPath, "out", "", "", "output file path")
 }
+
+func codeToTestRatioBadge(c *config.Config, r *report.Report) (*badge.Badge, error) {
+	tr := r.CodeToTestRatioRatio()
+	var b *badge.Badge
+	if c.IsCodeToTestRatioMeaningful(r.CodeToTestRatioCodeFiles()) {
+		b = badge.New(c.CodeToTestRatioBadgeLabel(), fmt.Sprintf("1:%.1f", tr))
+		b.MessageColor = c.CodeToTestRatioColor(tr)
+	} else {
+		b = badge.New(c.CodeToTestRatioBadgeLabel(), "n/a")
+		b.MessageColor = naColor
+	}
+	if err := b.SetStyle(c.CodeToTestRatio.Badge.Style); err != nil {
+		return nil, err
+	}
+	if err := b.AddIcon(internal.Icon); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// coverageBadge returns the badge of the code coverage, with the trend arrow of delta (the change from the previous report) if coverage.badge.showTrend is true.
+func coverageBadge(c *config.Config, cp float64, delta *float64) (*badge.Badge, error) {
+	var b *badge.Badge
+	if c.Coverage.Badge.Style == config.BadgeStyleGoal {
+		goal, err := c.CoverageGoal()
+		if err != nil {
+			return nil, err
+		}
+		b = badge.New(c.CoverageBadgeLabel(), fmt.Sprintf("%.1f/%s%%", cp, strconv.FormatFloat(goal, 'f', -1, 64)))
+		b.MessageColor = c.CoverageGoalColor(cp, goal)
+	} else {
+		b = badge.New(c.CoverageBadgeLabel(), fmt.Sprintf("%.1f%%", cp))
+		b.MessageColor = c.CoverageBadgeColor(cp)
+		if err := b.SetStyle(c.Coverage.Badge.Style); err != nil {
+			return nil, err
+		}
+	}
+	if c.Coverage.Badge.ShowTrend && delta != nil {
+		b.Message = fmt.Sprintf("%s %s", b.Message, badge.Trend(*delta))
+	}
+	if err := b.AddIcon(internal.Icon); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -20,6 +20,10 @@ func replaceInsertReportToBody(ctx context.Context, c *config.Config, content, k
 	if err != nil {
 		return err
 	}
+	if dryRun {
+		dryRunf("insert report to body of pull request #%d:\n%s", n, content)
+		return nil
+	}
 	if err := g.ReplaceInsertToBody(ctx, repo.Owner, repo.Repo, n, content, key); err != nil {
 		return err
 	}
@@ -6,8 +6,10 @@ import (
 	"strings"
 
 	"github.com/hashicorp/go-multierror"
+	"github.com/k1LoW/octocov/azure"
 	"github.com/k1LoW/octocov/config"
 	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/gitlab"
 	"github.com/k1LoW/octocov/report"
 )
 
@@ -24,11 +26,20 @@ func commentReport(ctx context.Context, c *config.Config, content, key string) e
 	if err != nil {
 		return err
 	}
-	if c.Comment.DeletePrevious {
+	if dryRun {
+		dryRunf("comment to pull request #%d:\n%s", n, content)
+		return nil
+	}
+	switch {
+	case c.Comment.UpdatePrevious:
+		if err := g.PutCommentWithUpdate(ctx, repo.Owner, repo.Repo, n, content, key); err != nil {
+			return err
+		}
+	case c.Comment.DeletePrevious:
 		if err := g.PutCommentWithDeletion(ctx, repo.Owner, repo.Repo, n, content, key); err != nil {
 			return err
 		}
-	} else {
+	default:
 		if err := g.PutComment(ctx, repo.Owner, repo.Repo, n, content, key); err != nil {
 			return err
 		}
@@ -36,7 +47,94 @@ func commentReport(ctx context.Context, c *config.Config, content, key string) e
 	return nil
 }
 
-func createReportContent(ctx context.Context, c *config.Config, r, rPrev *report.Report, hideFooterLink bool) (string, error) {
+// commentReportToIssue upserts the comment of the report on the issue of report.issue, editing the previous comment with the same key in place.
+func commentReportToIssue(ctx context.Context, c *config.Config, content, key string) error {
+	repo, err := gh.Parse(c.Repository)
+	if err != nil {
+		return err
+	}
+	g, err := gh.New()
+	if err != nil {
+		return err
+	}
+	n := c.Report.Issue
+	i, err := g.FetchIssue(ctx, repo.Owner, repo.Repo, n)
+	if err != nil {
+		return fmt.Errorf("report.issue: failed to fetch issue #%d of %s: %w", n, c.Repository, err)
+	}
+	if i.IsPullRequest {
+		return fmt.Errorf("report.issue: #%d of %s is not an issue but a pull request", n, c.Repository)
+	}
+	if dryRun {
+		dryRunf("comment to issue #%d:\n%s", n, content)
+		return nil
+	}
+	return g.PutCommentWithUpdate(ctx, repo.Owner, repo.Repo, n, content, key)
+}
+
+// noteReportToGitLab posts the report as a note of the merge request of the current GitLab CI pipeline.
+func noteReportToGitLab(ctx context.Context, c *config.Config, r, rPrev *report.Report, key string) error {
+	e, err := gitlab.DecodeGitLabCIEnv()
+	if err != nil {
+		return err
+	}
+	g, err := gitlab.New()
+	if err != nil {
+		return err
+	}
+	project := c.ReportGitLabProject()
+	mrFiles, err := g.FetchMergeRequestFiles(ctx, project, e.MergeRequestIID, e.ProjectURL, e.CommitSHA)
+	if err != nil {
+		return err
+	}
+	files := make([]*gh.PullRequestFile, 0, len(mrFiles))
+	for _, f := range mrFiles {
+		files = append(files, &gh.PullRequestFile{Filename: f.Filename, BlobURL: f.BlobURL})
+	}
+	content, err := renderReportContent(ctx, c, r, rPrev, files, c.Comment != nil && c.Comment.HideFooterLink, c.Comment != nil && c.Comment.Collapse, c.CommentTemplate())
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		dryRunf("post note to merge request !%d of %s:\n%s", e.MergeRequestIID, project, content)
+		return nil
+	}
+	return g.PutNote(ctx, project, e.MergeRequestIID, content, key)
+}
+
+// commentReportToAzure posts the report as a thread comment of the pull request of the current Azure Pipelines run.
+func commentReportToAzure(ctx context.Context, c *config.Config, r, rPrev *report.Report, key string) error {
+	e, err := azure.DecodeAzurePipelinesEnv()
+	if err != nil {
+		return err
+	}
+	a, err := azure.New()
+	if err != nil {
+		return err
+	}
+	collectionURL := c.ReportAzureCollectionURL()
+	project := c.ReportAzureProject()
+	repo := c.ReportAzureRepository()
+	prFiles, err := a.FetchPullRequestFiles(ctx, collectionURL, project, repo, e.PullRequestID, e.CommitSHA)
+	if err != nil {
+		return err
+	}
+	files := make([]*gh.PullRequestFile, 0, len(prFiles))
+	for _, f := range prFiles {
+		files = append(files, &gh.PullRequestFile{Filename: f.Filename, BlobURL: f.BlobURL})
+	}
+	content, err := renderReportContent(ctx, c, r, rPrev, files, c.Comment != nil && c.Comment.HideFooterLink, c.Comment != nil && c.Comment.Collapse, c.CommentTemplate())
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		dryRunf("comment to pull request !%d of %s/%s:\n%s", e.PullRequestID, project, repo, content)
+		return nil
+	}
+	return a.PutComment(ctx, collectionURL, project, repo, e.PullRequestID, content, key)
+}
+
+func createReportContent(ctx context.Context, c *config.Config, r, rPrev *report.Report, hideFooterLink, collapse bool, tmplPath string) (string, error) {
 	repo, err := gh.Parse(c.Repository)
 	if err != nil {
 		return "", err
@@ -58,56 +156,174 @@ func createReportContent(ctx context.Context, c *config.Config, r, rPrev *report
 			return "", err
 		}
 	}
-	footer := "Reported by [octocov](https://github.com/k1LoW/octocov)"
+	return renderReportContent(ctx, c, r, rPrev, files, hideFooterLink, collapse, tmplPath)
+}
+
+// renderReportContent renders the Markdown report, listing the coverages of the changed files.
+// If tmplPath is set, the report is rendered using the custom template instead of the built-in one.
+func renderReportContent(ctx context.Context, c *config.Config, r, rPrev *report.Report, files []*gh.PullRequestFile, hideFooterLink, collapse bool, tmplPath string) (string, error) {
+	d := &report.TemplateData{
+		Repository: c.Repository,
+		Title:      r.Title(),
+		Report:     r,
+		Prev:       rPrev,
+		Footer:     "Reported by [octocov](https://github.com/k1LoW/octocov)",
+	}
 	if hideFooterLink {
-		footer = "Reported by octocov"
+		d.Footer = "Reported by octocov"
 	}
-	var (
-		table, fileTable string
-		customTables     []string
-	)
 	if rPrev != nil {
-		d := r.Compare(rPrev)
-		table = d.Table()
-		fileTable = d.FileCoveragesTable(files)
-		for _, s := range d.CustomMetrics {
-			customTables = append(customTables, s.Table(), s.MetadataTable())
+		diff := r.Compare(rPrev)
+		d.Diff = diff
+		if diff.Coverage != nil {
+			d.CoverageDelta = diff.Coverage.Diff
+		}
+		d.Table = diff.Table()
+		d.FileTable = diff.FileCoveragesTable(files)
+		for _, s := range diff.CustomMetrics {
+			d.CustomTables = append(d.CustomTables, s.Table(), s.MetadataTable())
 		}
 	} else {
-		table = r.Table()
-		fileTable = r.FileCoveragesTable(files)
+		d.Table = r.Table()
+		if err := c.DiffConfigReady(); err == nil {
+			d.Note = "_No baseline report to compare with._\n"
+		}
+		d.FileTable = r.FileCoveragesTable(files)
 		for _, s := range r.CustomMetrics {
-			customTables = append(customTables, s.Table(), s.MetadataTable())
+			d.CustomTables = append(d.CustomTables, s.Table(), s.MetadataTable())
 		}
 	}
-
-	var comment []string
-	if r.IsMeasuredCoverage() || r.IsMeasuredTestExecutionTime() || r.IsMeasuredCodeToTestRatio() {
-		comment = append(comment, fmt.Sprintf("## %s", r.Title()))
-	}
-	if err := c.Acceptable(r, rPrev); err != nil {
+	var acceptable string
+	if err := c.Acceptable(ctx, r, rPrev); err != nil {
 		merr, ok := err.(*multierror.Error) //nolint:errorlint
 		if !ok {
 			return "", fmt.Errorf("failed to convert error to multierror: %w", err)
 		}
+		for _, err := range merr.Errors {
+			if config.IsAcceptableWarning(err) {
+				d.Warnings = append(d.Warnings, capitalize(err.Error()))
+				continue
+			}
+			d.Errors = append(d.Errors, capitalize(err.Error()))
+		}
 		merr.ErrorFormat = func(errors []error) string {
 			var out string
 			for _, err := range errors {
+				if config.IsAcceptableWarning(err) {
+					out += fmt.Sprintf("**:warning: %s**\n\n", capitalize(err.Error()))
+					continue
+				}
 				out += fmt.Sprintf("**:no_entry_sign: %s**\n\n", capitalize(err.Error()))
 			}
 			return out
 		}
-		comment = append(comment, merr.Error())
+		acceptable = merr.Error()
+	}
+	measured := r.IsMeasuredCoverage() || r.IsMeasuredTestExecutionTime() || r.IsMeasuredCodeToTestRatio() || r.IsMeasuredDocCoverage()
+	if measured {
+		d.LabelTable = r.LabelCoveragesTable(rPrev)
+		d.GroupRatioTable = r.CodeToTestRatioNamedGroupsTable()
+		d.RatioTable = r.CodeToTestRatioGroupsTable(c.CodeToTestRatioGroupDepth())
+		if collapse {
+			d.FileTable = collapseSection(d.FileTable)
+			d.LabelTable = collapseSection(d.LabelTable)
+			d.GroupRatioTable = collapseSection(d.GroupRatioTable)
+			d.RatioTable = collapseSection(d.RatioTable)
+		}
+		d.Trend = coverageTrend(ctx, c, r)
+		if r.IsMeasuredPatchCoverage() {
+			d.PatchCoverage = patchCoverageLine(r)
+		}
 	}
-	if r.IsMeasuredCoverage() || r.IsMeasuredTestExecutionTime() || r.IsMeasuredCodeToTestRatio() {
-		comment = append(comment, table, "", fileTable)
+
+	if tmplPath != "" {
+		buf := new(strings.Builder)
+		if err := report.RenderTemplate(buf, tmplPath, d); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
 	}
-	comment = append(comment, customTables...)
-	comment = append(comment, "---", footer)
+
+	var comment []string
+	if measured {
+		comment = append(comment, fmt.Sprintf("## %s", d.Title))
+	}
+	if acceptable != "" {
+		comment = append(comment, acceptable)
+	}
+	if measured {
+		comment = append(comment, d.Table, "")
+		if d.Note != "" {
+			comment = append(comment, d.Note)
+		}
+		if d.Trend != "" {
+			comment = append(comment, d.Trend)
+		}
+		if d.PatchCoverage != "" {
+			comment = append(comment, d.PatchCoverage)
+		}
+		comment = append(comment, d.FileTable)
+		if d.LabelTable != "" {
+			comment = append(comment, d.LabelTable)
+		}
+		if d.GroupRatioTable != "" {
+			comment = append(comment, d.GroupRatioTable)
+		}
+		if d.RatioTable != "" {
+			comment = append(comment, d.RatioTable)
+		}
+	}
+	comment = append(comment, d.CustomTables...)
+	comment = append(comment, "---", d.Footer)
 
 	return strings.Join(comment, "\n"), nil
 }
 
+// patchCoverageLine returns the line of the code coverage of the lines added by the pull request.
+func patchCoverageLine(r *report.Report) string {
+	pc := r.PatchCoverage
+	if pc.Total == 0 {
+		return "Patch coverage: no added lines to be covered\n"
+	}
+	return fmt.Sprintf("Patch coverage: **%.1f%%** (%d/%d added lines covered)\n", r.PatchCoveragePercent(), pc.Covered, pc.Total)
+}
+
+// coverageTrend returns the line of the sparkline of the code coverage of recent reports, if coverage.history.sparkline is enabled.
+func coverageTrend(ctx context.Context, c *config.Config, r *report.Report) string {
+	if err := c.CoverageSparklineConfigReady(); err != nil || !r.IsMeasuredCoverage() || c.CoverageSparklineCount() < 2 {
+		return ""
+	}
+	recent := fetchRecentCoverages(ctx, c, c.Diff.Datastores, r, c.CoverageSparklineCount()-1)
+	percents := make([]float64, 0, len(recent)+1)
+	for i := len(recent) - 1; i >= 0; i-- {
+		percents = append(percents, recent[i])
+	}
+	percents = append(percents, r.CoveragePercent())
+	sl := report.Sparkline(percents)
+	if sl == "" {
+		return ""
+	}
+	return fmt.Sprintf("Coverage trend (last %d reports): `%s`\n", len(percents), sl)
+}
+
+// collapseSection wraps the section in a collapsible <details> element, using its heading as the summary.
+func collapseSection(section string) string {
+	if section == "" {
+		return ""
+	}
+	summary := "Details"
+	body := section
+	if strings.HasPrefix(section, "### ") {
+		splitted := strings.SplitN(section, "\n", 2)
+		summary = strings.TrimPrefix(splitted[0], "### ")
+		body = ""
+		if len(splitted) == 2 {
+			body = splitted[1]
+		}
+	}
+	return fmt.Sprintf("<details>\n\n<summary>%s</summary>\n\n%s\n</details>\n", summary, strings.Trim(body, "\n"))
+}
+
 func capitalize(w string) string {
 	splitted := strings.SplitN(w, "", 2)
 	switch len(splitted) {
@@ -0,0 +1,96 @@
+/*
+Copyright © 2021 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/report"
+	"github.com/spf13/cobra"
+)
+
+var compareBranchesJSON bool
+
+// compareBranchesCmd represents the compare-branches command.
+var compareBranchesCmd = &cobra.Command{
+	Use:   "compare-branches [BRANCH...]",
+	Short: "compare code metrics of the branches using the reports stored in datastores",
+	Long:  `compare code metrics of the branches using the reports stored in datastores.`,
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := config.New()
+		if err := c.Load(configPath); err != nil {
+			return err
+		}
+		c.Build()
+		ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+		defer cancel()
+
+		datastores := c.ReportDatastoresToRead()
+		if c.Diff != nil {
+			datastores = append(datastores, c.Diff.Datastores...)
+		}
+		if len(datastores) == 0 {
+			return errors.New("report.datastores: and diff.datastores: are not set")
+		}
+		var (
+			brs   report.BranchReports
+			found bool
+		)
+		for _, b := range args {
+			r, err := fetchReportOfRef(ctx, c, datastores, b)
+			if err != nil {
+				cmd.PrintErrf("Skip comparing branch %s: %v\n", b, err)
+			} else {
+				found = true
+			}
+			brs = append(brs, &report.BranchReport{Branch: b, Report: r})
+		}
+		if !found {
+			return errors.New("reports of the branches are not found in datastores")
+		}
+
+		if compareBranchesJSON {
+			b, err := brs.JSON()
+			if err != nil {
+				return err
+			}
+			cmd.Println(string(b))
+			return nil
+		}
+		cmd.Println("")
+		if err := brs.Out(os.Stdout); err != nil {
+			return err
+		}
+		cmd.Println("")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compareBranchesCmd)
+	compareBranchesCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
+	compareBranchesCmd.Flags().BoolVarP(&compareBranchesJSON, "json", "", false, "output in JSON format")
+}
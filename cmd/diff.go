@@ -22,6 +22,7 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/k1LoW/octocov/report"
@@ -30,9 +31,12 @@ import (
 
 // diffCmd represents the diff command.
 var diffCmd = &cobra.Command{
-	Use:     "diff [REPORT_A] [REPORT_B]",
-	Short:   "compare reports (code coverage report or octocov report.json)",
-	Long:    `compare reports (code coverage report or octocov report.json).`,
+	Use:   "diff [REPORT_A] [REPORT_B]",
+	Short: "compare reports (code coverage report or octocov report.json)",
+	Long: `compare reports (code coverage report or octocov report.json).
+
+REPORT_A is compared against REPORT_B (the baseline), and the files whose code coverage decreased are listed.
+It exits with a non-zero status if the code coverage decreased.`,
 	Aliases: []string{"compare"},
 	Args:    cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -60,7 +64,15 @@ var diffCmd = &cobra.Command{
 			b.Timestamp = fi.ModTime()
 		}
 
-		a.Compare(b).Out(os.Stdout)
+		d := a.Compare(b)
+		d.Out(os.Stdout)
+		if files := d.RegressedFiles(); len(files) > 0 {
+			cmd.Println("")
+			d.OutRegressedFiles(os.Stdout)
+		}
+		if d.Coverage != nil && d.Coverage.Diff < 0 {
+			return fmt.Errorf("code coverage decreased: %.1f%% -> %.1f%%", d.Coverage.B, d.Coverage.A)
+		}
 		return nil
 	},
 }
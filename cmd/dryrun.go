@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/k1LoW/octocov/gh"
+)
+
+// dryRunf prints the side effect that would be performed instead of performing it (--dry-run).
+func dryRunf(format string, a ...any) {
+	_, _ = fmt.Fprintf(os.Stderr, "[dry-run] "+format+"\n", a...) //nostyle:handlerrors
+}
+
+// writeFile writes b to path, or only prints it with --dry-run.
+func writeFile(path string, b []byte) error {
+	if dryRun {
+		dryRunf("write %s (%d bytes)", path, len(b))
+		return nil
+	}
+	return os.WriteFile(path, b, os.ModePerm)
+}
+
+// pushUsingLocalGit commits and pushes the files, or only prints them with --dry-run.
+func pushUsingLocalGit(ctx context.Context, gitRoot string, paths []string, message string) (int, error) {
+	if dryRun {
+		dryRunf("commit and push %d files with the message %q: %s", len(paths), message, strings.Join(paths, ", "))
+		return len(paths), nil
+	}
+	return gh.PushUsingLocalGit(ctx, gitRoot, paths, message)
+}
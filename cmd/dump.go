@@ -45,13 +45,18 @@ var dumpCmd = &cobra.Command{
 			return err
 		}
 		c.Build()
+		c, err := selectCoverageConfig(c)
+		if err != nil {
+			return err
+		}
 		if reportPath != "" {
 			c.Coverage.Paths = []string{reportPath}
 			c.CodeToTestRatio = nil
 			c.TestExecutionTime = nil
+			c.DocCoverage = nil
 		}
 
-		r, err := report.New(c.Repository)
+		r, err := report.New(c.Repository, report.CaseInsensitive(c.Coverage.CaseInsensitive), report.ParserCommand(c.CoverageParserCommand()), report.CoverageFormat(c.Coverage.Format))
 		if err != nil {
 			return err
 		}
@@ -67,7 +72,7 @@ var dumpCmd = &cobra.Command{
 		if err := c.CodeToTestRatioConfigReady(); err != nil {
 			cmd.PrintErrf("Skip measuring code to test ratio: %v\n", err)
 		} else {
-			if err := r.MeasureCodeToTestRatio(c.Root(), c.CodeToTestRatio.Code, c.CodeToTestRatio.Test); err != nil {
+			if err := r.MeasureCodeToTestRatioByConfig(c); err != nil {
 				cmd.PrintErrf("Skip measuring code to test ratio: %v\n", err)
 			}
 		}
@@ -84,6 +89,14 @@ var dumpCmd = &cobra.Command{
 			}
 		}
 
+		if err := c.DocCoverageConfigReady(); err != nil {
+			cmd.PrintErrf("Skip measuring doc coverage: %v\n", err)
+		} else {
+			if err := r.MeasureDocCoverage(c.Root(), c.DocCoverage.Include, c.DocCoverage.Exclude); err != nil {
+				cmd.PrintErrf("Skip measuring doc coverage: %v\n", err)
+			}
+		}
+
 		if err := r.CollectCustomMetrics(); err != nil {
 			cmd.PrintErrf("Skip collecting custom metrics: %v\n", err)
 		}
@@ -103,5 +116,6 @@ var dumpCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(dumpCmd)
 	dumpCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
+	dumpCmd.Flags().StringVarP(&coverageName, "coverage-name", "", "", "name of the coverage config to be used when coverage: is a list of named coverage configs")
 	dumpCmd.Flags().StringVarP(&reportPath, "report", "r", "", "coverage report file path")
 }
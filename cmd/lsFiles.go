@@ -49,6 +49,10 @@ var lsFilesCmd = &cobra.Command{
 			return err
 		}
 		c.Build()
+		c, err := selectCoverageConfig(c)
+		if err != nil {
+			return err
+		}
 		if reportPath != "" {
 			c.Coverage.Paths = []string{reportPath}
 			c.CodeToTestRatio = nil
@@ -57,7 +61,7 @@ var lsFilesCmd = &cobra.Command{
 		if c.Coverage == nil {
 			return errors.New("coverage: is not set")
 		}
-		r, err := report.New(c.Repository)
+		r, err := report.New(c.Repository, report.CaseInsensitive(c.Coverage.CaseInsensitive), report.ParserCommand(c.CoverageParserCommand()), report.CoverageFormat(c.Coverage.Format))
 		if err != nil {
 			return err
 		}
@@ -169,5 +173,6 @@ func detectTermColor(cl string) (*color.Color, error) {
 func init() {
 	rootCmd.AddCommand(lsFilesCmd)
 	lsFilesCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
+	lsFilesCmd.Flags().StringVarP(&coverageName, "coverage-name", "", "", "name of the coverage config to be used when coverage: is a list of named coverage configs")
 	lsFilesCmd.Flags().StringVarP(&reportPath, "report", "r", "", "coverage report file path")
 }
@@ -0,0 +1,208 @@
+/*
+Copyright © 2021 Ken'ichiro Oyama <k1lowxb@gmail.com>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/datastore"
+	"github.com/k1LoW/octocov/datastore/artifact"
+	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/report"
+	"github.com/k1LoW/octocov/version"
+	"github.com/spf13/cobra"
+)
+
+var measureRef string
+
+// measureCmd represents the measure command.
+var measureCmd = &cobra.Command{
+	Use:   "measure",
+	Short: "measure code metrics of the specified ref from the coverage artifact stored in datastores",
+	Long: `measure code metrics of the specified ref from the coverage artifact stored in datastores, without a checkout.
+The code coverage is computed from the file coverages of the artifact with the coverage settings of the config (e.g. coverage.exclude:).`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if measureRef == "" {
+			return errors.New("--ref is required")
+		}
+		cmd.PrintErrf("%s version %s\n", version.Name, version.Version)
+		c := config.New()
+		if err := c.Load(configPath); err != nil {
+			return err
+		}
+		c.Build()
+		ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+		defer cancel()
+
+		datastores := c.ReportDatastoresToRead()
+		if c.Diff != nil {
+			datastores = append(datastores, c.Diff.Datastores...)
+		}
+		if len(datastores) == 0 {
+			return errors.New("report.datastores: and diff.datastores: are not set")
+		}
+		stored, err := fetchReportOfRef(ctx, c, datastores, measureRef)
+		if err != nil {
+			return err
+		}
+		r, err := measureReportOfRef(c, stored)
+		if err != nil {
+			return err
+		}
+
+		cmd.Println("")
+		if err := r.Out(os.Stdout); err != nil {
+			return err
+		}
+		cmd.Println("")
+
+		return checkAcceptable(cmd, c.Acceptable(ctx, r, (*report.Report)(nil)))
+	},
+}
+
+// measureReportOfRef measures the code metrics of the ref from the stored report fetched from datastores.
+// The code coverage is computed again from the file coverages with coverage.exclude:, and the metrics that need a checkout or a workflow run (code to test ratio, test execution time, doc coverage and custom metrics) are taken as they are.
+func measureReportOfRef(c *config.Config, stored *report.Report) (*report.Report, error) {
+	r, err := report.New(c.Repository, report.Locale(c.Locale), report.CoverageLabels(c.CoverageLabelPaths()), report.CaseInsensitive(c.Coverage.CaseInsensitive), report.FilesTableMax(c.CoverageReportFiles()))
+	if err != nil {
+		return nil, err
+	}
+	r.Ref = stored.Ref
+	r.Commit = stored.Commit
+	r.Timestamp = stored.Timestamp
+	if stored.Coverage != nil {
+		cov := stored.Coverage
+		cov.CaseInsensitive = c.Coverage.CaseInsensitive
+		if err := cov.Exclude(c.Coverage.Exclude); err != nil {
+			return nil, err
+		}
+		r.Coverage = cov
+	}
+	r.CodeToTestRatio = stored.CodeToTestRatio
+	r.TestExecutionTime = stored.TestExecutionTime
+	r.DocCoverage = stored.DocCoverage
+	r.CustomMetrics = stored.CustomMetrics
+	return r, nil
+}
+
+// fetchReportOfRef fetches the report whose commit or ref matches the ref from datastores.
+func fetchReportOfRef(ctx context.Context, c *config.Config, datastores []string, ref string) (*report.Report, error) {
+	repo, err := gh.Parse(c.Repository)
+	if err != nil {
+		return nil, err
+	}
+	repository := fmt.Sprintf("%s/%s", repo.Owner, repo.Reponame())
+	path := fmt.Sprintf("%s/report.json", repository)
+	for _, s := range datastores {
+		log.Printf("Get report of %s from %s", ref, s)
+		d, err := datastore.New(ctx, s, datastore.Root(c.Root()), datastore.GitHubRetries(c.DatastoreGitHubRetries()))
+		if err != nil {
+			return nil, err
+		}
+		if a, ok := d.(*artifact.Artifact); ok {
+			r, err := a.FetchReportOfRef(ctx, ref)
+			if err != nil {
+				log.Printf("%s: %v", s, err)
+				continue
+			}
+			return r, nil
+		}
+		fsys, err := d.FS()
+		if err != nil {
+			return nil, err
+		}
+		// The report stored keyed by the ref (report.storeByRef) takes precedence over the latest report
+		for _, rp := range refReportPaths(repository, ref) {
+			r, err := readReport(fsys, rp)
+			if err != nil {
+				log.Printf("%s: %v", s, err)
+				continue
+			}
+			return r, nil
+		}
+		r, err := readReport(fsys, path)
+		if err != nil {
+			log.Printf("%s: %v", s, err)
+			continue
+		}
+		if !matchRef(r, ref) {
+			log.Printf("%s: the stored report is of %s (%s)", s, r.Ref, r.Commit)
+			continue
+		}
+		return r, nil
+	}
+	return nil, fmt.Errorf("report of %s not found in datastores", ref)
+}
+
+// refReportPaths returns the candidate paths of the report keyed by the ref. A ref without the refs/ prefix may be a branch or a tag.
+func refReportPaths(repository, ref string) []string {
+	if strings.HasPrefix(ref, "refs/") {
+		return []string{datastore.RefReportPath(repository, ref)}
+	}
+	return []string{
+		datastore.RefReportPath(repository, "refs/heads/"+ref),
+		datastore.RefReportPath(repository, "refs/tags/"+ref),
+	}
+}
+
+func readReport(fsys fs.FS, path string) (*report.Report, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	b, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	r := &report.Report{}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func matchRef(r *report.Report, ref string) bool {
+	if ref == "" {
+		return false
+	}
+	if strings.HasPrefix(r.Commit, ref) {
+		return true
+	}
+	return r.Ref == ref || strings.TrimPrefix(r.Ref, "refs/heads/") == ref || strings.TrimPrefix(r.Ref, "refs/tags/") == ref
+}
+
+func init() {
+	rootCmd.AddCommand(measureCmd)
+	measureCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
+	measureCmd.Flags().StringVarP(&measureRef, "ref", "", "", "git ref (commit SHA, branch or tag) to measure")
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/coverage"
+	"github.com/k1LoW/octocov/report"
+)
+
+func TestRefReportPaths(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want []string
+	}{
+		{"refs/tags/v1.0.0", []string{"owner/repo/refs/tags/v1.0.0/report.json"}},
+		{"refs/heads/main", []string{"owner/repo/refs/heads/main/report.json"}},
+		{"main", []string{"owner/repo/refs/heads/main/report.json", "owner/repo/refs/tags/main/report.json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got := refReportPaths("owner/repo", tt.ref)
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestMatchRef(t *testing.T) {
+	tests := []struct {
+		ref    string
+		commit string
+		in     string
+		want   bool
+	}{
+		{"refs/heads/main", "5b2f1a9c0d", "5b2f1a9", true},
+		{"refs/heads/main", "5b2f1a9c0d", "main", true},
+		{"refs/heads/main", "5b2f1a9c0d", "refs/heads/main", true},
+		{"refs/tags/v1.0.0", "5b2f1a9c0d", "v1.0.0", true},
+		{"refs/heads/main", "5b2f1a9c0d", "develop", false},
+		{"refs/heads/main", "5b2f1a9c0d", "c0d", false},
+		{"refs/heads/main", "5b2f1a9c0d", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			r := &report.Report{Ref: tt.ref, Commit: tt.commit}
+			if got := matchRef(r, tt.in); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchReportOfRef(t *testing.T) {
+	dir := t.TempDir()
+	writeReport(t, filepath.Join(dir, "owner/repo/report.json"), &report.Report{Repository: "owner/repo", Ref: "refs/heads/main", Commit: "5b2f1a9c0d"})
+	writeReport(t, filepath.Join(dir, "owner/repo/refs/tags/v1.0.0/report.json"), &report.Report{Repository: "owner/repo", Ref: "refs/tags/v1.0.0", Commit: "e8a3c7b41f"})
+
+	tests := []struct {
+		ref        string
+		wantCommit string
+		wantErr    bool
+	}{
+		{"v1.0.0", "e8a3c7b41f", false},
+		{"refs/tags/v1.0.0", "e8a3c7b41f", false},
+		{"main", "5b2f1a9c0d", false},
+		{"5b2f1a9", "5b2f1a9c0d", false},
+		{"develop", "", true},
+	}
+	c := config.New()
+	c.Repository = "owner/repo"
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := fetchReportOfRef(context.Background(), c, []string{"local://" + dir}, tt.ref)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got error %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Error("want error")
+			}
+			if got.Commit != tt.wantCommit {
+				t.Errorf("got %v\nwant %v", got.Commit, tt.wantCommit)
+			}
+		})
+	}
+}
+
+func TestMeasureReportOfRef(t *testing.T) {
+	c := config.New()
+	c.Repository = "owner/repo"
+	c.Build()
+	c.Coverage.Exclude = []string{"gen/**"}
+	stored := &report.Report{
+		Repository: "owner/repo",
+		Ref:        "refs/heads/main",
+		Commit:     "5b2f1a9c0d",
+		Coverage: &coverage.Coverage{
+			Type:    coverage.TypeLOC,
+			Total:   30,
+			Covered: 15,
+			// Shrunk report without the block coverages
+			Files: coverage.FileCoverages{
+				{Type: coverage.TypeLOC, File: "main.go", Total: 10, Covered: 8},
+				{Type: coverage.TypeLOC, File: "gen/gen.go", Total: 20, Covered: 7},
+			},
+		},
+	}
+	r, err := measureReportOfRef(c, stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Commit != stored.Commit {
+		t.Errorf("got %v\nwant %v", r.Commit, stored.Commit)
+	}
+	if r.CoverageTotal() != 10 {
+		t.Errorf("got %v\nwant %v", r.CoverageTotal(), 10)
+	}
+	if r.CoveragePercent() != 80 {
+		t.Errorf("got %v\nwant %v", r.CoveragePercent(), 80)
+	}
+}
+
+func writeReport(t *testing.T, path string, r *report.Report) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, r.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -56,7 +56,7 @@ var migrateBqTableCmd = &cobra.Command{
 			return err
 		}
 		datastores := map[string]datastore.Datastore{}
-		for _, u := range c.Report.Datastores {
+		for _, u := range c.ReportDatastoresToRead() {
 			if !strings.HasPrefix(u, "bq://") {
 				continue
 			}
@@ -27,29 +27,42 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/k1LoW/octocov/badge"
 	"github.com/k1LoW/octocov/central"
 	"github.com/k1LoW/octocov/config"
 	"github.com/k1LoW/octocov/datastore"
+	"github.com/k1LoW/octocov/datastore/artifact"
+	httpd "github.com/k1LoW/octocov/datastore/http"
+	"github.com/k1LoW/octocov/datastore/statsd"
 	"github.com/k1LoW/octocov/gh"
 	"github.com/k1LoW/octocov/internal"
 	"github.com/k1LoW/octocov/report"
+	"github.com/k1LoW/octocov/slack"
 	"github.com/k1LoW/octocov/version"
 	"github.com/spf13/cobra"
 )
 
 const defaultCommitMessage = "Update by octocov"
+const commitStatusContext = "octocov/coverage"
 
 var (
-	configPath  string
-	reportPath  string
-	createTable bool
+	configPath         string
+	reportPath         string
+	coverageAcceptable string
+	coverageName       string
+	createTable        bool
+	validateConfig     bool
+	dryRun             bool
 )
 
 var rootCmd = &cobra.Command{
@@ -60,6 +73,10 @@ var rootCmd = &cobra.Command{
 	Args:         cobra.NoArgs,
 	SilenceUsage: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if validateConfig {
+			return validate(cmd)
+		}
+
 		if os.Getenv("CI") == "" {
 			return printMetrics(cmd)
 		}
@@ -71,12 +88,19 @@ var rootCmd = &cobra.Command{
 		if err := c.Load(configPath); err != nil {
 			return err
 		}
+		c.OverrideCoverageAcceptable(coverageAcceptable)
+		c.SetDryRun(dryRun)
 		c.Build()
 
 		if !c.Loaded() {
 			cmd.PrintErrf("%s are not found\n", strings.Join(config.DefaultPaths, " and "))
 		}
 
+		if err := c.RootConfigReady(); err != nil {
+			cmd.PrintErrf("Skip octocov: %v\n", err)
+			return nil
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
 		defer cancel()
 
@@ -84,6 +108,7 @@ var rootCmd = &cobra.Command{
 			c.Coverage.Paths = []string{reportPath}
 			c.CodeToTestRatio = nil
 			c.TestExecutionTime = nil
+			c.DocCoverage = nil
 		}
 
 		if c.Central != nil {
@@ -94,7 +119,7 @@ var rootCmd = &cobra.Command{
 
 			var badges []datastore.Datastore
 			for _, s := range c.Central.Badges.Datastores {
-				d, err := datastore.New(ctx, s, datastore.Root(c.Root()))
+				d, err := datastore.New(ctx, s, datastore.Root(c.Root()), datastore.GitHubRetries(c.DatastoreGitHubRetries()))
 				if err != nil {
 					return err
 				}
@@ -103,27 +128,62 @@ var rootCmd = &cobra.Command{
 
 			var reports []datastore.Datastore
 			for _, s := range c.Central.Reports.Datastores {
-				d, err := datastore.New(ctx, s, datastore.Root(c.Root()))
+				d, err := datastore.New(ctx, s, datastore.Root(c.Root()), datastore.GitHubRetries(c.DatastoreGitHubRetries()))
 				if err != nil {
 					return err
 				}
 				reports = append(reports, d)
 			}
 
-			ctr := central.New(&central.Config{
+			var jsonPath string
+			if c.Central.JSON != nil {
+				jsonPath = c.Central.JSON.Path
+			}
+			acceptable := func(r *report.Report) error {
+				return config.AcceptableFailures(c.Acceptable(ctx, r, (*report.Report)(nil)))
+			}
+
+			cc := &central.Config{
 				Repository:             c.Repository,
 				Index:                  c.Central.Root,
 				Wd:                     c.Wd(),
 				Badges:                 badges,
 				Reports:                reports,
-				CoverageColor:          c.CoverageColor,
+				JSON:                   jsonPath,
+				Acceptable:             acceptable,
+				CoverageColor:          c.CoverageBadgeColor,
 				CodeToTestRatioColor:   c.CodeToTestRatioColor,
 				TestExecutionTimeColor: c.TestExecutionTimeColor,
-			})
+				DocCoverageColor:       c.DocCoverageColor,
+				SortByCoverage:         c.Central.Sort == config.CentralSortCoverage,
+				SummaryAverage:         c.CentralSummaryAverage(),
+				Concurrency:            c.CentralConcurrency(),
+			}
+			if c.Central.Cache != nil {
+				cc.Cache = central.NewFileCache(c.Central.Cache.Dir, c.Central.Cache.TTL)
+			}
+			if c.Central.Repositories != nil {
+				cc.RepositoryFilter = c.CentralRepositoryMatch
+			}
+			ctr := central.New(cc)
 
-			paths, err := ctr.Generate(ctx)
-			if err != nil {
-				return err
+			var paths []string
+			if dryRun {
+				rs, err := ctr.CollectReports()
+				if err != nil {
+					return err
+				}
+				dryRunf("generate badges of %d repositories to %s", len(rs), strings.Join(c.Central.Badges.Datastores, ", "))
+				dryRunf("write index to %s", c.Central.Root)
+				if jsonPath != "" {
+					dryRunf("write JSON to %s", jsonPath)
+				}
+			} else {
+				var err error
+				paths, err = ctr.Generate(ctx)
+				if err != nil {
+					return err
+				}
 			}
 
 			// re report
@@ -150,7 +210,7 @@ var rootCmd = &cobra.Command{
 					m = c.Central.Push.Message
 				}
 
-				c, err := gh.PushUsingLocalGit(ctx, c.GitRoot, paths, m)
+				c, err := pushUsingLocalGit(ctx, c.GitRoot, paths, m)
 				if err != nil {
 					return err
 				}
@@ -161,176 +221,109 @@ var rootCmd = &cobra.Command{
 			return nil
 		}
 
-		r, err := report.New(c.Repository, report.Locale(c.Locale))
+		configs, err := c.CoverageConfigs()
 		if err != nil {
 			return err
 		}
-
-		if err := c.CoverageConfigReady(); err != nil {
-			cmd.PrintErrf("Skip measuring code coverage: %v\n", err)
-		} else {
-			if err := r.MeasureCoverage(c.Coverage.Paths, c.Coverage.Exclude); err != nil {
-				cmd.PrintErrf("Skip measuring code coverage: %v\n", err)
-			}
+		if reportPath != "" && len(c.Coverages) > 0 {
+			return errors.New("--report can not be used with the named coverage configs of coverage:")
 		}
-
-		if err := c.CodeToTestRatioConfigReady(); err != nil {
-			cmd.PrintErrf("Skip measuring code to test ratio: %v\n", err)
-		} else {
-			if err := r.MeasureCodeToTestRatio(c.Root(), c.CodeToTestRatio.Code, c.CodeToTestRatio.Test); err != nil {
-				cmd.PrintErrf("Skip measuring code to test ratio: %v\n", err)
+		var (
+			acceptableErr error
+			shared        *report.Report
+		)
+		for _, cc := range configs {
+			if cc.Coverage.Name != "" {
+				cmd.PrintErrf("Measuring code metrics of coverage %s...\n", cc.Coverage.Name)
 			}
-		}
-
-		if err := c.TestExecutionTimeConfigReady(); err != nil {
-			cmd.PrintErrf("Skip measuring test execution time: %v\n", err)
-		} else {
-			var stepNames []string
-			if len(c.TestExecutionTime.Steps) > 0 {
-				stepNames = c.TestExecutionTime.Steps
+			// The code metrics that do not depend on the coverage config are measured once with the first coverage config
+			r, err := report.MeasureSharing(ctx, cc, shared)
+			if err != nil {
+				return err
 			}
-			if err := r.MeasureTestExecutionTime(ctx, stepNames); err != nil {
-				cmd.PrintErrf("Skip measuring test execution time: %v\n", err)
+			if shared == nil {
+				shared = r
 			}
-		}
-
-		if err := r.CollectCustomMetrics(); err != nil {
-			cmd.PrintErrf("Skip collecting custom metrics: %v\n", err)
-		}
-
-		if r.CountMeasured() == 0 {
-			return errors.New("nothing could be measured")
-		}
-
-		if err := r.Validate(); err != nil {
-			return fmt.Errorf("validation error: %w", err)
-		}
-
-		cmd.Println("")
-		if err := r.Out(os.Stdout); err != nil {
-			return err
-		}
-		cmd.Println("")
-
-		// Generate coverage report badge
-		if err := c.CoverageBadgeConfigReady(); err == nil {
-			if err := func() error {
-				if !r.IsMeasuredCoverage() {
-					cmd.PrintErrf("Skip generating badge: %s\n", "coverage is not measured")
-					return nil
-				}
-				cp := r.CoveragePercent()
-				cmd.PrintErrln("Generate coverage report badge...")
-				out, err := badgeFile(c.Coverage.Badge.Path)
-				if err != nil {
-					return err
-				}
-				bp, err := filepath.Abs(filepath.Clean(c.Coverage.Badge.Path))
-				if err != nil {
-					return err
-				}
-				addPaths = append(addPaths, bp)
-
-				b := badge.New("coverage", fmt.Sprintf("%.1f%%", cp))
-				b.MessageColor = c.CoverageColor(cp)
-				if err := b.AddIcon(internal.Icon); err != nil {
-					return err
-				}
-				if err := b.Render(out); err != nil {
-					return err
-				}
-				return nil
-			}(); err != nil {
+			paths, aerr, err := measureAndReport(ctx, cmd, cc, r)
+			if err != nil {
 				return err
 			}
+			addPaths = append(addPaths, paths...)
+			if aerr != nil {
+				acceptableErr = multierror.Append(acceptableErr, withCoverageName(cc, aerr))
+			}
 		}
 
-		// Generate code-to-test-ratio report badge
-		if err := c.CodeToTestRatioBadgeConfigReady(); err == nil {
-			if err := func() error {
-				if !r.IsMeasuredCodeToTestRatio() {
-					cmd.PrintErrf("Skip generating badge: %s\n", "coverage is not measured")
-					return nil
-				}
+		// Push generated files
+		if err := c.PushConfigReady(); err != nil {
+			cmd.PrintErrf("Skip pushing generate files: %v\n", err)
+		} else {
+			cmd.PrintErrln("Pushing generated files...")
 
-				tr := r.CodeToTestRatioRatio()
-				cmd.PrintErrln("Generate code-to-test-ratio report badge...")
-				out, err := badgeFile(c.CodeToTestRatio.Badge.Path)
-				if err != nil {
-					return err
-				}
-				bp, err := filepath.Abs(filepath.Clean(c.CodeToTestRatio.Badge.Path))
-				if err != nil {
-					return err
-				}
-				addPaths = append(addPaths, bp)
+			m := defaultCommitMessage
+			if c.Push.Message != "" {
+				m = c.Push.Message
+			}
 
-				b := badge.New("code to test ratio", fmt.Sprintf("1:%.1f", tr))
-				b.MessageColor = c.CodeToTestRatioColor(tr)
-				if err := b.AddIcon(internal.Icon); err != nil {
-					return err
-				}
-				if err := b.Render(out); err != nil {
-					return err
-				}
-				return nil
-			}(); err != nil {
+			c, err := pushUsingLocalGit(ctx, c.GitRoot, addPaths, m)
+			if err != nil {
 				return err
 			}
+			if c == 0 {
+				cmd.PrintErrln("No files to be commit")
+			}
 		}
 
-		// Generate test-execution-time report badge
-		if err := c.TestExecutionTimeBadgeConfigReady(); err == nil {
-			if err := func() error {
-				if !r.IsMeasuredTestExecutionTime() {
-					cmd.PrintErrf("Skip generating badge: %s\n", "test-execution-time is not measured")
-					return nil
-				}
+		return checkAcceptable(cmd, acceptableErr)
+	},
+}
 
-				cmd.PrintErrln("Generate test-execution-time report badge...")
-				out, err := badgeFile(c.TestExecutionTime.Badge.Path)
-				if err != nil {
-					return err
-				}
-				bp, err := filepath.Abs(filepath.Clean(c.TestExecutionTime.Badge.Path))
-				if err != nil {
-					return err
-				}
-				addPaths = append(addPaths, bp)
+// measureAndReport reports the code metrics of the config measured in r.
+// It returns the paths of the generated files to be pushed and the result of checking the acceptable conditions.
+func measureAndReport(ctx context.Context, cmd *cobra.Command, c *config.Config, r *report.Report) ([]string, error, error) {
+	var (
+		addPaths   []string
+		badgePaths []string
+	)
+	c.SetReport(r)
 
-				d := time.Duration(r.TestExecutionTimeNano())
-				b := badge.New("test execution time", d.String())
-				b.MessageColor = c.TestExecutionTimeColor(d)
-				if err := b.AddIcon(internal.Icon); err != nil {
-					return err
-				}
-				if err := b.Render(out); err != nil {
-					return err
-				}
-				return nil
-			}(); err != nil {
-				return err
-			}
-		}
+	cmd.Println("")
+	if err := r.Out(os.Stdout); err != nil {
+		return nil, nil, err
+	}
+	cmd.Println("")
 
-		// Get previous report for comparing reports
-		var rPrev *report.Report
-		if err := c.DiffConfigReady(); err == nil {
-			log.Println("Get previous report for comparing reports")
+	// Get previous report for comparing reports
+	var rPrev *report.Report
+	if err := c.DiffConfigReady(); err == nil {
+		log.Println("Get previous report for comparing reports")
+		baseline, err := c.CoverageBaselineRef()
+		if err != nil {
+			return nil, nil, err
+		}
+		if baseline != "" {
+			// Compare against the report of the tag or ref set by coverage.baseline instead of the base branch
+			log.Printf("Get previous report of the baseline (%s)", c.Coverage.Baseline)
+			rt, err := fetchReportOfRef(ctx, c, c.Diff.Datastores, baseline)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get the baseline report (coverage.baseline: %s): %w", c.Coverage.Baseline, err)
+			}
+			rPrev = rt
+		} else {
 			repo, err := gh.Parse(c.Repository)
 			if err != nil {
-				return err
+				return nil, nil, err
 			}
 			path := fmt.Sprintf("%s/%s/report.json", repo.Owner, repo.Reponame())
 			for _, s := range c.Diff.Datastores {
 				log.Printf("Get previous report from %s", s)
-				d, err := datastore.New(ctx, s, datastore.Root(c.Root()), datastore.Report(r))
+				d, err := datastore.New(ctx, s, datastore.Root(c.Root()), datastore.GitHubRetries(c.DatastoreGitHubRetries()), datastore.Report(r))
 				if err != nil {
-					return err
+					return nil, nil, err
 				}
 				fsys, err := d.FS()
 				if err != nil {
-					return err
+					return nil, nil, err
 				}
 				f, err := fsys.Open(path)
 				if err != nil {
@@ -354,9 +347,10 @@ var rootCmd = &cobra.Command{
 				}
 			}
 			if c.Diff.Path != "" {
-				rt, err := report.New(c.Repository, report.Locale(c.Locale))
+				// The coverage of diff.path is measured without coverage.cacheDir, since the previous report is not parsed again
+				rt, err := report.New(c.Repository, report.Locale(c.Locale), report.CoverageLabels(c.CoverageLabelPaths()), report.CaseInsensitive(c.Coverage.CaseInsensitive), report.ParserCommand(c.CoverageParserCommand()), report.CoverageFormat(c.Coverage.Format), report.CoveragePattern(c.Coverage.Pattern), report.FilesTableMax(c.CoverageReportFiles()))
 				if err != nil {
-					return err
+					return nil, nil, err
 				}
 				if err := rt.MeasureCoverage([]string{c.Diff.Path}, c.Coverage.Exclude); err == nil {
 					if rPrev == nil || rPrev.Timestamp.UnixNano() < rt.Timestamp.UnixNano() {
@@ -364,208 +358,1068 @@ var rootCmd = &cobra.Command{
 					}
 				}
 			}
-		}
-
-		// Comment report to pull request
-		if err := c.CommentConfigReady(); err != nil {
-			cmd.PrintErrf("Skip commenting report to pull request: %v\n", err)
-		} else {
-			if err := func() error {
-				cmd.PrintErrln("Commenting report...")
-				if rPrev == nil {
-					cmd.PrintErrln("Skip comparing reports: previous report not found")
-				}
-				if err := c.DiffConfigReady(); err != nil {
-					cmd.PrintErrf("Skip comparing reports: %v\n", err)
+			if branch := detectPushedBranch(); branch != "" && len(c.Diff.Datastores) > 0 {
+				// On push events (e.g. to main), there is no pull request to compare against, so compare against the last stored report of the pushed branch
+				if rPrev == nil || !matchRef(rPrev, branch) {
+					log.Printf("Get previous report of the pushed branch (%s)", branch)
+					rt, err := fetchReportOfRef(ctx, c, c.Diff.Datastores, branch)
+					switch {
+					case err == nil:
+						rPrev = rt
+					case c.Diff.Path == "":
+						// The first push of the branch is evaluated without the previous report
+						cmd.PrintErrf("Skip comparing against the previous report of the branch %s: %v\n", branch, err)
+						rPrev = nil
+					default:
+						cmd.PrintErrf("Skip comparing against the previous report of the branch %s (compare against diff.path): %v\n", branch, err)
+					}
 				}
-				content, err := createReportContent(ctx, c, r, rPrev, c.Comment.HideFooterLink)
-				if err != nil {
-					return err
+			} else {
+				if c.Diff.CompareAgainst != config.DiffCompareAgainstMergeBase && len(c.Diff.Datastores) > 0 {
+					// Compare against the latest report of the base branch of the pull request (e.g. develop or release branches)
+					if base := detectBaseBranch(); base != "" && (rPrev == nil || !matchRef(rPrev, base)) {
+						log.Printf("Get previous report of the base branch (%s)", base)
+						rt, err := fetchReportOfRef(ctx, c, c.Diff.Datastores, base)
+						if err != nil {
+							cmd.PrintErrf("Skip comparing against the base branch %s (compare against the latest report): %v\n", base, err)
+						} else {
+							rPrev = rt
+						}
+					}
 				}
-				if err := commentReport(ctx, c, content, r.Key()); err != nil {
-					return err
+				if c.Diff.CompareAgainst == config.DiffCompareAgainstMergeBase {
+					if err := func() error {
+						sha, err := detectMergeBase(ctx, c)
+						if err != nil {
+							return err
+						}
+						log.Printf("Get previous report of the merge-base (%s)", sha)
+						rt, err := fetchReportOfRef(ctx, c, c.Diff.Datastores, sha)
+						if err != nil {
+							return err
+						}
+						rPrev = rt
+						return nil
+					}(); err != nil {
+						cmd.PrintErrf("Skip comparing against the merge-base (compare against the branch tip): %v\n", err)
+					}
 				}
-				return nil
-			}(); err != nil {
-				cmd.PrintErrf("Skip commenting report to pull request: %v\n", err)
 			}
 		}
+		if c.IsCoverageRatchetEnabled() {
+			// The high-water mark is taken over before the baseline is dropped by diff.baselineMaxAge
+			r.UpdateCoverageHighWaterMark(rPrev)
+		}
+		if rPrev != nil && c.IsBaselineTooOld(rPrev.Timestamp) {
+			cmd.PrintErrf("Skip comparing reports: previous report (%s) is older than diff.baselineMaxAge (%s)\n", rPrev.Timestamp.Format(time.RFC3339), c.Diff.BaselineMaxAge)
+			rPrev = nil
+		}
+		if c.Coverage != nil && c.Coverage.Acceptable.Window > 0 {
+			log.Printf("Get recent reports for coverage.acceptable.window (%d)", c.Coverage.Acceptable.Window)
+			c.SetCoverageHistory(fetchRecentCoverages(ctx, c, c.Diff.Datastores, r, c.Coverage.Acceptable.Window))
+		}
+	}
 
-		// Add report to job summary page
-		if err := c.SummaryConfigReady(); err != nil {
-			cmd.PrintErrf("Skip adding report to job summary page: %v\n", err)
-		} else {
-			if err := func() error {
-				cmd.PrintErrln("Adding report to job summary page...")
-				if rPrev == nil {
-					cmd.PrintErrln("Skip comparing reports: previous report not found")
-				}
-				if err := c.DiffConfigReady(); err != nil {
-					cmd.PrintErrf("Skip comparing reports: %v\n", err)
+	if c.IsCoverageRatchetEnabled() && r.CoverageHighWaterMark == nil {
+		// The first run establishes the high-water mark.
+		r.UpdateCoverageHighWaterMark(nil)
+	}
+
+	manifest := badge.NewManifest()
+
+	// Generate coverage report badge
+	if err := c.CoverageBadgeConfigReady(); err == nil {
+		if err := func() error {
+			if !r.IsMeasuredCoverage() {
+				cmd.PrintErrf("Skip generating badge: %s\n", "coverage is not measured")
+				return nil
+			}
+			cp := r.CoveragePercent()
+			cmd.PrintErrln("Generate coverage report badge...")
+			var delta *float64
+			if rPrev != nil && rPrev.IsMeasuredCoverage() {
+				d := r.Compare(rPrev).Coverage.Diff
+				delta = &d
+			}
+			b, err := coverageBadge(c, cp, delta)
+			if err != nil {
+				return err
+			}
+			for _, p := range c.CoverageBadgePaths() {
+				out, err := badgeFile(p)
+				if err != nil {
+					return err
 				}
-				content, err := createReportContent(ctx, c, r, rPrev, c.Summary.HideFooterLink)
+				bp, err := filepath.Abs(filepath.Clean(p))
 				if err != nil {
 					return err
 				}
-				if err := addReportContentToSummary(content); err != nil {
+				addPaths = append(addPaths, bp)
+				badgePaths = append(badgePaths, bp)
+
+				if err := b.RenderAs(badge.FormatOf(p), out); err != nil {
 					return err
 				}
-				return nil
-			}(); err != nil {
-				cmd.PrintErrf("Skip adding report to job summary page: %v\n", err)
+				manifest.Add("coverage", p, cp, b)
 			}
+			return nil
+		}(); err != nil {
+			return nil, nil, err
 		}
+	}
 
-		// Insert report to body of pull request
-		if err := c.BodyConfigReady(); err != nil {
-			cmd.PrintErrf("Skip inserting report to body of pull request: %v\n", err)
-		} else {
-			if err := func() error {
-				cmd.PrintErrln("Inserting report...")
-				if rPrev == nil {
-					cmd.PrintErrln("Skip comparing reports: previous report not found")
-				}
-				if err := c.DiffConfigReady(); err != nil {
-					cmd.PrintErrf("Skip comparing reports: %v\n", err)
-				}
-				content, err := createReportContent(ctx, c, r, rPrev, c.Body.HideFooterLink)
-				if err != nil {
-					return err
-				}
-				if err := replaceInsertReportToBody(ctx, c, content, r.Key()); err != nil {
+	// Generate coverage heatmap
+	if err := c.CoverageBadgeHeatmapConfigReady(); err == nil {
+		if err := func() error {
+			if !r.IsMeasuredCoverage() {
+				cmd.PrintErrf("Skip generating heatmap: %s\n", "coverage is not measured")
+				return nil
+			}
+			cmd.PrintErrln("Generate coverage heatmap...")
+			days := c.CoverageBadgeHeatmapDays()
+			reports := []*report.Report{r}
+			if c.Diff != nil && len(c.Diff.Datastores) > 0 {
+				log.Printf("Get recent reports for coverage.badge.heatmap (%d days)", days)
+				reports = append(reports, fetchRecentReports(ctx, c, c.Diff.Datastores, r, days)...)
+			}
+			h := badge.NewHeatmap("coverage", days, time.Now())
+			// Set the oldest first so that the latest report of the day wins
+			for i := len(reports) - 1; i >= 0; i-- {
+				cp := reports[i].CoveragePercent()
+				if err := h.Set(reports[i].Timestamp, fmt.Sprintf("%.1f%%", cp), c.CoverageColor(cp)); err != nil {
 					return err
 				}
-				return nil
-			}(); err != nil {
-				cmd.PrintErrf("Skip inserting report to body of pull request: %v\n", err)
 			}
+			hp := c.CoverageBadgeHeatmapPath()
+			out, err := badgeFile(hp)
+			if err != nil {
+				return err
+			}
+			p, err := filepath.Abs(filepath.Clean(hp))
+			if err != nil {
+				return err
+			}
+			addPaths = append(addPaths, p)
+			badgePaths = append(badgePaths, p)
+			if err := h.Render(out); err != nil {
+				return err
+			}
+			cp := r.CoveragePercent()
+			manifest.AddImage("coverage_heatmap", hp, cp, h.Label, fmt.Sprintf("%.1f%%", cp), c.CoverageColor(cp))
+			return nil
+		}(); err != nil {
+			return nil, nil, err
 		}
+	}
 
-		// Store report
-		if err := c.ReportConfigReady(); err != nil {
-			cmd.PrintErrf("Skip storing report: %v\n", err)
-		} else {
-			cmd.PrintErrln("Storing report...")
-			if c.Report.Path != "" {
-				rp, err := filepath.Abs(filepath.Clean(c.Report.Path))
-				if err != nil {
+	// Generate coverage chart
+	if err := c.CoverageChartConfigReady(); err == nil {
+		if err := func() error {
+			if !r.IsMeasuredCoverage() {
+				cmd.PrintErrf("Skip generating chart: %s\n", "coverage is not measured")
+				return nil
+			}
+			cmd.PrintErrln("Generate coverage chart...")
+			window := c.CoverageChartWindow()
+			reports := []*report.Report{r}
+			if c.Diff != nil && len(c.Diff.Datastores) > 0 && window > 1 {
+				log.Printf("Get recent reports for coverage.chart (%d)", window-1)
+				reports = append(reports, fetchRecentReports(ctx, c, c.Diff.Datastores, r, window-1)...)
+			}
+			ch := badge.NewChart("coverage")
+			for _, rt := range reports {
+				cp := rt.CoveragePercent()
+				if err := ch.Add(rt.Timestamp, cp, c.CoverageColor(cp)); err != nil {
 					return err
 				}
-				if err := os.WriteFile(rp, r.Bytes(), os.ModePerm); err != nil {
+			}
+			if goal, err := c.CoverageGoal(); err == nil {
+				if err := ch.SetTarget(goal, c.CoverageColor(goal)); err != nil {
 					return err
 				}
-				addPaths = append(addPaths, rp)
 			}
-			if err := reportToDatastores(ctx, c, c.Report.Datastores, r); err != nil {
+			out, err := badgeFile(c.Coverage.Chart.Path)
+			if err != nil {
+				return err
+			}
+			p, err := filepath.Abs(filepath.Clean(c.Coverage.Chart.Path))
+			if err != nil {
+				return err
+			}
+			addPaths = append(addPaths, p)
+			badgePaths = append(badgePaths, p)
+			if err := ch.Render(out); err != nil {
 				return err
 			}
+			cp := r.CoveragePercent()
+			manifest.AddImage("coverage_chart", c.Coverage.Chart.Path, cp, ch.Label, fmt.Sprintf("%.1f%%", cp), c.CoverageColor(cp))
+			return nil
+		}(); err != nil {
+			return nil, nil, err
 		}
+	}
 
-		// Push generated files
-		if err := c.PushConfigReady(); err != nil {
-			cmd.PrintErrf("Skip pushing generate files: %v\n", err)
-		} else {
-			cmd.PrintErrln("Pushing generated files...")
-
-			m := defaultCommitMessage
-			if c.Push.Message != "" {
-				m = c.Push.Message
+	// Generate code-to-test-ratio report badge
+	if err := c.CodeToTestRatioBadgeConfigReady(); err == nil {
+		if err := func() error {
+			if !r.IsMeasuredCodeToTestRatio() {
+				cmd.PrintErrf("Skip generating badge: %s\n", "coverage is not measured")
+				return nil
 			}
 
-			c, err := gh.PushUsingLocalGit(ctx, c.GitRoot, addPaths, m)
+			tr := r.CodeToTestRatioRatio()
+			cmd.PrintErrln("Generate code-to-test-ratio report badge...")
+			out, err := badgeFile(c.CodeToTestRatio.Badge.Path)
 			if err != nil {
 				return err
 			}
-			if c == 0 {
-				cmd.PrintErrln("No files to be commit")
+			bp, err := filepath.Abs(filepath.Clean(c.CodeToTestRatio.Badge.Path))
+			if err != nil {
+				return err
 			}
-		}
+			addPaths = append(addPaths, bp)
+			badgePaths = append(badgePaths, bp)
 
-		// Check for acceptable code metrics
-		if err := c.Acceptable(r, rPrev); err != nil {
-			return err
+			b, err := codeToTestRatioBadge(c, r)
+			if err != nil {
+				return err
+			}
+			if err := b.RenderAs(badge.FormatOf(c.CodeToTestRatio.Badge.Path), out); err != nil {
+				return err
+			}
+			manifest.Add("code_to_test_ratio", c.CodeToTestRatio.Badge.Path, tr, b)
+			return nil
+		}(); err != nil {
+			return nil, nil, err
 		}
+	}
 
-		return nil
-	},
-}
+	// Generate test-execution-time report badge
+	if err := c.TestExecutionTimeBadgeConfigReady(); err == nil {
+		if err := func() error {
+			if !r.IsMeasuredTestExecutionTime() {
+				cmd.PrintErrf("Skip generating badge: %s\n", "test-execution-time is not measured")
+				return nil
+			}
 
-func printMetrics(cmd *cobra.Command) error {
-	ctx := context.Background()
-	c := config.New()
-	if err := c.Load(configPath); err != nil {
-		return err
-	}
-	c.Build()
-	if reportPath != "" {
-		c.Coverage.Paths = []string{reportPath}
-		c.CodeToTestRatio = nil
-		c.TestExecutionTime = nil
-	}
-	r, err := report.New(c.Repository, report.Locale(c.Locale))
-	if err != nil {
-		return err
-	}
+			cmd.PrintErrln("Generate test-execution-time report badge...")
+			out, err := badgeFile(c.TestExecutionTime.Badge.Path)
+			if err != nil {
+				return err
+			}
+			bp, err := filepath.Abs(filepath.Clean(c.TestExecutionTime.Badge.Path))
+			if err != nil {
+				return err
+			}
+			addPaths = append(addPaths, bp)
+			badgePaths = append(badgePaths, bp)
 
-	if err := c.CoverageConfigReadyOnLocal(); err == nil {
-		if err := r.MeasureCoverage(c.Coverage.Paths, c.Coverage.Exclude); err != nil {
-			cmd.PrintErrf("Skip measuring code coverage: %v\n", err)
+			d := time.Duration(r.TestExecutionTimeNano())
+			b := badge.New("test execution time", d.String())
+			b.MessageColor = c.TestExecutionTimeColor(d)
+			if err := b.SetStyle(c.TestExecutionTime.Badge.Style); err != nil {
+				return err
+			}
+			if err := b.AddIcon(internal.Icon); err != nil {
+				return err
+			}
+			if err := b.Render(out); err != nil {
+				return err
+			}
+			manifest.Add("test_execution_time", c.TestExecutionTime.Badge.Path, r.TestExecutionTimeNano(), b)
+			return nil
+		}(); err != nil {
+			return nil, nil, err
 		}
 	}
 
-	if err := c.CodeToTestRatioConfigReady(); err == nil {
-		if err := r.MeasureCodeToTestRatio(c.Root(), c.CodeToTestRatio.Code, c.CodeToTestRatio.Test); err != nil {
-			cmd.PrintErrf("Skip measuring code to test ratio: %v\n", err)
+	// Generate doc-coverage report badge
+	if err := c.DocCoverageBadgeConfigReady(); err == nil {
+		if err := func() error {
+			if !r.IsMeasuredDocCoverage() {
+				cmd.PrintErrf("Skip generating badge: %s\n", "doc-coverage is not measured")
+				return nil
+			}
+
+			dp := r.DocCoveragePercent()
+			cmd.PrintErrln("Generate doc-coverage report badge...")
+			out, err := badgeFile(c.DocCoverage.Badge.Path)
+			if err != nil {
+				return err
+			}
+			bp, err := filepath.Abs(filepath.Clean(c.DocCoverage.Badge.Path))
+			if err != nil {
+				return err
+			}
+			addPaths = append(addPaths, bp)
+			badgePaths = append(badgePaths, bp)
+
+			b := badge.New("doc coverage", fmt.Sprintf("%.1f%%", dp))
+			b.MessageColor = c.DocCoverageColor(dp)
+			if err := b.SetStyle(c.DocCoverage.Badge.Style); err != nil {
+				return err
+			}
+			if err := b.AddIcon(internal.Icon); err != nil {
+				return err
+			}
+			if err := b.Render(out); err != nil {
+				return err
+			}
+			manifest.Add("doc_coverage", c.DocCoverage.Badge.Path, dp, b)
+			return nil
+		}(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Write badge manifest
+	if c.Badge != nil && c.Badge.Manifest != nil && c.Badge.Manifest.Path != "" {
+		cmd.PrintErrln("Write badge manifest...")
+		mp, err := filepath.Abs(filepath.Clean(c.Badge.Manifest.Path))
+		if err != nil {
+			return nil, nil, err
+		}
+		if dryRun {
+			dryRunf("write %s", mp)
+		} else {
+			if err := os.MkdirAll(filepath.Dir(mp), 0755); err != nil { // #nosec
+				return nil, nil, err
+			}
+			if err := manifest.Write(mp); err != nil {
+				return nil, nil, err
+			}
+		}
+		addPaths = append(addPaths, mp)
+	}
+
+	// Measure patch coverage
+	if err := c.PatchCoverageConfigReady(); err != nil {
+		cmd.PrintErrf("Skip measuring patch coverage: %v\n", err)
+	} else {
+		if err := measurePatchCoverage(ctx, c, r); err != nil {
+			cmd.PrintErrf("Skip measuring patch coverage: %v\n", err)
+		}
+	}
+
+	// Comment report to pull request
+	if err := c.CommentConfigReady(); err != nil {
+		cmd.PrintErrf("Skip commenting report to pull request: %v\n", err)
+	} else {
+		if err := func() error {
+			cmd.PrintErrln("Commenting report...")
+			if rPrev == nil {
+				cmd.PrintErrln("Skip comparing reports: previous report not found")
+			}
+			if err := c.DiffConfigReady(); err != nil {
+				cmd.PrintErrf("Skip comparing reports: %v\n", err)
+			}
+			content, err := createReportContent(ctx, c, r, rPrev, c.Comment.HideFooterLink, c.Comment.Collapse, c.CommentTemplate())
+			if err != nil {
+				return err
+			}
+			if c.Comment.Location == config.CommentLocationDescription {
+				if err := replaceInsertReportToBody(ctx, c, content, c.CommentKey(r.Key())); err != nil {
+					return err
+				}
+				return nil
+			}
+			if err := commentReport(ctx, c, content, c.CommentKey(r.Key())); err != nil {
+				return err
+			}
+			return nil
+		}(); err != nil {
+			cmd.PrintErrf("Skip commenting report to pull request: %v\n", err)
+		}
+	}
+
+	// Post report to GitLab merge request
+	if err := c.ReportGitLabConfigReady(); err != nil {
+		cmd.PrintErrf("Skip posting report to GitLab merge request: %v\n", err)
+	} else {
+		cmd.PrintErrln("Posting report to GitLab merge request...")
+		if err := noteReportToGitLab(ctx, c, r, rPrev, c.CommentKey(r.Key())); err != nil {
+			cmd.PrintErrf("Skip posting report to GitLab merge request: %v\n", err)
+		}
+	}
+
+	// Post report to Azure DevOps pull request
+	if err := c.ReportAzureConfigReady(); err != nil {
+		cmd.PrintErrf("Skip posting report to Azure DevOps pull request: %v\n", err)
+	} else {
+		cmd.PrintErrln("Posting report to Azure DevOps pull request...")
+		if err := commentReportToAzure(ctx, c, r, rPrev, c.CommentKey(r.Key())); err != nil {
+			cmd.PrintErrf("Skip posting report to Azure DevOps pull request: %v\n", err)
+		}
+	}
+
+	// Add report to job summary page
+	if err := c.SummaryConfigReady(); err != nil {
+		cmd.PrintErrf("Skip adding report to job summary page: %v\n", err)
+	} else {
+		if err := func() error {
+			cmd.PrintErrln("Adding report to job summary page...")
+			if rPrev == nil {
+				cmd.PrintErrln("Skip comparing reports: previous report not found")
+			}
+			if err := c.DiffConfigReady(); err != nil {
+				cmd.PrintErrf("Skip comparing reports: %v\n", err)
+			}
+			content, err := createReportContent(ctx, c, r, rPrev, c.Summary.HideFooterLink, false, "")
+			if err != nil {
+				return err
+			}
+			if err := addReportContentToSummary(content); err != nil {
+				return err
+			}
+			return nil
+		}(); err != nil {
+			cmd.PrintErrf("Skip adding report to job summary page: %v\n", err)
+		}
+	}
+
+	// Insert report to body of pull request
+	if err := c.BodyConfigReady(); err != nil {
+		cmd.PrintErrf("Skip inserting report to body of pull request: %v\n", err)
+	} else {
+		if err := func() error {
+			cmd.PrintErrln("Inserting report...")
+			if rPrev == nil {
+				cmd.PrintErrln("Skip comparing reports: previous report not found")
+			}
+			if err := c.DiffConfigReady(); err != nil {
+				cmd.PrintErrf("Skip comparing reports: %v\n", err)
+			}
+			content, err := createReportContent(ctx, c, r, rPrev, c.Body.HideFooterLink, false, "")
+			if err != nil {
+				return err
+			}
+			if err := replaceInsertReportToBody(ctx, c, content, r.Key()); err != nil {
+				return err
+			}
+			return nil
+		}(); err != nil {
+			cmd.PrintErrf("Skip inserting report to body of pull request: %v\n", err)
+		}
+	}
+
+	// Check for acceptable code metrics
+	if c.Coverage != nil && c.Coverage.Acceptable.RelativeTo == config.RelativeToOrgMedian {
+		log.Println("Get reports of the organization for coverage.acceptable.relativeTo")
+		c.SetOrgCoverages(fetchOrgCoverages(ctx, c, c.Coverage.Acceptable.OrgDatastores))
+	}
+	acceptableErr := c.Acceptable(ctx, r, rPrev)
+
+	// Create commit status
+	if err := c.ReportStatusConfigReady(); err != nil {
+		cmd.PrintErrf("Skip creating commit status: %v\n", err)
+	} else {
+		cmd.PrintErrln("Creating commit status...")
+		if err := createCommitStatus(ctx, c, r, acceptableErr); err != nil {
+			cmd.PrintErrf("Failed to create commit status: %v\n", err)
+		}
+	}
+
+	// Post report to Slack
+	if err := c.ReportSlackConfigReady(); err != nil {
+		cmd.PrintErrf("Skip posting report to Slack: %v\n", err)
+	} else if c.Report.Slack.OnFailure && config.AcceptableFailures(acceptableErr) == nil {
+		cmd.PrintErrf("Skip posting report to Slack: %s\n", "code metrics are acceptable (report.slack.onFailure: true)")
+	} else {
+		cmd.PrintErrln("Posting report to Slack...")
+		if err := postReportToSlack(ctx, c, r, rPrev, acceptableErr); err != nil {
+			cmd.PrintErrf("Failed to post report to Slack: %v\n", err)
+		}
+	}
+
+	// Comment report to issue
+	if err := c.ReportIssueConfigReady(); err != nil {
+		cmd.PrintErrf("Skip commenting report to issue: %v\n", err)
+	} else {
+		if err := func() error {
+			cmd.PrintErrln("Commenting report to issue...")
+			hideFooterLink := c.Comment != nil && c.Comment.HideFooterLink
+			collapse := c.Comment != nil && c.Comment.Collapse
+			content, err := createReportContent(ctx, c, r, rPrev, hideFooterLink, collapse, c.CommentTemplate())
+			if err != nil {
+				return err
+			}
+			return commentReportToIssue(ctx, c, content, c.CommentKey(r.Key()))
+		}(); err != nil {
+			cmd.PrintErrf("Failed to comment report to issue: %v\n", err)
+		}
+	}
+
+	sr := r
+	if c.Report != nil && c.Report.MaxFiles > 0 {
+		sr = r.TruncateFiles(c.Report.MaxFiles)
+	}
+
+	// Upload release assets before storing the report, since storing to some datastores shrinks the report in place
+	if err := c.ReportReleaseConfigReady(); err != nil {
+		cmd.PrintErrf("Skip uploading report to release assets: %v\n", err)
+	} else {
+		cmd.PrintErrln("Uploading report to release assets...")
+		if err := uploadReleaseAssets(ctx, c, sr, badgePaths); err != nil {
+			return nil, nil, err
 		}
 	}
 
-	if err := c.TestExecutionTimeConfigReady(); r.Repository != "" && err == nil {
-		var stepNames []string
-		if len(c.TestExecutionTime.Steps) > 0 {
-			stepNames = c.TestExecutionTime.Steps
+	// Store report
+	if err := c.ReportConfigReady(); err != nil {
+		cmd.PrintErrf("Skip storing report: %v\n", err)
+	} else if c.Report.StoreOnPass && config.AcceptableFailures(acceptableErr) != nil {
+		cmd.PrintErrf("Skip storing report: %s\n", "code metrics are not acceptable (report.storeOnPass: true)")
+	} else {
+		cmd.PrintErrln("Storing report...")
+		if c.Report.Path != "" {
+			rp, err := filepath.Abs(filepath.Clean(c.Report.Path))
+			if err != nil {
+				return nil, nil, err
+			}
+			if err := writeFile(rp, sr.Bytes()); err != nil {
+				return nil, nil, err
+			}
+			addPaths = append(addPaths, rp)
+		}
+		if c.Report.Codecov != nil && c.Report.Codecov.Path != "" {
+			if r.IsMeasuredCoverage() {
+				cp, err := filepath.Abs(filepath.Clean(c.Report.Codecov.Path))
+				if err != nil {
+					return nil, nil, err
+				}
+				b, err := r.CodecovJSON()
+				if err != nil {
+					return nil, nil, err
+				}
+				if err := writeFile(cp, b); err != nil {
+					return nil, nil, err
+				}
+				addPaths = append(addPaths, cp)
+			} else {
+				cmd.PrintErrf("Skip storing report in Codecov format: %s\n", "code coverage is not measured")
+			}
+		}
+		if c.Report.Cobertura != nil && c.Report.Cobertura.Path != "" {
+			if r.IsMeasuredCoverage() {
+				cp, err := filepath.Abs(filepath.Clean(c.Report.Cobertura.Path))
+				if err != nil {
+					return nil, nil, err
+				}
+				b, err := r.CoberturaXML()
+				if err != nil {
+					return nil, nil, err
+				}
+				if err := writeFile(cp, b); err != nil {
+					return nil, nil, err
+				}
+				addPaths = append(addPaths, cp)
+			} else {
+				cmd.PrintErrf("Skip storing report in Cobertura format: %s\n", "code coverage is not measured")
+			}
+		}
+		if c.Report.HTML != nil && c.Report.HTML.Dir != "" {
+			if r.IsMeasuredCoverage() {
+				hd, err := filepath.Abs(filepath.Clean(c.Report.HTML.Dir))
+				if err != nil {
+					return nil, nil, err
+				}
+				if dryRun {
+					dryRunf("write HTML report to %s", hd)
+				} else {
+					written, err := r.WriteHTML(hd, c.Root())
+					if err != nil {
+						return nil, nil, err
+					}
+					addPaths = append(addPaths, written...)
+				}
+			} else {
+				cmd.PrintErrf("Skip storing report in HTML: %s\n", "code coverage is not measured")
+			}
+		}
+		if c.Report.HTML != nil && c.Report.HTML.Path != "" {
+			if r.IsMeasuredCoverage() {
+				hp, err := filepath.Abs(filepath.Clean(c.Report.HTML.Path))
+				if err != nil {
+					return nil, nil, err
+				}
+				b, err := r.SummaryHTML(c.CoverageColor)
+				if err != nil {
+					return nil, nil, err
+				}
+				if err := writeFile(hp, b); err != nil {
+					return nil, nil, err
+				}
+				addPaths = append(addPaths, hp)
+			} else {
+				cmd.PrintErrf("Skip storing report summary in HTML: %s\n", "code coverage is not measured")
+			}
+		}
+		if c.Report.Statsd != nil && c.Report.Statsd.Addr != "" {
+			if dryRun {
+				dryRunf("send code metrics to StatsD %s", c.Report.Statsd.Addr)
+			} else if err := func() error {
+				s, err := statsd.New(c.Report.Statsd.Addr)
+				if err != nil {
+					return err
+				}
+				return s.StoreReport(ctx, r)
+			}(); err != nil {
+				cmd.PrintErrf("Failed to send code metrics to StatsD: %v\n", err)
+			}
+		}
+		if c.Report.HTTP != nil && c.Report.HTTP.URL != "" {
+			if dryRun {
+				dryRunf("send report to %s", c.Report.HTTP.URL)
+			} else {
+				h, err := httpd.New(c.Report.HTTP.URL, c.Report.HTTP.Method, c.Report.HTTP.Headers)
+				if err != nil {
+					return nil, nil, err
+				}
+				if err := h.StoreReport(ctx, sr); err != nil {
+					return nil, nil, err
+				}
+			}
+		}
+		if err := reportToDatastores(ctx, c, c.ReportDatastores(), sr); err != nil {
+			return nil, nil, err
 		}
-		if err := r.MeasureTestExecutionTime(ctx, stepNames); err != nil {
+	}
+
+	return addPaths, acceptableErr, nil
+}
+
+// selectCoverageConfig returns the config of the coverage config selected by --coverage-name.
+func selectCoverageConfig(c *config.Config) (*config.Config, error) {
+	cc, err := c.CoverageConfig(coverageName)
+	if err != nil {
+		return nil, fmt.Errorf("--coverage-name: %w", err)
+	}
+	return cc, nil
+}
+
+// withCoverageName prefixes the errors of the result of Acceptable with the name of the coverage config, if it is one of the named coverage configs.
+func withCoverageName(c *config.Config, err error) error {
+	if c.Coverage.Name == "" {
+		return err
+	}
+	var merr *multierror.Error
+	if !errors.As(err, &merr) {
+		return fmt.Errorf("coverage %s: %w", c.Coverage.Name, err)
+	}
+	var result *multierror.Error
+	for _, e := range merr.Errors {
+		result = multierror.Append(result, fmt.Errorf("coverage %s: %w", c.Coverage.Name, e))
+	}
+	return result.ErrorOrNil()
+}
+
+// checkAcceptable returns the result of Acceptable as is if it contains failures. Otherwise, it prints the warnings and returns nil.
+func checkAcceptable(cmd *cobra.Command, err error) error {
+	if err == nil {
+		return nil
+	}
+	if config.AcceptableFailures(err) != nil {
+		return err
+	}
+	var merr *multierror.Error
+	if errors.As(err, &merr) {
+		for _, e := range merr.Errors {
+			cmd.PrintErrf("Warning: %v\n", e)
+		}
+		return nil
+	}
+	cmd.PrintErrf("Warning: %v\n", err)
+	return nil
+}
+
+func validate(cmd *cobra.Command) error {
+	c := config.New()
+	if err := c.Load(configPath); err != nil {
+		return err
+	}
+	if !c.Loaded() {
+		return fmt.Errorf("%s are not found", strings.Join(config.DefaultPaths, " and "))
+	}
+	c.OverrideCoverageAcceptable(coverageAcceptable)
+	c.Build()
+	if err := c.Validate(datastore.Validate); err != nil {
+		return err
+	}
+	cmd.Println("Config is valid")
+	return nil
+}
+
+func printMetrics(cmd *cobra.Command) error {
+	ctx := context.Background()
+	c := config.New()
+	if err := c.Load(configPath); err != nil {
+		return err
+	}
+	c.OverrideCoverageAcceptable(coverageAcceptable)
+	c.Build()
+	if reportPath != "" {
+		c.Coverage.Paths = []string{reportPath}
+		c.CodeToTestRatio = nil
+		c.TestExecutionTime = nil
+		c.DocCoverage = nil
+	}
+	configs, err := c.CoverageConfigs()
+	if err != nil {
+		return err
+	}
+	if reportPath != "" && len(c.Coverages) > 0 {
+		return errors.New("--report can not be used with the named coverage configs of coverage:")
+	}
+	var shared *report.Report
+	for _, cc := range configs {
+		r, err := printMetricsOf(ctx, cmd, cc, shared)
+		if err != nil {
+			return err
+		}
+		if shared == nil {
+			shared = r
+		}
+	}
+	return nil
+}
+
+// printMetricsOf measures the code metrics of the config and prints them.
+// The code metrics that do not depend on the coverage config are taken over from shared if it is not nil.
+func printMetricsOf(ctx context.Context, cmd *cobra.Command, c *config.Config, shared *report.Report) (*report.Report, error) {
+	r, err := report.New(c.Repository, report.Locale(c.Locale), report.CoverageLabels(c.CoverageLabelPaths()), report.CaseInsensitive(c.Coverage.CaseInsensitive), report.CoverageCacheDir(c.Coverage.CacheDir), report.ParserCommand(c.CoverageParserCommand()), report.CoverageFormat(c.Coverage.Format), report.CoveragePattern(c.Coverage.Pattern), report.FilesTableMax(c.CoverageReportFiles()))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.CoverageConfigReadyOnLocal(); err == nil {
+		if err := r.MeasureCoverage(c.Coverage.Paths, c.Coverage.Exclude); err != nil {
+			cmd.PrintErrf("Skip measuring code coverage: %v\n", err)
+		}
+	}
+
+	if shared != nil {
+		r.TakeOverSharedMetrics(shared)
+	}
+
+	if err := c.CodeToTestRatioConfigReady(); err == nil && shared == nil {
+		if err := r.MeasureCodeToTestRatioByConfig(c); err != nil {
+			cmd.PrintErrf("Skip measuring code to test ratio: %v\n", err)
+		}
+	}
+
+	if err := c.TestExecutionTimeConfigReady(); err == nil && shared == nil && (r.Repository != "" || c.TestExecutionTime.GoTestJSON != "") {
+		if err := r.MeasureTestExecutionTimeByConfig(ctx, c); err != nil {
 			cmd.PrintErrf("Skip measuring test execution time: %v\n", err)
 		}
 	}
 
+	if err := c.DocCoverageConfigReady(); err == nil && shared == nil {
+		if err := r.MeasureDocCoverage(c.Root(), c.DocCoverage.Include, c.DocCoverage.Exclude); err != nil {
+			cmd.PrintErrf("Skip measuring doc coverage: %v\n", err)
+		}
+	}
+
 	if err := r.CollectCustomMetrics(); err != nil {
 		cmd.PrintErrf("Skip collecting custom metrics: %v\n", err)
 	}
 
 	if r.CountMeasured() == 0 {
-		return errors.New("nothing could be measured")
+		return nil, errors.New("nothing could be measured")
 	}
 
 	cmd.Println("")
 	if err := r.Out(os.Stdout); err != nil {
-		return err
+		return nil, err
 	}
 	cmd.Println("")
 
-	return nil
+	return r, nil
 }
 
 func init() {
 	rootCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
 	rootCmd.Flags().StringVarP(&reportPath, "report", "r", "", "coverage report file path")
 	rootCmd.Flags().BoolVarP(&createTable, "create-bq-table", "", false, "create table of BigQuery dataset")
+	rootCmd.Flags().BoolVarP(&validateConfig, "validate", "", false, "validate the config and report all errors")
+	rootCmd.Flags().BoolVarP(&dryRun, "dry-run", "", false, "print the files to be written, the reports to be stored and the comments to be posted without performing them")
+	rootCmd.Flags().StringVarP(&coverageAcceptable, "coverage-acceptable", "", "", "override the condition of coverage.acceptable (e.g. \"current >= 60%\")")
 }
 
-func reportToDatastores(ctx context.Context, c *config.Config, datastores []string, r *report.Report) error {
+// detectBaseBranch detects the base branch of the current pull request from the event payload (pull_request.base.ref).
+// It returns an empty string if not in a pull request context.
+func detectBaseBranch() string {
+	e, err := gh.DecodeGitHubEvent()
+	if err != nil {
+		return ""
+	}
+	return e.BaseRef
+}
+
+// detectPushedBranch detects the branch pushed by the current push event. It is empty if the event is not a push to a branch.
+func detectPushedBranch() string {
+	e, err := gh.DecodeGitHubEvent()
+	if err != nil || e.Name != "push" {
+		return ""
+	}
+	ref := os.Getenv("GITHUB_REF")
+	if !strings.HasPrefix(ref, "refs/heads/") {
+		return ""
+	}
+	return strings.TrimPrefix(ref, "refs/heads/")
+}
+
+// detectMergeBase detects the merge-base commit of the current pull request and its base branch.
+func detectMergeBase(ctx context.Context, c *config.Config) (string, error) {
+	repo, err := gh.Parse(c.Repository)
+	if err != nil {
+		return "", err
+	}
+	g, err := gh.New()
+	if err != nil {
+		return "", err
+	}
+	n, err := g.DetectCurrentPullRequestNumber(ctx, repo.Owner, repo.Repo)
+	if err != nil {
+		return "", err
+	}
+	pr, err := g.FetchPullRequest(ctx, repo.Owner, repo.Repo, n)
+	if err != nil {
+		return "", err
+	}
+	return g.FetchMergeBase(ctx, repo.Owner, repo.Repo, pr.BaseRef, pr.HeadSHA)
+}
+
+// measurePatchCoverage measures the code coverage of the lines added by the current pull request.
+func measurePatchCoverage(ctx context.Context, c *config.Config, r *report.Report) error {
+	repo, err := gh.Parse(c.Repository)
+	if err != nil {
+		return err
+	}
+	g, err := gh.New()
+	if err != nil {
+		return err
+	}
+	n, err := g.DetectCurrentPullRequestNumber(ctx, repo.Owner, repo.Repo)
+	if err != nil {
+		return err
+	}
+	diff, err := g.FetchPullRequestDiff(ctx, repo.Owner, repo.Repo, n)
+	if err != nil {
+		return err
+	}
+	return r.MeasurePatchCoverage(strings.NewReader(diff), c.PatchCoverageCountsMissingFiles())
+}
+
+// uploadReleaseAssets uploads the report and the generated badges as assets of the release of the current tag.
+func uploadReleaseAssets(ctx context.Context, c *config.Config, r *report.Report, badgePaths []string) error {
+	repo, err := gh.Parse(c.Repository)
+	if err != nil {
+		return err
+	}
+	g, err := gh.New()
+	if err != nil {
+		return err
+	}
+	tag, err := g.DetectCurrentTag(ctx)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		dryRunf("upload report.json and %d badges to the release assets of %s", len(badgePaths), tag)
+		return nil
+	}
+	if err := g.PutReleaseAsset(ctx, repo.Owner, repo.Repo, tag, "report.json", r.Bytes()); err != nil {
+		return err
+	}
+	for _, p := range badgePaths {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if err := g.PutReleaseAsset(ctx, repo.Owner, repo.Repo, tag, filepath.Base(p), b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createCommitStatus creates the commit status (octocov/coverage) of GITHUB_SHA from the result of the acceptable check.
+func createCommitStatus(ctx context.Context, c *config.Config, r *report.Report, acceptableErr error) error {
+	repo, err := gh.Parse(c.Repository)
+	if err != nil {
+		return err
+	}
+	g, err := gh.New()
+	if err != nil {
+		return err
+	}
+	state := gh.CommitStatusSuccess
+	description := "Code metrics are acceptable"
+	if r.IsMeasuredCoverage() {
+		description = fmt.Sprintf("Code Coverage: %.1f%%", r.CoveragePercent())
+	}
+	if err := config.AcceptableFailures(acceptableErr); err != nil {
+		state = gh.CommitStatusFailure
+		var merr *multierror.Error
+		if errors.As(err, &merr) && len(merr.Errors) > 0 {
+			description = fmt.Sprintf("%s - %v", description, merr.Errors[0])
+		} else {
+			description = fmt.Sprintf("%s - %v", description, err)
+		}
+	}
+	targetURL := workflowRunURL(c)
+	if dryRun {
+		dryRunf("create commit status %s of %s: %s (%s)", commitStatusContext, os.Getenv("GITHUB_SHA"), state, description)
+		return nil
+	}
+	return g.CreateCommitStatus(ctx, repo.Owner, repo.Repo, os.Getenv("GITHUB_SHA"), state, commitStatusContext, description, targetURL)
+}
+
+// postReportToSlack posts the summary of the report and the failures of the acceptable conditions to the Slack incoming webhook.
+func postReportToSlack(ctx context.Context, c *config.Config, r, rPrev *report.Report, acceptableErr error) error {
+	var failures []string
+	if err := config.AcceptableFailures(acceptableErr); err != nil {
+		var merr *multierror.Error
+		if errors.As(err, &merr) {
+			for _, e := range merr.Errors {
+				failures = append(failures, capitalize(e.Error()))
+			}
+		} else {
+			failures = append(failures, capitalize(err.Error()))
+		}
+	}
+	m := slack.NewMessage(r, rPrev, failures, workflowRunURL(c))
+	if dryRun {
+		dryRunf("post message to Slack: %s", m.Text)
+		return nil
+	}
+	s, err := slack.New(c.Report.Slack.WebhookURL)
+	if err != nil {
+		return err
+	}
+	return s.Post(ctx, m)
+}
+
+// workflowRunURL returns the URL of the current workflow run of GitHub Actions, or an empty string if it is not running on GitHub Actions.
+func workflowRunURL(c *config.Config) string {
+	if os.Getenv("GITHUB_SERVER_URL") == "" || os.Getenv("GITHUB_RUN_ID") == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/actions/runs/%s", os.Getenv("GITHUB_SERVER_URL"), c.Repository, os.Getenv("GITHUB_RUN_ID"))
+}
+
+// fetchOrgCoverages returns the code coverage of the latest reports of the other repositories stored in the datastores.
+func fetchOrgCoverages(ctx context.Context, c *config.Config, datastores []string) []float64 {
+	var ds []datastore.Datastore
 	for _, s := range datastores {
-		if datastore.NeedToShrink(s) {
+		d, err := datastore.New(ctx, s, datastore.Root(c.Root()), datastore.GitHubRetries(c.DatastoreGitHubRetries()))
+		if err != nil {
+			log.Printf("%s: %v", s, err)
+			continue
+		}
+		ds = append(ds, d)
+	}
+	if len(ds) == 0 {
+		return nil
+	}
+	reports, err := central.New(&central.Config{Reports: ds}).CollectReports()
+	if err != nil {
+		log.Printf("%v", err)
+		return nil
+	}
+	var coverages []float64
+	for _, rt := range reports {
+		if rt.Repository == c.Repository || !rt.IsMeasuredCoverage() {
 			continue
 		}
-		d, err := datastore.New(ctx, s, datastore.Root(c.Root()), datastore.Report(r))
+		coverages = append(coverages, rt.CoveragePercent())
+	}
+	return coverages
+}
+
+// fetchRecentCoverages returns the code coverage of up to n recent reports stored in the datastores (latest first).
+func fetchRecentCoverages(ctx context.Context, c *config.Config, datastores []string, r *report.Report, n int) []float64 {
+	var coverages []float64
+	for _, rt := range fetchRecentReports(ctx, c, datastores, r, n) {
+		coverages = append(coverages, rt.CoveragePercent())
+	}
+	return coverages
+}
+
+// fetchRecentReports returns up to n recent reports measuring code coverage stored in the datastores (latest first).
+// Datastores other than artifact hold only the latest report.
+func fetchRecentReports(ctx context.Context, c *config.Config, datastores []string, r *report.Report, n int) []*report.Report {
+	repo, err := gh.Parse(c.Repository)
+	if err != nil {
+		log.Printf("%v", err)
+		return nil
+	}
+	path := fmt.Sprintf("%s/%s/report.json", repo.Owner, repo.Reponame())
+	var reports []*report.Report
+	for _, s := range datastores {
+		d, err := datastore.New(ctx, s, datastore.Root(c.Root()), datastore.GitHubRetries(c.DatastoreGitHubRetries()), datastore.Report(r))
 		if err != nil {
-			return err
+			log.Printf("%s: %v", s, err)
+			continue
 		}
-		log.Printf("Storing report to %s", s)
-		if err := d.StoreReport(ctx, r); err != nil {
-			return err
+		if a, ok := d.(*artifact.Artifact); ok {
+			rs, err := a.FetchRecentReports(ctx, n)
+			if err != nil {
+				log.Printf("%s: %v", s, err)
+				continue
+			}
+			reports = append(reports, rs...)
+			continue
+		}
+		fsys, err := d.FS()
+		if err != nil {
+			log.Printf("%s: %v", s, err)
+			continue
+		}
+		b, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			log.Printf("%s: %v", s, err)
+			continue
+		}
+		rt := &report.Report{}
+		if err := json.Unmarshal(b, rt); err != nil {
+			log.Printf("%s: %v", s, err)
+			continue
+		}
+		reports = append(reports, rt)
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].Timestamp.After(reports[j].Timestamp)
+	})
+	var (
+		recent []*report.Report
+		last   time.Time
+	)
+	for _, rt := range reports {
+		if !rt.IsMeasuredCoverage() || rt.Timestamp.Equal(last) {
+			continue
+		}
+		last = rt.Timestamp
+		recent = append(recent, rt)
+		if len(recent) >= n {
+			break
+		}
+	}
+	return recent
+}
+
+func reportToDatastores(ctx context.Context, c *config.Config, datastores []string, r *report.Report) error {
+	var full, shrinked []string
+	for _, s := range datastores {
+		if datastore.NeedToShrink(s) {
+			shrinked = append(shrinked, s)
+		} else {
+			full = append(full, s)
 		}
 	}
+	if err := storeReport(ctx, c, full, r); err != nil {
+		return err
+	}
 	log.Println("Shrink report data")
 	if r.Coverage != nil {
 		r.Coverage.DeleteBlockCoverages()
@@ -573,11 +1427,26 @@ func reportToDatastores(ctx context.Context, c *config.Config, datastores []stri
 	if r.CodeToTestRatio != nil {
 		r.CodeToTestRatio.DeleteFiles()
 	}
-	for _, s := range datastores {
-		if !datastore.NeedToShrink(s) {
-			continue
+	if r.DocCoverage != nil {
+		r.DocCoverage.DeleteFiles()
+	}
+	return storeReport(ctx, c, shrinked, r)
+}
+
+// storeReport stores the report to datastores, running at most report.maxConcurrent store operations at a time.
+func storeReport(ctx context.Context, c *config.Config, datastores []string, r *report.Report) error {
+	rc := c.Report
+	if rc == nil {
+		// central.reReport: stores the reports without report:
+		rc = &config.Report{}
+	}
+	store := func(s string) error {
+		s = strings.ReplaceAll(s, "{sha}", r.Commit)
+		if dryRun {
+			dryRunf("store report to %s", s)
+			return nil
 		}
-		d, err := datastore.New(ctx, s, datastore.Root(c.Root()), datastore.Report(r))
+		d, err := datastore.New(ctx, s, datastore.Root(c.Root()), datastore.GitHubRetries(c.DatastoreGitHubRetries()), datastore.Report(r), datastore.Gzip(rc.Gzip))
 		if err != nil {
 			return err
 		}
@@ -585,11 +1454,56 @@ func reportToDatastores(ctx context.Context, c *config.Config, datastores []stri
 		if err := d.StoreReport(ctx, r); err != nil {
 			return err
 		}
+		if !rc.StoreByRef || !datastore.CanStoreByRef(s) || r.Ref == "" || r.Ref == "HEAD" || strings.HasPrefix(r.Ref, "refs/pull/") {
+			return nil
+		}
+		// Also store the report keyed by the ref, so that coverage.baseline can compare against it
+		path := datastore.RefReportPath(r.Repository, r.Ref)
+		if rc.Gzip {
+			path += internal.GzipExt
+		}
+		log.Printf("Storing report to %s (%s)", s, path)
+		return d.Put(ctx, path, r.Bytes())
 	}
-	return nil
+	if rc.MaxConcurrent <= 1 {
+		for _, s := range datastores {
+			if err := store(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var (
+		result *multierror.Error
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+	)
+	sem := make(chan struct{}, rc.MaxConcurrent)
+	for _, s := range datastores {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(s string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := store(s); err != nil {
+				mu.Lock()
+				result = multierror.Append(result, err)
+				mu.Unlock()
+			}
+		}(s)
+	}
+	wg.Wait()
+	return result.ErrorOrNil()
 }
 
-func badgeFile(path string) (*os.File, error) {
+// badgeFile returns the writer of the badge file. With --dry-run, the badge is discarded.
+func badgeFile(path string) (io.Writer, error) {
+	if dryRun {
+		dryRunf("write %s", path)
+		return io.Discard, nil
+	}
 	err := os.MkdirAll(filepath.Dir(path), 0755) // #nosec
 	if err != nil {
 		return nil, err
@@ -11,6 +11,10 @@ func addReportContentToSummary(content string) error {
 	if _, err := os.Stat(p); err != nil {
 		return err
 	}
+	if dryRun {
+		dryRunf("add report to job summary page %s:\n%s", p, content)
+		return nil
+	}
 	f, err := os.OpenFile(filepath.Clean(p), os.O_RDWR|os.O_CREATE|os.O_APPEND, os.ModePerm)
 	if err != nil {
 		return err
@@ -45,6 +45,10 @@ var viewCmd = &cobra.Command{
 			return err
 		}
 		c.Build()
+		c, err := selectCoverageConfig(c)
+		if err != nil {
+			return err
+		}
 		if reportPath != "" {
 			c.Coverage.Paths = []string{reportPath}
 			c.CodeToTestRatio = nil
@@ -53,7 +57,7 @@ var viewCmd = &cobra.Command{
 		if c.Coverage == nil {
 			return errors.New("coverage: is not set")
 		}
-		r, err := report.New(c.Repository)
+		r, err := report.New(c.Repository, report.CaseInsensitive(c.Coverage.CaseInsensitive), report.ParserCommand(c.CoverageParserCommand()), report.CoverageFormat(c.Coverage.Format))
 		if err != nil {
 			return err
 		}
@@ -95,5 +99,6 @@ var viewCmd = &cobra.Command{
 func init() {
 	rootCmd.AddCommand(viewCmd)
 	viewCmd.Flags().StringVarP(&configPath, "config", "", "", "config file path")
+	viewCmd.Flags().StringVarP(&coverageName, "coverage-name", "", "", "name of the coverage config to be used when coverage: is a list of named coverage configs")
 	viewCmd.Flags().StringVarP(&reportPath, "report", "r", "", "coverage report file path")
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 
@@ -15,28 +16,23 @@ func (c *Config) Build() {
 	}
 
 	// Coverage
-	if c.Coverage == nil {
-		c.Coverage = &Coverage{}
-	}
-	if c.Coverage.Path != "" {
-		_, _ = fmt.Fprintln(os.Stderr, "Deprecated: coverage.path: has been deprecated. please use coverage.paths: instead.") //nostyle:handlerrors
-		c.Coverage.Paths = append(c.Coverage.Paths, c.Coverage.Path)
-	}
-	if len(c.Coverage.Paths) == 0 {
-		c.Coverage.Paths = append(c.Coverage.Paths, filepath.Dir(c.path))
-	} else {
-		var paths []string
-		for _, p := range c.Coverage.Paths {
-			p = filepath.FromSlash(p)
-			paths = append(paths, filepath.Join(filepath.Dir(c.path), p))
-		}
-		c.Coverage.Paths = paths
-	}
+	c.buildCoverage()
 
 	// TestExecutionTime
 	if c.TestExecutionTime == nil {
 		c.TestExecutionTime = &TestExecutionTime{}
 	}
+	if c.TestExecutionTime.GoTestJSON != "" && !filepath.IsAbs(c.TestExecutionTime.GoTestJSON) {
+		c.TestExecutionTime.GoTestJSON = filepath.Clean(filepath.Join(c.Root(), c.TestExecutionTime.GoTestJSON))
+	}
+
+	// Badge
+	if c.Badge != nil && c.Badge.Manifest != nil && c.Badge.Manifest.Path != "" && !filepath.IsAbs(c.Badge.Manifest.Path) {
+		c.Badge.Manifest.Path = filepath.Clean(filepath.Join(c.Root(), c.Badge.Manifest.Path))
+	}
+
+	// Acceptable thresholds from GitHub Actions variables
+	c.overrideAcceptablesByEnv()
 
 	// Report
 
@@ -54,15 +50,114 @@ func (c *Config) Build() {
 		if len(c.Central.Badges.Datastores) == 0 {
 			c.Central.Badges.Datastores = append(c.Central.Badges.Datastores, defaultBadgesDatastore)
 		}
+		if c.Central.JSON != nil && c.Central.JSON.Path != "" && !filepath.IsAbs(c.Central.JSON.Path) {
+			c.Central.JSON.Path = filepath.Clean(filepath.Join(c.Root(), c.Central.JSON.Path))
+		}
+		if c.Central.Cache != nil {
+			if c.Central.Cache.Dir == "" {
+				c.Central.Cache.Dir = defaultCentralCacheDir
+			}
+			if !filepath.IsAbs(c.Central.Cache.Dir) {
+				c.Central.Cache.Dir = filepath.Clean(filepath.Join(c.Root(), c.Central.Cache.Dir))
+			}
+			if c.Central.Cache.TTL == 0 {
+				c.Central.Cache.TTL = defaultCentralCacheTTL
+			}
+		}
 	}
 
 	// Push
 
 	// Comment
+	if c.Comment != nil && c.Comment.Template != "" && !filepath.IsAbs(c.Comment.Template) {
+		c.Comment.Template = filepath.Clean(filepath.Join(c.Root(), c.Comment.Template))
+	}
 
 	// Diff
 
 	// GitRoot
 	gitRoot, _ := internal.RootPath(c.Root()) //nostyle:handlerrors
 	c.GitRoot = gitRoot
+
+	// Repository from the origin remote ( ex. running octocov locally )
+	if c.Repository == "" && gitRoot != "" {
+		repo, err := internal.RepositoryFromGitRemote(gitRoot)
+		if err != nil {
+			log.Printf("failed to detect repository from git remote: %v", err)
+		} else {
+			c.Repository = repo
+		}
+	}
+}
+
+// buildCoverage builds the coverage config: the paths relative to the config file and the overrides of coverage.acceptable.
+func (c *Config) buildCoverage() {
+	if c.Coverage == nil {
+		c.Coverage = &Coverage{}
+	}
+	if c.Coverage.Path != "" {
+		_, _ = fmt.Fprintln(os.Stderr, "Deprecated: coverage.path: has been deprecated. please use coverage.paths: instead.") //nostyle:handlerrors
+		c.Coverage.Paths = append(c.Coverage.Paths, c.Coverage.Path)
+	}
+	if len(c.Coverage.Paths) == 0 {
+		c.Coverage.Paths = append(c.Coverage.Paths, filepath.Dir(c.path))
+	} else {
+		var paths []string
+		for _, p := range c.Coverage.Paths {
+			if p == "-" {
+				// stdin
+				paths = append(paths, p)
+				continue
+			}
+			p = filepath.FromSlash(p)
+			paths = append(paths, filepath.Join(filepath.Dir(c.path), p))
+		}
+		c.Coverage.Paths = paths
+	}
+
+	if c.Coverage.CacheDir != "" && !filepath.IsAbs(c.Coverage.CacheDir) {
+		c.Coverage.CacheDir = filepath.Clean(filepath.Join(c.Root(), c.Coverage.CacheDir))
+	}
+
+	// Acceptable threshold from GitHub Actions variables and the command line flag
+	if v := os.Getenv(envCoverageAcceptable); v != "" {
+		log.Printf("coverage.acceptable: is overridden by env %s", envCoverageAcceptable)
+		c.Coverage.Acceptable.Condition = v
+	}
+	if c.coverageAcceptableOverride != "" {
+		log.Println("coverage.acceptable: is overridden by the command line flag")
+		c.Coverage.Acceptable.Condition = c.coverageAcceptableOverride
+	}
+}
+
+// Environment variables overriding the acceptable thresholds.
+// They are intended to be mapped from GitHub Actions variables (e.g. `OCTOCOV_COVERAGE_ACCEPTABLE: ${{ vars.OCTOCOV_COVERAGE_ACCEPTABLE }}`).
+const (
+	envCoverageAcceptable          = "OCTOCOV_COVERAGE_ACCEPTABLE"
+	envCodeToTestRatioAcceptable   = "OCTOCOV_CODE_TO_TEST_RATIO_ACCEPTABLE"
+	envTestExecutionTimeAcceptable = "OCTOCOV_TEST_EXECUTION_TIME_ACCEPTABLE"
+	envDocCoverageAcceptable       = "OCTOCOV_DOC_COVERAGE_ACCEPTABLE"
+)
+
+// OverrideCoverageAcceptable overrides the condition of coverage.acceptable at Build().
+// It takes precedence over both the config file and env OCTOCOV_COVERAGE_ACCEPTABLE.
+func (c *Config) OverrideCoverageAcceptable(cond string) {
+	c.coverageAcceptableOverride = cond
+}
+
+// overrideAcceptablesByEnv overrides the acceptable thresholds other than coverage.acceptable (see buildCoverage) by the non-empty environment variables.
+// Sections that are not configured (codeToTestRatio: and docCoverage:) are not enabled by the environment variables.
+func (c *Config) overrideAcceptablesByEnv() {
+	if v := os.Getenv(envCodeToTestRatioAcceptable); v != "" && c.CodeToTestRatio != nil {
+		log.Printf("codeToTestRatio.acceptable: is overridden by env %s", envCodeToTestRatioAcceptable)
+		c.CodeToTestRatio.Acceptable = v
+	}
+	if v := os.Getenv(envTestExecutionTimeAcceptable); v != "" {
+		log.Printf("testExecutionTime.acceptable: is overridden by env %s", envTestExecutionTimeAcceptable)
+		c.TestExecutionTime.Acceptable = v
+	}
+	if v := os.Getenv(envDocCoverageAcceptable); v != "" && c.DocCoverage != nil {
+		log.Printf("docCoverage.acceptable: is overridden by env %s", envDocCoverageAcceptable)
+		c.DocCoverage.Acceptable = v
+	}
 }
@@ -1,11 +1,18 @@
 package config
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,14 +22,28 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/k1LoW/duration"
 	"github.com/k1LoW/expand"
+	"github.com/k1LoW/octocov/azure"
 	"github.com/k1LoW/octocov/gh"
 	"golang.org/x/text/language"
 )
 
 const defaultBadgesDatastore = "local://reports"
 const defaultReportsDatastore = "local://reports"
+const defaultCentralCacheDir = ".octocov/cache/central"
+const defaultCentralCacheTTL = time.Hour
+const defaultCentralConcurrency = 5
 const defaultTimeout = "30sec"
+const defaultDatastoreGitHubRetries = 3
 const largeEnoughTime = float64(99 * time.Hour)
+const defaultCriticalAcceptable = "100%"
+const allFilesPattern = "**"
+const defaultCoverageBadgeLabel = "coverage"
+const defaultCodeToTestRatioBadgeLabel = "code to test ratio"
+const defaultHeatmapDays = 90
+const maxHeatmapDays = 365
+const defaultChartWindow = 30
+const maxChartWindow = 365
+const defaultSparklineCount = 10
 
 const (
 	// https://github.com/badges/shields/blob/7d452472defa0e0bd71d6443393e522e8457f856/badge-maker/lib/color.js#L8-L12
@@ -35,11 +56,79 @@ const (
 
 var DefaultPaths = []string{".octocov.yml", "octocov.yml"}
 
+// Orders of the repositories in the index of central mode (central.sort).
+const (
+	CentralSortRepository = "repository"
+	CentralSortCoverage   = "coverage"
+)
+
+// Averages of the code coverage across the repositories in central mode (central.summary.average).
+const (
+	CentralSummaryAverageWeighted = "weighted"
+	CentralSummaryAverageSimple   = "simple"
+)
+
+// Modes of coverage.verifySource.
+const (
+	VerifySourceOff   = "off"
+	VerifySourceWarn  = "warn"
+	VerifySourceError = "error"
+)
+
+// Locations of comment.location.
+const (
+	CommentLocationThread      = "thread"
+	CommentLocationDescription = "description"
+)
+
+// Kinds of coverage.baseline (e.g. tag:v1.2.0).
+const (
+	CoverageBaselineTag = "tag"
+	CoverageBaselineRef = "ref"
+)
+
+// Baselines of diff.compareAgainst.
+const (
+	DiffCompareAgainstBranchTip = "branchTip"
+	DiffCompareAgainstMergeBase = "mergeBase"
+)
+
+// Escape hatches of the delta-based conditions of coverage.acceptable (coverage.acceptable.allowSkipRegression).
+const (
+	SkipRegressionMarker = "[octocov skip-regression]"
+	SkipRegressionLabel  = "octocov:skip-regression"
+)
+
+// Baselines of coverage.acceptable.relativeTo.
+const (
+	RelativeToOrgMedian = "orgMedian"
+)
+
+// Modes of coverage.acceptable.mode.
+const (
+	CoverageAcceptableModeError = "error"
+	CoverageAcceptableModeWarn  = "warn"
+)
+
+// coverageAcceptableRatchet is the shorthand of coverage.acceptable to enable coverage.acceptable.ratchet.
+const coverageAcceptableRatchet = "ratchet"
+
+// Handling of the added lines of the files not found in the coverage report (coverage.patch.missingFiles).
+const (
+	CoveragePatchMissingFilesSkip      = "skip"
+	CoveragePatchMissingFilesUncovered = "uncovered"
+)
+
+// BadgeStyleGoal is the badge style showing progress toward the goal in coverage.acceptable.
+const BadgeStyleGoal = "goal"
+
 type Config struct {
 	Repository        string             `yaml:"repository"`
 	Coverage          *Coverage          `yaml:"coverage"`
 	CodeToTestRatio   *CodeToTestRatio   `yaml:"codeToTestRatio,omitempty"`
 	TestExecutionTime *TestExecutionTime `yaml:"testExecutionTime,omitempty"`
+	DocCoverage       *DocCoverage       `yaml:"docCoverage,omitempty"`
+	Badge             *Badge             `yaml:"badge,omitempty"`
 	Report            *Report            `yaml:"report,omitempty"`
 	Central           *Central           `yaml:"central,omitempty"`
 	Push              *Push              `yaml:"push,omitempty"`
@@ -47,27 +136,156 @@ type Config struct {
 	Summary           *Summary           `yaml:"summary,omitempty"`
 	Body              *Body              `yaml:"body,omitempty"`
 	Diff              *Diff              `yaml:"diff,omitempty"`
+	Policy            []*Policy          `yaml:"policy,omitempty"`
+	Datastore         *Datastore         `yaml:"datastore,omitempty"`
 	Timeout           time.Duration      `yaml:"timeout,omitempty"`
 	Locale            *language.Tag      `yaml:"locale,omitempty"`
+	If                string             `yaml:"if,omitempty"`
 	GitRoot           string             `yaml:"-"`
+	// named coverage configs of coverage: given as a list, measured and reported separately
+	Coverages []*Coverage `yaml:"-"`
 	// working directory
 	wd string
 	// config file path
 	path string
 	gh   *gh.Gh
+	// code coverage of recent reports for coverage.acceptable.window (latest first)
+	coverageHistory []float64
+	// code coverage of the repositories in the organization for coverage.acceptable.relativeTo
+	orgCoverages []float64
+	// condition of coverage.acceptable given by the command line flag (--coverage-acceptable)
+	coverageAcceptableOverride string
+	// --dry-run: do not ask coverage.acceptable.endpoint
+	dryRun bool
+	// measured report for the variables of `if` sections
+	report Reporter
 }
 
 type Coverage struct {
-	Path       string        `yaml:"path,omitempty"`
-	Paths      []string      `yaml:"paths,omitempty"`
-	Exclude    []string      `yaml:"exclude,omitempty"`
-	Badge      CoverageBadge `yaml:"badge,omitempty"`
-	Acceptable string        `yaml:"acceptable,omitempty"`
-	If         string        `yaml:"if,omitempty"`
+	Path            string                    `yaml:"path,omitempty"`
+	Paths           []string                  `yaml:"paths,omitempty"`
+	Format          string                    `yaml:"format,omitempty"`
+	Pattern         string                    `yaml:"pattern,omitempty"`
+	Baseline        string                    `yaml:"baseline,omitempty"`
+	Required        bool                      `yaml:"required,omitempty"`
+	Exclude         []string                  `yaml:"exclude,omitempty"`
+	Badge           CoverageBadge             `yaml:"badge,omitempty"`
+	Chart           *CoverageChart            `yaml:"chart,omitempty"`
+	History         *CoverageHistory          `yaml:"history,omitempty"`
+	Acceptable      CoverageAcceptable        `yaml:"acceptable,omitempty"`
+	Labels          map[string]*CoverageLabel `yaml:"labels,omitempty"`
+	Critical        *CoverageCritical         `yaml:"critical,omitempty"`
+	CaseInsensitive bool                      `yaml:"caseInsensitive,omitempty"`
+	CacheDir        string                    `yaml:"cacheDir,omitempty"`
+	VerifySource    string                    `yaml:"verifySource,omitempty"`
+	Parser          *CoverageParser           `yaml:"parser,omitempty"`
+	Report          *CoverageReport           `yaml:"report,omitempty"`
+	Patch           *CoveragePatch            `yaml:"patch,omitempty"`
+	If              string                    `yaml:"if,omitempty"`
+
+	// AcceptableMinLines is the total lines below which coverage.acceptable is not checked (e.g. tiny utility repositories)
+	AcceptableMinLines int `yaml:"acceptableMinLines,omitempty"`
+	// AcceptableMaxUncoveredFiles is the maximum number of the files with 0% coverage
+	AcceptableMaxUncoveredFiles *int `yaml:"acceptableMaxUncoveredFiles,omitempty"`
+	// AcceptableDiffTolerance is the percentage points of the decrease regarded as no change by coverage.acceptable (e.g. 0.2%)
+	AcceptableDiffTolerance string `yaml:"acceptableDiffTolerance,omitempty"`
+	// Name is the name of the entry of coverage: given as a list (e.g. unit, integration)
+	Name string `yaml:"name,omitempty"`
+}
+
+type CoverageLabel struct {
+	Path       string `yaml:"path"`
+	Acceptable string `yaml:"acceptable,omitempty"`
+}
+
+type CoverageParser struct {
+	Command string `yaml:"command"`
+}
+
+type CoverageReport struct {
+	Files int `yaml:"files,omitempty"`
+}
+
+// CoveragePatch is the config for measuring the code coverage of the lines added by the pull request (patch coverage).
+type CoveragePatch struct {
+	MissingFiles string `yaml:"missingFiles,omitempty"`
+}
+
+type CoverageCritical struct {
+	Paths      []string `yaml:"paths"`
+	Acceptable string   `yaml:"acceptable,omitempty"`
+}
+
+type CoverageAcceptable struct {
+	Condition           string                      `yaml:"condition,omitempty"`
+	Endpoint            string                      `yaml:"endpoint,omitempty"`
+	Timeout             time.Duration               `yaml:"timeout,omitempty"`
+	FailOpen            bool                        `yaml:"failOpen,omitempty"`
+	SkipNetDeletions    bool                        `yaml:"skipNetDeletions,omitempty"`
+	AllowSkipRegression bool                        `yaml:"allowSkipRegression,omitempty"`
+	Window              int                         `yaml:"window,omitempty"`
+	RequireImprovement  *CoverageRequireImprovement `yaml:"requireImprovement,omitempty"`
+	MaxUncoveredFuncs   *int                        `yaml:"maxUncoveredFuncs,omitempty"`
+	MaxViolations       int                         `yaml:"maxViolations,omitempty"`
+	RelativeTo          string                      `yaml:"relativeTo,omitempty"`
+	OrgDatastores       []string                    `yaml:"orgDatastores,omitempty"`
+	Mode                string                      `yaml:"mode,omitempty"`
+	Paths               []*CoverageAcceptablePath   `yaml:"paths,omitempty"`
+	EachFile            string                      `yaml:"eachFile,omitempty"`
+	Ratchet             *CoverageRatchet            `yaml:"ratchet,omitempty"`
+}
+
+// CoverageAcceptablePath is the condition of the code coverage of the files matching Path.
+type CoverageAcceptablePath struct {
+	Path      string `yaml:"path"`
+	Condition string `yaml:"condition"`
+}
+
+type CoverageRequireImprovement struct {
+	Delta string   `yaml:"delta"`
+	Paths []string `yaml:"paths,omitempty"`
+}
+
+// CoverageRatchet is the config of coverage.acceptable.ratchet, requiring the code coverage not to fall below the highest code coverage ever recorded.
+type CoverageRatchet struct {
+	// Tolerance is the percentage points allowed below the high-water mark (e.g. 0.5%)
+	Tolerance string `yaml:"tolerance,omitempty"`
 }
 
 type CoverageBadge struct {
-	Path string `yaml:"path,omitempty"`
+	Path               string                  `yaml:"path,omitempty"`
+	Paths              []string                `yaml:"paths,omitempty"`
+	Style              string                  `yaml:"style,omitempty"`
+	Label              string                  `yaml:"label,omitempty"`
+	ColorFromDisplayed bool                    `yaml:"colorFromDisplayed,omitempty"`
+	ShowTrend          bool                    `yaml:"showTrend,omitempty"`
+	Heatmap            *CoverageBadgeHeatmap   `yaml:"heatmap,omitempty"`
+	Thresholds         CoverageBadgeThresholds `yaml:"thresholds,omitempty"`
+}
+
+// CoverageBadgeThreshold is the color of the code coverage greater than or equal to Min.
+type CoverageBadgeThreshold struct {
+	Min   float64 `yaml:"min"`
+	Color string  `yaml:"color"`
+}
+
+// CoverageBadgeThresholds is the list of thresholds in descending order of Min.
+type CoverageBadgeThresholds []*CoverageBadgeThreshold
+
+type CoverageChart struct {
+	Path   string `yaml:"path"`
+	Window int    `yaml:"window,omitempty"`
+}
+
+type CoverageHistory struct {
+	Sparkline bool `yaml:"sparkline"`
+	Count     int  `yaml:"count,omitempty"`
+}
+
+type CoverageBadgeHeatmap struct {
+	Enable bool   `yaml:"enable"`
+	Path   string `yaml:"path,omitempty"`
+	Days   int    `yaml:"days,omitempty"`
 }
 
 type CodeToTestRatio struct {
@@ -75,31 +293,112 @@ type CodeToTestRatio struct {
 	Test       []string             `yaml:"test"`
 	Badge      CodeToTestRatioBadge `yaml:"badge,omitempty"`
 	Acceptable string               `yaml:"acceptable,omitempty"`
+	MinFiles   int                  `yaml:"minFiles,omitempty"`
+	GroupDepth int                  `yaml:"groupDepth,omitempty"`
 	If         string               `yaml:"if,omitempty"`
+
+	// Groups is the named groups (e.g. languages) of the code and test files, used instead of Code and Test
+	Groups []*CodeToTestRatioGroup `yaml:"groups,omitempty"`
+}
+
+// CodeToTestRatioGroup is the code and test files of a named group. A file matching more than one group belongs to the first declared one.
+type CodeToTestRatioGroup struct {
+	Name string   `yaml:"name"`
+	Code []string `yaml:"code"`
+	Test []string `yaml:"test"`
 }
 
 type CodeToTestRatioBadge struct {
-	Path string `yaml:"path,omitempty"`
+	Path       string                         `yaml:"path,omitempty"`
+	Style      string                         `yaml:"style,omitempty"`
+	Label      string                         `yaml:"label,omitempty"`
+	Thresholds CodeToTestRatioBadgeThresholds `yaml:"thresholds,omitempty"`
+}
+
+// CodeToTestRatioBadgeThreshold is the color of the code to test ratio greater than or equal to Min.
+type CodeToTestRatioBadgeThreshold struct {
+	Min   float64 `yaml:"min"`
+	Color string  `yaml:"color"`
 }
 
+// CodeToTestRatioBadgeThresholds is the list of thresholds in descending order of Min.
+type CodeToTestRatioBadgeThresholds []*CodeToTestRatioBadgeThreshold
+
 type TestExecutionTime struct {
 	Badge      TestExecutionTimeBadge `yaml:"badge,omitempty"`
 	Acceptable string                 `yaml:"acceptable,omitempty"`
 	Steps      []string               `yaml:"steps,omitempty"`
+	GoTestJSON string                 `yaml:"goTestJSON,omitempty"`
 	If         string                 `yaml:"if,omitempty"`
 }
 
 type TestExecutionTimeBadge struct {
-	Path string `yaml:"path,omitempty"`
+	Path       string                           `yaml:"path,omitempty"`
+	Style      string                           `yaml:"style,omitempty"`
+	Thresholds TestExecutionTimeBadgeThresholds `yaml:"thresholds,omitempty"`
+}
+
+// TestExecutionTimeBadgeThreshold is the color of the test execution time less than Max.
+type TestExecutionTimeBadgeThreshold struct {
+	Max   time.Duration `yaml:"max"`
+	Color string        `yaml:"color"`
+}
+
+// TestExecutionTimeBadgeThresholds is the list of thresholds in ascending order of Max.
+type TestExecutionTimeBadgeThresholds []*TestExecutionTimeBadgeThreshold
+
+type DocCoverage struct {
+	Enable     bool             `yaml:"enable"`
+	Include    []string         `yaml:"include,omitempty"`
+	Exclude    []string         `yaml:"exclude,omitempty"`
+	Badge      DocCoverageBadge `yaml:"badge,omitempty"`
+	Acceptable string           `yaml:"acceptable,omitempty"`
+	If         string           `yaml:"if,omitempty"`
+}
+
+type DocCoverageBadge struct {
+	Path  string `yaml:"path,omitempty"`
+	Style string `yaml:"style,omitempty"`
+}
+
+type Badge struct {
+	Manifest *BadgeManifest `yaml:"manifest,omitempty"`
+}
+
+type BadgeManifest struct {
+	Path string `yaml:"path"`
 }
 
 type Central struct {
-	Root     string         `yaml:"root"`
-	Reports  CentralReports `yaml:"reports"`
-	Badges   CentralBadges  `yaml:"badges"`
-	Push     *Push          `yaml:"push,omitempty"`
-	ReReport *Report        `yaml:"reReport,omitempty"`
-	If       string         `yaml:"if,omitempty"`
+	Root         string               `yaml:"root"`
+	Reports      CentralReports       `yaml:"reports"`
+	Badges       CentralBadges        `yaml:"badges"`
+	Push         *Push                `yaml:"push,omitempty"`
+	ReReport     *Report              `yaml:"reReport,omitempty"`
+	JSON         *CentralJSON         `yaml:"json,omitempty"`
+	Sort         string               `yaml:"sort,omitempty"`
+	Cache        *CentralCache        `yaml:"cache,omitempty"`
+	Repositories *CentralRepositories `yaml:"repositories,omitempty"`
+	Summary      *CentralSummary      `yaml:"summary,omitempty"`
+	Concurrency  int                  `yaml:"concurrency,omitempty"`
+	If           string               `yaml:"if,omitempty"`
+}
+
+// CentralSummary is the average code coverage across the repositories in central mode.
+type CentralSummary struct {
+	Average string `yaml:"average,omitempty"`
+}
+
+// CentralRepositories is the glob patterns of the repositories (owner/repo) to aggregate in central mode.
+type CentralRepositories struct {
+	Include []string `yaml:"include,omitempty"`
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// CentralCache is the on-disk cache of the reports read from central.reports.datastores.
+type CentralCache struct {
+	Dir string        `yaml:"dir,omitempty"`
+	TTL time.Duration `yaml:"ttl,omitempty"`
 }
 
 type CentralReports struct {
@@ -110,6 +409,20 @@ type CentralBadges struct {
 	Datastores []string `yaml:"datastores"`
 }
 
+type CentralJSON struct {
+	Path string `yaml:"path"`
+}
+
+// Datastore is the settings common to the datastores of the same type.
+type Datastore struct {
+	GitHub *DatastoreGitHub `yaml:"github,omitempty"`
+}
+
+type DatastoreGitHub struct {
+	// Retries is the number of retries of the GitHub API calls on transient errors
+	Retries *int `yaml:"retries,omitempty"`
+}
+
 type Push struct {
 	If      string `yaml:"if,omitempty"`
 	Message string `yaml:"message,omitempty"`
@@ -118,6 +431,11 @@ type Push struct {
 type Comment struct {
 	HideFooterLink bool   `yaml:"hideFooterLink"`
 	DeletePrevious bool   `yaml:"deletePrevious"`
+	UpdatePrevious bool   `yaml:"updatePrevious,omitempty"`
+	Key            string `yaml:"key,omitempty"`
+	Collapse       bool   `yaml:"collapse,omitempty"`
+	Location       string `yaml:"location,omitempty"`
+	Template       string `yaml:"template,omitempty"`
 	If             string `yaml:"if,omitempty"`
 }
 
@@ -132,9 +450,11 @@ type Body struct {
 }
 
 type Diff struct {
-	Path       string   `yaml:"path,omitempty"`
-	Datastores []string `yaml:"datastores,omitempty"`
-	If         string   `yaml:"if,omitempty"`
+	Path           string        `yaml:"path,omitempty"`
+	Datastores     []string      `yaml:"datastores,omitempty"`
+	BaselineMaxAge time.Duration `yaml:"baselineMaxAge,omitempty"`
+	CompareAgainst string        `yaml:"compareAgainst,omitempty"`
+	If             string        `yaml:"if,omitempty"`
 }
 
 func New() *Config {
@@ -167,21 +487,41 @@ func (c *Config) Load(path string) error {
 	if path == "" {
 		return nil
 	}
-	if filepath.IsAbs(path) {
-		c.path = path
-	} else {
-		c.path = filepath.Join(c.wd, path)
+	p := path
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(c.wd, p)
+	}
+	fi, err := os.Stat(p)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("config file %s does not exist", path)
+		}
+		return err
+	}
+	if fi.IsDir() {
+		return fmt.Errorf("config file %s is a directory", path)
 	}
-	buf, err := os.ReadFile(filepath.Clean(c.path))
+	buf, err := os.ReadFile(filepath.Clean(p))
 	if err != nil {
 		return err
 	}
-	if err := yaml.Unmarshal(expand.ExpandenvYAMLBytes(buf), c); err != nil {
+	c.path = p
+	if err := yaml.Unmarshal(expandenv(buf), c); err != nil {
 		return err
 	}
 	return nil
 }
 
+// escapedPlaceholder is a temporary replacement of `$${` to keep it as a literal `${` through env expansion.
+const escapedPlaceholder = "__OCTOCOV_ESCAPED_PLACEHOLDER__"
+
+// expandenv replaces ${var} in the values of YAML with the environment variables. `$${var}` is kept as a literal `${var}`.
+func expandenv(b []byte) []byte {
+	b = bytes.ReplaceAll(b, []byte("$${"), []byte(escapedPlaceholder))
+	b = expand.ExpandenvYAMLBytes(b)
+	return bytes.ReplaceAll(b, []byte(escapedPlaceholder), []byte("${"))
+}
+
 func (c *Config) Root() string {
 	if c.path != "" {
 		return filepath.Dir(c.path)
@@ -195,38 +535,294 @@ func (c *Config) Loaded() bool {
 
 type Reporter interface {
 	CoveragePercent() float64
+	CoverageTotal() int
 	CodeToTestRatioRatio() float64
+	CodeToTestRatioCodeFiles() int
 	TestExecutionTimeNano() float64
+	IsMeasuredCoverage() bool
+	IsMeasuredUncoveredFuncs() bool
+	UncoveredFuncs() int
+	UncoveredFiles() []string
 	IsMeasuredTestExecutionTime() bool
+	IsMeasuredCodeToTestRatio() bool
+	IsMeasuredDocCoverage() bool
+	DocCoveragePercent() float64
+	CoveragePercentOf(pattern string) float64
+	FileCoveragePercentsOf(patterns []string) map[string]float64
+	RepositoryName() string
+	IsMeasuredPatchCoverage() bool
+	PatchCoveragePercent() float64
+	CoverageHighWaterMarkPercent() (float64, bool)
+	IsFilesTruncated() bool
+}
+
+// noFilesReporter is the Reporter without the file coverages, used as the previous report whose file coverages are truncated.
+type noFilesReporter struct {
+	Reporter
+}
+
+func (r *noFilesReporter) CoveragePercentOf(pattern string) float64 {
+	return 0.0
+}
+
+func (r *noFilesReporter) FileCoveragePercentsOf(patterns []string) map[string]float64 {
+	return map[string]float64{}
 }
 
-func (c *Config) Acceptable(r, rPrev Reporter) error {
+func (c *Config) Acceptable(ctx context.Context, r, rPrev Reporter) error {
 	var result *multierror.Error
-	if err := c.CoverageConfigReady(); err == nil {
-		prev := rPrev.CoveragePercent()
-		if err := coverageAcceptable(r.CoveragePercent(), prev, c.Coverage.Acceptable); err != nil {
+	skipRegression := c.Coverage != nil && c.Coverage.Acceptable.AllowSkipRegression && c.isSkipRegressionRequested()
+	if err := c.CoverageConfigReady(); err == nil && c.Coverage.AcceptableMinLines > 0 && r.CoverageTotal() < c.Coverage.AcceptableMinLines {
+		_, _ = fmt.Fprintf(os.Stderr, "Skip checking coverage.acceptable: the total lines (%d) are less than coverage.acceptableMinLines (%d)\n", r.CoverageTotal(), c.Coverage.AcceptableMinLines) //nostyle:handlerrors
+	} else if err == nil {
+		// skipDelta returns the condition, or empty if it is delta-based and skipping regression is requested.
+		skipDelta := func(section, cond string) string {
+			if skipRegression && deltaCondRe.MatchString(cond) {
+				log.Printf("Skip checking %s: %s is requested (%s)", section, SkipRegressionMarker, cond)
+				return ""
+			}
+			return cond
+		}
+		rPrevFiles := rPrev
+		if rPrev.IsFilesTruncated() {
+			// The file coverages other than the worst covered ones are aggregated, so they are not compared as if there were no previous report.
+			log.Println("Skip comparing the code coverage of files against the previous report: the file coverages of the previous report are truncated by report.maxFiles")
+			rPrevFiles = &noFilesReporter{Reporter: rPrev}
+		}
+		prev := c.coveragePrev(rPrev.CoveragePercent())
+		if t, err := parseTolerance("coverage.acceptableDiffTolerance", c.Coverage.AcceptableDiffTolerance); err != nil {
 			result = multierror.Append(result, err)
+		} else {
+			prev = toleratePrev(r.CoveragePercent(), prev, t)
+		}
+		if c.Coverage.Acceptable.Endpoint != "" {
+			if c.Central != nil {
+				// Asking the endpoint for each collected report would flood it
+				log.Println("Skip checking coverage.acceptable.endpoint: it is not evaluated in central mode")
+			} else {
+				var pPrev *float64
+				if rPrev.IsMeasuredCoverage() || (c.Coverage.Acceptable.Window > 0 && len(c.coverageHistory) > 0) {
+					pPrev = &prev
+				}
+				if err := c.coverageAcceptableByEndpoint(ctx, r, pPrev); err != nil {
+					result = multierror.Append(result, err)
+				}
+			}
+		} else {
+			cond := c.Coverage.Acceptable.Condition
+			if c.Coverage.Acceptable.SkipNetDeletions && deltaCondRe.MatchString(cond) && c.isNetDeletionPullRequest(ctx) {
+				log.Printf("Skip checking coverage.acceptable: the pull request deletes more lines than it adds (%s)", cond)
+				cond = ""
+			}
+			cond = skipDelta("coverage.acceptable", cond)
+			if patchCondRe.MatchString(cond) {
+				if !r.IsMeasuredPatchCoverage() {
+					log.Printf("Skip checking coverage.acceptable: patch coverage is not measured (%s)", cond)
+				} else if err := patchCoverageAcceptable(r.CoveragePercent(), prev, r.PatchCoveragePercent(), cond); err != nil {
+					result = multierror.Append(result, err)
+				}
+			} else if err := coverageAcceptable(r.CoveragePercent(), prev, cond); err != nil {
+				result = multierror.Append(result, err)
+			}
+		}
+		for _, label := range c.CoverageLabelNames() {
+			l := c.Coverage.Labels[label]
+			if err := labelCoverageAcceptable(label, r.CoveragePercentOf(l.Path), rPrevFiles.CoveragePercentOf(l.Path), skipDelta(fmt.Sprintf("coverage.labels.%s.acceptable", label), l.Acceptable)); err != nil {
+				result = multierror.Append(result, err)
+			}
+		}
+		for _, p := range c.Coverage.Acceptable.Paths {
+			// Paths without files in the current report are not checked.
+			if len(r.FileCoveragePercentsOf([]string{p.Path})) == 0 {
+				log.Printf("Skip checking coverage.acceptable.paths: no files match %s", p.Path)
+				continue
+			}
+			if err := pathCoverageAcceptable(p.Path, r.CoveragePercentOf(p.Path), rPrevFiles.CoveragePercentOf(p.Path), skipDelta("coverage.acceptable.paths", p.Condition)); err != nil {
+				result = multierror.Append(result, err)
+			}
+		}
+		if cond := skipDelta("coverage.acceptable.eachFile", c.Coverage.Acceptable.EachFile); cond != "" {
+			if err := eachFileCoverageAcceptable(r.FileCoveragePercentsOf([]string{allFilesPattern}), rPrevFiles.FileCoveragePercentsOf([]string{allFilesPattern}), cond); err != nil {
+				result = multierror.Append(result, err)
+			}
+		}
+		if rc := c.Coverage.Acceptable.Ratchet; rc != nil {
+			hwm, ok := rPrev.CoverageHighWaterMarkPercent()
+			if !ok {
+				// The baseline may have been dropped by diff.baselineMaxAge after its high-water mark was taken over by the report.
+				hwm, ok = r.CoverageHighWaterMarkPercent()
+			}
+			switch {
+			case skipRegression:
+				log.Printf("Skip checking coverage.acceptable.ratchet: %s is requested", SkipRegressionMarker)
+			case !ok:
+				// The first run establishes the high-water mark.
+				log.Println("Skip checking coverage.acceptable.ratchet: no code coverage has been recorded yet")
+			default:
+				if err := ratchetAcceptable(r.CoveragePercent(), hwm, rc.Tolerance); err != nil {
+					result = multierror.Append(result, err)
+				}
+			}
+		}
+		if ri := c.Coverage.Acceptable.RequireImprovement; ri != nil && ri.Delta != "" && skipRegression {
+			log.Printf("Skip checking coverage.acceptable.requireImprovement: %s is requested", SkipRegressionMarker)
+		} else if ri != nil && ri.Delta != "" && rPrev.IsMeasuredCoverage() {
+			if len(ri.Paths) == 0 {
+				if err := improvementAcceptable("", r.CoveragePercent(), rPrev.CoveragePercent(), ri.Delta); err != nil {
+					result = multierror.Append(result, err)
+				}
+			}
+			for _, p := range ri.Paths {
+				// Paths without baseline (or without files in the current report) are not checked.
+				if len(r.FileCoveragePercentsOf([]string{p})) == 0 || len(rPrevFiles.FileCoveragePercentsOf([]string{p})) == 0 {
+					continue
+				}
+				if err := improvementAcceptable(p, r.CoveragePercentOf(p), rPrevFiles.CoveragePercentOf(p), ri.Delta); err != nil {
+					result = multierror.Append(result, err)
+				}
+			}
+		}
+		if maxFuncs := c.Coverage.Acceptable.MaxUncoveredFuncs; maxFuncs != nil {
+			if r.IsMeasuredUncoveredFuncs() {
+				if err := uncoveredFuncsAcceptable(r.UncoveredFuncs(), *maxFuncs); err != nil {
+					result = multierror.Append(result, err)
+				}
+			} else {
+				log.Println("Skip checking coverage.acceptable.maxUncoveredFuncs: function-level coverage is not measured")
+			}
+		}
+		if maxFiles := c.Coverage.AcceptableMaxUncoveredFiles; maxFiles != nil && r.IsMeasuredCoverage() {
+			if err := uncoveredFilesAcceptable(r.UncoveredFiles(), *maxFiles); err != nil {
+				result = multierror.Append(result, err)
+			}
+		}
+		switch c.Coverage.Acceptable.RelativeTo {
+		case "":
+		case RelativeToOrgMedian:
+			if median, ok := c.orgMedianCoverage(); ok {
+				if err := orgMedianAcceptable(r.CoveragePercent(), median); err != nil {
+					result = multierror.Append(result, err)
+				}
+			} else {
+				_, _ = fmt.Fprintln(os.Stderr, "Skip checking coverage.acceptable.relativeTo: the code coverage of the organization is not available") //nostyle:handlerrors
+			}
+		default:
+			result = multierror.Append(result, &invalidConfigError{Err: fmt.Errorf("invalid coverage.acceptable.relativeTo: %s", c.Coverage.Acceptable.RelativeTo)})
+		}
+		// An empty condition of coverage.critical.acceptable means the default condition, so the skipped one is not replaced by it.
+		if c.Coverage.Critical != nil && len(c.Coverage.Critical.Paths) > 0 && (c.Coverage.Critical.Acceptable == "" || skipDelta("coverage.critical.acceptable", c.Coverage.Critical.Acceptable) != "") {
+			err := criticalCoverageAcceptable(r.FileCoveragePercentsOf(c.Coverage.Critical.Paths), rPrevFiles.FileCoveragePercentsOf(c.Coverage.Critical.Paths), c.Coverage.Critical.Acceptable)
+			if tolerated, ok := toleratedViolations(err, c.Coverage.Acceptable.MaxViolations); ok {
+				// Violations within the allowance do not fail, but all of them are still listed.
+				for _, e := range tolerated {
+					_, _ = fmt.Fprintf(os.Stderr, "Tolerated (%d/%d violations allowed by coverage.acceptable.maxViolations): %v\n", len(tolerated), c.Coverage.Acceptable.MaxViolations, e) //nostyle:handlerrors
+				}
+			} else {
+				result = multierror.Append(result, err)
+			}
+		}
+		if c.Coverage.Acceptable.Mode == CoverageAcceptableModeWarn && result != nil {
+			// Only the coverage checks have been done so far, so all unacceptable results are downgraded to warnings.
+			// The errors of the invalid configuration still fail.
+			for i, err := range result.Errors {
+				if isInvalidConfig(err) {
+					continue
+				}
+				result.Errors[i] = &AcceptableWarning{Err: err}
+			}
 		}
 	}
 
 	if err := c.CodeToTestRatioConfigReady(); err == nil {
-		prev := rPrev.CodeToTestRatioRatio()
-		if err := codeToTestRatioAcceptable(r.CodeToTestRatioRatio(), prev, c.CodeToTestRatio.Acceptable); err != nil {
-			result = multierror.Append(result, err)
+		if c.IsCodeToTestRatioMeaningful(r.CodeToTestRatioCodeFiles()) {
+			prev := rPrev.CodeToTestRatioRatio()
+			if err := codeToTestRatioAcceptable(r.CodeToTestRatioRatio(), prev, c.CodeToTestRatio.Acceptable); err != nil {
+				result = multierror.Append(result, err)
+			}
+		} else {
+			log.Printf("Skip checking codeToTestRatio.acceptable: the number of code files is less than codeToTestRatio.minFiles (%d)", c.CodeToTestRatio.MinFiles)
 		}
 	}
 
 	if err := c.TestExecutionTimeConfigReady(); err == nil {
-		prev := largeEnoughTime
-		if rPrev.IsMeasuredTestExecutionTime() {
-			prev = rPrev.TestExecutionTimeNano()
+		current, prev := metricValues(PolicyMetricTestExecutionTime, r, rPrev)
+		if err := testExecutionTimeAcceptable(current, prev, c.TestExecutionTime.Acceptable); err != nil {
+			result = multierror.Append(result, err)
 		}
+	}
 
-		if err := testExecutionTimeAcceptable(r.TestExecutionTimeNano(), prev, c.TestExecutionTime.Acceptable); err != nil {
+	if err := c.DocCoverageConfigReady(); err == nil {
+		prev := rPrev.DocCoveragePercent()
+		if err := docCoverageAcceptable(r.DocCoveragePercent(), prev, c.DocCoverage.Acceptable); err != nil {
 			result = multierror.Append(result, err)
 		}
 	}
 
+	if err := c.PolicyConfigReady(); err == nil {
+		result = multierror.Append(result, c.policyAcceptable(r, rPrev, skipRegression)...)
+	} else if len(c.Policy) > 0 {
+		result = multierror.Append(result, err)
+	}
+
+	return result.ErrorOrNil()
+}
+
+// AcceptableWarning is an unacceptable result of the code metrics that should be reported but not fail (e.g. coverage.acceptable.mode: warn).
+type AcceptableWarning struct {
+	Err error
+}
+
+func (w *AcceptableWarning) Error() string {
+	return w.Err.Error()
+}
+
+func (w *AcceptableWarning) Unwrap() error {
+	return w.Err
+}
+
+// invalidConfigError is the error of the invalid configuration found while checking the code metrics (e.g. an invalid condition).
+// It is not downgraded to a warning by coverage.acceptable.mode: warn.
+type invalidConfigError struct {
+	Err error
+}
+
+func (e *invalidConfigError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *invalidConfigError) Unwrap() error {
+	return e.Err
+}
+
+func isInvalidConfig(err error) bool {
+	var e *invalidConfigError
+	return errors.As(err, &e)
+}
+
+// IsAcceptableWarning returns true if the error is an AcceptableWarning.
+func IsAcceptableWarning(err error) bool {
+	var w *AcceptableWarning
+	return errors.As(err, &w)
+}
+
+// AcceptableFailures returns the errors of the result of Acceptable excluding the warnings, or nil if there are only warnings.
+func AcceptableFailures(err error) error {
+	if err == nil {
+		return nil
+	}
+	var merr *multierror.Error
+	if !errors.As(err, &merr) {
+		if IsAcceptableWarning(err) {
+			return nil
+		}
+		return err
+	}
+	var result *multierror.Error
+	for _, e := range merr.Errors {
+		if !IsAcceptableWarning(e) {
+			result = multierror.Append(result, e)
+		}
+	}
 	return result.ErrorOrNil()
 }
 
@@ -235,85 +831,346 @@ var (
 	numberOnlyRe  = regexp.MustCompile(`^\s*[\d]+\.?[\d]*\s*$`)
 	compOpRe      = regexp.MustCompile(`^\s*[><=].+$`)
 
+	deltaCondRe = regexp.MustCompile(`\b(prev|diff)\b`)
+	patchCondRe = regexp.MustCompile(`\bpatch\b`)
+
+	goalRe = regexp.MustCompile(`^\s*(?:current\s*)?(?:>=?)?\s*([\d]+\.?[\d]*)\s*$`)
+
 	trimRatioPrefixRe = regexp.MustCompile(`1:([\d.]+)`)
 	durationRe        = regexp.MustCompile(`[\d][\d\.\sa-z]*[a-z]`)
 )
 
-func coverageAcceptable(current, prev float64, cond string) error {
-	if cond == "" {
-		return nil
-	}
-	org := cond
-	// Trim '%'
-	cond = trimPercentRe.ReplaceAllString(cond, "$1")
+// SetDryRun sets whether side effects of checking the code metrics (e.g. asking coverage.acceptable.endpoint) are only printed.
+func (c *Config) SetDryRun(dryRun bool) {
+	c.dryRun = dryRun
+}
 
-	if numberOnlyRe.MatchString(cond) {
-		cond = fmt.Sprintf("current >= %s", cond)
-	} else if compOpRe.MatchString(cond) {
-		cond = fmt.Sprintf("current %s", cond)
+// SetCoverageHistory sets the code coverage of recent reports (latest first) used for coverage.acceptable.window.
+func (c *Config) SetCoverageHistory(percents []float64) {
+	c.coverageHistory = percents
+}
+
+// IsCoverageRatchetEnabled reports whether coverage.acceptable.ratchet is enabled.
+func (c *Config) IsCoverageRatchetEnabled() bool {
+	return c.Coverage != nil && c.Coverage.Acceptable.Ratchet != nil
+}
+
+// PatchCoverageCountsMissingFiles returns true if the added lines of the files not found in the coverage report are counted as uncovered.
+func (c *Config) PatchCoverageCountsMissingFiles() bool {
+	return c.Coverage != nil && c.Coverage.Patch != nil && c.Coverage.Patch.MissingFiles == CoveragePatchMissingFilesUncovered
+}
+
+// coveragePrev returns the previous code coverage to evaluate coverage.acceptable against.
+// When coverage.acceptable.window is set, it is the average of the code coverage of up to N recent reports.
+func (c *Config) coveragePrev(prev float64) float64 {
+	n := c.Coverage.Acceptable.Window
+	if n <= 0 || len(c.coverageHistory) == 0 {
+		return prev
 	}
+	if len(c.coverageHistory) < n {
+		n = len(c.coverageHistory)
+	}
+	var sum float64
+	for _, p := range c.coverageHistory[:n] {
+		sum += p
+	}
+	return sum / float64(n)
+}
 
-	variables := map[string]any{
-		"current": current,
-		"prev":    prev,
-		"diff":    current - prev,
+// SetReport sets the measured report to make its code metrics available in `if` sections.
+func (c *Config) SetReport(r Reporter) {
+	c.report = r
+}
+
+// reportVariables returns the variables of the measured code metrics for `if` sections.
+// Only the measured code metrics are set, so that conditions referencing unmeasured ones fail.
+func (c *Config) reportVariables() map[string]any {
+	variables := map[string]any{}
+	if c.report == nil {
+		return variables
 	}
-	ok, err := expr.Eval(fmt.Sprintf("(%s) == true", cond), variables)
-	if err != nil {
-		return err
+	if c.report.IsMeasuredCoverage() {
+		variables["coverage"] = c.report.CoveragePercent()
 	}
+	if c.report.IsMeasuredCodeToTestRatio() {
+		variables["code_to_test_ratio"] = c.report.CodeToTestRatioRatio()
+	}
+	if c.report.IsMeasuredTestExecutionTime() {
+		variables["test_execution_time"] = c.report.TestExecutionTimeNano()
+	}
+	if c.report.IsMeasuredDocCoverage() {
+		variables["doc_coverage"] = c.report.DocCoveragePercent()
+	}
+	return variables
+}
 
-	if !ok.(bool) {
-		return fmt.Errorf("code coverage is %.1f%%. the condition in the `coverage.acceptable:` section is not met (`%s`)", current, org)
+// SetOrgCoverages sets the code coverage of the repositories in the organization used for coverage.acceptable.relativeTo.
+func (c *Config) SetOrgCoverages(percents []float64) {
+	c.orgCoverages = percents
+}
+
+// orgMedianCoverage returns the median of the code coverage of the repositories in the organization.
+func (c *Config) orgMedianCoverage() (float64, bool) {
+	if len(c.orgCoverages) == 0 {
+		return 0, false
 	}
-	return nil
+	percents := make([]float64, len(c.orgCoverages))
+	copy(percents, c.orgCoverages)
+	sort.Float64s(percents)
+	n := len(percents)
+	if n%2 == 1 {
+		return percents[n/2], true
+	}
+	return (percents[n/2-1] + percents[n/2]) / 2, true
 }
 
-func codeToTestRatioAcceptable(current, prev float64, cond string) error {
-	if cond == "" {
+func orgMedianAcceptable(current, median float64) error {
+	if current >= median {
 		return nil
 	}
-	org := cond
-	// Trim '1:'
-	cond = trimRatioPrefixRe.ReplaceAllString(cond, "$1")
+	return fmt.Errorf("code coverage is %.1f%%. it is below the median of the organization (%.1f%%) (`coverage.acceptable.relativeTo: %s`)", current, median, RelativeToOrgMedian)
+}
 
-	if numberOnlyRe.MatchString(cond) {
-		cond = fmt.Sprintf("current >= %s", cond)
-	} else if compOpRe.MatchString(cond) {
-		cond = fmt.Sprintf("current %s", cond)
-	}
+func coverageAcceptable(current, prev float64, cond string) error {
+	return metricAcceptable(PolicyMetricCoverage, "coverage.acceptable", current, prev, cond)
+}
 
-	variables := map[string]any{
-		"current": current,
-		"prev":    prev,
-		"diff":    current - prev,
+// improvementAcceptable checks that the code coverage is improved by at least delta percentage points from prev (at most 100%).
+func improvementAcceptable(path string, current, prev float64, delta string) error {
+	d, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(delta), "+"), "%"), 64)
+	if err != nil {
+		return &invalidConfigError{Err: fmt.Errorf("invalid coverage.acceptable.requireImprovement: %s", delta)}
 	}
-	ok, err := expr.Eval(fmt.Sprintf("(%s) == true", cond), variables)
+	required := math.Min(prev+d, 100.0)
+	if current >= required {
+		return nil
+	}
+	if path == "" {
+		return fmt.Errorf("code coverage is %.1f%%. it has to be improved by %s from %.1f%% (`coverage.acceptable.requireImprovement:`)", current, delta, prev)
+	}
+	return fmt.Errorf("code coverage of %s is %.1f%%. it has to be improved by %s from %.1f%% (`coverage.acceptable.requireImprovement:`)", path, current, delta, prev)
+}
+
+// ratchetAcceptable checks that the code coverage is not below the high-water mark minus tolerance percentage points.
+func ratchetAcceptable(current, highWaterMark float64, tolerance string) error {
+	t, err := parseTolerance("coverage.acceptable.ratchet.tolerance", tolerance)
 	if err != nil {
 		return err
 	}
-
-	if !ok.(bool) {
-		return fmt.Errorf("code to test ratio is 1:%.1f. the condition in the `codeToTestRatio.acceptable:` section is not met (`%s`)", current, org)
+	if current >= highWaterMark-t {
+		return nil
 	}
-	return nil
+	if t == 0 {
+		return fmt.Errorf("code coverage is %.1f%%. it is below the highest code coverage ever recorded (%.1f%%) (`coverage.acceptable.ratchet:`)", current, highWaterMark)
+	}
+	return fmt.Errorf("code coverage is %.1f%%. it is below the highest code coverage ever recorded (%.1f%%) minus the tolerance (%s) (`coverage.acceptable.ratchet:`)", current, highWaterMark, tolerance)
 }
 
-func testExecutionTimeAcceptable(current, prev float64, cond string) error {
-	if cond == "" {
-		return nil
+// parseTolerance parses the tolerance in percentage points (e.g. 0.5%) of the section.
+func parseTolerance(section, tolerance string) (float64, error) {
+	if strings.TrimSpace(tolerance) == "" {
+		return 0, nil
 	}
-	org := cond
-	matches := durationRe.FindAllString(cond, -1)
-	for _, m := range matches {
-		d, err := duration.Parse(m)
-		if err != nil {
-			return err
-		}
-		cond = strings.Replace(cond, m, strconv.FormatFloat(float64(d), 'f', -1, 64), 1)
+	t, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(tolerance), "%"), 64)
+	if err != nil || t < 0 {
+		return 0, &invalidConfigError{Err: fmt.Errorf("invalid %s: %s", section, tolerance)}
 	}
+	return t, nil
+}
 
-	if numberOnlyRe.MatchString(cond) {
+// toleratePrev returns current as the previous code coverage if the decrease from prev is within tolerance percentage points, so that the jitter of the code coverage is regarded as no change.
+func toleratePrev(current, prev, tolerance float64) float64 {
+	const epsilon = 1e-9
+	if current < prev && prev-current <= tolerance+epsilon {
+		return current
+	}
+	return prev
+}
+
+func uncoveredFilesAcceptable(files []string, maxFiles int) error {
+	if len(files) > maxFiles {
+		return fmt.Errorf("uncovered files are %d. the condition in the `coverage.acceptableMaxUncoveredFiles:` section is not met (`<= %d`): %s", len(files), maxFiles, strings.Join(files, ", "))
+	}
+	return nil
+}
+
+func uncoveredFuncsAcceptable(current, maxFuncs int) error {
+	if current > maxFuncs {
+		return fmt.Errorf("uncovered functions are %d. the condition in the `coverage.acceptable.maxUncoveredFuncs:` section is not met (`<= %d`)", current, maxFuncs)
+	}
+	return nil
+}
+
+func criticalCoverageAcceptable(current, prev map[string]float64, cond string) error {
+	if cond == "" {
+		cond = defaultCriticalAcceptable
+	}
+	var files []string
+	for f := range current {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	var result *multierror.Error
+	for _, f := range files {
+		ok, err := percentAcceptable(current[f], prev[f], cond)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			result = multierror.Append(result, fmt.Errorf("code coverage of critical file %s is %.1f%%. the condition in the `coverage.critical.acceptable:` section is not met (`%s`)", f, current[f], cond))
+		}
+	}
+	return result.ErrorOrNil()
+}
+
+// toleratedViolations returns the violations of the per-file rules and true when their number does not exceed maxViolations.
+// Errors other than violations (e.g. an invalid condition) are never tolerated.
+func toleratedViolations(err error, maxViolations int) ([]error, bool) {
+	if err == nil {
+		return nil, true
+	}
+	var merr *multierror.Error
+	if !errors.As(err, &merr) || len(merr.Errors) > maxViolations {
+		return nil, false
+	}
+	return merr.Errors, true
+}
+
+func labelCoverageAcceptable(label string, current, prev float64, cond string) error {
+	if cond == "" {
+		return nil
+	}
+	ok, err := percentAcceptable(current, prev, cond)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("code coverage of %s is %.1f%%. the condition in the `coverage.labels.%s.acceptable:` section is not met (`%s`)", label, current, label, cond)
+	}
+	return nil
+}
+
+func pathCoverageAcceptable(path string, current, prev float64, cond string) error {
+	if cond == "" {
+		return nil
+	}
+	ok, err := percentAcceptable(current, prev, cond)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("code coverage of %s is %.1f%%. the condition in the `coverage.acceptable.paths:` section is not met (`%s`)", path, current, cond)
+	}
+	return nil
+}
+
+// eachFileCoverageAcceptable checks the condition of coverage.acceptable.eachFile for every file, and returns the errors of all files not meeting it.
+func eachFileCoverageAcceptable(current, prev map[string]float64, cond string) error {
+	var files []string
+	for f := range current {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	var result *multierror.Error
+	for _, f := range files {
+		ok, err := percentAcceptable(current[f], prev[f], cond)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			result = multierror.Append(result, fmt.Errorf("code coverage of %s is %.1f%%. the condition in the `coverage.acceptable.eachFile:` section is not met (`%s`)", f, current[f], cond))
+		}
+	}
+	return result.ErrorOrNil()
+}
+
+func percentAcceptable(current, prev float64, cond string) (bool, error) {
+	return percentCondAcceptable(cond, map[string]any{
+		"current": current,
+		"prev":    prev,
+		"diff":    current - prev,
+	})
+}
+
+// patchCoverageAcceptable checks the condition of coverage.acceptable using the `patch` variable.
+func patchCoverageAcceptable(current, prev, patch float64, cond string) error {
+	ok, err := patchPercentAcceptable(current, prev, patch, cond)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("code coverage is %.1f%% and patch coverage is %.1f%%. the condition in the `coverage.acceptable:` section is not met (`%s`)", current, patch, cond)
+	}
+	return nil
+}
+
+func patchPercentAcceptable(current, prev, patch float64, cond string) (bool, error) {
+	return percentCondAcceptable(cond, map[string]any{
+		"current": current,
+		"prev":    prev,
+		"diff":    current - prev,
+		"patch":   patch,
+	})
+}
+
+func percentCondAcceptable(cond string, variables map[string]any) (bool, error) {
+	// Trim '%'
+	cond = trimPercentRe.ReplaceAllString(cond, "$1")
+
+	if numberOnlyRe.MatchString(cond) {
+		cond = fmt.Sprintf("current >= %s", cond)
+	} else if compOpRe.MatchString(cond) {
+		cond = fmt.Sprintf("current %s", cond)
+	}
+
+	ok, err := expr.Eval(fmt.Sprintf("(%s) == true", cond), variables)
+	if err != nil {
+		return false, &invalidConfigError{Err: err}
+	}
+	return ok.(bool), nil
+}
+
+func codeToTestRatioAcceptable(current, prev float64, cond string) error {
+	return metricAcceptable(PolicyMetricCodeToTestRatio, "codeToTestRatio.acceptable", current, prev, cond)
+}
+
+func ratioAcceptable(current, prev float64, cond string) (bool, error) {
+	// Trim '1:'
+	cond = trimRatioPrefixRe.ReplaceAllString(cond, "$1")
+
+	if numberOnlyRe.MatchString(cond) {
+		cond = fmt.Sprintf("current >= %s", cond)
+	} else if compOpRe.MatchString(cond) {
+		cond = fmt.Sprintf("current %s", cond)
+	}
+
+	variables := map[string]any{
+		"current": current,
+		"prev":    prev,
+		"diff":    current - prev,
+	}
+	ok, err := expr.Eval(fmt.Sprintf("(%s) == true", cond), variables)
+	if err != nil {
+		return false, err
+	}
+	return ok.(bool), nil
+}
+
+func testExecutionTimeAcceptable(current, prev float64, cond string) error {
+	return metricAcceptable(PolicyMetricTestExecutionTime, "testExecutionTime.acceptable", current, prev, cond)
+}
+
+func durationAcceptable(current, prev float64, cond string) (bool, error) {
+	matches := durationRe.FindAllString(cond, -1)
+	for _, m := range matches {
+		d, err := duration.Parse(m)
+		if err != nil {
+			return false, err
+		}
+		cond = strings.Replace(cond, m, strconv.FormatFloat(float64(d), 'f', -1, 64), 1)
+	}
+
+	if numberOnlyRe.MatchString(cond) {
 		cond = fmt.Sprintf("current <= %s", cond)
 	} else if compOpRe.MatchString(cond) {
 		cond = fmt.Sprintf("current %s", cond)
@@ -326,16 +1183,64 @@ func testExecutionTimeAcceptable(current, prev float64, cond string) error {
 	}
 	ok, err := expr.Eval(fmt.Sprintf("(%s) == true", cond), variables)
 	if err != nil {
-		return err
+		return false, err
+	}
+	return ok.(bool), nil
+}
+
+func docCoverageAcceptable(current, prev float64, cond string) error {
+	return metricAcceptable(PolicyMetricDocCoverage, "docCoverage.acceptable", current, prev, cond)
+}
+
+// IsBaselineTooOld reports whether the previous report is older than diff.baselineMaxAge.
+func (c *Config) IsBaselineTooOld(timestamp time.Time) bool {
+	if c.Diff == nil || c.Diff.BaselineMaxAge == 0 {
+		return false
 	}
+	return time.Since(timestamp) > c.Diff.BaselineMaxAge
+}
 
-	if !ok.(bool) {
-		return fmt.Errorf("test execution time is %v. the condition in the `testExecutionTime.acceptable:` section is not met (`%s`)", time.Duration(current), org)
+// CoverageLabelNames returns the names of coverage.labels in order.
+func (c *Config) CoverageLabelNames() []string {
+	if c.Coverage == nil {
+		return nil
 	}
-	return nil
+	var names []string
+	for k := range c.Coverage.Labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CoverageLabelPaths returns the mapping from label to path pattern of coverage.labels.
+func (c *Config) CoverageLabelPaths() map[string]string {
+	if c.Coverage == nil {
+		return nil
+	}
+	paths := map[string]string{}
+	for k, l := range c.Coverage.Labels {
+		paths[k] = l.Path
+	}
+	return paths
 }
 
+// CoverageColor returns the color of the code coverage.
+// If coverage.badge.thresholds is set, the color of the first threshold the code coverage reaches is returned.
 func (c *Config) CoverageColor(cover float64) string {
+	if c.Coverage != nil && len(c.Coverage.Badge.Thresholds) > 0 {
+		ts := c.Coverage.Badge.Thresholds
+		for _, t := range ts {
+			if cover >= t.Min {
+				return t.Color
+			}
+		}
+		return ts[len(ts)-1].Color
+	}
+	return defaultCoverageColor(cover)
+}
+
+func defaultCoverageColor(cover float64) string {
 	switch {
 	case cover >= 80.0:
 		return green
@@ -350,7 +1255,341 @@ func (c *Config) CoverageColor(cover float64) string {
 	}
 }
 
+// CoverageBadgeColor returns the color of the code coverage badge.
+// If coverage.badge.colorFromDisplayed is enabled, the color is decided by the displayed (rounded) value.
+func (c *Config) CoverageBadgeColor(cover float64) string {
+	return c.CoverageColor(c.coverageBadgeValue(cover))
+}
+
+func (c *Config) coverageBadgeValue(cover float64) float64 {
+	if c.Coverage == nil || !c.Coverage.Badge.ColorFromDisplayed {
+		return cover
+	}
+	v, err := strconv.ParseFloat(fmt.Sprintf("%.1f", cover), 64)
+	if err != nil {
+		return cover
+	}
+	return v
+}
+
+// CoverageBadgePaths returns the output paths of the coverage badge (coverage.badge.path and coverage.badge.paths).
+// The format of each output is inferred from its extension (.json for the shields.io endpoint JSON, otherwise SVG).
+func (c *Config) CoverageBadgePaths() []string {
+	if c.Coverage == nil {
+		return nil
+	}
+	var paths []string
+	if c.Coverage.Badge.Path != "" {
+		paths = append(paths, c.Coverage.Badge.Path)
+	}
+	for _, p := range c.Coverage.Badge.Paths {
+		if p == "" || slices.Contains(paths, p) {
+			continue
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// CoverageBadgeHeatmapPath returns the path of the coverage heatmap.
+// If coverage.badge.heatmap.path is not set, it is derived from the first SVG path of the coverage badge (e.g. docs/coverage.svg -> docs/coverage.heatmap.svg).
+func (c *Config) CoverageBadgeHeatmapPath() string {
+	if c.Coverage == nil || c.Coverage.Badge.Heatmap == nil {
+		return ""
+	}
+	if c.Coverage.Badge.Heatmap.Path != "" {
+		return c.Coverage.Badge.Heatmap.Path
+	}
+	for _, p := range c.CoverageBadgePaths() {
+		ext := filepath.Ext(p)
+		if strings.EqualFold(ext, ".json") {
+			continue
+		}
+		return fmt.Sprintf("%s.heatmap%s", strings.TrimSuffix(p, ext), ext)
+	}
+	return ""
+}
+
+// CoverageBadgeHeatmapDays returns the number of recent days shown in the coverage heatmap.
+func (c *Config) CoverageBadgeHeatmapDays() int {
+	if c.Coverage == nil || c.Coverage.Badge.Heatmap == nil || c.Coverage.Badge.Heatmap.Days <= 0 {
+		return defaultHeatmapDays
+	}
+	if c.Coverage.Badge.Heatmap.Days > maxHeatmapDays {
+		return maxHeatmapDays
+	}
+	return c.Coverage.Badge.Heatmap.Days
+}
+
+// CoverageChartWindow returns the number of recent reports drawn in the coverage chart.
+func (c *Config) CoverageChartWindow() int {
+	if c.Coverage == nil || c.Coverage.Chart == nil || c.Coverage.Chart.Window <= 0 {
+		return defaultChartWindow
+	}
+	if c.Coverage.Chart.Window > maxChartWindow {
+		return maxChartWindow
+	}
+	return c.Coverage.Chart.Window
+}
+
+// CoverageSparklineCount returns the number of recent reports (including the current one) drawn in the sparkline.
+func (c *Config) CoverageSparklineCount() int {
+	if c.Coverage == nil || c.Coverage.History == nil || c.Coverage.History.Count <= 0 {
+		return defaultSparklineCount
+	}
+	if c.Coverage.History.Count > maxChartWindow {
+		return maxChartWindow
+	}
+	return c.Coverage.History.Count
+}
+
+// CoverageGoal returns the goal of code coverage detected from coverage.acceptable.
+func (c *Config) CoverageGoal() (float64, error) {
+	if c.Coverage == nil || c.Coverage.Acceptable.Condition == "" {
+		return 0, errors.New("coverage.acceptable: is not set")
+	}
+	cond := trimPercentRe.ReplaceAllString(c.Coverage.Acceptable.Condition, "$1")
+	m := goalRe.FindStringSubmatch(cond)
+	if len(m) != 2 {
+		return 0, fmt.Errorf("could not detect the goal from coverage.acceptable: (%s)", c.Coverage.Acceptable.Condition)
+	}
+	return strconv.ParseFloat(m[1], 64)
+}
+
+// CoverageGoalColor returns the color of the code coverage by proximity to the goal.
+func (c *Config) CoverageGoalColor(cover, goal float64) string {
+	if goal <= 0 {
+		return green
+	}
+	p := c.coverageBadgeValue(cover) / goal
+	switch {
+	case p >= 1.0:
+		return green
+	case p >= 0.95:
+		return yellowgreen
+	case p >= 0.9:
+		return yellow
+	case p >= 0.8:
+		return orange
+	default:
+		return red
+	}
+}
+
+// CoverageReportFiles returns the maximum number of files shown in the file coverages table of the report.
+func (c *Config) CoverageReportFiles() int {
+	if c.Coverage == nil || c.Coverage.Report == nil || c.Coverage.Report.Files < 0 {
+		return 0
+	}
+	return c.Coverage.Report.Files
+}
+
+// CoverageParserCommand returns the external command to parse coverage reports.
+func (c *Config) CoverageParserCommand() string {
+	if c.Coverage == nil || c.Coverage.Parser == nil {
+		return ""
+	}
+	return c.Coverage.Parser.Command
+}
+
+// IsCodeToTestRatioMeaningful reports whether the number of code files reaches codeToTestRatio.minFiles.
+func (c *Config) IsCodeToTestRatioMeaningful(codeFiles int) bool {
+	if c.CodeToTestRatio == nil || c.CodeToTestRatio.MinFiles <= 0 {
+		return true
+	}
+	return codeFiles >= c.CodeToTestRatio.MinFiles
+}
+
+// CommentKey returns the key of the signature of the report comment, suffixing the key of the report with comment.key.
+func (c *Config) CommentKey(key string) string {
+	if c.Comment == nil || c.Comment.Key == "" {
+		return key
+	}
+	if key == "" {
+		return c.Comment.Key
+	}
+	return fmt.Sprintf("%s:%s", key, c.Comment.Key)
+}
+
+// DatastoreGitHubRetries returns the number of retries of the GitHub API calls of github:// datastores.
+func (c *Config) DatastoreGitHubRetries() int {
+	if c.Datastore == nil || c.Datastore.GitHub == nil || c.Datastore.GitHub.Retries == nil {
+		return defaultDatastoreGitHubRetries
+	}
+	return *c.Datastore.GitHub.Retries
+}
+
+// CentralRepositoryMatch reports whether the repository (owner/repo) is aggregated in central mode.
+// All repositories are aggregated if central.repositories.include is empty, except those matching central.repositories.exclude.
+func (c *Config) CentralRepositoryMatch(repo string) bool {
+	if c.Central == nil || c.Central.Repositories == nil {
+		return true
+	}
+	match := func(patterns []string) bool {
+		for _, p := range patterns {
+			if ok, err := path.Match(p, repo); err == nil && ok {
+				return true
+			}
+		}
+		return false
+	}
+	if len(c.Central.Repositories.Include) > 0 && !match(c.Central.Repositories.Include) {
+		return false
+	}
+	return !match(c.Central.Repositories.Exclude)
+}
+
+// CentralSummaryAverage returns the averaging of the code coverage across the repositories (central.summary.average).
+// It returns an empty string if central.summary is not set, and CentralSummaryAverageWeighted by default.
+func (c *Config) CentralSummaryAverage() string {
+	if c.Central == nil || c.Central.Summary == nil {
+		return ""
+	}
+	if c.Central.Summary.Average == "" {
+		return CentralSummaryAverageWeighted
+	}
+	return c.Central.Summary.Average
+}
+
+// CoverageBaselineRef returns the git ref of the report to compare against (coverage.baseline), e.g. refs/tags/v1.2.0 for tag:v1.2.0.
+// It returns an empty string if coverage.baseline is not set.
+func (c *Config) CoverageBaselineRef() (string, error) {
+	if c.Coverage == nil || c.Coverage.Baseline == "" {
+		return "", nil
+	}
+	kind, name, ok := strings.Cut(c.Coverage.Baseline, ":")
+	if !ok || name == "" {
+		return "", fmt.Errorf("invalid coverage.baseline: %s (tag:<tag> or ref:<ref>)", c.Coverage.Baseline)
+	}
+	switch kind {
+	case CoverageBaselineTag:
+		return "refs/tags/" + name, nil
+	case CoverageBaselineRef:
+		return name, nil
+	default:
+		return "", fmt.Errorf("invalid coverage.baseline: %s (tag:<tag> or ref:<ref>)", c.Coverage.Baseline)
+	}
+}
+
+// CentralConcurrency returns the number of the reports read simultaneously in central mode (central.concurrency).
+func (c *Config) CentralConcurrency() int {
+	if c.Central == nil || c.Central.Concurrency <= 0 {
+		return defaultCentralConcurrency
+	}
+	return c.Central.Concurrency
+}
+
+// CommentTemplate returns the path of the custom template of the report comment (comment.template).
+func (c *Config) CommentTemplate() string {
+	if c.Comment == nil {
+		return ""
+	}
+	return c.Comment.Template
+}
+
+// ReportDatastores returns the datastores of report.datastores to store the report to.
+// The entries with their own `if` sections are included only if the conditions are met. All matching entries are included.
+func (c *Config) ReportDatastores() []string {
+	if c.Report == nil {
+		return nil
+	}
+	datastores := append([]string{}, c.Report.Datastores...)
+	for _, d := range c.Report.ConditionalDatastores {
+		ok, err := c.CheckIf(d.If)
+		if err != nil {
+			log.Printf("Skip storing report to %s: the condition in the `if` section is not met (%s): %v", d.URL, d.If, err)
+			continue
+		}
+		if !ok {
+			log.Printf("Skip storing report to %s: the condition in the `if` section is not met (%s)", d.URL, d.If)
+			continue
+		}
+		datastores = append(datastores, d.URL)
+	}
+	return datastores
+}
+
+// ReportDatastoresToRead returns all the datastores of report.datastores regardless of the `if` sections of the entries, to read the stored reports from.
+func (c *Config) ReportDatastoresToRead() []string {
+	if c.Report == nil {
+		return nil
+	}
+	datastores := append([]string{}, c.Report.Datastores...)
+	for _, d := range c.Report.ConditionalDatastores {
+		datastores = append(datastores, d.URL)
+	}
+	return datastores
+}
+
+// ReportGitLabProject returns the ID or path of the GitLab project to post the report note to. Default is env CI_PROJECT_ID.
+func (c *Config) ReportGitLabProject() string {
+	if c.Report == nil || c.Report.GitLab == nil || c.Report.GitLab.Project == "" {
+		return os.Getenv("CI_PROJECT_ID")
+	}
+	return c.Report.GitLab.Project
+}
+
+// ReportAzureCollectionURL returns the URL of the Azure DevOps organization (collection) to post the report comment to.
+// Default is env SYSTEM_COLLECTIONURI.
+func (c *Config) ReportAzureCollectionURL() string {
+	if c.Report == nil || c.Report.Azure == nil || c.Report.Azure.Organization == "" {
+		return os.Getenv("SYSTEM_COLLECTIONURI")
+	}
+	return azure.OrganizationURL(c.Report.Azure.Organization)
+}
+
+// ReportAzureProject returns the Azure DevOps project to post the report comment to. Default is env SYSTEM_TEAMPROJECT.
+func (c *Config) ReportAzureProject() string {
+	if c.Report == nil || c.Report.Azure == nil || c.Report.Azure.Project == "" {
+		return os.Getenv("SYSTEM_TEAMPROJECT")
+	}
+	return c.Report.Azure.Project
+}
+
+// ReportAzureRepository returns the Azure Repos repository to post the report comment to. Default is env BUILD_REPOSITORY_NAME.
+func (c *Config) ReportAzureRepository() string {
+	if c.Report == nil || c.Report.Azure == nil || c.Report.Azure.Repository == "" {
+		return os.Getenv("BUILD_REPOSITORY_NAME")
+	}
+	return c.Report.Azure.Repository
+}
+
+// CoverageBadgeLabel returns the label of the coverage badge.
+func (c *Config) CoverageBadgeLabel() string {
+	if c.Coverage == nil || c.Coverage.Badge.Label == "" {
+		return defaultCoverageBadgeLabel
+	}
+	return c.Coverage.Badge.Label
+}
+
+// CodeToTestRatioBadgeLabel returns the label of the code to test ratio badge.
+func (c *Config) CodeToTestRatioBadgeLabel() string {
+	if c.CodeToTestRatio == nil || c.CodeToTestRatio.Badge.Label == "" {
+		return defaultCodeToTestRatioBadgeLabel
+	}
+	return c.CodeToTestRatio.Badge.Label
+}
+
+// CodeToTestRatioGroupDepth returns the directory depth to group the code to test ratio by. 0 means no grouping.
+func (c *Config) CodeToTestRatioGroupDepth() int {
+	if c.CodeToTestRatio == nil || c.CodeToTestRatio.GroupDepth < 0 {
+		return 0
+	}
+	return c.CodeToTestRatio.GroupDepth
+}
+
+// CodeToTestRatioColor returns the color of the code to test ratio.
+// If codeToTestRatio.badge.thresholds is set, the color of the first threshold the ratio reaches is returned.
 func (c *Config) CodeToTestRatioColor(ratio float64) string {
+	if c.CodeToTestRatio != nil && len(c.CodeToTestRatio.Badge.Thresholds) > 0 {
+		ts := c.CodeToTestRatio.Badge.Thresholds
+		for _, t := range ts {
+			if ratio >= t.Min {
+				return t.Color
+			}
+		}
+		return ts[len(ts)-1].Color
+	}
 	switch {
 	case ratio >= 1.2:
 		return green
@@ -366,6 +1605,15 @@ func (c *Config) CodeToTestRatioColor(ratio float64) string {
 }
 
 func (c *Config) TestExecutionTimeColor(d time.Duration) string {
+	if c.TestExecutionTime != nil && len(c.TestExecutionTime.Badge.Thresholds) > 0 {
+		ts := c.TestExecutionTime.Badge.Thresholds
+		for _, t := range ts {
+			if d < t.Max {
+				return t.Color
+			}
+		}
+		return ts[len(ts)-1].Color
+	}
 	switch {
 	case d < 5*time.Minute:
 		return green
@@ -380,6 +1628,87 @@ func (c *Config) TestExecutionTimeColor(d time.Duration) string {
 	}
 }
 
+func (c *Config) DocCoverageColor(cover float64) string {
+	return defaultCoverageColor(cover)
+}
+
+// isNetDeletionPullRequest reports whether the current pull request deletes more lines than it adds.
+func (c *Config) isNetDeletionPullRequest(ctx context.Context) bool {
+	repo, err := gh.Parse(c.Repository)
+	if err != nil {
+		return false
+	}
+	if c.gh == nil {
+		g, err := gh.New()
+		if err != nil {
+			return false
+		}
+		c.gh = g
+	}
+	n, err := c.gh.DetectCurrentPullRequestNumber(ctx, repo.Owner, repo.Repo)
+	if err != nil {
+		return false
+	}
+	pr, err := c.gh.FetchPullRequest(ctx, repo.Owner, repo.Repo, n)
+	if err != nil {
+		return false
+	}
+	return pr.Deletions > pr.Additions
+}
+
+// isSkipRegressionRequested reports whether the skip-regression escape hatch is present in the event payload.
+func (c *Config) isSkipRegressionRequested() bool {
+	e, err := gh.DecodeGitHubEvent()
+	if err != nil {
+		return false
+	}
+	return skipRegressionRequested(e.Payload)
+}
+
+// skipRegressionRequested reports whether the event payload has the SkipRegressionLabel label on the pull request,
+// or the SkipRegressionMarker in the title or body of the pull request or in the commit messages.
+func skipRegressionRequested(payload any) bool {
+	p, ok := payload.(map[string]any)
+	if !ok {
+		return false
+	}
+	var texts []string
+	if pr, ok := p["pull_request"].(map[string]any); ok {
+		if labels, ok := pr["labels"].([]any); ok {
+			for _, l := range labels {
+				if lm, ok := l.(map[string]any); ok && lm["name"] == SkipRegressionLabel {
+					return true
+				}
+			}
+		}
+		for _, k := range []string{"title", "body"} {
+			if v, ok := pr[k].(string); ok {
+				texts = append(texts, v)
+			}
+		}
+	}
+	if hc, ok := p["head_commit"].(map[string]any); ok {
+		if v, ok := hc["message"].(string); ok {
+			texts = append(texts, v)
+		}
+	}
+	if commits, ok := p["commits"].([]any); ok {
+		for _, cm := range commits {
+			if m, ok := cm.(map[string]any); ok {
+				if v, ok := m["message"].(string); ok {
+					texts = append(texts, v)
+				}
+			}
+		}
+	}
+	for _, t := range texts {
+		if strings.Contains(t, SkipRegressionMarker) {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Config) CheckIf(cond string) (bool, error) {
 	if cond == "" {
 		return true, nil
@@ -443,6 +1772,9 @@ func (c *Config) CheckIf(cond string) (bool, error) {
 		"is_draft":          isDraft,
 		"labels":            labels,
 	}
+	for k, v := range c.reportVariables() {
+		variables[k] = v
+	}
 	ok, err := expr.Eval(fmt.Sprintf("(%s) == true", cond), variables)
 	if err != nil {
 		return false, err
@@ -1,7 +1,12 @@
 package config
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,6 +14,11 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v58/github"
+	"github.com/hashicorp/go-multierror"
+	"github.com/k1LoW/go-github-client/v58/factory"
+	"github.com/k1LoW/octocov/gh"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
 	"golang.org/x/text/language"
 )
 
@@ -33,6 +43,7 @@ func TestLoad(t *testing.T) {
 		{filepath.Join(rootTestdataDir(t), "config"), "", false},
 		{filepath.Join(rootTestdataDir(t), "config"), ".octocov.yml", false},
 		{filepath.Join(rootTestdataDir(t), "config"), "no.yml", true},
+		{rootTestdataDir(t), "config", true},
 	}
 	for _, tt := range tests {
 		c := New()
@@ -41,6 +52,1537 @@ func TestLoad(t *testing.T) {
 			if !tt.wantErr {
 				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
 			}
+			if c.Loaded() {
+				t.Errorf("got loaded config %s\nwant not loaded", c.path)
+			}
+		} else {
+			if tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+			}
+		}
+	}
+}
+
+func TestCoverageConfigs(t *testing.T) {
+	dir := t.TempDir()
+	cfg := `repository: owner/repo
+coverage:
+  - name: unit
+    paths:
+      - unit.out
+    acceptable: 80%
+    badge:
+      path: docs/unit.svg
+  - name: integration
+    paths:
+      - integration.out
+    acceptable: 60%
+`
+	if err := os.WriteFile(filepath.Join(dir, ".octocov.yml"), []byte(cfg), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := New()
+	c.wd = dir
+	if err := c.Load(""); err != nil {
+		t.Fatal(err)
+	}
+	c.Build()
+	if got, want := len(c.Coverages), 2; got != want {
+		t.Fatalf("got %v\nwant %v", got, want)
+	}
+	configs, err := c.CoverageConfigs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		repository string
+		paths      []string
+		acceptable string
+		badge      string
+	}{
+		{"owner/repo/unit", []string{filepath.Join(dir, "unit.out")}, "80%", "docs/unit.svg"},
+		{"owner/repo/integration", []string{filepath.Join(dir, "integration.out")}, "60%", ""},
+	}
+	if len(configs) != len(tests) {
+		t.Fatalf("got %v\nwant %v", len(configs), len(tests))
+	}
+	for i, tt := range tests {
+		cc := configs[i]
+		if got := cc.Repository; got != tt.repository {
+			t.Errorf("got %v\nwant %v", got, tt.repository)
+		}
+		if diff := cmp.Diff(cc.Coverage.Paths, tt.paths); diff != "" {
+			t.Error(diff)
+		}
+		if got := cc.Coverage.Acceptable.Condition; got != tt.acceptable {
+			t.Errorf("got %v\nwant %v", got, tt.acceptable)
+		}
+		if got := cc.Coverage.Badge.Path; got != tt.badge {
+			t.Errorf("got %v\nwant %v", got, tt.badge)
+		}
+		if len(cc.Coverages) != 0 {
+			t.Errorf("got %v\nwant no named coverage configs", len(cc.Coverages))
+		}
+	}
+	if c.Repository != "owner/repo" {
+		t.Errorf("got %v\nwant %v", c.Repository, "owner/repo")
+	}
+
+	// The single object form returns the config itself
+	single := New()
+	single.Coverage = &Coverage{Paths: []string{"coverage.out"}}
+	got, err := single.CoverageConfigs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0] != single {
+		t.Errorf("got %v\nwant the config itself", got)
+	}
+
+	invalid := New()
+	invalid.Coverages = []*Coverage{{Name: "unit/integration"}}
+	if _, err := invalid.CoverageConfigs(); err == nil {
+		t.Error("got nil\nwant error")
+	}
+}
+
+func TestCoverageConfig(t *testing.T) {
+	c := New()
+	c.Repository = "owner/repo"
+	c.Coverages = []*Coverage{{Name: "unit"}, {Name: "integration"}}
+	single := New()
+	single.Coverage = &Coverage{}
+	tests := []struct {
+		c              *Config
+		name           string
+		wantRepository string
+		wantErr        bool
+	}{
+		{c, "integration", "owner/repo/integration", false},
+		{c, "", "", true},
+		{c, "e2e", "", true},
+		{single, "", "", false},
+		{single, "unit", "", true},
+	}
+	for _, tt := range tests {
+		got, err := tt.c.CoverageConfig(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got.Repository != tt.wantRepository {
+			t.Errorf("got %v\nwant %v", got.Repository, tt.wantRepository)
+		}
+	}
+}
+
+func TestLoadExplicitPath(t *testing.T) {
+	t.Setenv("OCTOCOV_TEST_CONFIG_REPOSITORY", "owner/ci")
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".octocov.yml"), []byte("repository: owner/default\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".octocov.ci.yml"), []byte("repository: ${OCTOCOV_TEST_CONFIG_REPOSITORY}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := New()
+	c.wd = dir
+	if err := c.Load(".octocov.ci.yml"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.Repository, "owner/ci"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	c = New()
+	c.wd = dir
+	err := c.Load(".octocov.missing.yml")
+	if err == nil {
+		t.Fatal("got nil\nwant error")
+	}
+	if got, want := err.Error(), "config file .octocov.missing.yml does not exist"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestBuildOverrideAcceptablesByEnv(t *testing.T) {
+	t.Setenv("OCTOCOV_COVERAGE_ACCEPTABLE", "current >= 70%")
+	t.Setenv("OCTOCOV_CODE_TO_TEST_RATIO_ACCEPTABLE", "")
+	t.Setenv("OCTOCOV_TEST_EXECUTION_TIME_ACCEPTABLE", "3min")
+	t.Setenv("OCTOCOV_DOC_COVERAGE_ACCEPTABLE", "50%")
+	c := New()
+	c.Coverage = &Coverage{Acceptable: CoverageAcceptable{Condition: "60%"}}
+	c.CodeToTestRatio = &CodeToTestRatio{Acceptable: "1:1.2"}
+	c.Build()
+	if got, want := c.Coverage.Acceptable.Condition, "current >= 70%"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := c.CodeToTestRatio.Acceptable, "1:1.2"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := c.TestExecutionTime.Acceptable, "3min"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if c.DocCoverage != nil {
+		t.Errorf("got %v\nwant %v", c.DocCoverage, nil)
+	}
+}
+
+func TestBuildOverrideCoverageAcceptable(t *testing.T) {
+	tests := []struct {
+		env  string
+		flag string
+		want string
+	}{
+		{"", "", "60%"},
+		{"current >= 70%", "", "current >= 70%"},
+		{"", "current >= 80%", "current >= 80%"},
+		{"current >= 70%", "current >= 80%", "current >= 80%"},
+	}
+	for _, tt := range tests {
+		t.Setenv("OCTOCOV_COVERAGE_ACCEPTABLE", tt.env)
+		c := New()
+		c.Coverage = &Coverage{Acceptable: CoverageAcceptable{Condition: "60%"}}
+		c.OverrideCoverageAcceptable(tt.flag)
+		c.Build()
+		if got := c.Coverage.Acceptable.Condition; got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestLoadComment(t *testing.T) {
+	tests := []struct {
+		path string
+		want *Comment
+	}{
+		{"comment_enabled_octocov.yml", &Comment{}},
+		{"comment_enabled_octocov2.yml", &Comment{If: "is_pull_request"}},
+		{"comment_disabled_octocov.yml", nil},
+	}
+	for _, tt := range tests {
+		c := New()
+		p := filepath.Join(testdataDir(t), tt.path)
+		if err := c.Load(p); err != nil {
+			t.Fatal(err)
+		}
+		got := c.Comment
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestLoadCentralPush(t *testing.T) {
+	tests := []struct {
+		path string
+		want *Push
+	}{
+		{"central_push_enabled_octocov.yml", &Push{}},
+		{"central_push_enabled_octocov2.yml", &Push{If: "is_default_branch"}},
+		{"central_push_disabled_octocov.yml", nil},
+	}
+	for _, tt := range tests {
+		c := New()
+		p := filepath.Join(testdataDir(t), tt.path)
+		if err := c.Load(p); err != nil {
+			t.Fatal(err)
+		}
+		got := c.Central.Push
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestLoadCentralCache(t *testing.T) {
+	c := New()
+	p := filepath.Join(testdataDir(t), "central_cache_octocov.yml")
+	if err := c.Load(p); err != nil {
+		t.Fatal(err)
+	}
+	c.Build()
+	want := &CentralCache{Dir: filepath.Join(testdataDir(t), ".octocov", "cache", "central"), TTL: 30 * time.Minute}
+	if diff := cmp.Diff(c.Central.Cache, want, nil); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLoadCentralSummary(t *testing.T) {
+	tests := []struct {
+		config  string
+		want    string
+		wantErr bool
+	}{
+		{"central:\n  root: .\n", "", false},
+		{"central:\n  summary:\n    average: weighted\n", CentralSummaryAverageWeighted, false},
+		{"central:\n  summary:\n    average: simple\n", CentralSummaryAverageSimple, false},
+		{"central:\n  summary: {}\n", CentralSummaryAverageWeighted, false},
+		{"central:\n  summary:\n    average: median\n", "", true},
+	}
+	for _, tt := range tests {
+		p := filepath.Join(t.TempDir(), ".octocov.yml")
+		if err := os.WriteFile(p, []byte(tt.config), 0600); err != nil {
+			t.Fatal(err)
+		}
+		c := New()
+		if err := c.Load(p); err != nil {
+			if !tt.wantErr {
+				t.Errorf("%q: %v", tt.config, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%q: want error", tt.config)
+			continue
+		}
+		if got := c.CentralSummaryAverage(); got != tt.want {
+			t.Errorf("%q: got %v\nwant %v", tt.config, got, tt.want)
+		}
+	}
+}
+
+func TestLoadCentralConcurrency(t *testing.T) {
+	tests := []struct {
+		config string
+		want   int
+	}{
+		{"central:\n  root: .\n", 5},
+		{"central:\n  concurrency: 20\n", 20},
+		{"central:\n  concurrency: 0\n", 5},
+	}
+	for _, tt := range tests {
+		p := filepath.Join(t.TempDir(), ".octocov.yml")
+		if err := os.WriteFile(p, []byte(tt.config), 0600); err != nil {
+			t.Fatal(err)
+		}
+		c := New()
+		if err := c.Load(p); err != nil {
+			t.Fatalf("%q: %v", tt.config, err)
+		}
+		if got := c.CentralConcurrency(); got != tt.want {
+			t.Errorf("%q: got %v\nwant %v", tt.config, got, tt.want)
+		}
+	}
+}
+
+func TestCoverageBaselineRef(t *testing.T) {
+	tests := []struct {
+		baseline string
+		want     string
+		wantErr  bool
+	}{
+		{"", "", false},
+		{"tag:v1.2.0", "refs/tags/v1.2.0", false},
+		{"ref:release", "release", false},
+		{"ref:refs/heads/release", "refs/heads/release", false},
+		{"ref:", "", true},
+		{"v1.2.0", "", true},
+		{"branch:main", "", true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &Coverage{Baseline: tt.baseline}
+		got, err := c.CoverageBaselineRef()
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: %v", tt.baseline, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%s: want error", tt.baseline)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %v\nwant %v", tt.baseline, got, tt.want)
+		}
+	}
+}
+
+func TestLoadDatastoreGitHubRetries(t *testing.T) {
+	tests := []struct {
+		config  string
+		want    int
+		wantErr bool
+	}{
+		{"", 3, false},
+		{"datastore:\n  github:\n    retries: 5\n", 5, false},
+		{"datastore:\n  github:\n    retries: 0\n", 0, false},
+		{"datastore:\n  github:\n    retries: -1\n", 0, true},
+	}
+	for _, tt := range tests {
+		p := filepath.Join(t.TempDir(), ".octocov.yml")
+		if err := os.WriteFile(p, []byte("repository: owner/repo\n"+tt.config), 0600); err != nil {
+			t.Fatal(err)
+		}
+		c := New()
+		if err := c.Load(p); err != nil {
+			if !tt.wantErr {
+				t.Errorf("%q: %v", tt.config, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%q: want error", tt.config)
+			continue
+		}
+		if got := c.DatastoreGitHubRetries(); got != tt.want {
+			t.Errorf("%q: got %v\nwant %v", tt.config, got, tt.want)
+		}
+	}
+}
+
+func TestLoadCoverageAcceptableRatchet(t *testing.T) {
+	tests := []struct {
+		acceptable string
+		want       *CoverageRatchet
+		wantErr    bool
+	}{
+		{"60%", nil, false},
+		{"ratchet", &CoverageRatchet{}, false},
+		{"\n    ratchet: true", &CoverageRatchet{}, false},
+		{"\n    ratchet: false", nil, false},
+		{"\n    condition: current >= 60%\n    ratchet:\n      tolerance: 0.5%", &CoverageRatchet{Tolerance: "0.5%"}, false},
+		{"\n    ratchet:\n      tolerance: foo", nil, true},
+		{"\n    ratchet:\n      tolerance: -1%", nil, true},
+	}
+	for _, tt := range tests {
+		p := filepath.Join(t.TempDir(), ".octocov.yml")
+		if err := os.WriteFile(p, []byte("coverage:\n  acceptable: "+tt.acceptable+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		c := New()
+		if err := c.Load(p); err != nil {
+			if !tt.wantErr {
+				t.Errorf("%q: %v", tt.acceptable, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%q: want error", tt.acceptable)
+			continue
+		}
+		if diff := cmp.Diff(c.Coverage.Acceptable.Ratchet, tt.want); diff != "" {
+			t.Errorf("%q: %s", tt.acceptable, diff)
+		}
+	}
+}
+
+func TestLoadCoverageAcceptable(t *testing.T) {
+	tests := []struct {
+		path string
+		want CoverageAcceptable
+	}{
+		{"acceptable_condition_octocov.yml", CoverageAcceptable{Condition: "current >= 60%"}},
+		{"acceptable_endpoint_octocov.yml", CoverageAcceptable{Endpoint: "https://policy.example.com/octocov", Timeout: 5 * time.Second, FailOpen: true}},
+		{"acceptable_window_octocov.yml", CoverageAcceptable{Condition: "current >= prev", Window: 5}},
+		{"acceptable_max_uncovered_funcs_octocov.yml", CoverageAcceptable{Condition: "current >= 60%", MaxUncoveredFuncs: func() *int { v := 0; return &v }()}},
+		{"acceptable_require_improvement_octocov.yml", CoverageAcceptable{Condition: "current >= 60%", RequireImprovement: &CoverageRequireImprovement{Delta: "+2%", Paths: []string{"legacy/**"}}}},
+		{"acceptable_relative_to_octocov.yml", CoverageAcceptable{Condition: "current >= 60%", RelativeTo: RelativeToOrgMedian, OrgDatastores: []string{"s3://octocov-reports/reports"}}},
+		{"acceptable_mode_octocov.yml", CoverageAcceptable{Condition: "current >= 80%", Mode: CoverageAcceptableModeWarn}},
+		{"acceptable_paths_octocov.yml", CoverageAcceptable{Condition: "current >= 60%", Paths: []*CoverageAcceptablePath{{Path: "core/**", Condition: "current >= 90%"}, {Path: "experimental/**", Condition: "current >= 50%"}}}},
+	}
+	for _, tt := range tests {
+		c := New()
+		p := filepath.Join(testdataDir(t), tt.path)
+		if err := c.Load(p); err != nil {
+			t.Fatal(err)
+		}
+		got := c.Coverage.Acceptable
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestLoadInvalidCoverageAcceptableMode(t *testing.T) {
+	p := filepath.Join(t.TempDir(), ".octocov.yml")
+	if err := os.WriteFile(p, []byte("coverage:\n  acceptable:\n    condition: current >= 80%\n    mode: fail\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := New()
+	if err := c.Load(p); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestLoadCoverageLabels(t *testing.T) {
+	c := New()
+	p := filepath.Join(testdataDir(t), "labels_octocov.yml")
+	if err := c.Load(p); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*CoverageLabel{
+		"api": {Path: "services/api/**"},
+		"web": {Path: "web/**", Acceptable: "60%"},
+	}
+	if diff := cmp.Diff(c.Coverage.Labels, want, nil); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(c.CoverageLabelNames(), []string{"api", "web"}, nil); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLoadCoverageCritical(t *testing.T) {
+	tests := []struct {
+		path string
+		want *CoverageCritical
+	}{
+		{"critical_octocov.yml", &CoverageCritical{Paths: []string{"auth/**", "crypto/**"}, Acceptable: "95%"}},
+		{"critical_list_octocov.yml", &CoverageCritical{Paths: []string{"auth/**", "crypto/**"}}},
+	}
+	for _, tt := range tests {
+		c := New()
+		p := filepath.Join(testdataDir(t), tt.path)
+		if err := c.Load(p); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(c.Coverage.Critical, tt.want, nil); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestLoadExpandenv(t *testing.T) {
+	t.Setenv("OCTOCOV_TEST_REPO", "owner/repo")
+	c := New()
+	p := filepath.Join(testdataDir(t), "expandenv_octocov.yml")
+	if err := c.Load(p); err != nil {
+		t.Fatal(err)
+	}
+	if want := "owner/repo"; c.Repository != want {
+		t.Errorf("got %v\nwant %v", c.Repository, want)
+	}
+	want := []string{
+		"path/to/${OCTOCOV_TEST_REPO}/coverage.out",
+		"path/to/$OCTOCOV_TEST_REPO/coverage.out",
+		"path/to/$$/coverage.out",
+	}
+	if diff := cmp.Diff(c.Coverage.Paths, want, nil); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLoadIf(t *testing.T) {
+	c := New()
+	p := filepath.Join(testdataDir(t), "if_octocov.yml")
+	if err := c.Load(p); err != nil {
+		t.Fatal(err)
+	}
+	if want := "is_default_branch"; c.If != want {
+		t.Errorf("got %v\nwant %v", c.If, want)
+	}
+}
+
+func TestLoadBadgeManifest(t *testing.T) {
+	c := New()
+	p := filepath.Join(testdataDir(t), "badge_manifest_octocov.yml")
+	if err := c.Load(p); err != nil {
+		t.Fatal(err)
+	}
+	if c.Badge == nil || c.Badge.Manifest == nil {
+		t.Fatal("got nil\nwant badge.manifest")
+	}
+	c.Build()
+	if want := filepath.Join(testdataDir(t), "docs", "badges.json"); c.Badge.Manifest.Path != want {
+		t.Errorf("got %v\nwant %v", c.Badge.Manifest.Path, want)
+	}
+}
+
+func TestLoadInvalidCoveragePathPattern(t *testing.T) {
+	c := New()
+	p := filepath.Join(testdataDir(t), "invalid_label_pattern_octocov.yml")
+	err := c.Load(p)
+	if err == nil {
+		t.Fatal("got nil\nwant error")
+	}
+	if want := "coverage.labels.api: invalid pattern (api/[**)"; !strings.Contains(err.Error(), want) {
+		t.Errorf("got %v\nwant %v", err, want)
+	}
+}
+
+func TestLoadCoverageBadgeHeatmap(t *testing.T) {
+	c := New()
+	p := filepath.Join(testdataDir(t), "heatmap_octocov.yml")
+	if err := c.Load(p); err != nil {
+		t.Fatal(err)
+	}
+	want := &CoverageBadgeHeatmap{Enable: true, Days: 30}
+	if diff := cmp.Diff(c.Coverage.Badge.Heatmap, want, nil); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLoadCoverageBadgeThresholds(t *testing.T) {
+	c := New()
+	p := filepath.Join(testdataDir(t), "badge_thresholds_octocov.yml")
+	if err := c.Load(p); err != nil {
+		t.Fatal(err)
+	}
+	want := CoverageBadgeThresholds{
+		{Min: 50, Color: "#97CA00"},
+		{Min: 30, Color: "#DFB317"},
+		{Min: 0, Color: "#E05D44"},
+	}
+	if diff := cmp.Diff(c.Coverage.Badge.Thresholds, want, nil); diff != "" {
+		t.Error(diff)
+	}
+	wantRatio := CodeToTestRatioBadgeThresholds{
+		{Min: 2.0, Color: "#97CA00"},
+		{Min: 1.0, Color: "#DFB317"},
+	}
+	if diff := cmp.Diff(c.CodeToTestRatio.Badge.Thresholds, wantRatio, nil); diff != "" {
+		t.Error(diff)
+	}
+	wantTime := TestExecutionTimeBadgeThresholds{
+		{Max: time.Minute, Color: "#97CA00"},
+		{Max: 5 * time.Minute, Color: "#DFB317"},
+	}
+	if diff := cmp.Diff(c.TestExecutionTime.Badge.Thresholds, wantTime, nil); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLoadCoverageBadgeColors(t *testing.T) {
+	p := filepath.Join(t.TempDir(), ".octocov.yml")
+	b := "coverage:\n  badge:\n    colors: [{min: 85, color: '#00aa00'}, {min: 50, color: '#DFB317'}, {min: 0, color: '#E05D44'}]\n"
+	if err := os.WriteFile(p, []byte(b), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := New()
+	if err := c.Load(p); err != nil {
+		t.Fatal(err)
+	}
+	want := CoverageBadgeThresholds{
+		{Min: 85, Color: "#00aa00"},
+		{Min: 50, Color: "#DFB317"},
+		{Min: 0, Color: "#E05D44"},
+	}
+	if diff := cmp.Diff(c.Coverage.Badge.Thresholds, want, nil); diff != "" {
+		t.Error(diff)
+	}
+	if got := c.CoverageColor(90.0); got != "#00aa00" {
+		t.Errorf("got %v\nwant %v", got, "#00aa00")
+	}
+}
+
+func TestLoadInvalidTestExecutionTimeBadgeThresholds(t *testing.T) {
+	tests := []struct {
+		thresholds string
+	}{
+		{"[{max: 1min, color: green}]"},
+		{"[{max: 1, color: '#97CA00'}]"},
+		{"[{max: 5min, color: '#97CA00'}, {max: 1min, color: '#E05D44'}]"},
+		{"[{max: 5min, color: '#97CA00'}, {max: 5min, color: '#E05D44'}]"},
+	}
+	for _, tt := range tests {
+		p := filepath.Join(t.TempDir(), ".octocov.yml")
+		b := fmt.Sprintf("testExecutionTime:\n  badge:\n    thresholds: %s\n", tt.thresholds)
+		if err := os.WriteFile(p, []byte(b), 0600); err != nil {
+			t.Fatal(err)
+		}
+		c := New()
+		if err := c.Load(p); err == nil {
+			t.Errorf("%s: want error", tt.thresholds)
+		}
+	}
+}
+
+func TestLoadInvalidCoverageBadgeThresholds(t *testing.T) {
+	tests := []struct {
+		thresholds string
+	}{
+		{"[{min: 50, color: green}]"},
+		{"[{min: 50, color: '#97CA0'}]"},
+		{"[{min: 50, color: '#97CA00'}, {min: 60, color: '#E05D44'}]"},
+		{"[{min: 50, color: '#97CA00'}, {min: 50, color: '#E05D44'}]"},
+	}
+	for _, tt := range tests {
+		for _, key := range []string{"coverage", "codeToTestRatio"} {
+			p := filepath.Join(t.TempDir(), ".octocov.yml")
+			b := fmt.Sprintf("%s:\n  badge:\n    thresholds: %s\n", key, tt.thresholds)
+			if err := os.WriteFile(p, []byte(b), 0600); err != nil {
+				t.Fatal(err)
+			}
+			c := New()
+			if err := c.Load(p); err == nil {
+				t.Errorf("%s: %s: want error", key, tt.thresholds)
+			}
+		}
+		p := filepath.Join(t.TempDir(), ".octocov.yml")
+		b := fmt.Sprintf("coverage:\n  badge:\n    colors: %s\n", tt.thresholds)
+		if err := os.WriteFile(p, []byte(b), 0600); err != nil {
+			t.Fatal(err)
+		}
+		c := New()
+		if err := c.Load(p); err == nil {
+			t.Errorf("coverage.badge.colors: %s: want error", tt.thresholds)
+		}
+	}
+	t.Run("both thresholds and colors", func(t *testing.T) {
+		p := filepath.Join(t.TempDir(), ".octocov.yml")
+		b := "coverage:\n  badge:\n    thresholds: [{min: 50, color: '#97CA00'}]\n    colors: [{min: 50, color: '#97CA00'}]\n"
+		if err := os.WriteFile(p, []byte(b), 0600); err != nil {
+			t.Fatal(err)
+		}
+		c := New()
+		if err := c.Load(p); err == nil {
+			t.Error("want error")
+		}
+	})
+}
+
+func TestLoadPolicy(t *testing.T) {
+	c := New()
+	p := filepath.Join(testdataDir(t), "policy_octocov.yml")
+	if err := c.Load(p); err != nil {
+		t.Fatal(err)
+	}
+	want := []*Policy{
+		{Name: "coverage-floor", Metric: PolicyMetricCoverage, Acceptable: "current >= 60%"},
+		{Name: "ratio", Metric: PolicyMetricCodeToTestRatio, Acceptable: "1:1.2"},
+		{Name: "fast-tests", Metric: PolicyMetricTestExecutionTime, Acceptable: "5min"},
+	}
+	if diff := cmp.Diff(c.Policy, want, nil); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLoadDiff(t *testing.T) {
+	c := New()
+	p := filepath.Join(testdataDir(t), "diff_octocov.yml")
+	if err := c.Load(p); err != nil {
+		t.Fatal(err)
+	}
+	want := &Diff{
+		Datastores:     []string{"artifact://owner/repo"},
+		BaselineMaxAge: 30 * 24 * time.Hour,
+		CompareAgainst: DiffCompareAgainstMergeBase,
+	}
+	if diff := cmp.Diff(c.Diff, want, nil); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLoadReportDatastores(t *testing.T) {
+	tests := []struct {
+		datastores string
+		want       *Report
+		wantErr    bool
+	}{
+		{
+			"s3://bucket/reports",
+			&Report{Datastores: []string{"s3://bucket/reports"}},
+			false,
+		},
+		{
+			"[s3://bucket/reports, {url: 'github://owner/coverages/reports'}]",
+			&Report{Datastores: []string{"s3://bucket/reports", "github://owner/coverages/reports"}},
+			false,
+		},
+		{
+			"{url: 'github://owner/coverages/reports', if: is_default_branch}",
+			&Report{ConditionalDatastores: []*ReportDatastore{{URL: "github://owner/coverages/reports", If: "is_default_branch"}}},
+			false,
+		},
+		{
+			"[s3://bucket/reports, {url: 'github://owner/coverages/releases', if: is_tag}]",
+			&Report{Datastores: []string{"s3://bucket/reports"}, ConditionalDatastores: []*ReportDatastore{{URL: "github://owner/coverages/releases", If: "is_tag"}}},
+			false,
+		},
+		{"[{if: is_default_branch}]", nil, true},
+		{"[1]", nil, true},
+	}
+	for _, tt := range tests {
+		p := filepath.Join(t.TempDir(), ".octocov.yml")
+		b := fmt.Sprintf("report:\n  datastores: %s\n", tt.datastores)
+		if err := os.WriteFile(p, []byte(b), 0600); err != nil {
+			t.Fatal(err)
+		}
+		c := New()
+		err := c.Load(p)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: want error", tt.datastores)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(c.Report, tt.want, nil); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestReportDatastores(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "token")
+	t.Setenv("GITHUB_EVENT_NAME", "pull_request")
+	t.Setenv("GITHUB_EVENT_PATH", filepath.Join(rootTestdataDir(t), "config", "event_pull_request_opened.json"))
+	t.Setenv("GITHUB_REF", "refs/pull/4/merge")
+	repo := github.Repository{DefaultBranch: github.String("main")}
+	pr := github.PullRequest{Number: github.Int(4)}
+	mockedHTTPClient := mock.NewMockedHTTPClient( //nostyle:funcfmt
+		mock.WithRequestMatch(mock.GetReposByOwnerByRepo, repo, repo, repo),
+		mock.WithRequestMatch(mock.GetReposPullsByOwnerByRepoByPullNumber, pr, pr, pr),
+	)
+	client, err := factory.NewGithubClient(factory.HTTPClient(mockedHTTPClient), factory.Timeout(10*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := gh.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetClient(client)
+	c := &Config{
+		Repository: "owner/repo",
+		Report: &Report{
+			Datastores: []string{"s3://bucket/reports"},
+			ConditionalDatastores: []*ReportDatastore{
+				{URL: "github://owner/coverages/prs", If: "is_pull_request"},
+				{URL: "github://owner/coverages/main", If: "is_default_branch"},
+				{URL: "github://owner/coverages/all", If: "true"},
+			},
+		},
+		gh: g,
+	}
+	want := []string{"s3://bucket/reports", "github://owner/coverages/prs", "github://owner/coverages/all"}
+	if diff := cmp.Diff(c.ReportDatastores(), want, nil); diff != "" {
+		t.Error(diff)
+	}
+	wantToRead := []string{"s3://bucket/reports", "github://owner/coverages/prs", "github://owner/coverages/main", "github://owner/coverages/all"}
+	if diff := cmp.Diff(c.ReportDatastoresToRead(), wantToRead, nil); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestLoadLocale(t *testing.T) {
+	tests := []struct {
+		path      string
+		want      *language.Tag
+		wantError bool
+	}{
+		{"locale_nothing.yml", nil, false},
+		{"locale_empty.yml", nil, false},
+		{"locale_ja.yml", &language.Japanese, false},
+		{"locale_ja_uppercase.yml", &language.Japanese, false},
+		{"locale_fr.yml", &language.French, false},
+		{"locale_unkown.yml", nil, true},
+	}
+	for _, tt := range tests {
+		c := New()
+		t.Run(fmt.Sprintf("%v", tt.path), func(t *testing.T) {
+			p := filepath.Join(testdataDir(t), tt.path)
+			if err := c.Load(p); err != nil {
+				if tt.wantError {
+					return
+				}
+				t.Fatal(err)
+			}
+			got := c.Locale
+			if tt.want == nil && got == nil {
+				return
+			}
+			if diff := cmp.Diff(got.String(), tt.want.String(), nil); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestCoveragePaths(t *testing.T) {
+	tests := []struct {
+		paths      []string
+		configPath string
+		want       []string
+	}{
+		{[]string{"a/b/coverage.out"}, "path/to/.octocov.yml", []string{"path/to/a/b/coverage.out"}},
+		{[]string{}, "path/to/.octocov.yml", []string{"path/to"}},
+		{[]string{"a/b/coverage.out"}, ".octocov.yml", []string{"a/b/coverage.out"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v", tt.paths), func(t *testing.T) {
+			c := New()
+			c.path = filepath.FromSlash(tt.configPath)
+			c.Coverage = &Coverage{
+				Paths: tt.paths,
+			}
+			c.Build()
+			got := c.Coverage.Paths
+			var want []string
+			for _, p := range tt.want {
+				want = append(want, filepath.FromSlash(p))
+			}
+			if diff := cmp.Diff(got, want, nil); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestIsBaselineTooOld(t *testing.T) {
+	tests := []struct {
+		diff      *Diff
+		timestamp time.Time
+		want      bool
+	}{
+		{nil, time.Now().Add(-24 * time.Hour), false},
+		{&Diff{}, time.Now().Add(-24 * time.Hour), false},
+		{&Diff{BaselineMaxAge: 48 * time.Hour}, time.Now().Add(-24 * time.Hour), false},
+		{&Diff{BaselineMaxAge: 12 * time.Hour}, time.Now().Add(-24 * time.Hour), true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Diff = tt.diff
+		if got := c.IsBaselineTooOld(tt.timestamp); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCoverageGoal(t *testing.T) {
+	tests := []struct {
+		cond    string
+		want    float64
+		wantErr bool
+	}{
+		{"80%", 80.0, false},
+		{"80", 80.0, false},
+		{">= 80.5%", 80.5, false},
+		{"current >= 80%", 80.0, false},
+		{"current >= 80% && diff >= 0", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &Coverage{Acceptable: CoverageAcceptable{Condition: tt.cond}}
+		got, err := c.CoverageGoal()
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCoverageBadgeColor(t *testing.T) {
+	tests := []struct {
+		colorFromDisplayed bool
+		cover              float64
+		want               string
+	}{
+		{false, 79.95, yellowgreen},
+		{true, 79.95, green},
+		{true, 79.94, yellowgreen},
+		{false, 80.0, green},
+		{true, 59.96, yellowgreen},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &Coverage{
+			Badge: CoverageBadge{
+				ColorFromDisplayed: tt.colorFromDisplayed,
+			},
+		}
+		if got := c.CoverageBadgeColor(tt.cover); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCoverageColorWithThresholds(t *testing.T) {
+	thresholds := CoverageBadgeThresholds{
+		{Min: 50, Color: green},
+		{Min: 30, Color: yellow},
+		{Min: 10, Color: red},
+	}
+	tests := []struct {
+		thresholds CoverageBadgeThresholds
+		cover      float64
+		want       string
+	}{
+		{nil, 50.0, yellow},
+		{thresholds, 50.0, green},
+		{thresholds, 49.9, yellow},
+		{thresholds, 30.0, yellow},
+		{thresholds, 10.0, red},
+		{thresholds, 5.0, red},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &Coverage{
+			Badge: CoverageBadge{
+				Thresholds: tt.thresholds,
+			},
+		}
+		if got := c.CoverageColor(tt.cover); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCodeToTestRatioColorWithThresholds(t *testing.T) {
+	thresholds := CodeToTestRatioBadgeThresholds{
+		{Min: 2.0, Color: green},
+		{Min: 1.5, Color: yellow},
+	}
+	tests := []struct {
+		thresholds CodeToTestRatioBadgeThresholds
+		ratio      float64
+		want       string
+	}{
+		{nil, 1.2, green},
+		{nil, 0.9, yellow},
+		{thresholds, 2.0, green},
+		{thresholds, 1.9, yellow},
+		{thresholds, 1.2, yellow},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.CodeToTestRatio = &CodeToTestRatio{
+			Badge: CodeToTestRatioBadge{
+				Thresholds: tt.thresholds,
+			},
+		}
+		if got := c.CodeToTestRatioColor(tt.ratio); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestTestExecutionTimeColor(t *testing.T) {
+	thresholds := TestExecutionTimeBadgeThresholds{
+		{Max: time.Minute, Color: green},
+		{Max: 5 * time.Minute, Color: yellow},
+	}
+	tests := []struct {
+		thresholds TestExecutionTimeBadgeThresholds
+		d          time.Duration
+		want       string
+	}{
+		{nil, 4 * time.Minute, green},
+		{nil, 12 * time.Minute, yellow},
+		{thresholds, 30 * time.Second, green},
+		{thresholds, time.Minute, yellow},
+		{thresholds, 10 * time.Minute, yellow},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.TestExecutionTime = &TestExecutionTime{
+			Badge: TestExecutionTimeBadge{
+				Thresholds: tt.thresholds,
+			},
+		}
+		if got := c.TestExecutionTimeColor(tt.d); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCoverageBadgePaths(t *testing.T) {
+	tests := []struct {
+		badge CoverageBadge
+		want  []string
+	}{
+		{CoverageBadge{}, nil},
+		{CoverageBadge{Path: "docs/coverage.svg"}, []string{"docs/coverage.svg"}},
+		{CoverageBadge{Paths: []string{"docs/coverage.svg", "docs/coverage.json"}}, []string{"docs/coverage.svg", "docs/coverage.json"}},
+		{CoverageBadge{Path: "docs/coverage.svg", Paths: []string{"docs/coverage.svg", "docs/coverage.json"}}, []string{"docs/coverage.svg", "docs/coverage.json"}},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &Coverage{Badge: tt.badge}
+		got := c.CoverageBadgePaths()
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestCentralRepositoryMatch(t *testing.T) {
+	tests := []struct {
+		repositories *CentralRepositories
+		repo         string
+		want         bool
+	}{
+		{nil, "owner/repo", true},
+		{&CentralRepositories{}, "owner/repo", true},
+		{&CentralRepositories{Include: []string{"owner/*"}}, "owner/repo", true},
+		{&CentralRepositories{Include: []string{"owner/*"}}, "other/repo", false},
+		{&CentralRepositories{Include: []string{"owner/*"}, Exclude: []string{"owner/repo"}}, "owner/repo", false},
+		{&CentralRepositories{Exclude: []string{"*/legacy-*"}}, "owner/legacy-app", false},
+		{&CentralRepositories{Exclude: []string{"*/legacy-*"}}, "owner/app", true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Central = &Central{Repositories: tt.repositories}
+		if got := c.CentralRepositoryMatch(tt.repo); got != tt.want {
+			t.Errorf("%s: got %v\nwant %v", tt.repo, got, tt.want)
+		}
+	}
+}
+
+func TestCoverageBadgeHeatmap(t *testing.T) {
+	tests := []struct {
+		badge    CoverageBadge
+		wantPath string
+		wantDays int
+	}{
+		{CoverageBadge{Path: "docs/coverage.svg", Heatmap: &CoverageBadgeHeatmap{Enable: true}}, "docs/coverage.heatmap.svg", 90},
+		{CoverageBadge{Path: "docs/coverage.svg", Heatmap: &CoverageBadgeHeatmap{Enable: true, Path: "docs/heatmap.svg", Days: 30}}, "docs/heatmap.svg", 30},
+		{CoverageBadge{Heatmap: &CoverageBadgeHeatmap{Enable: true, Days: 1000}}, "", 365},
+		{CoverageBadge{Path: "docs/coverage.svg"}, "", 90},
+		{CoverageBadge{Paths: []string{"docs/coverage.json", "docs/coverage.svg"}, Heatmap: &CoverageBadgeHeatmap{Enable: true}}, "docs/coverage.heatmap.svg", 90},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &Coverage{Badge: tt.badge}
+		if got := c.CoverageBadgeHeatmapPath(); got != tt.wantPath {
+			t.Errorf("got %v\nwant %v", got, tt.wantPath)
+		}
+		if got := c.CoverageBadgeHeatmapDays(); got != tt.wantDays {
+			t.Errorf("got %v\nwant %v", got, tt.wantDays)
+		}
+	}
+}
+
+func TestCoverageChartWindow(t *testing.T) {
+	tests := []struct {
+		chart *CoverageChart
+		want  int
+	}{
+		{nil, 30},
+		{&CoverageChart{Path: "docs/coverage-chart.svg"}, 30},
+		{&CoverageChart{Path: "docs/coverage-chart.svg", Window: 10}, 10},
+		{&CoverageChart{Path: "docs/coverage-chart.svg", Window: 1000}, 365},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &Coverage{Chart: tt.chart}
+		if got := c.CoverageChartWindow(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCoverageSparklineCount(t *testing.T) {
+	tests := []struct {
+		history *CoverageHistory
+		want    int
+	}{
+		{nil, 10},
+		{&CoverageHistory{Sparkline: true}, 10},
+		{&CoverageHistory{Sparkline: true, Count: 20}, 20},
+		{&CoverageHistory{Sparkline: true, Count: 1000}, 365},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &Coverage{History: tt.history}
+		if got := c.CoverageSparklineCount(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCommentKey(t *testing.T) {
+	tests := []struct {
+		comment *Comment
+		key     string
+		want    string
+	}{
+		{nil, "", ""},
+		{nil, "sub", "sub"},
+		{&Comment{Key: "linux"}, "", "linux"},
+		{&Comment{Key: "linux"}, "sub", "sub:linux"},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Comment = tt.comment
+		if got := c.CommentKey(tt.key); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCoverageBadgeLabel(t *testing.T) {
+	tests := []struct {
+		c    *Config
+		want string
+	}{
+		{&Config{}, "coverage"},
+		{&Config{Coverage: &Coverage{}}, "coverage"},
+		{&Config{Coverage: &Coverage{Badge: CoverageBadge{Label: "cov"}}}, "cov"},
+	}
+	for _, tt := range tests {
+		if got := tt.c.CoverageBadgeLabel(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCodeToTestRatioBadgeLabel(t *testing.T) {
+	tests := []struct {
+		c    *Config
+		want string
+	}{
+		{&Config{}, "code to test ratio"},
+		{&Config{CodeToTestRatio: &CodeToTestRatio{}}, "code to test ratio"},
+		{&Config{CodeToTestRatio: &CodeToTestRatio{Badge: CodeToTestRatioBadge{Label: "test ratio"}}}, "test ratio"},
+	}
+	for _, tt := range tests {
+		if got := tt.c.CodeToTestRatioBadgeLabel(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestIsCodeToTestRatioMeaningful(t *testing.T) {
+	tests := []struct {
+		c         *Config
+		codeFiles int
+		want      bool
+	}{
+		{&Config{}, 0, true},
+		{&Config{CodeToTestRatio: &CodeToTestRatio{}}, 0, true},
+		{&Config{CodeToTestRatio: &CodeToTestRatio{MinFiles: 10}}, 9, false},
+		{&Config{CodeToTestRatio: &CodeToTestRatio{MinFiles: 10}}, 10, true},
+		{&Config{CodeToTestRatio: &CodeToTestRatio{MinFiles: 10}}, 11, true},
+	}
+	for _, tt := range tests {
+		if got := tt.c.IsCodeToTestRatioMeaningful(tt.codeFiles); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCoverageAcceptable(t *testing.T) {
+	tests := []struct {
+		cond    string
+		cov     float64
+		prev    float64
+		wantErr bool
+	}{
+		{"60%", 50.0, 0, true},
+		{"50%", 50.0, 0, false},
+		{"49.9%", 50.0, 0, false},
+		{"49.9", 50.0, 0, false},
+		{">= 60%", 50.0, 0, true},
+		{">= 50%", 50.0, 0, false},
+		{">= 49.9%", 50.0, 0, false},
+		{">= 49.9", 50.0, 0, false},
+		{">=60%", 50.0, 0, true},
+		{">=50%", 50.0, 0, false},
+		{">=49.9%", 50.0, 0, false},
+		{">=49.9", 50.0, 0, false},
+
+		{"current >= 60%", 50.0, 0, true},
+		{"current > prev", 50.0, 49.0, false},
+		{"diff >= 0", 50.0, 49.0, false},
+		{"current >= 50% && diff >= 0%", 50.0, 49.0, false},
+	}
+	for _, tt := range tests {
+		if err := coverageAcceptable(tt.cov, tt.prev, tt.cond); err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+		} else {
+			if tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+			}
+		}
+	}
+}
+
+func TestMetricAcceptable(t *testing.T) {
+	tests := []struct {
+		metric  string
+		section string
+		current float64
+		prev    float64
+		cond    string
+		want    string
+	}{
+		{PolicyMetricCoverage, "policy.floor", 50.0, 0, "60%", "code coverage is 50.0%. the condition in the `policy.floor:` section is not met (`60%`)"},
+		{PolicyMetricCoverage, "policy.floor", 60.0, 0, "60%", ""},
+		{PolicyMetricCodeToTestRatio, "policy.ratio", 1.1, 0, "1:1.2", "code to test ratio is 1:1.1. the condition in the `policy.ratio:` section is not met (`1:1.2`)"},
+		{PolicyMetricTestExecutionTime, "policy.fast", float64(6 * time.Minute), largeEnoughTime, "5min", "test execution time is 6m0s. the condition in the `policy.fast:` section is not met (`5min`)"},
+		{PolicyMetricDocCoverage, "policy.doc", 40.0, 50.0, "diff >= 0", "doc coverage is 40.0%. the condition in the `policy.doc:` section is not met (`diff >= 0`)"},
+		{PolicyMetricDocCoverage, "policy.doc", 40.0, 50.0, "", ""},
+		{"unknown", "policy.unknown", 40.0, 50.0, "50%", "invalid metric: unknown"},
+	}
+	for _, tt := range tests {
+		err := metricAcceptable(tt.metric, tt.section, tt.current, tt.prev, tt.cond)
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("got %v\nwant %v", err, nil)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("got %v\nwant %v", nil, tt.want)
+			continue
+		}
+		if got := err.Error(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestToleratedViolations(t *testing.T) {
+	violations := criticalCoverageAcceptable(map[string]float64{"a.go": 50.0, "b.go": 60.0, "c.go": 100.0}, nil, "")
+	tests := []struct {
+		err           error
+		maxViolations int
+		wantTolerated int
+		wantOK        bool
+	}{
+		{nil, 0, 0, true},
+		{violations, 0, 0, false},
+		{violations, 1, 0, false},
+		{violations, 2, 2, true},
+		{violations, 3, 2, true},
+		{errors.New("invalid condition"), 3, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := toleratedViolations(tt.err, tt.maxViolations)
+		if ok != tt.wantOK {
+			t.Errorf("got %v\nwant %v", ok, tt.wantOK)
+		}
+		if len(got) != tt.wantTolerated {
+			t.Errorf("got %v\nwant %v", len(got), tt.wantTolerated)
+		}
+	}
+}
+
+func TestAcceptableFailures(t *testing.T) {
+	warning := &AcceptableWarning{Err: errors.New("code coverage is 70.0%")}
+	failure := errors.New("code to test ratio is 1:0.5")
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{warning, 0},
+		{failure, 1},
+		{multierror.Append(nil, warning), 0},
+		{multierror.Append(nil, warning, failure), 1},
+		{multierror.Append(nil, failure, failure), 2},
+	}
+	for _, tt := range tests {
+		err := AcceptableFailures(tt.err)
+		got := 0
+		if err != nil {
+			got = 1
+			var merr *multierror.Error
+			if errors.As(err, &merr) {
+				got = len(merr.Errors)
+			}
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+		if err != nil && IsAcceptableWarning(err) {
+			t.Errorf("got warning %v\nwant failures only", err)
+		}
+	}
+}
+
+func TestOrgMedianCoverage(t *testing.T) {
+	tests := []struct {
+		coverages []float64
+		current   float64
+		want      float64
+		wantOK    bool
+		wantErr   bool
+	}{
+		{nil, 50.0, 0, false, false},
+		{[]float64{70.0, 50.0, 60.0}, 60.0, 60.0, true, false},
+		{[]float64{70.0, 50.0, 60.0, 90.0}, 60.0, 65.0, true, true},
+		{[]float64{40.0}, 39.9, 40.0, true, true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.SetOrgCoverages(tt.coverages)
+		got, ok := c.orgMedianCoverage()
+		if ok != tt.wantOK {
+			t.Errorf("got %v\nwant %v", ok, tt.wantOK)
+		}
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+		if !ok {
+			continue
+		}
+		if err := orgMedianAcceptable(tt.current, got); (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+		}
+	}
+}
+
+func TestAcceptableWarnMode(t *testing.T) {
+	tests := []struct {
+		name         string
+		acceptable   CoverageAcceptable
+		tolerance    string
+		wantErr      bool
+		wantFailures bool
+	}{
+		{"acceptable", CoverageAcceptable{Condition: "current >= 40%"}, "", false, false},
+		{"unacceptable", CoverageAcceptable{Condition: "current >= 60%"}, "", true, false},
+		{"invalid condition", CoverageAcceptable{Condition: "current >="}, "", true, true},
+		{"invalid acceptableDiffTolerance", CoverageAcceptable{Condition: "current >= 60%"}, "-1%", true, true},
+		{"invalid relativeTo", CoverageAcceptable{Condition: "current >= 40%", RelativeTo: "invalid"}, "", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			tt.acceptable.Mode = CoverageAcceptableModeWarn
+			c.Coverage = &Coverage{
+				Paths:                   []string{"coverage.out"},
+				Acceptable:              tt.acceptable,
+				AcceptableDiffTolerance: tt.tolerance,
+			}
+			current := 50.0
+			prev := 50.0
+			err := c.Acceptable(context.Background(), &testReporter{coverage: &current}, &testReporter{coverage: &prev})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+			if got := AcceptableFailures(err); (got != nil) != tt.wantFailures {
+				t.Errorf("got %v\nwantFailures %v", got, tt.wantFailures)
+			}
+		})
+	}
+}
+
+func TestCoverageAcceptableByEndpoint(t *testing.T) {
+	prev := 40.0
+	tests := []struct {
+		status   int
+		body     string
+		failOpen bool
+		prev     *float64
+		wantErr  bool
+	}{
+		{http.StatusOK, `{"pass": true}`, false, &prev, false},
+		{http.StatusOK, `{"pass": true}`, false, nil, false},
+		{http.StatusOK, `{"pass": false, "message": "coverage must be >= 80%"}`, false, &prev, true},
+		{http.StatusOK, `{"pass": false, "message": "coverage must be >= 80%"}`, true, &prev, true},
+		{http.StatusInternalServerError, `error`, false, &prev, true},
+		{http.StatusInternalServerError, `error`, true, &prev, false},
+		{http.StatusOK, `invalid`, true, &prev, false},
+	}
+	for _, tt := range tests {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body := map[string]any{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			want := map[string]any{"repository": "owner/repo", "metric": "coverage", "current": 50.0}
+			if tt.prev != nil {
+				want["prev"] = 40.0
+				want["diff"] = 10.0
+			}
+			if diff := cmp.Diff(body, want, nil); diff != "" {
+				t.Error(diff)
+			}
+			w.WriteHeader(tt.status)
+			_, _ = w.Write([]byte(tt.body))
+		}))
+		c := New()
+		c.Repository = "owner/central"
+		c.Coverage = &Coverage{
+			Acceptable: CoverageAcceptable{
+				Endpoint: ts.URL,
+				FailOpen: tt.failOpen,
+			},
+		}
+		current := 50.0
+		err := c.coverageAcceptableByEndpoint(context.Background(), &testReporter{coverage: &current, repository: "owner/repo"}, tt.prev)
+		ts.Close()
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+		} else {
+			if tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+			}
+		}
+	}
+}
+
+func TestCoverageAcceptableByEndpointDryRun(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the endpoint should not be requested with --dry-run")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+	c := New()
+	c.Coverage = &Coverage{
+		Acceptable: CoverageAcceptable{
+			Endpoint: ts.URL,
+		},
+	}
+	c.SetDryRun(true)
+	current := 50.0
+	if err := c.coverageAcceptableByEndpoint(context.Background(), &testReporter{coverage: &current}, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCoverageAcceptableByEndpointCentral(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the endpoint should not be requested in central mode")
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+	c := New()
+	c.Central = &Central{}
+	c.Coverage = &Coverage{
+		Paths: []string{"coverage.out"},
+		Acceptable: CoverageAcceptable{
+			Endpoint: ts.URL,
+		},
+	}
+	current := 50.0
+	prev := 40.0
+	if err := c.Acceptable(context.Background(), &testReporter{coverage: &current}, &testReporter{coverage: &prev}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLabelCoverageAcceptable(t *testing.T) {
+	tests := []struct {
+		cond    string
+		cov     float64
+		prev    float64
+		wantErr bool
+	}{
+		{"", 50.0, 0, false},
+		{"60%", 50.0, 0, true},
+		{"50%", 50.0, 0, false},
+		{"diff >= 0", 50.0, 49.0, false},
+	}
+	for _, tt := range tests {
+		if err := labelCoverageAcceptable("api", tt.cov, tt.prev, tt.cond); err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
 		} else {
 			if tt.wantErr {
 				t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
@@ -49,148 +1591,618 @@ func TestLoad(t *testing.T) {
 	}
 }
 
-func TestLoadComment(t *testing.T) {
+func TestPathCoverageAcceptable(t *testing.T) {
+	tests := []struct {
+		cond    string
+		cov     float64
+		prev    float64
+		wantErr bool
+	}{
+		{"", 50.0, 0, false},
+		{"current >= 90%", 85.0, 0, true},
+		{"90%", 90.0, 0, false},
+		{"diff >= 0", 50.0, 51.0, true},
+	}
+	for _, tt := range tests {
+		if err := pathCoverageAcceptable("core/**", tt.cov, tt.prev, tt.cond); (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+		}
+	}
+}
+
+type pathsReporter struct {
+	Reporter
+	percents  map[string]float64
+	truncated bool
+}
+
+func (r *pathsReporter) CoveragePercent() float64 { return 80.0 }
+
+func (r *pathsReporter) CoveragePercentOf(pattern string) float64 { return r.percents[pattern] }
+
+func (r *pathsReporter) FileCoveragePercentsOf(patterns []string) map[string]float64 {
+	if v, ok := r.percents[patterns[0]]; ok {
+		return map[string]float64{patterns[0]: v}
+	}
+	return nil
+}
+
+func (r *pathsReporter) IsMeasuredCoverage() bool { return true }
+
+func (r *pathsReporter) IsMeasuredUncoveredFuncs() bool { return false }
+
+func (r *pathsReporter) IsFilesTruncated() bool { return r.truncated }
+
+func TestAcceptablePaths(t *testing.T) {
+	c := New()
+	c.Coverage = &Coverage{
+		Paths: []string{"coverage.out"},
+		Acceptable: CoverageAcceptable{
+			Condition: "current >= 60%",
+			Paths: []*CoverageAcceptablePath{
+				{Path: "core/**", Condition: "current >= 90%"},
+				{Path: "api/**", Condition: "current >= 90%"},
+				{Path: "experimental/**", Condition: "current >= 50%"},
+				{Path: "missing/**", Condition: "current >= 50%"},
+			},
+		},
+	}
+	r := &pathsReporter{percents: map[string]float64{"core/**": 85.0, "api/**": 70.0, "experimental/**": 55.0}}
+	rPrev := &pathsReporter{percents: map[string]float64{}}
+	err := c.Acceptable(context.Background(), r, rPrev)
+	var merr *multierror.Error
+	if !errors.As(err, &merr) {
+		t.Fatalf("got %v\nwant multierror", err)
+	}
+	if got := len(merr.Errors); got != 2 {
+		t.Errorf("got %v\nwant %v: %v", got, 2, err)
+	}
+	for _, w := range []string{"code coverage of core/** is 85.0%", "code coverage of api/** is 70.0%"} {
+		if !strings.Contains(err.Error(), w) {
+			t.Errorf("got %v\nwant containing %q", err, w)
+		}
+	}
+}
+
+func TestAcceptableTruncatedPrev(t *testing.T) {
+	c := New()
+	c.Coverage = &Coverage{
+		Paths: []string{"coverage.out"},
+		Labels: map[string]*CoverageLabel{
+			"core": {Path: "core/**", Acceptable: "diff >= 0"},
+		},
+		Acceptable: CoverageAcceptable{
+			Paths: []*CoverageAcceptablePath{
+				{Path: "api/**", Condition: "diff >= 0"},
+			},
+		},
+	}
+	r := &pathsReporter{percents: map[string]float64{"core/**": 85.0, "api/**": 70.0}}
+
+	// The previous report keeping only the worst covered files has skewed file coverages
+	rPrev := &pathsReporter{percents: map[string]float64{"core/**": 90.0, "api/**": 75.0}}
+	if err := c.Acceptable(context.Background(), r, rPrev); err == nil {
+		t.Error("got nil\nwant the errors of the delta conditions")
+	}
+	rPrev.truncated = true
+	if err := c.Acceptable(context.Background(), r, rPrev); err != nil {
+		t.Errorf("got %v\nwant nil", err)
+	}
+}
+
+type linesReporter struct {
+	Reporter
+	total int
+}
+
+func (r *linesReporter) CoveragePercent() float64 { return 50.0 }
+
+func (r *linesReporter) CoverageTotal() int { return r.total }
+
+func (r *linesReporter) IsMeasuredCoverage() bool { return true }
+
+func (r *linesReporter) IsFilesTruncated() bool { return false }
+
+func TestAcceptableMinLines(t *testing.T) {
+	tests := []struct {
+		minLines int
+		total    int
+		wantErr  bool
+	}{
+		{0, 10, true},
+		{200, 199, false},
+		{200, 200, true},
+		{200, 1000, true},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &Coverage{
+			Paths:              []string{"coverage.out"},
+			Acceptable:         CoverageAcceptable{Condition: "80%"},
+			AcceptableMinLines: tt.minLines,
+		}
+		err := c.Acceptable(context.Background(), &linesReporter{total: tt.total}, &linesReporter{})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("minLines %d, total %d: got %v\nwantErr %v", tt.minLines, tt.total, err, tt.wantErr)
+		}
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+type uncoveredFilesReporter struct {
+	Reporter
+	files []string
+}
+
+func (r *uncoveredFilesReporter) CoveragePercent() float64 { return 50.0 }
+
+func (r *uncoveredFilesReporter) IsMeasuredCoverage() bool { return true }
+
+func (r *uncoveredFilesReporter) IsFilesTruncated() bool { return false }
+
+func (r *uncoveredFilesReporter) UncoveredFiles() []string { return r.files }
+
+func TestAcceptableMaxUncoveredFiles(t *testing.T) {
+	tests := []struct {
+		maxFiles *int
+		files    []string
+		wantErr  string
+	}{
+		{nil, []string{"a.go", "b.go"}, ""},
+		{intPtr(2), []string{"a.go", "b.go"}, ""},
+		{intPtr(1), []string{"a.go", "b.go"}, "uncovered files are 2. the condition in the `coverage.acceptableMaxUncoveredFiles:` section is not met (`<= 1`): a.go, b.go"},
+		{intPtr(0), nil, ""},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &Coverage{
+			Paths:                       []string{"coverage.out"},
+			Acceptable:                  CoverageAcceptable{Condition: "current >= 10%"},
+			AcceptableMaxUncoveredFiles: tt.maxFiles,
+		}
+		err := c.Acceptable(context.Background(), &uncoveredFilesReporter{files: tt.files}, &uncoveredFilesReporter{})
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("got %v\nwant no error", err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("got %v\nwant %v", err, tt.wantErr)
+		}
+	}
+}
+
+func TestEachFileCoverageAcceptable(t *testing.T) {
 	tests := []struct {
-		path string
-		want *Comment
+		cond    string
+		cov     map[string]float64
+		prev    map[string]float64
+		wantErr []string
 	}{
-		{"comment_enabled_octocov.yml", &Comment{}},
-		{"comment_enabled_octocov2.yml", &Comment{If: "is_pull_request"}},
-		{"comment_disabled_octocov.yml", nil},
+		{"current >= 50%", map[string]float64{}, map[string]float64{}, nil},
+		{"current >= 50%", map[string]float64{"a.go": 50.0, "b.go": 100.0}, map[string]float64{}, nil},
+		{"current >= 50%", map[string]float64{"a.go": 49.9, "b.go": 100.0, "c.go": 10.0}, map[string]float64{}, []string{"a.go", "c.go"}},
+		{"diff >= 0", map[string]float64{"a.go": 80.0, "b.go": 80.0}, map[string]float64{"a.go": 90.0}, []string{"a.go"}},
 	}
 	for _, tt := range tests {
-		c := New()
-		p := filepath.Join(testdataDir(t), tt.path)
-		if err := c.Load(p); err != nil {
-			t.Fatal(err)
+		err := eachFileCoverageAcceptable(tt.cov, tt.prev, tt.cond)
+		if err == nil {
+			if len(tt.wantErr) > 0 {
+				t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+			}
+			continue
 		}
-		got := c.Comment
-		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
-			t.Error(diff)
+		var merr *multierror.Error
+		if !errors.As(err, &merr) {
+			t.Fatalf("got %v\nwant multierror", err)
+		}
+		if got := len(merr.Errors); got != len(tt.wantErr) {
+			t.Errorf("got %v\nwant %v", got, len(tt.wantErr))
+		}
+		for i, f := range tt.wantErr {
+			if want := "code coverage of " + f + " is"; i < len(merr.Errors) && !strings.HasPrefix(merr.Errors[i].Error(), want) {
+				t.Errorf("got %v\nwant prefix %q", merr.Errors[i], want)
+			}
 		}
 	}
+	if err := eachFileCoverageAcceptable(map[string]float64{"a.go": 50.0}, nil, "current >="); err == nil {
+		t.Error("want error")
+	}
 }
 
-func TestLoadCentralPush(t *testing.T) {
+func TestImprovementAcceptable(t *testing.T) {
 	tests := []struct {
-		path string
-		want *Push
+		delta   string
+		cov     float64
+		prev    float64
+		wantErr bool
 	}{
-		{"central_push_enabled_octocov.yml", &Push{}},
-		{"central_push_enabled_octocov2.yml", &Push{If: "is_default_branch"}},
-		{"central_push_disabled_octocov.yml", nil},
+		{"+2%", 52.0, 50.0, false},
+		{"+2%", 51.9, 50.0, true},
+		{"2", 52.0, 50.0, false},
+		{"+2%", 50.0, 50.0, true},
+		{"+2%", 100.0, 99.0, false},
+		{"+0.5%", 50.5, 50.0, false},
+		{"two", 52.0, 50.0, true},
 	}
 	for _, tt := range tests {
-		c := New()
-		p := filepath.Join(testdataDir(t), tt.path)
-		if err := c.Load(p); err != nil {
-			t.Fatal(err)
+		if err := improvementAcceptable("legacy/**", tt.cov, tt.prev, tt.delta); (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
 		}
-		got := c.Central.Push
-		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
-			t.Error(diff)
+	}
+}
+
+func TestRatchetAcceptable(t *testing.T) {
+	tests := []struct {
+		current   float64
+		hwm       float64
+		tolerance string
+		wantErr   bool
+	}{
+		{80.0, 80.0, "", false},
+		{80.1, 80.0, "", false},
+		{79.9, 80.0, "", true},
+		{79.5, 80.0, "0.5%", false},
+		{79.4, 80.0, "0.5%", true},
+		{79.5, 80.0, "0.5", false},
+		{80.0, 80.0, "foo", true},
+	}
+	for _, tt := range tests {
+		if err := ratchetAcceptable(tt.current, tt.hwm, tt.tolerance); (err != nil) != tt.wantErr {
+			t.Errorf("ratchetAcceptable(%v, %v, %q): got %v\nwantErr %v", tt.current, tt.hwm, tt.tolerance, err, tt.wantErr)
 		}
 	}
 }
 
-func TestLoadLocale(t *testing.T) {
+func TestAcceptableDiffTolerance(t *testing.T) {
 	tests := []struct {
-		path      string
-		want      *language.Tag
-		wantError bool
+		current   float64
+		prev      float64
+		tolerance string
+		wantErr   bool
 	}{
-		{"locale_nothing.yml", nil, false},
-		{"locale_empty.yml", nil, false},
-		{"locale_ja.yml", &language.Japanese, false},
-		{"locale_ja_uppercase.yml", &language.Japanese, false},
-		{"locale_fr.yml", &language.French, false},
-		{"locale_unkown.yml", nil, true},
+		{80.0, 80.1, "", true},
+		{80.0, 80.1, "0.2%", false},
+		{79.9, 80.1, "0.2%", false},
+		{79.8, 80.1, "0.2%", true},
+		{80.2, 80.1, "0.2%", false},
+		{80.0, 80.1, "foo", true},
 	}
 	for _, tt := range tests {
 		c := New()
-		t.Run(fmt.Sprintf("%v", tt.path), func(t *testing.T) {
-			p := filepath.Join(testdataDir(t), tt.path)
-			if err := c.Load(p); err != nil {
-				if tt.wantError {
-					return
-				}
-				t.Fatal(err)
-			}
-			got := c.Locale
-			if tt.want == nil && got == nil {
-				return
-			}
-			if diff := cmp.Diff(got.String(), tt.want.String(), nil); diff != "" {
-				t.Error(diff)
-			}
-		})
+		c.Coverage = &Coverage{
+			Paths:                   []string{"coverage.out"},
+			Acceptable:              CoverageAcceptable{Condition: "diff >= 0%"},
+			AcceptableDiffTolerance: tt.tolerance,
+		}
+		current, prev := tt.current, tt.prev
+		if err := c.Acceptable(context.Background(), &testReporter{coverage: &current}, &testReporter{coverage: &prev}); (err != nil) != tt.wantErr {
+			t.Errorf("current %v, prev %v, tolerance %q: got %v\nwantErr %v", tt.current, tt.prev, tt.tolerance, err, tt.wantErr)
+		}
 	}
 }
 
-func TestCoveragePaths(t *testing.T) {
+type ratchetReporter struct {
+	pathsReporter
+	hwm *float64
+}
+
+func (r *ratchetReporter) CoverageHighWaterMarkPercent() (float64, bool) {
+	if r.hwm == nil {
+		return 0, false
+	}
+	return *r.hwm, true
+}
+
+func TestAcceptableRatchet(t *testing.T) {
+	c := New()
+	c.Coverage = &Coverage{
+		Paths:      []string{"coverage.out"},
+		Acceptable: CoverageAcceptable{Ratchet: &CoverageRatchet{}},
+	}
+	r := &ratchetReporter{}
+	hwm80 := 80.0
+	hwm85 := 85.0
+
+	// The first run passes and establishes the high-water mark
+	if err := c.Acceptable(context.Background(), r, &ratchetReporter{}); err != nil {
+		t.Errorf("got %v\nwant nil", err)
+	}
+
+	if err := c.Acceptable(context.Background(), r, &ratchetReporter{hwm: &hwm80}); err != nil {
+		t.Errorf("got %v\nwant nil", err)
+	}
+
+	if err := c.Acceptable(context.Background(), r, &ratchetReporter{hwm: &hwm85}); err == nil || !strings.Contains(err.Error(), "highest code coverage ever recorded (85.0%)") {
+		t.Errorf("got %v\nwant the error of coverage.acceptable.ratchet", err)
+	}
+
+	// The high-water mark taken over by the report is used when the baseline is dropped
+	if err := c.Acceptable(context.Background(), &ratchetReporter{hwm: &hwm85}, &ratchetReporter{}); err == nil || !strings.Contains(err.Error(), "highest code coverage ever recorded (85.0%)") {
+		t.Errorf("got %v\nwant the error of coverage.acceptable.ratchet", err)
+	}
+}
+
+type patchReporter struct {
+	pathsReporter
+	patch *float64
+}
+
+func (r *patchReporter) IsMeasuredPatchCoverage() bool { return r.patch != nil }
+
+func (r *patchReporter) PatchCoveragePercent() float64 { return *r.patch }
+
+func TestAcceptablePatch(t *testing.T) {
+	c := New()
+	c.Coverage = &Coverage{
+		Paths:      []string{"coverage.out"},
+		Acceptable: CoverageAcceptable{Condition: "patch >= 80%"},
+	}
+	patch79 := 79.9
+	patch80 := 80.0
 	tests := []struct {
-		paths      []string
-		configPath string
-		want       []string
+		patch   *float64
+		wantErr bool
 	}{
-		{[]string{"a/b/coverage.out"}, "path/to/.octocov.yml", []string{"path/to/a/b/coverage.out"}},
-		{[]string{}, "path/to/.octocov.yml", []string{"path/to"}},
-		{[]string{"a/b/coverage.out"}, ".octocov.yml", []string{"a/b/coverage.out"}},
+		{nil, false},
+		{&patch80, false},
+		{&patch79, true},
 	}
 	for _, tt := range tests {
-		t.Run(fmt.Sprintf("%v", tt.paths), func(t *testing.T) {
-			c := New()
-			c.path = filepath.FromSlash(tt.configPath)
-			c.Coverage = &Coverage{
-				Paths: tt.paths,
-			}
-			c.Build()
-			got := c.Coverage.Paths
-			var want []string
-			for _, p := range tt.want {
-				want = append(want, filepath.FromSlash(p))
-			}
-			if diff := cmp.Diff(got, want, nil); diff != "" {
-				t.Error(diff)
-			}
-		})
+		if err := c.Acceptable(context.Background(), &patchReporter{patch: tt.patch}, &patchReporter{}); (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+		}
 	}
 }
 
-func TestCoverageAcceptable(t *testing.T) {
+func TestPatchCoverageAcceptable(t *testing.T) {
 	tests := []struct {
 		cond    string
 		cov     float64
-		prev    float64
+		patch   float64
 		wantErr bool
 	}{
-		{"60%", 50.0, 0, true},
-		{"50%", 50.0, 0, false},
-		{"49.9%", 50.0, 0, false},
-		{"49.9", 50.0, 0, false},
-		{">= 60%", 50.0, 0, true},
-		{">= 50%", 50.0, 0, false},
-		{">= 49.9%", 50.0, 0, false},
-		{">= 49.9", 50.0, 0, false},
-		{">=60%", 50.0, 0, true},
-		{">=50%", 50.0, 0, false},
-		{">=49.9%", 50.0, 0, false},
-		{">=49.9", 50.0, 0, false},
+		{"patch >= 80%", 50.0, 80.0, false},
+		{"patch >= 80%", 90.0, 79.9, true},
+		{"current >= 60% && patch >= 80", 60.0, 100.0, false},
+		{"current >= 60% && patch >= 80", 59.9, 100.0, true},
+		{"patch >=", 50.0, 80.0, true},
+	}
+	for _, tt := range tests {
+		if err := patchCoverageAcceptable(tt.cov, 0, tt.patch, tt.cond); (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+		}
+	}
+}
 
-		{"current >= 60%", 50.0, 0, true},
-		{"current > prev", 50.0, 49.0, false},
-		{"diff >= 0", 50.0, 49.0, false},
-		{"current >= 50% && diff >= 0%", 50.0, 49.0, false},
+func TestPatchCoverageCountsMissingFiles(t *testing.T) {
+	tests := []struct {
+		c    *Config
+		want bool
+	}{
+		{&Config{}, false},
+		{&Config{Coverage: &Coverage{}}, false},
+		{&Config{Coverage: &Coverage{Patch: &CoveragePatch{}}}, false},
+		{&Config{Coverage: &Coverage{Patch: &CoveragePatch{MissingFiles: CoveragePatchMissingFilesSkip}}}, false},
+		{&Config{Coverage: &Coverage{Patch: &CoveragePatch{MissingFiles: CoveragePatchMissingFilesUncovered}}}, true},
 	}
 	for _, tt := range tests {
-		if err := coverageAcceptable(tt.cov, tt.prev, tt.cond); err != nil {
-			if !tt.wantErr {
-				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
-			}
-		} else {
-			if tt.wantErr {
+		if got := tt.c.PatchCoverageCountsMissingFiles(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestUncoveredFuncsAcceptable(t *testing.T) {
+	tests := []struct {
+		current  int
+		maxFuncs int
+		wantErr  bool
+	}{
+		{0, 0, false},
+		{1, 0, true},
+		{50, 50, false},
+		{51, 50, true},
+	}
+	for _, tt := range tests {
+		if err := uncoveredFuncsAcceptable(tt.current, tt.maxFuncs); (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+		}
+	}
+}
+
+func TestCriticalCoverageAcceptable(t *testing.T) {
+	tests := []struct {
+		cond    string
+		cov     map[string]float64
+		prev    map[string]float64
+		wantErr int
+	}{
+		{"", map[string]float64{}, map[string]float64{}, 0},
+		{"", map[string]float64{"auth/a.go": 100.0, "crypto/b.go": 100.0}, map[string]float64{}, 0},
+		{"", map[string]float64{"auth/a.go": 99.9, "crypto/b.go": 50.0}, map[string]float64{}, 2},
+		{"90%", map[string]float64{"auth/a.go": 99.9, "crypto/b.go": 50.0}, map[string]float64{}, 1},
+		{"diff >= 0", map[string]float64{"auth/a.go": 80.0}, map[string]float64{"auth/a.go": 90.0}, 1},
+	}
+	for _, tt := range tests {
+		err := criticalCoverageAcceptable(tt.cov, tt.prev, tt.cond)
+		if err == nil {
+			if tt.wantErr > 0 {
 				t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
 			}
+			continue
+		}
+		merr, ok := err.(*multierror.Error) //nolint:errorlint
+		if !ok {
+			t.Fatalf("failed to convert error to multierror: %v", err)
+		}
+		if got := len(merr.Errors); got != tt.wantErr {
+			t.Errorf("got %v\nwant %v", got, tt.wantErr)
+		}
+	}
+}
+
+func TestCoveragePrev(t *testing.T) {
+	tests := []struct {
+		window  int
+		history []float64
+		prev    float64
+		want    float64
+	}{
+		{0, nil, 80.0, 80.0},
+		{0, []float64{80.0, 70.0, 75.0}, 80.0, 80.0},
+		{3, []float64{80.0, 70.0, 75.0}, 80.0, 75.0},
+		{3, []float64{80.0, 70.0, 75.0, 100.0}, 80.0, 75.0},
+		{5, []float64{80.0, 70.0}, 80.0, 75.0},
+		{5, nil, 80.0, 80.0},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Coverage = &Coverage{Acceptable: CoverageAcceptable{Condition: "current >= prev", Window: tt.window}}
+		c.SetCoverageHistory(tt.history)
+		if got := c.coveragePrev(tt.prev); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestIsNetDeletionPullRequest(t *testing.T) {
+	t.Setenv("GITHUB_EVENT_NAME", "pull_request")
+	t.Setenv("GITHUB_EVENT_PATH", filepath.Join(rootTestdataDir(t), "config", "event_pull_request_opened.json"))
+	t.Setenv("GITHUB_REF", "refs/pull/4/merge")
+	t.Setenv("GITHUB_TOKEN", "token")
+
+	tests := []struct {
+		additions int
+		deletions int
+		want      bool
+	}{
+		{10, 100, true},
+		{100, 10, false},
+		{10, 10, false},
+	}
+	for _, tt := range tests {
+		mockedHTTPClient := mock.NewMockedHTTPClient( //nostyle:funcfmt
+			mock.WithRequestMatch( //nostyle:funcfmt
+				mock.GetReposPullsByOwnerByRepoByPullNumber,
+				github.PullRequest{
+					Number:    github.Int(4),
+					Additions: github.Int(tt.additions),
+					Deletions: github.Int(tt.deletions),
+				},
+			),
+		)
+		client, err := factory.NewGithubClient(factory.HTTPClient(mockedHTTPClient), factory.Timeout(10*time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		g, err := gh.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		g.SetClient(client)
+		c := &Config{
+			Repository: "owner/repo",
+			gh:         g,
+		}
+		if got := c.isNetDeletionPullRequest(context.Background()); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestAcceptableSkipRegression(t *testing.T) {
+	c := New()
+	c.Coverage = &Coverage{
+		Paths: []string{"coverage.out"},
+		Labels: map[string]*CoverageLabel{
+			"core": {Path: "core/**", Acceptable: "diff >= 0"},
+		},
+		Acceptable: CoverageAcceptable{
+			Paths: []*CoverageAcceptablePath{
+				{Path: "api/**", Condition: "current >= prev"},
+				{Path: "experimental/**", Condition: "current >= 50%"},
+			},
+			EachFile:            "diff >= 0",
+			AllowSkipRegression: true,
+		},
+		Critical: &CoverageCritical{
+			Paths:      []string{"core/**"},
+			Acceptable: "diff >= 0",
+		},
+	}
+	r := &pathsReporter{percents: map[string]float64{"core/**": 85.0, "api/**": 70.0, "experimental/**": 40.0, "**": 60.0}}
+	rPrev := &pathsReporter{percents: map[string]float64{"core/**": 90.0, "api/**": 75.0, "experimental/**": 40.0, "**": 65.0}}
+
+	err := c.Acceptable(context.Background(), r, rPrev)
+	var merr *multierror.Error
+	if !errors.As(err, &merr) {
+		t.Fatalf("got %v\nwant multierror", err)
+	}
+	if got := len(merr.Errors); got != 5 {
+		t.Errorf("got %v\nwant %v: %v", got, 5, err)
+	}
+
+	p := filepath.Join(t.TempDir(), "event.json")
+	if err := os.WriteFile(p, []byte(`{"pull_request": {"labels": [{"name": "octocov:skip-regression"}]}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_EVENT_NAME", "pull_request")
+	t.Setenv("GITHUB_EVENT_PATH", p)
+
+	// Only the condition not based on delta is checked
+	err = c.Acceptable(context.Background(), r, rPrev)
+	if !errors.As(err, &merr) {
+		t.Fatalf("got %v\nwant multierror", err)
+	}
+	if got := len(merr.Errors); got != 1 || !strings.Contains(err.Error(), "code coverage of experimental/** is 40.0%") {
+		t.Errorf("got %v\nwant only the error of experimental/**", err)
+	}
+}
+
+func TestPolicyAcceptableSkipRegression(t *testing.T) {
+	c := New()
+	c.Policy = []*Policy{
+		{Name: "noRegression", Metric: PolicyMetricCoverage, Acceptable: "diff >= 0"},
+		{Name: "floor", Metric: PolicyMetricCoverage, Acceptable: "current >= 70%"},
+	}
+	current := 60.0
+	prev := 65.0
+	r := &testReporter{coverage: &current}
+	rPrev := &testReporter{coverage: &prev}
+	if got := len(c.policyAcceptable(r, rPrev, false)); got != 2 {
+		t.Errorf("got %v\nwant %v", got, 2)
+	}
+	// Only the policy not based on delta is checked
+	errs := c.policyAcceptable(r, rPrev, true)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "policy.floor") {
+		t.Errorf("got %v\nwant only the error of policy.floor", errs)
+	}
+}
+
+func TestSkipRegressionRequested(t *testing.T) {
+	tests := []struct {
+		payload string
+		want    bool
+	}{
+		{`{"pull_request": {"title": "Remove legacy tests", "labels": [{"name": "octocov:skip-regression"}]}}`, true},
+		{`{"pull_request": {"title": "Remove legacy tests [octocov skip-regression]", "labels": []}}`, true},
+		{`{"pull_request": {"title": "Remove legacy tests", "body": "Intended drop.\n\n[octocov skip-regression]"}}`, true},
+		{`{"pull_request": {"title": "Remove legacy tests", "labels": [{"name": "bug"}]}}`, false},
+		{`{"head_commit": {"message": "Remove legacy tests [octocov skip-regression]"}}`, true},
+		{`{"commits": [{"message": "Fix typo"}, {"message": "Remove legacy tests\n\n[octocov skip-regression]"}]}`, true},
+		{`{"commits": [{"message": "Remove legacy tests (octocov skip-regression)"}]}`, false},
+		{`[]`, false},
+	}
+	for _, tt := range tests {
+		var payload any
+		if err := json.Unmarshal([]byte(tt.payload), &payload); err != nil {
+			t.Fatal(err)
+		}
+		if got := skipRegressionRequested(payload); got != tt.want {
+			t.Errorf("%s: got %v\nwant %v", tt.payload, got, tt.want)
 		}
 	}
 }
@@ -276,6 +2288,32 @@ func TestTestExecutionTimeAcceptable(t *testing.T) {
 	}
 }
 
+func TestDocCoverageAcceptable(t *testing.T) {
+	tests := []struct {
+		cond    string
+		cov     float64
+		prev    float64
+		wantErr bool
+	}{
+		{"60%", 50.0, 0, true},
+		{"50%", 50.0, 0, false},
+		{">= 49.9", 50.0, 0, false},
+		{"current > prev", 50.0, 49.0, false},
+		{"diff >= 0", 50.0, 51.0, true},
+	}
+	for _, tt := range tests {
+		if err := docCoverageAcceptable(tt.cov, tt.prev, tt.cond); err != nil {
+			if !tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", err, tt.wantErr)
+			}
+		} else {
+			if tt.wantErr {
+				t.Errorf("got %v\nwantErr %v", nil, tt.wantErr)
+			}
+		}
+	}
+}
+
 func revertEnv(envCache []string) error {
 	if err := clearEnv(); err != nil {
 		return err
@@ -311,3 +2349,41 @@ func rootTestdataDir(t *testing.T) string {
 	}
 	return dir
 }
+
+type testReporter struct {
+	Reporter
+	coverage   *float64
+	ratio      *float64
+	repository string
+}
+
+func (r *testReporter) IsMeasuredCoverage() bool          { return r.coverage != nil }
+func (r *testReporter) CoveragePercent() float64          { return *r.coverage }
+func (r *testReporter) IsMeasuredCodeToTestRatio() bool   { return r.ratio != nil }
+func (r *testReporter) CodeToTestRatioRatio() float64     { return *r.ratio }
+func (r *testReporter) IsMeasuredTestExecutionTime() bool { return false }
+func (r *testReporter) IsMeasuredDocCoverage() bool       { return false }
+func (r *testReporter) RepositoryName() string            { return r.repository }
+func (r *testReporter) IsFilesTruncated() bool            { return false }
+
+func TestReportVariables(t *testing.T) {
+	cov := 85.5
+	ratio := 1.2
+	tests := []struct {
+		r    Reporter
+		want map[string]any
+	}{
+		{nil, map[string]any{}},
+		{&testReporter{coverage: &cov}, map[string]any{"coverage": 85.5}},
+		{&testReporter{coverage: &cov, ratio: &ratio}, map[string]any{"coverage": 85.5, "code_to_test_ratio": 1.2}},
+	}
+	for _, tt := range tests {
+		c := New()
+		if tt.r != nil {
+			c.SetReport(tt.r)
+		}
+		if diff := cmp.Diff(c.reportVariables(), tt.want, nil); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var coverageNameRe = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// CoverageConfigs returns the configs to be measured and reported separately, one for each named coverage config of coverage: given as a list.
+// The repository of each config is suffixed with the name (e.g. owner/repo/unit), so that the report is stored under its own key in the datastores and commented separately.
+// If coverage: is not a list, it returns the config itself. It should be called after Build().
+func (c *Config) CoverageConfigs() ([]*Config, error) {
+	if len(c.Coverages) == 0 {
+		return []*Config{c}, nil
+	}
+	if err := c.validateSingleTargetReports(); err != nil {
+		return nil, err
+	}
+	names := map[string]struct{}{}
+	configs := make([]*Config, 0, len(c.Coverages))
+	for i, cov := range c.Coverages {
+		switch {
+		case cov.Name == "":
+			return nil, fmt.Errorf("coverage[%d].name: is not set", i)
+		case !coverageNameRe.MatchString(cov.Name):
+			return nil, fmt.Errorf("coverage[%d].name: invalid name (%s)", i, cov.Name)
+		}
+		if _, ok := names[cov.Name]; ok {
+			return nil, fmt.Errorf("coverage[%d].name: %s is duplicated", i, cov.Name)
+		}
+		names[cov.Name] = struct{}{}
+		cc := *c
+		e := *cov
+		cc.Coverage = &e
+		cc.Coverages = nil
+		if c.Repository != "" {
+			cc.Repository = fmt.Sprintf("%s/%s", c.Repository, cov.Name)
+		}
+		cc.buildCoverage()
+		configs = append(configs, &cc)
+	}
+	return configs, nil
+}
+
+// CoverageConfig returns the config of the named coverage config of coverage: given as a list.
+// If coverage: is not a list, name has to be empty and it returns the config itself. It should be called after Build().
+func (c *Config) CoverageConfig(name string) (*Config, error) {
+	if len(c.Coverages) == 0 {
+		if name != "" {
+			return nil, fmt.Errorf("coverage: has no named coverage config %s", name)
+		}
+		return c, nil
+	}
+	if name == "" {
+		return nil, errors.New("the name of the coverage config is not set (coverage: is a list of named coverage configs)")
+	}
+	configs, err := c.CoverageConfigs()
+	if err != nil {
+		return nil, err
+	}
+	for _, cc := range configs {
+		if cc.Coverage.Name == name {
+			return cc, nil
+		}
+	}
+	return nil, fmt.Errorf("coverage: has no named coverage config %s", name)
+}
+
+// validateSingleTargetReports checks that the sections of report: (and badge.manifest:) that write to a single target are not set,
+// since the report of each named coverage config would overwrite the others.
+func (c *Config) validateSingleTargetReports() error {
+	var sections []string
+	if c.Badge != nil && c.Badge.Manifest != nil && c.Badge.Manifest.Path != "" {
+		sections = append(sections, "badge.manifest:")
+	}
+	if c.Report == nil {
+		return singleTargetReportsError(sections)
+	}
+	if c.Report.Path != "" {
+		sections = append(sections, "report.path:")
+	}
+	if c.Report.HTML != nil {
+		sections = append(sections, "report.html:")
+	}
+	if c.Report.Codecov != nil {
+		sections = append(sections, "report.codecov:")
+	}
+	if c.Report.Cobertura != nil {
+		sections = append(sections, "report.cobertura:")
+	}
+	if c.Report.Status {
+		sections = append(sections, "report.status:")
+	}
+	if c.Report.Release != nil && c.Report.Release.Enable {
+		sections = append(sections, "report.release:")
+	}
+	return singleTargetReportsError(sections)
+}
+
+func singleTargetReportsError(sections []string) error {
+	if len(sections) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s can not be used with the named coverage configs of coverage:", strings.Join(sections, ", "))
+}
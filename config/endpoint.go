@@ -0,0 +1,103 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+const defaultAcceptableEndpointTimeout = 10 * time.Second
+
+type acceptableRequest struct {
+	Repository string  `json:"repository"`
+	Metric     string  `json:"metric"`
+	Current    float64 `json:"current"`
+	// Prev and Diff are omitted when there is no previous code coverage to compare with
+	Prev *float64 `json:"prev,omitempty"`
+	Diff *float64 `json:"diff,omitempty"`
+}
+
+type acceptableResponse struct {
+	Pass    bool   `json:"pass"`
+	Message string `json:"message"`
+}
+
+// coverageAcceptableByEndpoint asks the external policy endpoint whether the code coverage of the report is acceptable.
+// prev is nil when there is no previous code coverage.
+func (c *Config) coverageAcceptableByEndpoint(ctx context.Context, r Reporter, prev *float64) error {
+	a := c.Coverage.Acceptable
+	repo := r.RepositoryName()
+	if repo == "" {
+		repo = c.Repository
+	}
+	current := r.CoveragePercent()
+	if c.dryRun {
+		_, _ = fmt.Fprintf(os.Stderr, "[dry-run] ask %s whether the code coverage of %s (%.1f%%) is acceptable\n", a.Endpoint, repo, current) //nostyle:handlerrors
+		return nil
+	}
+	req := &acceptableRequest{
+		Repository: repo,
+		Metric:     "coverage",
+		Current:    current,
+	}
+	if prev != nil {
+		diff := current - *prev
+		req.Prev = prev
+		req.Diff = &diff
+	}
+	res, err := postAcceptable(ctx, a.Endpoint, a.Timeout, req)
+	if err != nil {
+		if a.FailOpen {
+			log.Printf("failed to evaluate coverage.acceptable.endpoint (fail-open): %v", err)
+			return nil
+		}
+		return fmt.Errorf("failed to evaluate the condition in the `coverage.acceptable.endpoint:` section: %w", err)
+	}
+	if !res.Pass {
+		if res.Message == "" {
+			return fmt.Errorf("code coverage is %.1f%%. the condition in the `coverage.acceptable.endpoint:` section is not met (`%s`)", current, a.Endpoint)
+		}
+		return fmt.Errorf("code coverage is %.1f%%. the condition in the `coverage.acceptable.endpoint:` section is not met (%s)", current, res.Message)
+	}
+	return nil
+}
+
+func postAcceptable(ctx context.Context, endpoint string, timeout time.Duration, req *acceptableRequest) (*acceptableResponse, error) {
+	if timeout == 0 {
+		timeout = defaultAcceptableEndpointTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	hreq.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(hreq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+	res := &acceptableResponse{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// Metrics of policy.
+const (
+	PolicyMetricCoverage          = "coverage"
+	PolicyMetricCodeToTestRatio   = "codeToTestRatio"
+	PolicyMetricTestExecutionTime = "testExecutionTime"
+	PolicyMetricDocCoverage       = "docCoverage"
+)
+
+var PolicyMetrics = []string{PolicyMetricCoverage, PolicyMetricCodeToTestRatio, PolicyMetricTestExecutionTime, PolicyMetricDocCoverage}
+
+// Policy is a named quality gate of the `policy:` section.
+// The `acceptable:` condition of each metric section (e.g. `coverage.acceptable:`) is evaluated in the same way as a policy of the metric.
+type Policy struct {
+	Name       string `yaml:"name"`
+	Metric     string `yaml:"metric"`
+	Acceptable string `yaml:"acceptable"`
+}
+
+// policyAcceptable evaluates all policies and aggregates the failures.
+// Policies of metrics that are not measured are skipped, and so are the delta-based policies of the code coverage when skipRegression is true (coverage.acceptable.allowSkipRegression).
+func (c *Config) policyAcceptable(r, rPrev Reporter, skipRegression bool) []error {
+	var errs []error
+	for _, p := range c.Policy {
+		if !isMeasuredMetric(p.Metric, r) {
+			log.Printf("Skip checking policy.%s: %s is not measured", p.Name, p.Metric)
+			continue
+		}
+		if skipRegression && p.Metric == PolicyMetricCoverage && deltaCondRe.MatchString(p.Acceptable) {
+			log.Printf("Skip checking policy.%s: %s is requested (%s)", p.Name, SkipRegressionMarker, p.Acceptable)
+			continue
+		}
+		current, prev := metricValues(p.Metric, r, rPrev)
+		if err := metricAcceptable(p.Metric, fmt.Sprintf("policy.%s", p.Name), current, prev, p.Acceptable); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// metricAcceptable checks whether the value of the metric meets the condition of the section.
+func metricAcceptable(metric, section string, current, prev float64, cond string) error {
+	if cond == "" {
+		return nil
+	}
+	var (
+		ok    bool
+		err   error
+		value string
+	)
+	switch metric {
+	case PolicyMetricCoverage:
+		ok, err = percentAcceptable(current, prev, cond)
+		value = fmt.Sprintf("code coverage is %.1f%%", current)
+	case PolicyMetricCodeToTestRatio:
+		ok, err = ratioAcceptable(current, prev, cond)
+		value = fmt.Sprintf("code to test ratio is 1:%.1f", current)
+	case PolicyMetricTestExecutionTime:
+		ok, err = durationAcceptable(current, prev, cond)
+		value = fmt.Sprintf("test execution time is %v", time.Duration(current))
+	case PolicyMetricDocCoverage:
+		ok, err = percentAcceptable(current, prev, cond)
+		value = fmt.Sprintf("doc coverage is %.1f%%", current)
+	default:
+		return fmt.Errorf("invalid metric: %s", metric)
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s. the condition in the `%s:` section is not met (`%s`)", value, section, cond)
+	}
+	return nil
+}
+
+func metricValues(metric string, r, rPrev Reporter) (float64, float64) {
+	switch metric {
+	case PolicyMetricCoverage:
+		return r.CoveragePercent(), rPrev.CoveragePercent()
+	case PolicyMetricCodeToTestRatio:
+		return r.CodeToTestRatioRatio(), rPrev.CodeToTestRatioRatio()
+	case PolicyMetricTestExecutionTime:
+		prev := largeEnoughTime
+		if rPrev.IsMeasuredTestExecutionTime() {
+			prev = rPrev.TestExecutionTimeNano()
+		}
+		return r.TestExecutionTimeNano(), prev
+	case PolicyMetricDocCoverage:
+		return r.DocCoveragePercent(), rPrev.DocCoveragePercent()
+	}
+	return 0, 0
+}
+
+func isMeasuredMetric(metric string, r Reporter) bool {
+	switch metric {
+	case PolicyMetricCoverage:
+		return r.IsMeasuredCoverage()
+	case PolicyMetricCodeToTestRatio:
+		return r.IsMeasuredCodeToTestRatio()
+	case PolicyMetricTestExecutionTime:
+		return r.IsMeasuredTestExecutionTime()
+	case PolicyMetricDocCoverage:
+		return r.IsMeasuredDocCoverage()
+	}
+	return false
+}
+
+func isPolicyMetric(metric string) bool {
+	for _, m := range PolicyMetrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
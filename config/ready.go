@@ -6,15 +6,25 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/k1LoW/octocov/azure"
 	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/gitlab"
 )
 
-func (c *Config) CoverageConfigReady() error {
-	if c.Coverage == nil {
-		return errors.New("coverage: is not set")
+func (c *Config) RootConfigReady() error {
+	ok, err := c.CheckIf(c.If)
+	if err != nil {
+		return fmt.Errorf("the condition in the `if` section is not met (%s): %w", c.If, err)
 	}
-	if len(c.Coverage.Paths) == 0 {
-		return errors.New("coverage.paths: is not set")
+	if !ok {
+		return fmt.Errorf("the condition in the `if` section is not met (%s)", c.If)
+	}
+	return nil
+}
+
+func (c *Config) CoverageConfigReady() error {
+	if err := c.CoverageConfigReadyOnLocal(); err != nil {
+		return err
 	}
 	ok, err := c.CheckIf(c.Coverage.If)
 	if err != nil {
@@ -36,12 +46,43 @@ func (c *Config) CoverageConfigReadyOnLocal() error {
 	return nil
 }
 
-func (c *Config) CodeToTestRatioConfigReady() error {
-	if c.CodeToTestRatio == nil {
-		return errors.New("codeToTestRatio: is not set")
+// PatchCoverageConfigReady checks that the patch coverage of the current pull request can be measured.
+// It is measured when coverage.patch: is set or the condition of coverage.acceptable: uses the `patch` variable.
+func (c *Config) PatchCoverageConfigReady() error {
+	if err := c.CoverageConfigReady(); err != nil {
+		return err
 	}
-	if len(c.CodeToTestRatio.Test) == 0 {
-		return errors.New("codeToTestRatio.test: is not set")
+	if c.Coverage.Patch == nil && !patchCondRe.MatchString(c.Coverage.Acceptable.Condition) {
+		return errors.New("coverage.patch: is not set")
+	}
+	if c.Coverage.Patch != nil {
+		if err := validateCoveragePatch(c.Coverage.Patch); err != nil {
+			return err
+		}
+	}
+	if c.Repository == "" {
+		return fmt.Errorf("env %s is not set", "GITHUB_REPOSITORY")
+	}
+	repo, err := gh.Parse(c.Repository)
+	if err != nil {
+		return err
+	}
+	if c.gh == nil {
+		g, err := gh.New()
+		if err != nil {
+			return err
+		}
+		c.gh = g
+	}
+	if _, err := c.gh.DetectCurrentPullRequestNumber(context.Background(), repo.Owner, repo.Repo); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Config) CodeToTestRatioConfigReady() error {
+	if err := c.codeToTestRatioConfigValid(); err != nil {
+		return err
 	}
 	ok, err := c.CheckIf(c.CodeToTestRatio.If)
 	if err != nil {
@@ -53,11 +94,22 @@ func (c *Config) CodeToTestRatioConfigReady() error {
 	return nil
 }
 
+// codeToTestRatioConfigValid checks the part of CodeToTestRatioConfigReady that does not depend on the CI environment.
+func (c *Config) codeToTestRatioConfigValid() error {
+	if c.CodeToTestRatio == nil {
+		return errors.New("codeToTestRatio: is not set")
+	}
+	if len(c.CodeToTestRatio.Test) == 0 && len(c.CodeToTestRatio.Groups) == 0 {
+		return errors.New("codeToTestRatio.test: is not set")
+	}
+	return nil
+}
+
 func (c *Config) TestExecutionTimeConfigReady() error {
 	if c.TestExecutionTime == nil {
 		return errors.New("testExecutionTime: is not set")
 	}
-	if err := c.CoverageConfigReady(); err != nil && len(c.TestExecutionTime.Steps) == 0 {
+	if err := c.CoverageConfigReady(); err != nil && len(c.TestExecutionTime.Steps) == 0 && c.TestExecutionTime.GoTestJSON == "" {
 		return err
 	}
 	ok, err := c.CheckIf(c.TestExecutionTime.If)
@@ -70,6 +122,23 @@ func (c *Config) TestExecutionTimeConfigReady() error {
 	return nil
 }
 
+func (c *Config) DocCoverageConfigReady() error {
+	if c.DocCoverage == nil {
+		return errors.New("docCoverage: is not set")
+	}
+	if !c.DocCoverage.Enable {
+		return errors.New("docCoverage.enable: is not true")
+	}
+	ok, err := c.CheckIf(c.DocCoverage.If)
+	if err != nil {
+		return fmt.Errorf("the condition in the `if` section is not met (%s): %w", c.DocCoverage.If, err)
+	}
+	if !ok {
+		return fmt.Errorf("the condition in the `if` section is not met (%s)", c.DocCoverage.If)
+	}
+	return nil
+}
+
 func (c *Config) PushConfigReady() error {
 	if c.Push == nil {
 		return errors.New("push: is not set")
@@ -88,8 +157,8 @@ func (c *Config) PushConfigReady() error {
 }
 
 func (c *Config) CommentConfigReady() error {
-	if c.Comment == nil {
-		return errors.New("comment: is not set")
+	if err := c.commentConfigValid(); err != nil {
+		return err
 	}
 	if c.Repository == "" {
 		return fmt.Errorf("env %s is not set", "GITHUB_REPOSITORY")
@@ -119,6 +188,22 @@ func (c *Config) CommentConfigReady() error {
 	return nil
 }
 
+// commentConfigValid checks the part of CommentConfigReady that does not depend on the CI environment.
+func (c *Config) commentConfigValid() error {
+	if c.Comment == nil {
+		return errors.New("comment: is not set")
+	}
+	switch c.Comment.Location {
+	case "", CommentLocationThread, CommentLocationDescription:
+	default:
+		return fmt.Errorf("invalid comment.location: %s", c.Comment.Location)
+	}
+	if c.Comment.DeletePrevious && c.Comment.UpdatePrevious {
+		return errors.New("comment.deletePrevious: and comment.updatePrevious: cannot be enabled at the same time")
+	}
+	return nil
+}
+
 func (c *Config) SummaryConfigReady() error {
 	if c.Summary == nil {
 		return errors.New("summary: is not set")
@@ -175,8 +260,44 @@ func (c *Config) CoverageBadgeConfigReady() error {
 	if err := c.CoverageConfigReady(); err != nil {
 		return err
 	}
-	if c.Coverage.Badge.Path == "" {
-		return errors.New("coverage.badge.path: is not set")
+	if len(c.CoverageBadgePaths()) == 0 {
+		return errors.New("coverage.badge.path: and coverage.badge.paths: are not set")
+	}
+	return nil
+}
+
+func (c *Config) CoverageBadgeHeatmapConfigReady() error {
+	if err := c.CoverageConfigReady(); err != nil {
+		return err
+	}
+	if c.Coverage.Badge.Heatmap == nil || !c.Coverage.Badge.Heatmap.Enable {
+		return errors.New("coverage.badge.heatmap: is not enabled")
+	}
+	if c.CoverageBadgeHeatmapPath() == "" {
+		return errors.New("coverage.badge.heatmap.path: and coverage.badge.path: are not set")
+	}
+	return nil
+}
+
+func (c *Config) CoverageChartConfigReady() error {
+	if err := c.CoverageConfigReady(); err != nil {
+		return err
+	}
+	if c.Coverage.Chart == nil || c.Coverage.Chart.Path == "" {
+		return errors.New("coverage.chart.path: is not set")
+	}
+	return nil
+}
+
+func (c *Config) CoverageSparklineConfigReady() error {
+	if err := c.CoverageConfigReady(); err != nil {
+		return err
+	}
+	if c.Coverage.History == nil || !c.Coverage.History.Sparkline {
+		return errors.New("coverage.history.sparkline: is not true")
+	}
+	if c.Diff == nil || len(c.Diff.Datastores) == 0 {
+		return errors.New("diff.datastores: is not set")
 	}
 	return nil
 }
@@ -201,15 +322,19 @@ func (c *Config) TestExecutionTimeBadgeConfigReady() error {
 	return nil
 }
 
-func (c *Config) CentralConfigReady() error {
-	if c.Central == nil {
-		return errors.New("central: is not set")
+func (c *Config) DocCoverageBadgeConfigReady() error {
+	if err := c.DocCoverageConfigReady(); err != nil {
+		return err
 	}
-	if c.Repository == "" {
-		return errors.New("repository: not set (or env GITHUB_REPOSITORY is not set)")
+	if c.DocCoverage.Badge.Path == "" {
+		return errors.New("docCoverage.badge.path: is not set")
 	}
-	if len(c.Central.Reports.Datastores) == 0 {
-		return errors.New("central.reports.datastores is not set")
+	return nil
+}
+
+func (c *Config) CentralConfigReady() error {
+	if err := c.centralConfigValid(); err != nil {
+		return err
 	}
 	ok, err := c.CheckIf(c.Central.If)
 	if err != nil {
@@ -221,6 +346,20 @@ func (c *Config) CentralConfigReady() error {
 	return nil
 }
 
+// centralConfigValid checks the part of CentralConfigReady that does not depend on the CI environment.
+func (c *Config) centralConfigValid() error {
+	if c.Central == nil {
+		return errors.New("central: is not set")
+	}
+	if c.Repository == "" {
+		return errors.New("repository: not set (or env GITHUB_REPOSITORY is not set)")
+	}
+	if len(c.Central.Reports.Datastores) == 0 {
+		return errors.New("central.reports.datastores is not set")
+	}
+	return nil
+}
+
 func (c *Config) CentralPushConfigReady() error {
 	if err := c.CentralConfigReady(); err != nil {
 		return err
@@ -259,11 +398,8 @@ func (c *Config) CentralReReportReady() error {
 }
 
 func (c *Config) DiffConfigReady() error {
-	if c.Diff == nil {
-		return errors.New("diff: is not set")
-	}
-	if c.Diff.Path == "" && len(c.Diff.Datastores) == 0 {
-		return errors.New("diff.path: and diff.datastores: are not set")
+	if err := c.diffConfigValid(); err != nil {
+		return err
 	}
 	ok, err := c.CheckIf(c.Diff.If)
 	if err != nil {
@@ -275,6 +411,22 @@ func (c *Config) DiffConfigReady() error {
 	return nil
 }
 
+// diffConfigValid checks the part of DiffConfigReady that does not depend on the CI environment.
+func (c *Config) diffConfigValid() error {
+	if c.Diff == nil {
+		return errors.New("diff: is not set")
+	}
+	if c.Diff.Path == "" && len(c.Diff.Datastores) == 0 {
+		return errors.New("diff.path: and diff.datastores: are not set")
+	}
+	switch c.Diff.CompareAgainst {
+	case "", DiffCompareAgainstBranchTip, DiffCompareAgainstMergeBase:
+	default:
+		return fmt.Errorf("invalid diff.compareAgainst: %s", c.Diff.CompareAgainst)
+	}
+	return nil
+}
+
 func (c *Config) ReportConfigReady() error {
 	if err := c.ReportConfigTargetReady(); err != nil {
 		return err
@@ -289,12 +441,159 @@ func (c *Config) ReportConfigReady() error {
 	return nil
 }
 
+func (c *Config) ReportReleaseConfigReady() error {
+	if c.Report == nil || c.Report.Release == nil || !c.Report.Release.Enable {
+		return errors.New("report.release.enable: is not true")
+	}
+	if c.Repository == "" {
+		return fmt.Errorf("env %s is not set", "GITHUB_REPOSITORY")
+	}
+	if c.gh == nil {
+		g, err := gh.New()
+		if err != nil {
+			return err
+		}
+		c.gh = g
+	}
+	if _, err := c.gh.DetectCurrentTag(context.Background()); err != nil {
+		return err
+	}
+	ok, err := c.CheckIf(c.Report.Release.If)
+	if err != nil {
+		return fmt.Errorf("the condition in the `if` section is not met (%s): %w", c.Report.Release.If, err)
+	}
+	if !ok {
+		return fmt.Errorf("the condition in the `if` section is not met (%s)", c.Report.Release.If)
+	}
+	return nil
+}
+
+func (c *Config) ReportStatusConfigReady() error {
+	if c.Report == nil || !c.Report.Status {
+		return errors.New("report.status: is not true")
+	}
+	if c.Repository == "" {
+		return fmt.Errorf("env %s is not set", "GITHUB_REPOSITORY")
+	}
+	if os.Getenv("GITHUB_SHA") == "" {
+		return fmt.Errorf("env %s is not set", "GITHUB_SHA")
+	}
+	return nil
+}
+
+func (c *Config) ReportSlackConfigReady() error {
+	if err := c.reportSlackConfigValid(); err != nil {
+		return err
+	}
+	ok, err := c.CheckIf(c.Report.Slack.If)
+	if err != nil {
+		return fmt.Errorf("the condition in the `if` section is not met (%s): %w", c.Report.Slack.If, err)
+	}
+	if !ok {
+		return fmt.Errorf("the condition in the `if` section is not met (%s)", c.Report.Slack.If)
+	}
+	return nil
+}
+
+// reportSlackConfigValid checks the part of ReportSlackConfigReady that does not depend on the CI environment.
+func (c *Config) reportSlackConfigValid() error {
+	if c.Report == nil || c.Report.Slack == nil {
+		return errors.New("report.slack: is not set")
+	}
+	if c.Report.Slack.WebhookURL == "" {
+		return errors.New("report.slack.webhookURL: is not set")
+	}
+	return nil
+}
+
+func (c *Config) ReportIssueConfigReady() error {
+	if err := c.reportIssueConfigValid(); err != nil {
+		return err
+	}
+	if c.Repository == "" {
+		return fmt.Errorf("env %s is not set", "GITHUB_REPOSITORY")
+	}
+	return nil
+}
+
+// reportIssueConfigValid checks the part of ReportIssueConfigReady that does not depend on the CI environment.
+func (c *Config) reportIssueConfigValid() error {
+	if c.Report == nil || c.Report.Issue == 0 {
+		return errors.New("report.issue: is not set")
+	}
+	if c.Report.Issue < 0 {
+		return fmt.Errorf("invalid report.issue: %d", c.Report.Issue)
+	}
+	return nil
+}
+
+func (c *Config) ReportGitLabConfigReady() error {
+	if c.Report == nil || c.Report.GitLab == nil {
+		return errors.New("report.gitlab: is not set")
+	}
+	if os.Getenv("GITLAB_TOKEN") == "" {
+		return fmt.Errorf("env %s is not set", "GITLAB_TOKEN")
+	}
+	if c.ReportGitLabProject() == "" {
+		return fmt.Errorf("report.gitlab.project: is not set (or env %s is not set)", "CI_PROJECT_ID")
+	}
+	if _, err := gitlab.DecodeGitLabCIEnv(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *Config) ReportAzureConfigReady() error {
+	if c.Report == nil || c.Report.Azure == nil {
+		return errors.New("report.azure: is not set")
+	}
+	if os.Getenv("SYSTEM_ACCESSTOKEN") == "" {
+		return fmt.Errorf("env %s is not set", "SYSTEM_ACCESSTOKEN")
+	}
+	if c.ReportAzureCollectionURL() == "" {
+		return fmt.Errorf("report.azure.organization: is not set (or env %s is not set)", "SYSTEM_COLLECTIONURI")
+	}
+	if c.ReportAzureProject() == "" {
+		return fmt.Errorf("report.azure.project: is not set (or env %s is not set)", "SYSTEM_TEAMPROJECT")
+	}
+	if c.ReportAzureRepository() == "" {
+		return fmt.Errorf("report.azure.repository: is not set (or env %s is not set)", "BUILD_REPOSITORY_NAME")
+	}
+	if _, err := azure.DecodeAzurePipelinesEnv(); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (c *Config) ReportConfigTargetReady() error {
 	if c.Report == nil {
 		return errors.New("report: is not set")
 	}
-	if c.Report.Path == "" && len(c.Report.Datastores) == 0 {
-		return errors.New("report.datastores: and report.path: are not set")
+	if c.Report.Path == "" && len(c.Report.Datastores) == 0 && len(c.Report.ConditionalDatastores) == 0 && (c.Report.Codecov == nil || c.Report.Codecov.Path == "") && (c.Report.Cobertura == nil || c.Report.Cobertura.Path == "") && (c.Report.Statsd == nil || c.Report.Statsd.Addr == "") && (c.Report.HTML == nil || (c.Report.HTML.Dir == "" && c.Report.HTML.Path == "")) && (c.Report.HTTP == nil || c.Report.HTTP.URL == "") {
+		return errors.New("report.datastores:, report.path:, report.codecov.path:, report.cobertura.path:, report.statsd.addr:, report.html.dir:, report.html.path: and report.http.url: are not set")
+	}
+	return nil
+}
+
+func (c *Config) PolicyConfigReady() error {
+	if len(c.Policy) == 0 {
+		return errors.New("policy: is not set")
+	}
+	names := map[string]struct{}{}
+	for i, p := range c.Policy {
+		if p.Name == "" {
+			return fmt.Errorf("policy[%d].name: is not set", i)
+		}
+		if _, ok := names[p.Name]; ok {
+			return fmt.Errorf("duplicate policy name: %s", p.Name)
+		}
+		names[p.Name] = struct{}{}
+		if !isPolicyMetric(p.Metric) {
+			return fmt.Errorf("invalid policy.%s.metric: %s", p.Name, p.Metric)
+		}
+		if p.Acceptable == "" {
+			return fmt.Errorf("policy.%s.acceptable: is not set", p.Name)
+		}
 	}
 	return nil
 }
@@ -13,6 +13,55 @@ import (
 	"github.com/migueleliasweb/go-github-mock/src/mock"
 )
 
+func TestRootConfigReady(t *testing.T) {
+	t.Setenv("GITHUB_EVENT_NAME", "pull_request")
+	t.Setenv("GITHUB_EVENT_PATH", filepath.Join(rootTestdataDir(t), "config", "event_pull_request_opened.json"))
+	t.Setenv("GITHUB_REF", "refs/pull/4/merge")
+	t.Setenv("GITHUB_TOKEN", "token")
+
+	tests := []struct {
+		c    *Config
+		want string
+	}{
+		{
+			&Config{},
+			"",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				If:         "false",
+				gh:         mockedGh(t),
+			},
+			"the condition in the `if` section is not met (false)",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				If:         "is_pull_request",
+				gh:         mockedGh(t),
+			},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		err := tt.c.RootConfigReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
 func TestCoverageConfigReady(t *testing.T) {
 	tests := []struct {
 		c    *Config
@@ -78,6 +127,16 @@ func TestCodeToTestRatioConfigReady(t *testing.T) {
 			},
 			"",
 		},
+		{
+			&Config{
+				CodeToTestRatio: &CodeToTestRatio{
+					Groups: []*CodeToTestRatioGroup{
+						{Name: "go", Code: []string{"**/*.go", "!**/*_test.go"}, Test: []string{"**/*_test.go"}},
+					},
+				},
+			},
+			"",
+		},
 	}
 	for _, tt := range tests {
 		err := tt.c.CodeToTestRatioConfigReady()
@@ -124,6 +183,14 @@ func TestTestExecutionTimeConfigReady(t *testing.T) {
 			},
 			"",
 		},
+		{
+			&Config{
+				TestExecutionTime: &TestExecutionTime{
+					GoTestJSON: "test.json",
+				},
+			},
+			"",
+		},
 	}
 	for _, tt := range tests {
 		err := tt.c.TestExecutionTimeConfigReady()
@@ -248,6 +315,26 @@ func TestCommentConfigReady(t *testing.T) {
 			},
 			"",
 		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Comment: &Comment{
+					Location: "description",
+				},
+				gh: mg,
+			},
+			"",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Comment: &Comment{
+					Location: "top",
+				},
+				gh: mg,
+			},
+			"invalid comment.location: top",
+		},
 		{
 			&Config{
 				Repository: "owner/repo",
@@ -288,7 +375,7 @@ func TestCoverageBadgeConfigReady(t *testing.T) {
 					Paths: []string{"path/to/coverage.xml"},
 				},
 			},
-			"coverage.badge.path: is not set",
+			"coverage.badge.path: and coverage.badge.paths: are not set",
 		},
 		{
 			&Config{
@@ -301,6 +388,17 @@ func TestCoverageBadgeConfigReady(t *testing.T) {
 			},
 			"",
 		},
+		{
+			&Config{
+				Coverage: &Coverage{
+					Paths: []string{"path/to/coverage.xml"},
+					Badge: CoverageBadge{
+						Paths: []string{"path/to/coverage.json"},
+					},
+				},
+			},
+			"",
+		},
 	}
 	for _, tt := range tests {
 		err := tt.c.CoverageBadgeConfigReady()
@@ -320,6 +418,39 @@ func TestCoverageBadgeConfigReady(t *testing.T) {
 	}
 }
 
+func TestCoverageChartConfigReady(t *testing.T) {
+	tests := []struct {
+		chart *CoverageChart
+		want  string
+	}{
+		{nil, "coverage.chart.path: is not set"},
+		{&CoverageChart{Window: 10}, "coverage.chart.path: is not set"},
+		{&CoverageChart{Path: "docs/coverage-chart.svg"}, ""},
+	}
+	for _, tt := range tests {
+		c := &Config{
+			Coverage: &Coverage{
+				Paths: []string{"path/to/coverage.xml"},
+				Chart: tt.chart,
+			},
+		}
+		err := c.CoverageChartConfigReady()
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("got %v\nwant %v", err, nil)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("got %v\nwant %v", nil, tt.want)
+			continue
+		}
+		if got := err.Error(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
 func TestCodeToTestRatioBadgeConfigReady(t *testing.T) {
 	tests := []struct {
 		c    *Config
@@ -610,6 +741,28 @@ func TestDiffConfigReady(t *testing.T) {
 			},
 			"",
 		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Diff: &Diff{
+					Path:           "path/to/report.json",
+					CompareAgainst: "mergeBase",
+				},
+				gh: mg,
+			},
+			"",
+		},
+		{
+			&Config{
+				Repository: "owner/repo",
+				Diff: &Diff{
+					Path:           "path/to/report.json",
+					CompareAgainst: "base",
+				},
+				gh: mg,
+			},
+			"invalid diff.compareAgainst: base",
+		},
 		{
 			&Config{
 				Repository: "owner/repo",
@@ -663,7 +816,7 @@ func TestReportConfigReady(t *testing.T) {
 				Report:     &Report{},
 				gh:         mockedGh(t),
 			},
-			"report.datastores: and report.path: are not set",
+			"report.datastores:, report.path:, report.codecov.path:, report.cobertura.path:, report.statsd.addr:, report.html.dir:, report.html.path: and report.http.url: are not set",
 		},
 		{
 			&Config{
@@ -722,6 +875,277 @@ func TestReportConfigReady(t *testing.T) {
 	}
 }
 
+func TestReportReleaseConfigReady(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "token")
+
+	tests := []struct {
+		ref  string
+		c    *Config
+		want string
+	}{
+		{
+			"refs/tags/v1.0.0",
+			&Config{
+				Repository: "owner/repo",
+				Report:     &Report{},
+				gh:         mockedGh(t),
+			},
+			"report.release.enable: is not true",
+		},
+		{
+			"refs/heads/main",
+			&Config{
+				Repository: "owner/repo",
+				Report:     &Report{Release: &ReportRelease{Enable: true}},
+				gh:         mockedGh(t),
+			},
+			"env GITHUB_REF is not a tag: refs/heads/main",
+		},
+		{
+			"refs/tags/v1.0.0",
+			&Config{
+				Repository: "owner/repo",
+				Report:     &Report{Release: &ReportRelease{Enable: true}},
+				gh:         mockedGh(t),
+			},
+			"",
+		},
+		{
+			"refs/tags/v1.0.0",
+			&Config{
+				Repository: "owner/repo",
+				Report:     &Report{Release: &ReportRelease{Enable: true, If: "false"}},
+				gh:         mockedGh(t),
+			},
+			"the condition in the `if` section is not met (false)",
+		},
+	}
+	for _, tt := range tests {
+		t.Setenv("GITHUB_REF", tt.ref)
+		err := tt.c.ReportReleaseConfigReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestReportStatusConfigReady(t *testing.T) {
+	tests := []struct {
+		sha  string
+		c    *Config
+		want string
+	}{
+		{
+			"a1b2c3d",
+			&Config{
+				Repository: "owner/repo",
+				Report:     &Report{},
+			},
+			"report.status: is not true",
+		},
+		{
+			"",
+			&Config{
+				Repository: "owner/repo",
+				Report:     &Report{Status: true},
+			},
+			"env GITHUB_SHA is not set",
+		},
+		{
+			"a1b2c3d",
+			&Config{
+				Report: &Report{Status: true},
+			},
+			"env GITHUB_REPOSITORY is not set",
+		},
+		{
+			"a1b2c3d",
+			&Config{
+				Repository: "owner/repo",
+				Report:     &Report{Status: true},
+			},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Setenv("GITHUB_SHA", tt.sha)
+		err := tt.c.ReportStatusConfigReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestReportSlackConfigReady(t *testing.T) {
+	tests := []struct {
+		c    *Config
+		want string
+	}{
+		{
+			&Config{Report: &Report{}},
+			"report.slack: is not set",
+		},
+		{
+			&Config{Report: &Report{Slack: &ReportSlack{}}},
+			"report.slack.webhookURL: is not set",
+		},
+		{
+			&Config{Report: &Report{Slack: &ReportSlack{WebhookURL: "https://hooks.slack.com/services/T000/B000/XXX"}}},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		err := tt.c.ReportSlackConfigReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestReportIssueConfigReady(t *testing.T) {
+	tests := []struct {
+		c    *Config
+		want string
+	}{
+		{
+			&Config{Report: &Report{}},
+			"report.issue: is not set",
+		},
+		{
+			&Config{Report: &Report{Issue: -1}},
+			"invalid report.issue: -1",
+		},
+		{
+			&Config{Report: &Report{Issue: 12}},
+			"env GITHUB_REPOSITORY is not set",
+		},
+		{
+			&Config{Repository: "owner/repo", Report: &Report{Issue: 12}},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		err := tt.c.ReportIssueConfigReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestReportGitLabConfigReady(t *testing.T) {
+	tests := []struct {
+		token     string
+		projectID string
+		iid       string
+		c         *Config
+		want      string
+	}{
+		{"token", "123", "2", &Config{Report: &Report{}}, "report.gitlab: is not set"},
+		{"", "123", "2", &Config{Report: &Report{GitLab: &ReportGitLab{}}}, "env GITLAB_TOKEN is not set"},
+		{"token", "", "2", &Config{Report: &Report{GitLab: &ReportGitLab{}}}, "report.gitlab.project: is not set (or env CI_PROJECT_ID is not set)"},
+		{"token", "", "", &Config{Report: &Report{GitLab: &ReportGitLab{Project: "group/project"}}}, "env CI_MERGE_REQUEST_IID is not set"},
+		{"token", "", "2", &Config{Report: &Report{GitLab: &ReportGitLab{Project: "group/project"}}}, ""},
+		{"token", "123", "2", &Config{Report: &Report{GitLab: &ReportGitLab{}}}, ""},
+	}
+	for _, tt := range tests {
+		t.Setenv("GITLAB_TOKEN", tt.token)
+		t.Setenv("CI_PROJECT_ID", tt.projectID)
+		t.Setenv("CI_MERGE_REQUEST_IID", tt.iid)
+		err := tt.c.ReportGitLabConfigReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
+func TestReportAzureConfigReady(t *testing.T) {
+	tests := []struct {
+		token         string
+		collectionURI string
+		prID          string
+		c             *Config
+		want          string
+	}{
+		{"token", "https://dev.azure.com/org/", "2", &Config{Report: &Report{}}, "report.azure: is not set"},
+		{"", "https://dev.azure.com/org/", "2", &Config{Report: &Report{Azure: &ReportAzure{}}}, "env SYSTEM_ACCESSTOKEN is not set"},
+		{"token", "", "2", &Config{Report: &Report{Azure: &ReportAzure{}}}, "report.azure.organization: is not set (or env SYSTEM_COLLECTIONURI is not set)"},
+		{"token", "", "", &Config{Report: &Report{Azure: &ReportAzure{Organization: "org"}}}, "env SYSTEM_PULLREQUEST_PULLREQUESTID is not set"},
+		{"token", "", "2", &Config{Report: &Report{Azure: &ReportAzure{Organization: "org"}}}, ""},
+		{"token", "https://dev.azure.com/org/", "2", &Config{Report: &Report{Azure: &ReportAzure{}}}, ""},
+	}
+	for _, tt := range tests {
+		t.Setenv("SYSTEM_ACCESSTOKEN", tt.token)
+		t.Setenv("SYSTEM_COLLECTIONURI", tt.collectionURI)
+		t.Setenv("SYSTEM_TEAMPROJECT", "project")
+		t.Setenv("BUILD_REPOSITORY_NAME", "repo")
+		t.Setenv("SYSTEM_PULLREQUEST_PULLREQUESTID", tt.prID)
+		err := tt.c.ReportAzureConfigReady()
+		if err == nil && tt.want != "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want == "" {
+			t.Errorf("got %v\nwant %v", err, tt.want)
+			continue
+		}
+		if err != nil && tt.want != "" {
+			if got := err.Error(); got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		}
+	}
+}
+
 func mockedGh(t *testing.T) *gh.Gh {
 	mockedHTTPClient := mock.NewMockedHTTPClient( //nostyle:funcfmt
 		mock.WithRequestMatch( //nostyle:funcfmt
@@ -749,3 +1173,35 @@ func mockedGh(t *testing.T) *gh.Gh {
 	g.SetClient(client)
 	return g
 }
+
+func TestPolicyConfigReady(t *testing.T) {
+	tests := []struct {
+		policy []*Policy
+		want   string
+	}{
+		{nil, "policy: is not set"},
+		{[]*Policy{{Metric: PolicyMetricCoverage, Acceptable: "60%"}}, "policy[0].name: is not set"},
+		{[]*Policy{{Name: "a", Metric: "patch", Acceptable: "60%"}}, "invalid policy.a.metric: patch"},
+		{[]*Policy{{Name: "a", Metric: PolicyMetricCoverage}}, "policy.a.acceptable: is not set"},
+		{[]*Policy{{Name: "a", Metric: PolicyMetricCoverage, Acceptable: "60%"}, {Name: "a", Metric: PolicyMetricDocCoverage, Acceptable: "60%"}}, "duplicate policy name: a"},
+		{[]*Policy{{Name: "a", Metric: PolicyMetricCoverage, Acceptable: "60%"}, {Name: "b", Metric: PolicyMetricDocCoverage, Acceptable: "60%"}}, ""},
+	}
+	for _, tt := range tests {
+		c := New()
+		c.Policy = tt.policy
+		err := c.PolicyConfigReady()
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("got %v\nwant %v", err, nil)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("got %v\nwant %v", nil, tt.want)
+			continue
+		}
+		if got := err.Error(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
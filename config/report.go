@@ -1,7 +1,88 @@
 package config
 
+// Sources of the timestamp of the report (report.timestamp).
+const (
+	ReportTimestampNow    = "now"
+	ReportTimestampCommit = "commit"
+)
+
 type Report struct {
-	If         string   `yaml:"if,omitempty"`
-	Path       string   `yaml:"path,omitempty"`
-	Datastores []string `yaml:"datastores,omitempty"`
+	If            string           `yaml:"if,omitempty"`
+	Path          string           `yaml:"path,omitempty"`
+	Datastores    []string         `yaml:"datastores,omitempty"`
+	StoreOnPass   bool             `yaml:"storeOnPass,omitempty"`
+	StoreByRef    bool             `yaml:"storeByRef,omitempty"`
+	Gzip          bool             `yaml:"gzip,omitempty"`
+	MaxConcurrent int              `yaml:"maxConcurrent,omitempty"`
+	MaxFiles      int              `yaml:"maxFiles,omitempty"`
+	Timestamp     string           `yaml:"timestamp,omitempty"`
+	Codecov       *ReportCodecov   `yaml:"codecov,omitempty"`
+	Cobertura     *ReportCobertura `yaml:"cobertura,omitempty"`
+	Statsd        *ReportStatsd    `yaml:"statsd,omitempty"`
+	Release       *ReportRelease   `yaml:"release,omitempty"`
+	HTML          *ReportHTML      `yaml:"html,omitempty"`
+	GitLab        *ReportGitLab    `yaml:"gitlab,omitempty"`
+	Azure         *ReportAzure     `yaml:"azure,omitempty"`
+	HTTP          *ReportHTTP      `yaml:"http,omitempty"`
+	Status        bool             `yaml:"status,omitempty"`
+	Slack         *ReportSlack     `yaml:"slack,omitempty"`
+	// Issue is the number of the issue to upsert the comment of the report on
+	Issue int `yaml:"issue,omitempty"`
+	// datastores of report.datastores with their own `if` sections
+	ConditionalDatastores []*ReportDatastore `yaml:"-"`
+}
+
+// ReportDatastore is an entry of report.datastores with its own `if` section.
+type ReportDatastore struct {
+	URL string `yaml:"url"`
+	If  string `yaml:"if,omitempty"`
+}
+
+type ReportCodecov struct {
+	Path string `yaml:"path"`
+}
+
+type ReportCobertura struct {
+	Path string `yaml:"path"`
+}
+
+type ReportStatsd struct {
+	Addr string `yaml:"addr"`
+}
+
+type ReportRelease struct {
+	Enable bool   `yaml:"enable"`
+	If     string `yaml:"if,omitempty"`
+}
+
+type ReportHTML struct {
+	Dir string `yaml:"dir"`
+	// Path is the path of the self-contained HTML page summarizing the report
+	Path string `yaml:"path,omitempty"`
+}
+
+// ReportHTTP is the config for sending the report to an HTTP endpoint.
+type ReportHTTP struct {
+	URL     string            `yaml:"url"`
+	Method  string            `yaml:"method,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+}
+
+// ReportGitLab is the config for posting the report as a note of the GitLab merge request.
+type ReportGitLab struct {
+	Project string `yaml:"project,omitempty"`
+}
+
+// ReportAzure is the config for posting the report as a thread comment of the Azure DevOps pull request.
+type ReportAzure struct {
+	Organization string `yaml:"organization,omitempty"`
+	Project      string `yaml:"project,omitempty"`
+	Repository   string `yaml:"repository,omitempty"`
+}
+
+// ReportSlack is the config for posting the summary of the report to Slack via an incoming webhook.
+type ReportSlack struct {
+	WebhookURL string `yaml:"webhookURL"`
+	OnFailure  bool   `yaml:"onFailure,omitempty"`
+	If         string `yaml:"if,omitempty"`
 }
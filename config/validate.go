@@ -0,0 +1,287 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/hashicorp/go-multierror"
+	"github.com/k1LoW/octocov/gh"
+)
+
+// Validate checks the entire config and returns a combined error listing every problem found.
+// It shares the checks of the *ConfigReady methods that do not depend on the CI environment, and does not evaluate the `if` sections.
+// validateDatastore is used to check each datastore URL, and is skipped if nil.
+func (c *Config) Validate(validateDatastore func(u string) error) error {
+	var result *multierror.Error
+	appendErr := func(err error) {
+		if err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+
+	if c.Repository != "" {
+		if _, err := gh.Parse(c.Repository); err != nil {
+			appendErr(fmt.Errorf("repository: %w", err))
+		}
+	}
+
+	if c.Coverage != nil || len(c.Coverages) > 0 {
+		configs, err := c.CoverageConfigs()
+		appendErr(err)
+		for i, cc := range configs {
+			if cc.Coverage.Name == "" {
+				cc.validateCoverage(appendErr)
+				continue
+			}
+			cc.validateCoverage(func(err error) {
+				if err != nil {
+					appendErr(fmt.Errorf("coverage[%d]: %w", i, err))
+				}
+			})
+		}
+	}
+
+	if c.CodeToTestRatio != nil {
+		appendErr(c.codeToTestRatioConfigValid())
+		if len(c.CodeToTestRatio.Groups) > 0 && (len(c.CodeToTestRatio.Code) > 0 || len(c.CodeToTestRatio.Test) > 0) {
+			appendErr(errors.New("codeToTestRatio.code: and codeToTestRatio.test: can not be used with codeToTestRatio.groups:"))
+		}
+		names := map[string]struct{}{}
+		for i, g := range c.CodeToTestRatio.Groups {
+			if g.Name == "" {
+				appendErr(fmt.Errorf("codeToTestRatio.groups[%d].name: is not set", i))
+			} else if _, ok := names[g.Name]; ok {
+				appendErr(fmt.Errorf("codeToTestRatio.groups[%d].name: %s is duplicated", i, g.Name))
+			}
+			names[g.Name] = struct{}{}
+			if len(g.Test) == 0 {
+				appendErr(fmt.Errorf("codeToTestRatio.groups[%d].test: is not set", i))
+			}
+		}
+		if c.CodeToTestRatio.Acceptable != "" {
+			if _, err := ratioAcceptable(0, 0, c.CodeToTestRatio.Acceptable); err != nil {
+				appendErr(fmt.Errorf("codeToTestRatio.acceptable: invalid condition (%s): %w", c.CodeToTestRatio.Acceptable, err))
+			}
+		}
+		appendErr(validateBadgePath("codeToTestRatio.badge.path", c.CodeToTestRatio.Badge.Path))
+	}
+
+	if c.TestExecutionTime != nil {
+		if c.TestExecutionTime.Acceptable != "" {
+			if _, err := durationAcceptable(0, 0, c.TestExecutionTime.Acceptable); err != nil {
+				appendErr(fmt.Errorf("testExecutionTime.acceptable: invalid condition (%s): %w", c.TestExecutionTime.Acceptable, err))
+			}
+		}
+		appendErr(validateBadgePath("testExecutionTime.badge.path", c.TestExecutionTime.Badge.Path))
+	}
+
+	if c.DocCoverage != nil {
+		if c.DocCoverage.Acceptable != "" {
+			if _, err := percentAcceptable(0, 0, c.DocCoverage.Acceptable); err != nil {
+				appendErr(fmt.Errorf("docCoverage.acceptable: invalid condition (%s): %w", c.DocCoverage.Acceptable, err))
+			}
+		}
+		appendErr(validateBadgePath("docCoverage.badge.path", c.DocCoverage.Badge.Path))
+	}
+
+	if c.Report != nil {
+		if !c.Report.Status && c.Report.Slack == nil && c.Report.Issue == 0 && c.Report.GitLab == nil && c.Report.Azure == nil {
+			// report.status:, report.slack:, report.issue:, report.gitlab: and report.azure: alone are valid report sections without storing the report
+			appendErr(c.ReportConfigTargetReady())
+		}
+		switch c.Report.Timestamp {
+		case "", ReportTimestampNow, ReportTimestampCommit:
+		default:
+			appendErr(fmt.Errorf("invalid report.timestamp: %s", c.Report.Timestamp))
+		}
+		if c.Report.Issue != 0 {
+			appendErr(c.reportIssueConfigValid())
+		}
+		if c.Report.Slack != nil {
+			appendErr(c.reportSlackConfigValid())
+		}
+		if c.Report.HTTP != nil && c.Report.HTTP.URL == "" {
+			appendErr(errors.New("report.http.url: is not set"))
+		}
+		appendErr(validateDatastores("report.datastores", c.ReportDatastoresToRead(), validateDatastore))
+	}
+
+	if c.Comment != nil {
+		appendErr(c.commentConfigValid())
+		if c.Comment.Template != "" {
+			if _, err := os.Stat(c.Comment.Template); err != nil {
+				appendErr(fmt.Errorf("comment.template: %s does not exist", c.Comment.Template))
+			}
+		}
+	}
+
+	if c.Diff != nil {
+		appendErr(c.diffConfigValid())
+		appendErr(validateDatastores("diff.datastores", c.Diff.Datastores, validateDatastore))
+	}
+
+	if c.Central != nil {
+		appendErr(c.centralConfigValid())
+		appendErr(validateDatastores("central.reports.datastores", c.Central.Reports.Datastores, validateDatastore))
+		appendErr(validateDatastores("central.badges.datastores", c.Central.Badges.Datastores, validateDatastore))
+		if c.Central.ReReport != nil {
+			appendErr(validateDatastores("central.reReport.datastores", c.Central.ReReport.Datastores, validateDatastore))
+		}
+	}
+
+	if len(c.Policy) > 0 {
+		appendErr(c.PolicyConfigReady())
+	}
+
+	return result.ErrorOrNil()
+}
+
+// validateCoverage checks the coverage config and passes each problem found to appendErr.
+func (c *Config) validateCoverage(appendErr func(err error)) {
+	appendErr(c.CoverageConfigReadyOnLocal())
+	for _, p := range c.Coverage.Paths {
+		if p == "-" {
+			continue
+		}
+		if _, err := os.Stat(p); err != nil {
+			appendErr(fmt.Errorf("coverage.paths: %s does not exist", p))
+		}
+	}
+	if _, err := c.CoverageBaselineRef(); err != nil {
+		appendErr(err)
+	} else if c.Coverage.Baseline != "" && (c.Diff == nil || len(c.Diff.Datastores) == 0) {
+		appendErr(errors.New("coverage.baseline: diff.datastores: is not set"))
+	}
+	if _, err := parseTolerance("coverage.acceptableDiffTolerance", c.Coverage.AcceptableDiffTolerance); err != nil {
+		appendErr(err)
+	}
+	if c.Coverage.Pattern != "" && !doublestar.ValidatePattern(c.Coverage.Pattern) {
+		appendErr(fmt.Errorf("coverage.pattern: invalid pattern (%s)", c.Coverage.Pattern))
+	}
+	if c.Coverage.Acceptable.Condition != "" {
+		if _, err := patchPercentAcceptable(0, 0, 0, c.Coverage.Acceptable.Condition); err != nil {
+			appendErr(fmt.Errorf("coverage.acceptable: invalid condition (%s): %w", c.Coverage.Acceptable.Condition, err))
+		}
+	}
+	for i, p := range c.Coverage.Acceptable.Paths {
+		if p.Path == "" {
+			appendErr(fmt.Errorf("coverage.acceptable.paths[%d].path: is not set", i))
+		}
+		if p.Condition == "" {
+			appendErr(fmt.Errorf("coverage.acceptable.paths[%d].condition: is not set", i))
+		} else if _, err := percentAcceptable(0, 0, p.Condition); err != nil {
+			appendErr(fmt.Errorf("coverage.acceptable.paths[%d]: invalid condition (%s): %w", i, p.Condition, err))
+		}
+	}
+	for _, label := range c.CoverageLabelNames() {
+		l := c.Coverage.Labels[label]
+		if l.Acceptable == "" {
+			continue
+		}
+		if _, err := percentAcceptable(0, 0, l.Acceptable); err != nil {
+			appendErr(fmt.Errorf("coverage.labels.%s.acceptable: invalid condition (%s): %w", label, l.Acceptable, err))
+		}
+	}
+	appendErr(validateBadgePath("coverage.badge.path", c.Coverage.Badge.Path))
+	for i, p := range c.Coverage.Badge.Paths {
+		appendErr(validateBadgePath(fmt.Sprintf("coverage.badge.paths[%d]", i), p))
+	}
+	if c.Coverage.Patch != nil {
+		appendErr(validateCoveragePatch(c.Coverage.Patch))
+	}
+	appendErr(validateCoveragePathPatterns(c.Coverage))
+}
+
+// validateCoveragePathPatterns checks the path patterns of the files used by the conditions of the code coverage.
+// An invalid pattern would otherwise match no files and silently pass or fail the conditions.
+func validateCoveragePathPatterns(cov *Coverage) error {
+	if cov == nil {
+		return nil
+	}
+	var result *multierror.Error
+	validate := func(section, pattern string) {
+		if !doublestar.ValidatePattern(pattern) {
+			result = multierror.Append(result, fmt.Errorf("%s: invalid pattern (%s)", section, pattern))
+		}
+	}
+	labels := make([]string, 0, len(cov.Labels))
+	for label := range cov.Labels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		if l := cov.Labels[label]; l != nil {
+			validate(fmt.Sprintf("coverage.labels.%s", label), l.Path)
+		}
+	}
+	if cov.Critical != nil {
+		for i, p := range cov.Critical.Paths {
+			validate(fmt.Sprintf("coverage.critical.paths[%d]", i), p)
+		}
+	}
+	for i, p := range cov.Acceptable.Paths {
+		if p != nil && p.Path != "" {
+			validate(fmt.Sprintf("coverage.acceptable.paths[%d].path", i), p.Path)
+		}
+	}
+	if ri := cov.Acceptable.RequireImprovement; ri != nil {
+		for i, p := range ri.Paths {
+			validate(fmt.Sprintf("coverage.acceptable.requireImprovement.paths[%d]", i), p)
+		}
+	}
+	return result.ErrorOrNil()
+}
+
+func validateCoveragePatch(p *CoveragePatch) error {
+	switch p.MissingFiles {
+	case "", CoveragePatchMissingFilesSkip, CoveragePatchMissingFilesUncovered:
+		return nil
+	default:
+		return fmt.Errorf("invalid coverage.patch.missingFiles: %s", p.MissingFiles)
+	}
+}
+
+func validateDatastores(section string, datastores []string, validateDatastore func(u string) error) error {
+	if validateDatastore == nil {
+		return nil
+	}
+	var result *multierror.Error
+	for i, u := range datastores {
+		if err := validateDatastore(u); err != nil {
+			result = multierror.Append(result, fmt.Errorf("%s[%d]: %w", section, i, err))
+		}
+	}
+	return result.ErrorOrNil()
+}
+
+// validateBadgePath checks that the badge can be written to path, i.e. the nearest existing ancestor directory is a writable directory.
+func validateBadgePath(section, path string) error {
+	if path == "" {
+		return nil
+	}
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		return fmt.Errorf("%s: %s is a directory", section, path)
+	}
+	dir := filepath.Dir(filepath.Clean(path))
+	for {
+		fi, err := os.Stat(dir)
+		if err == nil {
+			if !fi.IsDir() {
+				return fmt.Errorf("%s: %s is not a directory", section, dir)
+			}
+			if fi.Mode().Perm()&0222 == 0 {
+				return fmt.Errorf("%s: %s is not writable", section, dir)
+			}
+			return nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	cp := filepath.Join(dir, "coverage.out")
+	if err := os.WriteFile(cp, []byte("mode: set\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, []byte{}, 0600); err != nil {
+		t.Fatal(err)
+	}
+	invalidDatastore := func(u string) error {
+		if strings.HasPrefix(u, "invalid://") {
+			return errors.New("invalid datastore")
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name string
+		c    *Config
+		want []string
+	}{
+		{
+			"valid",
+			&Config{
+				Repository: "owner/repo",
+				Coverage: &Coverage{
+					Paths:      []string{cp},
+					Acceptable: CoverageAcceptable{Condition: "current >= 60%"},
+					Badge:      CoverageBadge{Path: filepath.Join(dir, "docs", "coverage.svg")},
+				},
+				Report: &Report{Datastores: []string{"local://reports"}},
+			},
+			nil,
+		},
+		{
+			"all errors",
+			&Config{
+				Repository: "owner",
+				Coverage: &Coverage{
+					Paths:      []string{filepath.Join(dir, "missing.out")},
+					Acceptable: CoverageAcceptable{Condition: "current >="},
+					Badge:      CoverageBadge{Path: filepath.Join(file, "coverage.svg")},
+					Patch:      &CoveragePatch{MissingFiles: "ignore"},
+				},
+				TestExecutionTime: &TestExecutionTime{Acceptable: "1min &&"},
+				Report:            &Report{Datastores: []string{"invalid://reports"}},
+				Diff:              &Diff{},
+				Central:           &Central{},
+			},
+			[]string{
+				"repository:",
+				"coverage.paths:",
+				"coverage.acceptable:",
+				"coverage.badge.path:",
+				"invalid coverage.patch.missingFiles: ignore",
+				"testExecutionTime.acceptable:",
+				"report.datastores[0]:",
+				"diff.path: and diff.datastores: are not set",
+				"central.reports.datastores is not set",
+			},
+		},
+		{
+			"codeToTestRatio.groups",
+			&Config{
+				CodeToTestRatio: &CodeToTestRatio{
+					Code: []string{"**/*.go"},
+					Groups: []*CodeToTestRatioGroup{
+						{Name: "go", Test: []string{"**/*_test.go"}},
+						{Name: "go", Test: []string{"**/*.test.ts"}},
+						{Test: []string{"**/*.test.js"}},
+						{Name: "python"},
+					},
+				},
+			},
+			[]string{
+				"codeToTestRatio.code: and codeToTestRatio.test: can not be used with codeToTestRatio.groups:",
+				"codeToTestRatio.groups[1].name: go is duplicated",
+				"codeToTestRatio.groups[2].name: is not set",
+				"codeToTestRatio.groups[3].test: is not set",
+			},
+		},
+		{
+			"comment",
+			&Config{
+				Comment: &Comment{Location: "footer"},
+			},
+			[]string{
+				"invalid comment.location: footer",
+			},
+		},
+		{
+			"comment.deletePrevious: and comment.updatePrevious:",
+			&Config{
+				Comment: &Comment{DeletePrevious: true, UpdatePrevious: true},
+			},
+			[]string{
+				"comment.deletePrevious: and comment.updatePrevious: cannot be enabled at the same time",
+			},
+		},
+		{
+			"coverage path patterns",
+			&Config{
+				Coverage: &Coverage{
+					Paths:    []string{cp},
+					Labels:   map[string]*CoverageLabel{"api": {Path: "api/[**"}, "web": {Path: "web/**"}},
+					Critical: &CoverageCritical{Paths: []string{"auth/**", "crypto/{a,b"}},
+					Acceptable: CoverageAcceptable{
+						Paths:              []*CoverageAcceptablePath{{Path: "pkg/[", Condition: "80%"}},
+						RequireImprovement: &CoverageRequireImprovement{Delta: "1%", Paths: []string{"core/[a-"}},
+					},
+				},
+			},
+			[]string{
+				"coverage.labels.api: invalid pattern (api/[**)",
+				"coverage.critical.paths[1]: invalid pattern (crypto/{a,b)",
+				"coverage.acceptable.paths[0].path: invalid pattern (pkg/[)",
+				"coverage.acceptable.requireImprovement.paths[0]: invalid pattern (core/[a-)",
+			},
+		},
+		{
+			"named coverages",
+			&Config{
+				Repository: "owner/repo",
+				Coverages: []*Coverage{
+					{Name: "unit", Paths: []string{"testdata"}},
+					{Name: "integration", Paths: []string{filepath.Join("testdata", "missing.out")}, Acceptable: CoverageAcceptable{Condition: "current >="}},
+				},
+			},
+			[]string{
+				"coverage[1]: coverage.paths:",
+				"coverage[1]: coverage.acceptable:",
+			},
+		},
+		{
+			"duplicate names of coverages",
+			&Config{
+				Repository: "owner/repo",
+				Coverages: []*Coverage{
+					{Name: "unit", Paths: []string{"testdata"}},
+					{Name: "unit", Paths: []string{"testdata"}},
+				},
+			},
+			[]string{
+				"coverage[1].name: unit is duplicated",
+			},
+		},
+		{
+			"single target reports with named coverages",
+			&Config{
+				Repository: "owner/repo",
+				Coverages: []*Coverage{
+					{Name: "unit", Paths: []string{"testdata"}},
+					{Name: "integration", Paths: []string{"testdata"}},
+				},
+				Report: &Report{Path: "report.json", Status: true},
+			},
+			[]string{
+				"report.path:, report.status: can not be used with the named coverage configs of coverage:",
+			},
+		},
+		{
+			"badge manifest with named coverages",
+			&Config{
+				Repository: "owner/repo",
+				Coverages: []*Coverage{
+					{Name: "unit", Paths: []string{"testdata"}},
+					{Name: "integration", Paths: []string{"testdata"}},
+				},
+				Badge: &Badge{Manifest: &BadgeManifest{Path: "badges.json"}},
+			},
+			[]string{
+				"badge.manifest: can not be used with the named coverage configs of coverage:",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.c.Validate(invalidDatastore)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("got %v\nwant nil", err)
+				}
+				return
+			}
+			var merr *multierror.Error
+			if !errors.As(err, &merr) {
+				t.Fatalf("got %v\nwant multierror", err)
+			}
+			if got := len(merr.Errors); got != len(tt.want) {
+				t.Errorf("got %v\nwant %v: %v", got, len(tt.want), err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("got %v\nwant containing %q", err, w)
+				}
+			}
+		})
+	}
+}
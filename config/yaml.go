@@ -1,7 +1,9 @@
 package config
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/goccy/go-yaml"
 	"github.com/k1LoW/duration"
@@ -11,13 +13,16 @@ import (
 var commentRe = regexp.MustCompile(`(?m)^comment:`)
 var pushRe = regexp.MustCompile(`(?m)^push:`)
 var centralPushRe = regexp.MustCompile(`(?m)^\s+push:`)
+var hexColorRe = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
 
 func (c *Config) UnmarshalYAML(data []byte) error {
 	s := struct {
 		Repository        string             `yaml:"repository"`
-		Coverage          *Coverage          `yaml:"coverage"`
+		Coverage          any                `yaml:"coverage"`
 		CodeToTestRatio   *CodeToTestRatio   `yaml:"codeToTestRatio,omitempty"`
 		TestExecutionTime *TestExecutionTime `yaml:"testExecutionTime,omitempty"`
+		DocCoverage       *DocCoverage       `yaml:"docCoverage,omitempty"`
+		Badge             *Badge             `yaml:"badge,omitempty"`
 		Report            *Report            `yaml:"report,omitempty"`
 		Central           *Central           `yaml:"central,omitempty"`
 		Push              any                `yaml:"push,omitempty"`
@@ -25,22 +30,64 @@ func (c *Config) UnmarshalYAML(data []byte) error {
 		Summary           *Summary           `yaml:"summary,omitempty"`
 		Body              *Body              `yaml:"body,omitempty"`
 		Diff              *Diff              `yaml:"diff,omitempty"`
+		Policy            []*Policy          `yaml:"policy,omitempty"`
+		Datastore         *Datastore         `yaml:"datastore,omitempty"`
 		Timeout           string             `yaml:"timeout,omitempty"`
 		Locale            string             `yaml:"locale,omitempty"`
+		If                string             `yaml:"if,omitempty"`
 	}{}
 	err := yaml.Unmarshal(data, &s)
 	if err != nil {
 		return err
 	}
 	c.Repository = s.Repository
-	c.Coverage = s.Coverage
+	// coverage: accepts a coverage config or a list of named coverage configs
+	switch v := s.Coverage.(type) {
+	case nil:
+	case []any:
+		for i, e := range v {
+			tmp, err := yaml.Marshal(e)
+			if err != nil {
+				return err
+			}
+			cov := &Coverage{}
+			if err := yaml.Unmarshal(tmp, cov); err != nil {
+				return fmt.Errorf("coverage[%d]: %w", i, err)
+			}
+			if err := validateCoveragePathPatterns(cov); err != nil {
+				return fmt.Errorf("coverage[%d]: %w", i, err)
+			}
+			c.Coverages = append(c.Coverages, cov)
+		}
+	default:
+		tmp, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		cov := &Coverage{}
+		if err := yaml.Unmarshal(tmp, cov); err != nil {
+			return err
+		}
+		if err := validateCoveragePathPatterns(cov); err != nil {
+			return err
+		}
+		c.Coverage = cov
+	}
 	c.CodeToTestRatio = s.CodeToTestRatio
 	c.TestExecutionTime = s.TestExecutionTime
+	c.DocCoverage = s.DocCoverage
+	c.Badge = s.Badge
 	c.Report = s.Report
 	c.Central = s.Central
 	c.Summary = s.Summary
 	c.Body = s.Body
 	c.Diff = s.Diff
+	c.Policy = s.Policy
+	c.Datastore = s.Datastore
+	c.If = s.If
+	if c.Datastore != nil && c.Datastore.GitHub != nil && c.Datastore.GitHub.Retries != nil && *c.Datastore.GitHub.Retries < 0 {
+		return fmt.Errorf("invalid datastore.github.retries: %d", *c.Datastore.GitHub.Retries)
+	}
 	if s.Timeout == "" {
 		s.Timeout = defaultTimeout
 	}
@@ -101,21 +148,45 @@ func (c *Config) UnmarshalYAML(data []byte) error {
 
 func (c *Central) UnmarshalYAML(data []byte) error {
 	s := struct {
-		Root     string         `yaml:"root"`
-		Reports  CentralReports `yaml:"reports"`
-		Badges   CentralBadges  `yaml:"badges"`
-		Push     any            `yaml:"push,omitempty"`
-		ReReport *Report        `yaml:"reReport,omitempty"`
-		If       string         `yaml:"if,omitempty"`
+		Root         string               `yaml:"root"`
+		Reports      CentralReports       `yaml:"reports"`
+		Badges       CentralBadges        `yaml:"badges"`
+		Push         any                  `yaml:"push,omitempty"`
+		ReReport     *Report              `yaml:"reReport,omitempty"`
+		JSON         *CentralJSON         `yaml:"json,omitempty"`
+		Sort         string               `yaml:"sort,omitempty"`
+		Cache        *CentralCache        `yaml:"cache,omitempty"`
+		Repositories *CentralRepositories `yaml:"repositories,omitempty"`
+		Summary      *CentralSummary      `yaml:"summary,omitempty"`
+		Concurrency  int                  `yaml:"concurrency,omitempty"`
+		If           string               `yaml:"if,omitempty"`
 	}{}
 	err := yaml.Unmarshal(data, &s)
 	if err != nil {
 		return err
 	}
+	switch s.Sort {
+	case "", CentralSortRepository, CentralSortCoverage:
+	default:
+		return fmt.Errorf("invalid central.sort: %s", s.Sort)
+	}
+	if s.Summary != nil {
+		switch s.Summary.Average {
+		case "", CentralSummaryAverageWeighted, CentralSummaryAverageSimple:
+		default:
+			return fmt.Errorf("invalid central.summary.average: %s", s.Summary.Average)
+		}
+	}
 	c.Root = s.Root
 	c.Reports = s.Reports
 	c.Badges = s.Badges
 	c.ReReport = s.ReReport
+	c.JSON = s.JSON
+	c.Sort = s.Sort
+	c.Cache = s.Cache
+	c.Repositories = s.Repositories
+	c.Summary = s.Summary
+	c.Concurrency = s.Concurrency
 	c.If = s.If
 
 	switch v := s.Push.(type) {
@@ -139,3 +210,414 @@ func (c *Central) UnmarshalYAML(data []byte) error {
 
 	return nil
 }
+
+func (a *CoverageAcceptable) UnmarshalYAML(data []byte) error {
+	var cond string
+	if err := yaml.Unmarshal(data, &cond); err == nil {
+		if strings.TrimSpace(cond) == coverageAcceptableRatchet {
+			a.Ratchet = &CoverageRatchet{}
+			return nil
+		}
+		a.Condition = cond
+		return nil
+	}
+	s := struct {
+		Condition           string                      `yaml:"condition,omitempty"`
+		Endpoint            string                      `yaml:"endpoint,omitempty"`
+		Timeout             string                      `yaml:"timeout,omitempty"`
+		FailOpen            bool                        `yaml:"failOpen,omitempty"`
+		SkipNetDeletions    bool                        `yaml:"skipNetDeletions,omitempty"`
+		AllowSkipRegression bool                        `yaml:"allowSkipRegression,omitempty"`
+		Window              int                         `yaml:"window,omitempty"`
+		RequireImprovement  *CoverageRequireImprovement `yaml:"requireImprovement,omitempty"`
+		MaxUncoveredFuncs   *int                        `yaml:"maxUncoveredFuncs,omitempty"`
+		MaxViolations       int                         `yaml:"maxViolations,omitempty"`
+		RelativeTo          string                      `yaml:"relativeTo,omitempty"`
+		OrgDatastores       []string                    `yaml:"orgDatastores,omitempty"`
+		Mode                string                      `yaml:"mode,omitempty"`
+		Paths               []*CoverageAcceptablePath   `yaml:"paths,omitempty"`
+		EachFile            string                      `yaml:"eachFile,omitempty"`
+		Ratchet             any                         `yaml:"ratchet,omitempty"`
+	}{}
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	a.Condition = s.Condition
+	a.Endpoint = s.Endpoint
+	a.FailOpen = s.FailOpen
+	a.SkipNetDeletions = s.SkipNetDeletions
+	a.AllowSkipRegression = s.AllowSkipRegression
+	a.Window = s.Window
+	a.RequireImprovement = s.RequireImprovement
+	a.MaxUncoveredFuncs = s.MaxUncoveredFuncs
+	a.MaxViolations = s.MaxViolations
+	a.RelativeTo = s.RelativeTo
+	a.OrgDatastores = s.OrgDatastores
+	a.Paths = s.Paths
+	a.EachFile = s.EachFile
+	// coverage.acceptable.ratchet accepts a boolean or the config with tolerance.
+	switch v := s.Ratchet.(type) {
+	case nil:
+	case bool:
+		if v {
+			a.Ratchet = &CoverageRatchet{}
+		}
+	default:
+		tmp, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		rc := &CoverageRatchet{}
+		if err := yaml.Unmarshal(tmp, rc); err != nil {
+			return err
+		}
+		if _, err := parseTolerance("coverage.acceptable.ratchet.tolerance", rc.Tolerance); err != nil {
+			return err
+		}
+		a.Ratchet = rc
+	}
+	switch s.Mode {
+	case "", CoverageAcceptableModeError, CoverageAcceptableModeWarn:
+		a.Mode = s.Mode
+	default:
+		return fmt.Errorf("invalid coverage.acceptable.mode: %s", s.Mode)
+	}
+	if s.Timeout != "" {
+		d, err := duration.Parse(s.Timeout)
+		if err != nil {
+			return err
+		}
+		a.Timeout = d
+	}
+	return nil
+}
+
+func (cc *CentralCache) UnmarshalYAML(data []byte) error {
+	s := struct {
+		Dir string `yaml:"dir,omitempty"`
+		TTL string `yaml:"ttl,omitempty"`
+	}{}
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	cc.Dir = s.Dir
+	if s.TTL != "" {
+		d, err := duration.Parse(s.TTL)
+		if err != nil {
+			return fmt.Errorf("invalid central.cache.ttl: %w", err)
+		}
+		cc.TTL = d
+	}
+	return nil
+}
+
+func (l *CoverageLabel) UnmarshalYAML(data []byte) error {
+	var path string
+	if err := yaml.Unmarshal(data, &path); err == nil {
+		l.Path = path
+		return nil
+	}
+	s := struct {
+		Path       string `yaml:"path"`
+		Acceptable string `yaml:"acceptable,omitempty"`
+	}{}
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	l.Path = s.Path
+	l.Acceptable = s.Acceptable
+	return nil
+}
+
+func (h *CoverageBadgeHeatmap) UnmarshalYAML(data []byte) error {
+	var enable bool
+	if err := yaml.Unmarshal(data, &enable); err == nil {
+		h.Enable = enable
+		return nil
+	}
+	s := struct {
+		Enable *bool  `yaml:"enable,omitempty"`
+		Path   string `yaml:"path,omitempty"`
+		Days   int    `yaml:"days,omitempty"`
+	}{}
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	// The mapping form enables the heatmap unless `enable: false` is set explicitly.
+	h.Enable = s.Enable == nil || *s.Enable
+	h.Path = s.Path
+	h.Days = s.Days
+	return nil
+}
+
+// UnmarshalYAML accepts coverage.badge.colors: as an alias of coverage.badge.thresholds:.
+func (b *CoverageBadge) UnmarshalYAML(data []byte) error {
+	s := struct {
+		Path               string                    `yaml:"path,omitempty"`
+		Paths              []string                  `yaml:"paths,omitempty"`
+		Style              string                    `yaml:"style,omitempty"`
+		Label              string                    `yaml:"label,omitempty"`
+		ColorFromDisplayed bool                      `yaml:"colorFromDisplayed,omitempty"`
+		ShowTrend          bool                      `yaml:"showTrend,omitempty"`
+		Heatmap            *CoverageBadgeHeatmap     `yaml:"heatmap,omitempty"`
+		Thresholds         []*CoverageBadgeThreshold `yaml:"thresholds,omitempty"`
+		Colors             []*CoverageBadgeThreshold `yaml:"colors,omitempty"`
+	}{}
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	b.Path = s.Path
+	b.Paths = s.Paths
+	b.Style = s.Style
+	b.Label = s.Label
+	b.ColorFromDisplayed = s.ColorFromDisplayed
+	b.ShowTrend = s.ShowTrend
+	b.Heatmap = s.Heatmap
+	switch {
+	case len(s.Thresholds) > 0 && len(s.Colors) > 0:
+		return fmt.Errorf("coverage.badge.thresholds: and coverage.badge.colors: cannot be set at the same time")
+	case len(s.Colors) > 0:
+		if err := validateCoverageBadgeThresholds("coverage.badge.colors", s.Colors); err != nil {
+			return err
+		}
+		b.Thresholds = s.Colors
+	default:
+		if err := validateCoverageBadgeThresholds("coverage.badge.thresholds", s.Thresholds); err != nil {
+			return err
+		}
+		b.Thresholds = s.Thresholds
+	}
+	return nil
+}
+
+func (ts *CoverageBadgeThresholds) UnmarshalYAML(data []byte) error {
+	var s []*CoverageBadgeThreshold
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if err := validateCoverageBadgeThresholds("coverage.badge.thresholds", s); err != nil {
+		return err
+	}
+	*ts = s
+	return nil
+}
+
+func validateCoverageBadgeThresholds(section string, s []*CoverageBadgeThreshold) error {
+	for i, t := range s {
+		if t == nil {
+			return fmt.Errorf("%s[%d]: is empty", section, i)
+		}
+		var prev *float64
+		if i > 0 {
+			prev = &s[i-1].Min
+		}
+		if err := validateBadgeThreshold(section, i, t.Min, t.Color, prev); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ts *CodeToTestRatioBadgeThresholds) UnmarshalYAML(data []byte) error {
+	var s []*CodeToTestRatioBadgeThreshold
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	for i, t := range s {
+		if t == nil {
+			return fmt.Errorf("codeToTestRatio.badge.thresholds[%d]: is empty", i)
+		}
+		var prev *float64
+		if i > 0 {
+			prev = &s[i-1].Min
+		}
+		if err := validateBadgeThreshold("codeToTestRatio.badge.thresholds", i, t.Min, t.Color, prev); err != nil {
+			return err
+		}
+	}
+	*ts = s
+	return nil
+}
+
+func (ts *TestExecutionTimeBadgeThresholds) UnmarshalYAML(data []byte) error {
+	var s []*struct {
+		Max   string `yaml:"max"`
+		Color string `yaml:"color"`
+	}
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	var thresholds TestExecutionTimeBadgeThresholds
+	for i, t := range s {
+		if t == nil {
+			return fmt.Errorf("testExecutionTime.badge.thresholds[%d]: is empty", i)
+		}
+		if !hexColorRe.MatchString(t.Color) {
+			return fmt.Errorf("testExecutionTime.badge.thresholds[%d].color: invalid hex color: %q", i, t.Color)
+		}
+		d, err := duration.Parse(t.Max)
+		if err != nil {
+			return fmt.Errorf("testExecutionTime.badge.thresholds[%d].max: %w", i, err)
+		}
+		if i > 0 && d <= thresholds[i-1].Max {
+			return fmt.Errorf("testExecutionTime.badge.thresholds[%d].max: must be greater than the previous max (%v): %v", i, thresholds[i-1].Max, d)
+		}
+		thresholds = append(thresholds, &TestExecutionTimeBadgeThreshold{Max: d, Color: t.Color})
+	}
+	*ts = thresholds
+	return nil
+}
+
+func validateBadgeThreshold(key string, i int, min float64, color string, prev *float64) error {
+	if !hexColorRe.MatchString(color) {
+		return fmt.Errorf("%s[%d].color: invalid hex color: %q", key, i, color)
+	}
+	if prev != nil && min >= *prev {
+		return fmt.Errorf("%s[%d].min: must be less than the previous min (%v): %v", key, i, *prev, min)
+	}
+	return nil
+}
+
+func (ri *CoverageRequireImprovement) UnmarshalYAML(data []byte) error {
+	var delta string
+	if err := yaml.Unmarshal(data, &delta); err == nil {
+		ri.Delta = delta
+		return nil
+	}
+	s := struct {
+		Delta string   `yaml:"delta"`
+		Paths []string `yaml:"paths,omitempty"`
+	}{}
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	ri.Delta = s.Delta
+	ri.Paths = s.Paths
+	return nil
+}
+
+func (cc *CoverageCritical) UnmarshalYAML(data []byte) error {
+	var paths []string
+	if err := yaml.Unmarshal(data, &paths); err == nil {
+		cc.Paths = paths
+		return nil
+	}
+	s := struct {
+		Paths      []string `yaml:"paths"`
+		Acceptable string   `yaml:"acceptable,omitempty"`
+	}{}
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	cc.Paths = s.Paths
+	cc.Acceptable = s.Acceptable
+	return nil
+}
+
+func (r *Report) UnmarshalYAML(data []byte) error {
+	s := struct {
+		If            string           `yaml:"if,omitempty"`
+		Path          string           `yaml:"path,omitempty"`
+		Datastores    any              `yaml:"datastores,omitempty"`
+		StoreOnPass   bool             `yaml:"storeOnPass,omitempty"`
+		StoreByRef    bool             `yaml:"storeByRef,omitempty"`
+		Gzip          bool             `yaml:"gzip,omitempty"`
+		MaxConcurrent int              `yaml:"maxConcurrent,omitempty"`
+		MaxFiles      int              `yaml:"maxFiles,omitempty"`
+		Timestamp     string           `yaml:"timestamp,omitempty"`
+		Codecov       *ReportCodecov   `yaml:"codecov,omitempty"`
+		Cobertura     *ReportCobertura `yaml:"cobertura,omitempty"`
+		Statsd        *ReportStatsd    `yaml:"statsd,omitempty"`
+		Release       *ReportRelease   `yaml:"release,omitempty"`
+		HTML          *ReportHTML      `yaml:"html,omitempty"`
+		GitLab        *ReportGitLab    `yaml:"gitlab,omitempty"`
+		Azure         *ReportAzure     `yaml:"azure,omitempty"`
+		HTTP          *ReportHTTP      `yaml:"http,omitempty"`
+		Status        bool             `yaml:"status,omitempty"`
+		Slack         *ReportSlack     `yaml:"slack,omitempty"`
+		Issue         int              `yaml:"issue,omitempty"`
+	}{}
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	r.If = s.If
+	r.Path = s.Path
+	r.StoreOnPass = s.StoreOnPass
+	r.StoreByRef = s.StoreByRef
+	r.Gzip = s.Gzip
+	r.MaxConcurrent = s.MaxConcurrent
+	r.MaxFiles = s.MaxFiles
+	r.Timestamp = s.Timestamp
+	r.Codecov = s.Codecov
+	r.Cobertura = s.Cobertura
+	r.Statsd = s.Statsd
+	r.Release = s.Release
+	r.HTML = s.HTML
+	r.GitLab = s.GitLab
+	r.Azure = s.Azure
+	r.HTTP = s.HTTP
+	r.Status = s.Status
+	r.Slack = s.Slack
+	r.Issue = s.Issue
+
+	// report.datastores accepts a datastore URL, an entry with its own `if` section, or a list of them.
+	var entries []any
+	switch v := s.Datastores.(type) {
+	case nil:
+	case []any:
+		entries = v
+	default:
+		entries = []any{v}
+	}
+	for i, e := range entries {
+		switch v := e.(type) {
+		case string:
+			r.Datastores = append(r.Datastores, v)
+		case map[string]any:
+			tmp, err := yaml.Marshal(v)
+			if err != nil {
+				return err
+			}
+			d := &ReportDatastore{}
+			if err := yaml.Unmarshal(tmp, d); err != nil {
+				return err
+			}
+			if d.URL == "" {
+				return fmt.Errorf("report.datastores[%d].url: is not set", i)
+			}
+			if d.If == "" {
+				r.Datastores = append(r.Datastores, d.URL)
+				continue
+			}
+			r.ConditionalDatastores = append(r.ConditionalDatastores, d)
+		default:
+			return fmt.Errorf("invalid report.datastores[%d]: %v", i, v)
+		}
+	}
+	return nil
+}
+
+func (d *Diff) UnmarshalYAML(data []byte) error {
+	s := struct {
+		Path           string   `yaml:"path,omitempty"`
+		Datastores     []string `yaml:"datastores,omitempty"`
+		BaselineMaxAge string   `yaml:"baselineMaxAge,omitempty"`
+		CompareAgainst string   `yaml:"compareAgainst,omitempty"`
+		If             string   `yaml:"if,omitempty"`
+	}{}
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	d.Path = s.Path
+	d.Datastores = s.Datastores
+	d.CompareAgainst = s.CompareAgainst
+	d.If = s.If
+	if s.BaselineMaxAge != "" {
+		ma, err := duration.Parse(s.BaselineMaxAge)
+		if err != nil {
+			return err
+		}
+		d.BaselineMaxAge = ma
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package coverage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/cover"
+)
+
+const cacheExt = ".cache"
+
+// blockValues is the number of values stored per block: start line, start col, end line, end col, num stmt and count.
+const blockValues = 6
+
+// profilePackage is the profile lines of a package.
+type profilePackage struct {
+	lines [][]byte
+	hash  hash.Hash
+}
+
+// parseProfilesWithCache parses a Go coverage profile package by package.
+// The parsed file coverages of each package are cached in dir keyed by the hash of the profile lines of the package,
+// so that only packages whose profile lines have changed are parsed again.
+// Each report path has its own subdirectory of dir, so that pruning the outdated entries of a profile does not remove the entries of the other profiles.
+// pp is the path of the profile to be parsed, which differs from the report path rp when rp is converted (e.g. GOCOVERDIR to a temporary profile).
+// It also returns the mode of the profile.
+func (g *Gocover) parseProfilesWithCache(rp, pp, dir string) (string, FileCoverages, error) {
+	ns, err := cacheNamespace(rp)
+	if err != nil {
+		return "", nil, err
+	}
+	dir = filepath.Join(dir, ns)
+	if err := os.MkdirAll(dir, 0755); err != nil { // #nosec
+		return "", nil, err
+	}
+	mode, pkgs, err := splitProfileByPackage(pp)
+	if err != nil {
+		return "", nil, err
+	}
+	used := map[string]struct{}{}
+	var files FileCoverages
+	for _, pkg := range pkgs {
+		key := hex.EncodeToString(pkg.hash.Sum(nil))
+		used[key+cacheExt] = struct{}{}
+		cp := filepath.Join(dir, key+cacheExt)
+		if fcs, err := loadCache(cp); err == nil {
+			files = append(files, fcs...)
+			continue
+		}
+		buf := new(bytes.Buffer)
+		buf.WriteString(fmt.Sprintf("mode: %s\n", mode))
+		for _, l := range pkg.lines {
+			buf.Write(l)
+			buf.WriteByte('\n')
+		}
+		profiles, err := cover.ParseProfilesFromReader(buf)
+		if err != nil {
+			return "", nil, err
+		}
+		fcs := g.toFileCoverages(profiles)
+		if err := storeCache(cp, fcs); err != nil {
+			log.Printf("failed to store coverage cache: %s", err)
+		}
+		files = append(files, fcs...)
+	}
+	pruneCache(dir, used)
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].File < files[j].File
+	})
+	return mode, files, nil
+}
+
+// cacheNamespace returns the name of the subdirectory of the cache directory for the profile path.
+func cacheNamespace(rp string) (string, error) {
+	abs, err := filepath.Abs(rp)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256([]byte(abs))
+	return hex.EncodeToString(h[:8]), nil
+}
+
+func splitProfileByPackage(rp string) (string, map[string]*profilePackage, error) {
+	b, err := os.ReadFile(filepath.Clean(rp))
+	if err != nil {
+		return "", nil, err
+	}
+	first, rest, _ := bytes.Cut(b, []byte("\n"))
+	if !bytes.HasPrefix(first, []byte(gocoverModePrefix)) {
+		return "", nil, fmt.Errorf("%s is not Go coverage format", rp)
+	}
+	mode := strings.TrimSpace(strings.TrimPrefix(string(first), gocoverModePrefix))
+	pkgs := map[string]*profilePackage{}
+	for len(rest) > 0 {
+		var line []byte
+		line, rest, _ = bytes.Cut(rest, []byte("\n"))
+		line = bytes.TrimRight(line, "\r")
+		if len(line) == 0 {
+			continue
+		}
+		if bytes.HasPrefix(line, []byte(gocoverModePrefix)) {
+			// mode line of a concatenated profile
+			if err := sameMode(gocoverModePrefix+mode, string(line)); err != nil {
+				return "", nil, err
+			}
+			continue
+		}
+		i := bytes.LastIndexByte(line, ':')
+		if i < 0 {
+			return "", nil, fmt.Errorf("%s is not Go coverage format", rp)
+		}
+		dir := line[:i]
+		if j := bytes.LastIndexByte(dir, '/'); j >= 0 {
+			dir = dir[:j]
+		} else {
+			dir = nil
+		}
+		pkg, ok := pkgs[string(dir)]
+		if !ok {
+			pkg = &profilePackage{hash: sha256.New()}
+			_, _ = pkg.hash.Write([]byte(mode))
+			pkgs[string(dir)] = pkg
+		}
+		_, _ = pkg.hash.Write([]byte{'\n'})
+		_, _ = pkg.hash.Write(line)
+		pkg.lines = append(pkg.lines, line)
+	}
+	return mode, pkgs, nil
+}
+
+func loadCache(cp string) (FileCoverages, error) {
+	b, err := os.ReadFile(filepath.Clean(cp))
+	if err != nil {
+		return nil, err
+	}
+	r := bytes.NewReader(b)
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, errors.New("empty coverage cache")
+	}
+	fcs := make(FileCoverages, 0, n)
+	for i := uint64(0); i < n; i++ {
+		l, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		name := make([]byte, l)
+		if _, err := io.ReadFull(r, name); err != nil {
+			return nil, err
+		}
+		fc := NewFileCoverage(string(name), TypeStmt)
+		nb, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		// Allocate the values of all blocks at once.
+		values := make([]int, nb*blockValues)
+		blocks := make([]BlockCoverage, nb)
+		fc.Blocks = make(BlockCoverages, nb)
+		for j := range blocks {
+			v := values[j*blockValues : (j+1)*blockValues]
+			for k := range v {
+				x, err := binary.ReadVarint(r)
+				if err != nil {
+					return nil, err
+				}
+				v[k] = int(x)
+			}
+			blocks[j] = BlockCoverage{
+				Type:      TypeStmt,
+				StartLine: &v[0],
+				StartCol:  &v[1],
+				EndLine:   &v[2],
+				EndCol:    &v[3],
+				NumStmt:   &v[4],
+				Count:     &v[5],
+			}
+			fc.Blocks[j] = &blocks[j]
+			fc.Total += v[4]
+			if v[5] > 0 {
+				fc.Covered += v[4]
+			}
+		}
+		fcs = append(fcs, fc)
+	}
+	return fcs, nil
+}
+
+func storeCache(cp string, fcs FileCoverages) error {
+	buf := new(bytes.Buffer)
+	var tmp [binary.MaxVarintLen64]byte
+	putUvarint := func(x uint64) {
+		buf.Write(tmp[:binary.PutUvarint(tmp[:], x)])
+	}
+	putUvarint(uint64(len(fcs)))
+	for _, fc := range fcs {
+		putUvarint(uint64(len(fc.File)))
+		buf.WriteString(fc.File)
+		putUvarint(uint64(len(fc.Blocks)))
+		for _, b := range fc.Blocks {
+			for _, v := range []*int{b.StartLine, b.StartCol, b.EndLine, b.EndCol, b.NumStmt, b.Count} {
+				x := 0
+				if v != nil {
+					x = *v
+				}
+				buf.Write(tmp[:binary.PutVarint(tmp[:], int64(x))])
+			}
+		}
+	}
+	return os.WriteFile(cp, buf.Bytes(), 0600)
+}
+
+// pruneCache removes cache entries that were not used, since they belong to outdated profile lines.
+func pruneCache(dir string, used map[string]struct{}) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != cacheExt {
+			continue
+		}
+		if _, ok := used[e.Name()]; ok {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			log.Printf("failed to remove coverage cache: %s", err)
+		}
+	}
+}
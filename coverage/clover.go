@@ -135,23 +135,44 @@ func (c *Clover) ParseReport(path string) (*Coverage, string, error) {
 	return cov, rp, nil
 }
 
+// parseReportFile computes the coverage of the file from the <line type="stmt"> elements,
+// and falls back to the <metrics> summary only when the file has no statement lines.
 func parseReportFile(f CloverReportFile) *FileCoverage {
 	fcov := NewFileCoverage(f.Name, TypeLOC)
-	fcov.Covered = f.Metrics.Coveredstatements
-	fcov.Total = f.Metrics.Statements
+	lines := map[int]*BlockCoverage{}
 	for _, l := range f.Line {
 		if l.Type != "stmt" {
 			continue
 		}
+		if b, ok := lines[l.Num]; ok {
+			if l.Count > *b.Count {
+				c := l.Count
+				b.Count = &c
+			}
+			continue
+		}
 		sl := l.Num
 		el := l.Num
 		c := l.Count
-		fcov.Blocks = append(fcov.Blocks, &BlockCoverage{
+		b := &BlockCoverage{
 			Type:      TypeLOC,
 			StartLine: &sl,
 			EndLine:   &el,
 			Count:     &c,
-		})
+		}
+		lines[l.Num] = b
+		fcov.Blocks = append(fcov.Blocks, b)
+	}
+	if len(fcov.Blocks) == 0 {
+		fcov.Covered = f.Metrics.Coveredstatements
+		fcov.Total = f.Metrics.Statements
+		return fcov
+	}
+	for _, b := range fcov.Blocks {
+		fcov.Total += 1
+		if *b.Count > 0 {
+			fcov.Covered += 1
+		}
 	}
 	return fcov
 }
@@ -1,6 +1,7 @@
 package coverage
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -77,3 +78,45 @@ func TestCloverParseAllFormat(t *testing.T) {
 		}
 	}
 }
+
+func TestCloverPreferLines(t *testing.T) {
+	xml := `<?xml version="1.0" encoding="UTF-8"?>
+<coverage generated="1625148427">
+  <project timestamp="1625148427">
+    <package name="App">
+      <file name="/src/App/Lines.php">
+        <line num="3" type="method" name="run" count="1"/>
+        <line num="4" type="stmt" count="1"/>
+        <line num="5" type="stmt" count="0"/>
+        <line num="5" type="stmt" count="2"/>
+        <line num="6" type="stmt" count="0"/>
+        <metrics statements="10" coveredstatements="10"/>
+      </file>
+      <file name="/src/App/Metrics.php">
+        <metrics statements="4" coveredstatements="1"/>
+      </file>
+      <file name="/src/App/Empty.php">
+        <metrics statements="0" coveredstatements="0"/>
+      </file>
+    </package>
+  </project>
+</coverage>`
+	p := filepath.Join(t.TempDir(), CloverDefaultPath)
+	if err := os.WriteFile(p, []byte(xml), 0600); err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := NewClover().ParseReport(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Total != 7 || got.Covered != 3 {
+		t.Errorf("got %v/%v\nwant %v/%v", got.Covered, got.Total, 3, 7)
+	}
+	fc, err := got.Files.FindByFile("/src/App/Lines.php")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fc.Total != 3 || fc.Covered != 2 {
+		t.Errorf("got %v/%v\nwant %v/%v", fc.Covered, fc.Total, 2, 3)
+	}
+}
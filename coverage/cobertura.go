@@ -3,8 +3,10 @@ package coverage
 import (
 	"encoding/xml"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 var _ Processor = (*Cobertura)(nil)
@@ -101,7 +103,7 @@ func (c *Cobertura) ParseReport(path string) (*Coverage, string, error) {
 	flm := map[string]BlockCoverages{}
 	for _, p := range r.Packages.Package {
 		for _, c := range p.Classes.Class {
-			n := c.Filename
+			n := coberturaFilename(r.Sources.Source, c.Filename)
 			f, ok := flm[n]
 			if !ok {
 				f = BlockCoverages{}
@@ -135,9 +137,45 @@ func (c *Cobertura) ParseReport(path string) (*Coverage, string, error) {
 		cov.Files = append(cov.Files, fcov)
 	}
 
+	// fallback to the attributes of the root element when no line is reported
+	if cov.Total == 0 && r.LinesValid > 0 {
+		cov.Total = r.LinesValid
+		cov.Covered = r.LinesCovered
+		if cov.Covered == 0 && r.LineRate > 0 {
+			cov.Covered = int(math.Round(r.LineRate * float64(r.LinesValid)))
+		}
+	}
+
 	return cov, rp, nil
 }
 
+// coberturaFilename returns the filename prefixed with the source path listed in <sources>.
+// When multiple sources are listed, the source in which the file exists is used.
+func coberturaFilename(sources []string, filename string) string {
+	if filepath.IsAbs(filename) {
+		return filename
+	}
+	var srcs []string
+	for _, s := range sources {
+		if s = strings.TrimSpace(s); s != "" && s != "." {
+			srcs = append(srcs, s)
+		}
+	}
+	if len(srcs) == 0 {
+		return filename
+	}
+	for _, s := range srcs {
+		p := filepath.Join(s, filename)
+		if fi, err := os.Stat(p); err == nil && !fi.IsDir() {
+			return p
+		}
+	}
+	if len(srcs) == 1 {
+		return filepath.Join(srcs[0], filename)
+	}
+	return filename
+}
+
 func (c *Cobertura) detectReportPath(path string) (string, error) {
 	p, err := os.Stat(path)
 	if err != nil {
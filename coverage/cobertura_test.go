@@ -1,6 +1,8 @@
 package coverage
 
 import (
+	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -60,3 +62,71 @@ func TestCoberturaParseAllFormat(t *testing.T) {
 		}
 	}
 }
+
+func TestCoberturaSources(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "tests"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "tests", "test_app.py"), []byte(""), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		sources string
+		want    string
+	}{
+		{"", "app.py"},
+		{"<source>/src/pkg</source>", filepath.Join("/src/pkg", "app.py")},
+		{fmt.Sprintf("<source>%s</source><source>%s</source>", filepath.Join(root, "src"), filepath.Join(root, "tests")), filepath.Join(root, "tests", "test_app.py")},
+	}
+	for _, tt := range tests {
+		filename := filepath.Base(tt.want)
+		xml := fmt.Sprintf(`<?xml version="1.0" ?>
+<coverage lines-valid="2" lines-covered="1" line-rate="0.5">
+	<sources>%s</sources>
+	<packages><package name="."><classes>
+		<class name="%s" filename="%s"><lines><line number="1" hits="1"/><line number="2" hits="0"/></lines></class>
+	</classes></package></packages>
+</coverage>`, tt.sources, filename, filename)
+		p := filepath.Join(t.TempDir(), CoberturaDefaultPath)
+		if err := os.WriteFile(p, []byte(xml), 0600); err != nil {
+			t.Fatal(err)
+		}
+		got, _, err := NewCobertura().ParseReport(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := got.Files[0].File; got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestCoberturaFallbackToLineRate(t *testing.T) {
+	tests := []struct {
+		attrs       string
+		wantTotal   int
+		wantCovered int
+	}{
+		{`lines-valid="10" lines-covered="7" line-rate="0.7"`, 10, 7},
+		{`lines-valid="10" line-rate="0.8"`, 10, 8},
+		{`line-rate="0.8"`, 0, 0},
+	}
+	for _, tt := range tests {
+		xml := fmt.Sprintf(`<?xml version="1.0" ?>
+<coverage %s>
+	<packages><package name="."><classes><class name="app.py" filename="app.py"><lines/></class></classes></package></packages>
+</coverage>`, tt.attrs)
+		p := filepath.Join(t.TempDir(), CoberturaDefaultPath)
+		if err := os.WriteFile(p, []byte(xml), 0600); err != nil {
+			t.Fatal(err)
+		}
+		got, _, err := NewCobertura().ParseReport(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Total != tt.wantTotal || got.Covered != tt.wantCovered {
+			t.Errorf("got %v/%v\nwant %v/%v", got.Covered, got.Total, tt.wantCovered, tt.wantTotal)
+		}
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/zhangyunhao116/skipmap"
 )
 
@@ -17,12 +19,19 @@ const (
 	TypeMerged Type = "merged"
 )
 
+// OtherFiles is the name of the file coverage aggregating the files truncated by TruncateFiles.
+const OtherFiles = "(other files)"
+
 type Coverage struct {
 	Type    Type          `json:"type"`
 	Format  string        `json:"format"`
 	Total   int           `json:"total"`
 	Covered int           `json:"covered"`
 	Files   FileCoverages `json:"files"`
+	// Mode is the mode of the Go coverage profile (set, count or atomic)
+	Mode string `json:"mode,omitempty"`
+	// CaseInsensitive is whether to match file paths case-insensitively on exclude, merge and label
+	CaseInsensitive bool `json:"-"`
 }
 
 type FileCoverage struct {
@@ -31,6 +40,7 @@ type FileCoverage struct {
 	Total   int            `json:"total"`
 	Covered int            `json:"covered"`
 	Blocks  BlockCoverages `json:"blocks,omitempty"`
+	Funcs   FuncCoverages  `json:"funcs,omitempty"`
 	cache   map[int]BlockCoverages
 }
 
@@ -70,12 +80,77 @@ func NewFileCoverage(file string, coverageType Type) *FileCoverage { //nostyle:r
 	}
 }
 
+func (c *Coverage) match(pattern, file string) (bool, error) {
+	if c.CaseInsensitive {
+		return doublestar.Match(strings.ToLower(pattern), strings.ToLower(file))
+	}
+	return doublestar.Match(pattern, file)
+}
+
 func (c *Coverage) DeleteBlockCoverages() {
 	for _, f := range c.Files {
 		f.Blocks = BlockCoverages{}
 	}
 }
 
+// TruncateFiles returns a copy of the coverage keeping the file coverages of the n worst covered files,
+// and aggregating the rest into a file coverage named OtherFiles. The totals of the coverage are kept as they are.
+func (c *Coverage) TruncateFiles(n int) *Coverage {
+	tc := *c
+	if n <= 0 || len(c.Files) <= n {
+		return &tc
+	}
+	fcs := make(FileCoverages, len(c.Files))
+	copy(fcs, c.Files)
+	sort.SliceStable(fcs, func(i, j int) bool {
+		ri, rj := fcs[i].coveredRatio(), fcs[j].coveredRatio()
+		if ri != rj {
+			return ri < rj
+		}
+		ui, uj := fcs[i].Total-fcs[i].Covered, fcs[j].Total-fcs[j].Covered
+		if ui != uj {
+			return ui > uj
+		}
+		return fcs[i].File < fcs[j].File
+	})
+	other := &FileCoverage{
+		Type: c.Type,
+		File: OtherFiles,
+	}
+	tc.Files = FileCoverages{}
+	for i, fc := range fcs {
+		if i < n {
+			cfc := *fc
+			tc.Files = append(tc.Files, &cfc)
+			continue
+		}
+		other.Total += fc.Total
+		other.Covered += fc.Covered
+	}
+	tc.Files = append(tc.Files, other)
+	return &tc
+}
+
+// IsTruncated returns true if the file coverages are truncated by TruncateFiles.
+func (c *Coverage) IsTruncated() bool {
+	if c == nil {
+		return false
+	}
+	for _, fc := range c.Files {
+		if fc.File == OtherFiles {
+			return true
+		}
+	}
+	return false
+}
+
+func (fc *FileCoverage) coveredRatio() float64 {
+	if fc.Total == 0 {
+		return 1
+	}
+	return float64(fc.Covered) / float64(fc.Total)
+}
+
 func (fc FileCoverages) FindByFile(file string) (*FileCoverage, error) { //nostyle:recvtype
 	for _, c := range fc {
 		if c.File == file {
@@ -85,6 +160,15 @@ func (fc FileCoverages) FindByFile(file string) (*FileCoverage, error) { //nosty
 	return nil, fmt.Errorf("file name not found: %s", file)
 }
 
+func (fc FileCoverages) findByFileFold(file string) (*FileCoverage, error) { //nostyle:recvtype
+	for _, c := range fc {
+		if strings.EqualFold(c.File, file) {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("file name not found: %s", file)
+}
+
 func (fc FileCoverages) FuzzyFindByFile(file string) (*FileCoverage, error) { //nostyle:recvtype
 	var match *FileCoverage
 	for _, c := range fc {
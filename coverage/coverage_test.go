@@ -425,3 +425,48 @@ func newBlockCoverage(t Type, sl, sc, el, ec, ns, c int) *BlockCoverage {
 
 	return bc
 }
+
+func TestTruncateFiles(t *testing.T) {
+	c := &Coverage{
+		Type:    TypeLOC,
+		Total:   100,
+		Covered: 70,
+		Files: FileCoverages{
+			&FileCoverage{Type: TypeLOC, File: "a.go", Total: 40, Covered: 40},
+			&FileCoverage{Type: TypeLOC, File: "b.go", Total: 20, Covered: 10},
+			&FileCoverage{Type: TypeLOC, File: "c.go", Total: 10, Covered: 5},
+			&FileCoverage{Type: TypeLOC, File: "d.go", Total: 30, Covered: 15},
+		},
+	}
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{0, []string{"a.go", "b.go", "c.go", "d.go"}},
+		{4, []string{"a.go", "b.go", "c.go", "d.go"}},
+		{2, []string{"d.go", "b.go", OtherFiles}},
+		{1, []string{"d.go", OtherFiles}},
+	}
+	for _, tt := range tests {
+		got := c.TruncateFiles(tt.n)
+		if got.Total != c.Total || got.Covered != c.Covered {
+			t.Errorf("got %v/%v\nwant %v/%v", got.Covered, got.Total, c.Covered, c.Total)
+		}
+		var files []string
+		total, covered := 0, 0
+		for _, fc := range got.Files {
+			files = append(files, fc.File)
+			total += fc.Total
+			covered += fc.Covered
+		}
+		if diff := cmp.Diff(files, tt.want); diff != "" {
+			t.Error(diff)
+		}
+		if total != c.Total || covered != c.Covered {
+			t.Errorf("got %v/%v\nwant %v/%v", covered, total, c.Covered, c.Total)
+		}
+	}
+	if got := len(c.Files); got != 4 {
+		t.Errorf("got %v\nwant %v", got, 4)
+	}
+}
@@ -0,0 +1,152 @@
+package coverage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/goccy/go-json"
+)
+
+var _ Processor = (*CoveragePy)(nil)
+
+const (
+	CoveragePyDefaultPath     = ".coverage"
+	CoveragePyJSONDefaultPath = "coverage.json"
+)
+
+var sqliteHeader = []byte("SQLite format 3\x00")
+
+// CoveragePy is the parser of the JSON report of coverage.py (`coverage json`).
+type CoveragePy struct{}
+
+type CoveragePyReport struct {
+	Meta  *CoveragePyMeta                   `json:"meta"`
+	Files map[string]CoveragePyFileCoverage `json:"files"`
+}
+
+type CoveragePyMeta struct {
+	Version string `json:"version"`
+	Format  int    `json:"format"`
+}
+
+type CoveragePyFileCoverage struct {
+	ExecutedLines []int `json:"executed_lines"`
+	MissingLines  []int `json:"missing_lines"`
+	ExcludedLines []int `json:"excluded_lines"`
+}
+
+func NewCoveragePy() *CoveragePy {
+	return &CoveragePy{}
+}
+
+func (c *CoveragePy) Name() string {
+	return "coverage.py"
+}
+
+func (c *CoveragePy) ParseReport(path string) (*Coverage, string, error) {
+	rp, err := c.detectReportPath(path)
+	if err != nil {
+		return nil, "", err
+	}
+	b, err := os.ReadFile(filepath.Clean(rp))
+	if err != nil {
+		return nil, "", err
+	}
+	r := CoveragePyReport{}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return nil, "", err
+	}
+	if r.Meta == nil || r.Files == nil {
+		return nil, "", fmt.Errorf("%s is not coverage.py JSON format", filepath.Clean(rp))
+	}
+
+	cov := New()
+	cov.Type = TypeLOC
+	cov.Format = c.Name()
+
+	var files []string
+	for f := range r.Files {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	// the aggregate is recomputed from the lines instead of trusting totals.percent_covered
+	for _, f := range files {
+		fc := r.Files[f]
+		excluded := map[int]struct{}{}
+		for _, l := range fc.ExcludedLines {
+			excluded[l] = struct{}{}
+		}
+		fcov := NewFileCoverage(f, TypeLOC)
+		appendLines := func(lines []int, count int) {
+			for _, l := range lines {
+				// excluded lines are not counted in the denominator, same as coverage.py
+				if _, ok := excluded[l]; ok {
+					continue
+				}
+				sl := l
+				el := l
+				c := count
+				fcov.Blocks = append(fcov.Blocks, &BlockCoverage{
+					Type:      TypeLOC,
+					StartLine: &sl,
+					EndLine:   &el,
+					Count:     &c,
+				})
+			}
+		}
+		appendLines(fc.ExecutedLines, 1)
+		appendLines(fc.MissingLines, 0)
+		lcs := fcov.Blocks.ToLineCoverages()
+		fcov.Total = lcs.Total()
+		fcov.Covered = lcs.Covered()
+		cov.Total += fcov.Total
+		cov.Covered += fcov.Covered
+		cov.Files = append(cov.Files, fcov)
+	}
+
+	return cov, rp, nil
+}
+
+func (c *CoveragePy) detectReportPath(path string) (string, error) {
+	p, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if p.IsDir() {
+		path = filepath.Join(path, CoveragePyJSONDefaultPath)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// DetectCoveragePyDataFile returns an error explaining how to convert the coverage.py data file (SQLite database) if path is (or directory path contains) it.
+//
+// The data file records only the executed lines. The executable lines are computed by coverage.py from the Python sources at reporting time,
+// so the code coverage cannot be measured from the data file alone.
+func DetectCoveragePyDataFile(path string) error {
+	p := path
+	if fi, err := os.Stat(p); err != nil {
+		return nil
+	} else if fi.IsDir() {
+		p = filepath.Join(p, CoveragePyDefaultPath)
+	}
+	f, err := os.Open(filepath.Clean(p))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	h := make([]byte, len(sqliteHeader))
+	if _, err := io.ReadFull(f, h); err != nil {
+		return nil
+	}
+	if !bytes.Equal(h, sqliteHeader) {
+		return nil
+	}
+	return fmt.Errorf("coverage.py data file (SQLite database) does not contain the executable lines: %s. convert it into a supported format with `coverage json`, `coverage xml` (Cobertura) or `coverage lcov` (LCOV)", p)
+}
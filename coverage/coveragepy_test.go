@@ -0,0 +1,66 @@
+package coverage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCoveragePy(t *testing.T) {
+	tests := []struct {
+		path        string
+		wantTotal   int
+		wantCovered int
+		wantErr     bool
+	}{
+		{filepath.Join(testdataDir(t), "coveragepy"), 9, 7, false},
+		{filepath.Join(testdataDir(t), "coveragepy", "coverage.json"), 9, 7, false},
+		{filepath.Join(testdataDir(t), "simplecov", ".resultset.json"), 0, 0, true},
+		{filepath.Join(testdataDir(t), "cobertura"), 0, 0, true},
+	}
+	for _, tt := range tests {
+		got, _, err := NewCoveragePy().ParseReport(tt.path)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: %v", tt.path, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%s: want error", tt.path)
+			continue
+		}
+		if got.Total != tt.wantTotal {
+			t.Errorf("%s: got total %v\nwant %v", tt.path, got.Total, tt.wantTotal)
+		}
+		if got.Covered != tt.wantCovered {
+			t.Errorf("%s: got covered %v\nwant %v", tt.path, got.Covered, tt.wantCovered)
+		}
+		fc, err := got.Files.FindByFile("app/main.py")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// line 12 is excluded even though it is listed in missing_lines
+		if fc.Total != 7 || fc.Covered != 5 {
+			t.Errorf("%s: got %d/%d\nwant 5/7", tt.path, fc.Covered, fc.Total)
+		}
+	}
+}
+
+func TestDetectCoveragePyDataFile(t *testing.T) {
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{filepath.Join(testdataDir(t), "coveragepy"), true},
+		{filepath.Join(testdataDir(t), "coveragepy", ".coverage"), true},
+		{filepath.Join(testdataDir(t), "cobertura"), false},
+		{filepath.Join(testdataDir(t), "lcov", "lcov.info"), false},
+		{filepath.Join(testdataDir(t), "not_exist"), false},
+	}
+	for _, tt := range tests {
+		err := DetectCoveragePyDataFile(tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: got %v\nwantErr %v", tt.path, err, tt.wantErr)
+		}
+	}
+}
@@ -1,9 +1,8 @@
 package coverage
 
 import (
+	"fmt"
 	"strings"
-
-	"github.com/bmatcuk/doublestar/v4"
 )
 
 func (c *Coverage) Exclude(exclude []string) error {
@@ -21,7 +20,7 @@ func (c *Coverage) Exclude(exclude []string) error {
 				e = strings.TrimPrefix(e, "!")
 				not = true
 			}
-			match, err := doublestar.Match(e, f.File)
+			match, err := c.match(e, f.File)
 			if err != nil {
 				return err
 			}
@@ -37,6 +36,9 @@ func (c *Coverage) Exclude(exclude []string) error {
 			files = append(files, c.Files[i])
 		}
 	}
+	if len(c.Files) > 0 && len(files) == 0 {
+		return fmt.Errorf("all files of the coverage report are excluded by %v", exclude)
+	}
 	c.Files = files
 
 	return c.reCalc()
@@ -114,39 +114,6 @@ func TestExclude(t *testing.T) {
 				},
 			},
 		},
-		{
-			&Coverage{
-				Type: TypeLOC,
-				Files: FileCoverages{
-					&FileCoverage{
-						File: "file_a.go",
-						Type: TypeLOC,
-						Blocks: BlockCoverages{
-							newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 1),
-							newBlockCoverage(TypeLOC, 2, -1, 2, -1, -1, 0),
-							newBlockCoverage(TypeLOC, 3, -1, 3, -1, -1, 1),
-						},
-					},
-					&FileCoverage{
-						File: "file_b.go",
-						Blocks: BlockCoverages{
-							newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 0),
-							newBlockCoverage(TypeLOC, 2, -1, 2, -1, -1, 1),
-							newBlockCoverage(TypeLOC, 3, -1, 3, -1, -1, 1),
-						},
-					},
-				},
-			},
-			[]string{
-				"file_*.go",
-			},
-			&Coverage{
-				Type:    TypeLOC,
-				Total:   0,
-				Covered: 0,
-				Files:   nil,
-			},
-		},
 		{
 			&Coverage{
 				Type: TypeLOC,
@@ -220,3 +187,67 @@ func TestExclude(t *testing.T) {
 		}
 	}
 }
+
+func TestExcludeCaseInsensitive(t *testing.T) {
+	tests := []struct {
+		caseInsensitive bool
+		exclude         []string
+		want            []string
+	}{
+		{false, []string{"pkg/*.go"}, []string{"Pkg/file_a.go", "other/file_b.go"}},
+		{true, []string{"pkg/*.go"}, []string{"other/file_b.go"}},
+		{true, []string{"**/*.GO", "!Other/**"}, []string{"other/file_b.go"}},
+	}
+	for _, tt := range tests {
+		c := &Coverage{
+			Type: TypeLOC,
+			Files: FileCoverages{
+				&FileCoverage{
+					File:   "Pkg/file_a.go",
+					Type:   TypeLOC,
+					Blocks: BlockCoverages{newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 1)},
+				},
+				&FileCoverage{
+					File:   "other/file_b.go",
+					Type:   TypeLOC,
+					Blocks: BlockCoverages{newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 0)},
+				},
+			},
+			CaseInsensitive: tt.caseInsensitive,
+		}
+		if err := c.Exclude(tt.exclude); err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, f := range c.Files {
+			got = append(got, f.File)
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestExcludeAllFiles(t *testing.T) {
+	c := &Coverage{
+		Type: TypeLOC,
+		Files: FileCoverages{
+			&FileCoverage{
+				File:   "mocks/file_a.go",
+				Type:   TypeLOC,
+				Blocks: BlockCoverages{newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 1)},
+			},
+			&FileCoverage{
+				File:   "pkg/file_gen.go",
+				Type:   TypeLOC,
+				Blocks: BlockCoverages{newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 0)},
+			},
+		},
+	}
+	if err := c.Exclude([]string{"mocks/**", "**/*_gen.go"}); err == nil {
+		t.Error("want error")
+	}
+	if want := 2; len(c.Files) != want {
+		t.Errorf("got %v\nwant %v", len(c.Files), want)
+	}
+}
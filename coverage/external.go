@@ -0,0 +1,98 @@
+package coverage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+var _ Processor = (*External)(nil)
+
+// External is a parser that delegates parsing of the coverage report to an external command.
+//
+// The command is invoked by `sh -c` with the path of the coverage report file as the last argument.
+// It must exit with status 0 and print the octocov report JSON (or only its `coverage` object) to stdout.
+// A non-zero exit status means that the coverage report could not be parsed.
+type External struct {
+	command string
+}
+
+type externalReport struct {
+	Coverage *Coverage `json:"coverage"`
+}
+
+func NewExternal(command string) *External {
+	return &External{
+		command: command,
+	}
+}
+
+func (e *External) Name() string {
+	return "External"
+}
+
+func (e *External) ParseReport(path string) (*Coverage, string, error) {
+	if e.command == "" {
+		return nil, "", errors.New("external parser command is not set")
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, "", err
+	}
+	if fi.IsDir() {
+		return nil, "", fmt.Errorf("external parser requires a coverage report file: %s", path)
+	}
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	cmd := exec.Command("sh", "-c", fmt.Sprintf(`%s "$1"`, e.command), "octocov", path) //#nosec G204
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return nil, "", fmt.Errorf("external parser command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	cov, err := e.decode(stdout.Bytes())
+	if err != nil {
+		return nil, "", err
+	}
+	return cov, path, nil
+}
+
+func (e *External) decode(b []byte) (*Coverage, error) {
+	r := &externalReport{}
+	if err := json.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("invalid output of external parser command: %w", err)
+	}
+	cov := r.Coverage
+	if cov == nil {
+		cov = &Coverage{}
+		if err := json.Unmarshal(b, cov); err != nil {
+			return nil, fmt.Errorf("invalid output of external parser command: %w", err)
+		}
+	}
+	if len(cov.Files) == 0 {
+		return nil, errors.New("invalid output of external parser command: no file coverages")
+	}
+	if cov.Total == 0 {
+		for _, fc := range cov.Files {
+			cov.Total += fc.Total
+			cov.Covered += fc.Covered
+		}
+	}
+	if cov.Type == "" {
+		cov.Type = TypeLOC
+	}
+	if cov.Format == "" {
+		cov.Format = e.Name()
+	}
+	for _, fc := range cov.Files {
+		if fc.Type == "" {
+			fc.Type = cov.Type
+		}
+	}
+	return cov, nil
+}
@@ -0,0 +1,58 @@
+package coverage
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestExternal(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not found")
+	}
+	path := filepath.Join(testdataDir(t), "external", "report.json")
+	tests := []struct {
+		command string
+		wantErr bool
+	}{
+		{"cat", false},
+		{"false", true},
+		{"echo '{}' #", true},
+		{"echo 'not json' #", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got, rp, err := NewExternal(tt.command).ParseReport(path)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got err: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Error("want err")
+			}
+			if rp != path {
+				t.Errorf("got %v\nwant %v", rp, path)
+			}
+			if len(got.Files) != 2 {
+				t.Errorf("got %v\nwant %v", len(got.Files), 2)
+			}
+			if got.Total != 6 {
+				t.Errorf("got %v\nwant %v", got.Total, 6)
+			}
+			if got.Covered != 3 {
+				t.Errorf("got %v\nwant %v", got.Covered, 3)
+			}
+			if got.Format != "External" {
+				t.Errorf("got %v\nwant %v", got.Format, "External")
+			}
+		})
+	}
+}
+
+func TestExternalRequiresFile(t *testing.T) {
+	if _, _, err := NewExternal("cat").ParseReport(filepath.Join(testdataDir(t), "external")); err == nil {
+		t.Error("want err")
+	}
+}
@@ -0,0 +1,56 @@
+package coverage
+
+// FuncCoverage is the function-level coverage.
+type FuncCoverage struct {
+	Name  string `json:"name"`
+	Line  int    `json:"line,omitempty"`
+	Count int    `json:"count"`
+}
+
+type FuncCoverages []*FuncCoverage
+
+// IsMeasuredFuncs reports whether the coverage contains function-level coverage.
+func (c *Coverage) IsMeasuredFuncs() bool {
+	if c == nil {
+		return false
+	}
+	for _, fc := range c.Files {
+		if len(fc.Funcs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// UncoveredFuncs returns the number of functions that are never executed.
+func (c *Coverage) UncoveredFuncs() int {
+	if c == nil {
+		return 0
+	}
+	n := 0
+	for _, fc := range c.Files {
+		for _, f := range fc.Funcs {
+			if f.Count == 0 {
+				n += 1
+			}
+		}
+	}
+	return n
+}
+
+func (fcs FuncCoverages) merge(fcs2 FuncCoverages) FuncCoverages {
+	for _, f2 := range fcs2 {
+		merged := false
+		for _, f := range fcs {
+			if f.Name == f2.Name {
+				f.Count += f2.Count
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			fcs = append(fcs, f2)
+		}
+	}
+	return fcs
+}
@@ -1,8 +1,12 @@
 package coverage
 
 import (
+	"bytes"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"golang.org/x/tools/cover"
 )
@@ -11,12 +15,31 @@ var _ Processor = (*Gocover)(nil)
 
 const GocoverDefaultPath = "coverage.out"
 
-type Gocover struct{}
+const gocoverModePrefix = "mode: "
+
+// Modes of Go coverage profiles.
+const (
+	GocoverModeSet    = "set"
+	GocoverModeCount  = "count"
+	GocoverModeAtomic = "atomic"
+)
+
+type Gocover struct {
+	// cacheDir is the directory for caching parsed coverages per package
+	cacheDir string
+}
 
 func NewGocover() *Gocover {
 	return &Gocover{}
 }
 
+// NewGocoverWithCache returns a Gocover that caches parsed coverages per package in dir.
+func NewGocoverWithCache(dir string) *Gocover {
+	return &Gocover{
+		cacheDir: dir,
+	}
+}
+
 func (g *Gocover) Name() string {
 	return "Go coverage"
 }
@@ -26,13 +49,48 @@ func (g *Gocover) ParseReport(path string) (*Coverage, string, error) {
 	if err != nil {
 		return nil, "", err
 	}
-	profiles, err := cover.ParseProfiles(rp)
-	if err != nil {
-		return nil, "", err
+	pp := rp
+	if isCoverDir(rp) {
+		// Binary coverage data directory written by a binary built with `go build -cover` (Go 1.20+)
+		pp, err = convertCoverDir(rp)
+		if err != nil {
+			return nil, "", err
+		}
+		defer os.RemoveAll(filepath.Dir(pp))
+	}
+	var (
+		mode  string
+		files FileCoverages
+	)
+	if g.cacheDir != "" {
+		mode, files, err = g.parseProfilesWithCache(rp, pp, g.cacheDir)
+		if err != nil {
+			return nil, "", err
+		}
+	} else {
+		profiles, err := parseProfiles(pp)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(profiles) > 0 {
+			mode = profiles[0].Mode
+		}
+		files = g.toFileCoverages(profiles)
 	}
 	cov := New()
 	cov.Type = TypeStmt
 	cov.Format = g.Name()
+	cov.Mode = mode
+	for _, fcov := range files {
+		cov.Total += fcov.Total
+		cov.Covered += fcov.Covered
+		cov.Files = append(cov.Files, fcov)
+	}
+	return cov, rp, nil
+}
+
+func (g *Gocover) toFileCoverages(profiles []*cover.Profile) FileCoverages {
+	files := FileCoverages{}
 	for _, p := range profiles {
 		total, covered := g.countProfile(p)
 		fcov := NewFileCoverage(p.FileName, TypeStmt)
@@ -55,11 +113,51 @@ func (g *Gocover) ParseReport(path string) (*Coverage, string, error) {
 				Count:     &c,
 			})
 		}
-		cov.Total += total
-		cov.Covered += covered
-		cov.Files = append(cov.Files, fcov)
+		files = append(files, fcov)
 	}
-	return cov, rp, nil
+	return files
+}
+
+// parseProfiles parses the Go coverage profile.
+// Profiles concatenated into one file (e.g. `go test -coverpkg` run per package) are accepted: the repeated mode lines are dropped,
+// and the blocks of the same file are merged in the same way as the go tool merges profiles.
+func parseProfiles(pp string) ([]*cover.Profile, error) {
+	b, err := os.ReadFile(filepath.Clean(pp))
+	if err != nil {
+		return nil, err
+	}
+	first, rest, _ := bytes.Cut(b, []byte("\n"))
+	mode := bytes.TrimRight(first, "\r")
+	if !bytes.HasPrefix(mode, []byte(gocoverModePrefix)) {
+		return cover.ParseProfilesFromReader(bytes.NewReader(b))
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, len(b)))
+	buf.Write(mode)
+	buf.WriteByte('\n')
+	for len(rest) > 0 {
+		var line []byte
+		line, rest, _ = bytes.Cut(rest, []byte("\n"))
+		line = bytes.TrimRight(line, "\r")
+		if bytes.HasPrefix(line, []byte(gocoverModePrefix)) {
+			if err := sameMode(string(mode), string(line)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	return cover.ParseProfilesFromReader(buf)
+}
+
+// sameMode returns an error if the mode lines of the concatenated profiles differ.
+func sameMode(mode, line string) error {
+	m := strings.TrimSpace(strings.TrimPrefix(mode, gocoverModePrefix))
+	m2 := strings.TrimSpace(strings.TrimPrefix(line, gocoverModePrefix))
+	if m != m2 {
+		return fmt.Errorf("can not merge Go coverage profiles of different modes (%s, %s)", m, m2)
+	}
+	return nil
 }
 
 func (g *Gocover) detectReportPath(path string) (string, error) {
@@ -68,6 +166,9 @@ func (g *Gocover) detectReportPath(path string) (string, error) {
 		return "", err
 	}
 	if p.IsDir() {
+		if isCoverDir(path) {
+			return path, nil
+		}
 		path = filepath.Join(path, GocoverDefaultPath)
 	}
 	if _, err := os.Stat(path); err != nil {
@@ -89,3 +190,31 @@ func (g *Gocover) countProfile(p *cover.Profile) (int, int) {
 	}
 	return total, covered
 }
+
+// isCoverDir reports whether the path is a GOCOVERDIR directory containing covmeta files.
+func isCoverDir(path string) bool {
+	p, err := os.Stat(path)
+	if err != nil || !p.IsDir() {
+		return false
+	}
+	m, err := filepath.Glob(filepath.Join(path, "covmeta.*"))
+	if err != nil {
+		return false
+	}
+	return len(m) > 0
+}
+
+// convertCoverDir converts the GOCOVERDIR directory to a coverage profile using `go tool covdata textfmt`.
+func convertCoverDir(dir string) (string, error) {
+	tmp, err := os.MkdirTemp("", "octocov-covdata")
+	if err != nil {
+		return "", err
+	}
+	pp := filepath.Join(tmp, GocoverDefaultPath)
+	cmd := exec.Command("go", "tool", "covdata", "textfmt", fmt.Sprintf("-i=%s", dir), fmt.Sprintf("-o=%s", pp)) // #nosec
+	if out, err := cmd.CombinedOutput(); err != nil {
+		_ = os.RemoveAll(tmp)
+		return "", fmt.Errorf("failed to convert %s using go tool covdata: %w: %s", dir, err, strings.TrimSpace(string(out)))
+	}
+	return pp, nil
+}
@@ -1,9 +1,16 @@
 package coverage
 
 import (
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func TestGocover(t *testing.T) {
@@ -22,6 +29,9 @@ func TestGocover(t *testing.T) {
 	if len(got.Files) == 0 {
 		t.Error("got 0 want > 0")
 	}
+	if want := GocoverModeCount; got.Mode != want {
+		t.Errorf("got %v\nwant %v", got.Mode, want)
+	}
 	for _, f := range got.Files {
 		total := 0
 		covered := 0
@@ -41,6 +51,86 @@ func TestGocover(t *testing.T) {
 	}
 }
 
+func TestGocoverCoverDir(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	path := filepath.Join(testdataDir(t), "gocoverdir")
+	gcov := NewGocover()
+	got, rp, err := gcov.ParseReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rp != path {
+		t.Errorf("got %v\nwant %v", rp, path)
+	}
+	if got.Total != 7 {
+		t.Errorf("got %v\nwant %v", got.Total, 7)
+	}
+	if got.Covered != 4 {
+		t.Errorf("got %v\nwant %v", got.Covered, 4)
+	}
+	if len(got.Files) != 1 {
+		t.Fatalf("got %v\nwant %v", len(got.Files), 1)
+	}
+	if want := "example.com/covapp/main.go"; got.Files[0].File != want {
+		t.Errorf("got %v\nwant %v", got.Files[0].File, want)
+	}
+}
+
+func TestGocoverCoverDirWithCache(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	path := filepath.Join(testdataDir(t), "gocoverdir")
+	dir := t.TempDir()
+	if _, _, err := NewGocoverWithCache(dir).ParseReport(path); err != nil {
+		t.Fatal(err)
+	}
+	ns, err := cacheNamespace(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, ns))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("got 0 want > 0")
+	}
+	// Cache entries are not written again on a cache hit
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for _, e := range entries {
+		if err := os.Chtimes(filepath.Join(dir, ns, e.Name()), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, _, err := NewGocoverWithCache(dir).ParseReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Total != 7 || got.Covered != 4 {
+		t.Errorf("got %v/%v\nwant %v/%v", got.Covered, got.Total, 4, 7)
+	}
+	namespaces, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(namespaces) != 1 {
+		t.Errorf("got %v namespaces\nwant %v", len(namespaces), 1)
+	}
+	for _, e := range entries {
+		fi, err := os.Stat(filepath.Join(dir, ns, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !fi.ModTime().Equal(old) {
+			t.Errorf("%s is written again", e.Name())
+		}
+	}
+}
+
 func TestGocoverParseAllFormat(t *testing.T) {
 	tests := []struct {
 		path    string
@@ -73,3 +163,160 @@ func testdataDir(t *testing.T) string {
 	}
 	return dir
 }
+
+func TestGocoverWithCache(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join(testdataDir(t), "gocover", "coverage.out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "coverage.out")
+	if err := os.WriteFile(path, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	want, _, err := NewGocover().ParseReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	for i := 0; i < 2; i++ {
+		got, _, err := NewGocoverWithCache(dir).ParseReport(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		opts := []cmp.Option{
+			cmpopts.IgnoreUnexported(FileCoverage{}),
+		}
+		if diff := cmp.Diff(got, want, opts...); diff != "" {
+			t.Error(diff)
+		}
+	}
+	ns, err := cacheNamespace(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, ns))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 {
+		t.Error("got 0 want > 0")
+	}
+	n := len(entries)
+
+	// The cache entries of the other profiles are not removed
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	other := filepath.Join(t.TempDir(), "coverage.out")
+	if err := os.WriteFile(other, []byte(strings.Join(lines[:2], "\n")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := NewGocoverWithCache(dir).ParseReport(other); err != nil {
+		t.Fatal(err)
+	}
+	entries, err = os.ReadDir(filepath.Join(dir, ns))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != n {
+		t.Errorf("got %v\nwant %v", len(entries), n)
+	}
+
+	// Outdated cache entries of the profile are removed
+	if err := os.WriteFile(path, []byte(strings.Join(lines[:2], "\n")), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := NewGocoverWithCache(dir).ParseReport(path); err != nil {
+		t.Fatal(err)
+	}
+	entries, err = os.ReadDir(filepath.Join(dir, ns))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("got %v\nwant %v", len(entries), 1)
+	}
+}
+
+func BenchmarkGocover(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "coverage.out")
+	lines := []string{"mode: atomic"}
+	for p := 0; p < 200; p++ {
+		for f := 0; f < 10; f++ {
+			for l := 1; l < 200; l++ {
+				lines = append(lines, fmt.Sprintf("example.com/mono/pkg%d/file%d.go:%d.2,%d.16 1 %d", p, f, l*2, l*2+1, l%3))
+			}
+		}
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0600); err != nil {
+		b.Fatal(err)
+	}
+	b.Run("without cache", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, _, err := NewGocover().ParseReport(path); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("with cache", func(b *testing.B) {
+		dir := b.TempDir()
+		if _, _, err := NewGocoverWithCache(dir).ParseReport(path); err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, _, err := NewGocoverWithCache(dir).ParseReport(path); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestGocoverCoverpkg(t *testing.T) {
+	tests := []struct {
+		file    string
+		total   int
+		covered int
+	}{
+		// `go test -coverpkg=./... -covermode=count ./...` of two packages, so the blocks of each file appear once per test binary.
+		// `go tool cover -func` reports total: 71.4% (5/7).
+		{"coverage.out", 7, 5},
+		// Concatenated profiles of `go test -coverpkg=./... -covermode=count` run per package, with the repeated mode lines.
+		{"concat.out", 7, 5},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(testdataDir(t), "gocover_coverpkg", tt.file)
+		for _, gcov := range []*Gocover{NewGocover(), NewGocoverWithCache(t.TempDir())} {
+			got, _, err := gcov.ParseReport(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Total != tt.total {
+				t.Errorf("%s: got %v\nwant %v", tt.file, got.Total, tt.total)
+			}
+			if got.Covered != tt.covered {
+				t.Errorf("%s: got %v\nwant %v", tt.file, got.Covered, tt.covered)
+			}
+			want := map[string]int{"example.com/cpk/a/a.go": 3, "example.com/cpk/b/b.go": 4}
+			if len(got.Files) != len(want) {
+				t.Fatalf("%s: got %v\nwant %v", tt.file, len(got.Files), len(want))
+			}
+			for _, f := range got.Files {
+				if got := len(f.Blocks); got != want[f.File] {
+					t.Errorf("%s: %s: got %v blocks\nwant %v", tt.file, f.File, got, want[f.File])
+				}
+			}
+		}
+	}
+}
+
+func TestGocoverDifferentModes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "coverage.out")
+	profile := "mode: set\nexample.com/a/a.go:6.2,6.11 1 1\nmode: count\nexample.com/a/a.go:6.2,6.11 1 2\n"
+	if err := os.WriteFile(path, []byte(profile), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, gcov := range []*Gocover{NewGocover(), NewGocoverWithCache(t.TempDir())} {
+		if _, _, err := gcov.ParseReport(path); err == nil {
+			t.Error("got nil\nwant error")
+		}
+	}
+}
@@ -95,13 +95,16 @@ func (c *Jacoco) ParseReport(path string) (*Coverage, string, error) {
 	cov.Type = TypeLOC
 	cov.Format = c.Name()
 
-	flm := map[string]BlockCoverages{}
+	fcm := map[string]*FileCoverage{}
+	var names []string
 	for _, p := range r.Package {
 		for _, s := range p.Sourcefile {
 			n := fmt.Sprintf("%s/%s", p.Name, s.Name)
-			f, ok := flm[n]
+			fcov, ok := fcm[n]
 			if !ok {
-				f = BlockCoverages{}
+				fcov = NewFileCoverage(n, TypeLOC)
+				fcm[n] = fcov
+				names = append(names, n)
 			}
 			for _, l := range s.Line {
 				sl := l.Nr
@@ -110,34 +113,53 @@ func (c *Jacoco) ParseReport(path string) (*Coverage, string, error) {
 				if l.Ci > 0 {
 					c = 1
 				}
-				f = append(f, &BlockCoverage{
+				fcov.Blocks = append(fcov.Blocks, &BlockCoverage{
 					Type:      TypeLOC,
 					StartLine: &sl,
 					EndLine:   &el,
 					Count:     &c,
 				})
 			}
-			flm[n] = f
+			// Prefer the LINE counter of the source file, and fall back to its lines.
+			if lc, ok := jacocoLineCounter(s.Counter); ok {
+				fcov.Total += lc.Missed + lc.Covered
+				fcov.Covered += lc.Covered
+				continue
+			}
+			for _, l := range s.Line {
+				fcov.Total += 1
+				if l.Ci > 0 {
+					fcov.Covered += 1
+				}
+			}
 		}
 	}
 
-	for f, blocks := range flm {
-		fcov := NewFileCoverage(f, TypeLOC)
-		for _, b := range blocks {
-			fcov.Total += 1
-			if *b.Count > 0 {
-				fcov.Covered += 1
-			}
-		}
-		fcov.Blocks = blocks
+	for _, n := range names {
+		fcov := fcm[n]
 		cov.Total += fcov.Total
 		cov.Covered += fcov.Covered
 		cov.Files = append(cov.Files, fcov)
 	}
 
+	// The aggregate is driven by the report level LINE counter (not INSTRUCTION) when it exists.
+	if lc, ok := jacocoLineCounter(r.Counter); ok {
+		cov.Total = lc.Missed + lc.Covered
+		cov.Covered = lc.Covered
+	}
+
 	return cov, rp, nil
 }
 
+func jacocoLineCounter(counters []*JacocoReportCounter) (*JacocoReportCounter, bool) {
+	for _, c := range counters {
+		if c.Type == "LINE" {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
 func (c *Jacoco) detectReportPath(path string) (string, error) {
 	p, err := os.Stat(path)
 	if err != nil {
@@ -1,6 +1,7 @@
 package coverage
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -60,3 +61,39 @@ func TestJacocoParseAllFormat(t *testing.T) {
 		}
 	}
 }
+
+func TestJacocoWithoutLineCounter(t *testing.T) {
+	xml := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<report name="example">
+  <package name="com/example">
+    <sourcefile name="App.kt">
+      <line nr="3" mi="0" ci="2" mb="0" cb="0"/>
+      <line nr="4" mi="1" ci="0" mb="1" cb="1"/>
+      <counter type="BRANCH" missed="1" covered="1"/>
+    </sourcefile>
+    <counter type="BRANCH" missed="1" covered="1"/>
+  </package>
+  <counter type="BRANCH" missed="1" covered="1"/>
+</report>
+`
+	path := filepath.Join(t.TempDir(), "jacoco.xml")
+	if err := os.WriteFile(path, []byte(xml), 0600); err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := NewJacoco().ParseReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 2; got.Total != want {
+		t.Errorf("got %v\nwant %v", got.Total, want)
+	}
+	if want := 1; got.Covered != want {
+		t.Errorf("got %v\nwant %v", got.Covered, want)
+	}
+	if want := 1; len(got.Files) != want {
+		t.Fatalf("got %v\nwant %v", len(got.Files), want)
+	}
+	if want := "com/example/App.kt"; got.Files[0].File != want {
+		t.Errorf("got %v\nwant %v", got.Files[0].File, want)
+	}
+}
@@ -0,0 +1,59 @@
+package coverage
+
+type LabelCoverage struct {
+	Label   string `json:"label"`
+	Total   int    `json:"total"`
+	Covered int    `json:"covered"`
+}
+
+type LabelCoverages []*LabelCoverage
+
+// CoverageOfLabel returns the coverage of files matching the pattern.
+func (c *Coverage) CoverageOfLabel(label, pattern string) (*LabelCoverage, error) {
+	lc := &LabelCoverage{
+		Label: label,
+	}
+	if c == nil {
+		return lc, nil
+	}
+	for _, f := range c.Files {
+		match, err := c.match(pattern, f.File)
+		if err != nil {
+			return nil, err
+		}
+		if !match {
+			continue
+		}
+		lc.Total += f.Total
+		lc.Covered += f.Covered
+	}
+	return lc, nil
+}
+
+func (lc *LabelCoverage) Percent() float64 {
+	if lc == nil || lc.Total == 0 {
+		return 0.0
+	}
+	return float64(lc.Covered) / float64(lc.Total) * 100
+}
+
+// FilesOf returns the file coverages matching any of the patterns.
+func (c *Coverage) FilesOf(patterns []string) (FileCoverages, error) {
+	var files FileCoverages
+	if c == nil {
+		return files, nil
+	}
+	for _, f := range c.Files {
+		for _, p := range patterns {
+			match, err := c.match(p, f.File)
+			if err != nil {
+				return nil, err
+			}
+			if match {
+				files = append(files, f)
+				break
+			}
+		}
+	}
+	return files, nil
+}
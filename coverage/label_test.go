@@ -0,0 +1,69 @@
+package coverage
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCoverageOfLabel(t *testing.T) {
+	c := &Coverage{
+		Files: FileCoverages{
+			&FileCoverage{File: "services/api/a.go", Total: 10, Covered: 5},
+			&FileCoverage{File: "services/api/sub/b.go", Total: 10, Covered: 10},
+			&FileCoverage{File: "web/c.ts", Total: 4, Covered: 1},
+		},
+	}
+	tests := []struct {
+		pattern string
+		want    *LabelCoverage
+		percent float64
+	}{
+		{"services/api/**", &LabelCoverage{Label: "l", Total: 20, Covered: 15}, 75.0},
+		{"web/**", &LabelCoverage{Label: "l", Total: 4, Covered: 1}, 25.0},
+		{"docs/**", &LabelCoverage{Label: "l", Total: 0, Covered: 0}, 0.0},
+	}
+	for _, tt := range tests {
+		got, err := c.CoverageOfLabel("l", tt.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Error(diff)
+		}
+		if got.Percent() != tt.percent {
+			t.Errorf("got %v\nwant %v", got.Percent(), tt.percent)
+		}
+	}
+}
+
+func TestFilesOf(t *testing.T) {
+	c := &Coverage{
+		Files: FileCoverages{
+			&FileCoverage{File: "auth/a.go", Total: 10, Covered: 10},
+			&FileCoverage{File: "crypto/sub/b.go", Total: 10, Covered: 5},
+			&FileCoverage{File: "web/c.ts", Total: 4, Covered: 1},
+		},
+	}
+	tests := []struct {
+		patterns []string
+		want     []string
+	}{
+		{[]string{"auth/**", "crypto/**"}, []string{"auth/a.go", "crypto/sub/b.go"}},
+		{[]string{"auth/**", "**/*.go"}, []string{"auth/a.go", "crypto/sub/b.go"}},
+		{[]string{"docs/**"}, nil},
+	}
+	for _, tt := range tests {
+		files, err := c.FilesOf(tt.patterns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, f := range files {
+			got = append(got, f.File)
+		}
+		if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
@@ -43,26 +43,39 @@ func (l *Lcov) ParseReport(path string) (*Coverage, string, error) {
 	cov.Format = l.Name()
 	parsed := false
 	blocks := BlockCoverages{}
+	funcs := FuncCoverages{}
 	for scanner.Scan() {
 		l := scanner.Text()
 		if l == "end_of_record" {
 			fcov, err := cov.Files.FindByFile(fileName)
 			if err != nil {
 				fcov = NewFileCoverage(fileName, TypeLOC)
+				fcov.Total = total
+				fcov.Covered = covered
+				fcov.Blocks = blocks
+				cov.Files = append(cov.Files, fcov)
+			} else {
+				// multiple SF sections for the same file are merged line by line
+				total, covered = mergeLcovBlocks(fcov, blocks)
 			}
-			fcov.Total += total
-			fcov.Covered += covered
-			fcov.Blocks = blocks
+			fcov.Funcs = fcov.Funcs.merge(funcs)
 			cov.Total += total
 			cov.Covered += covered
-			cov.Files = append(cov.Files, fcov)
 			total = 0
 			covered = 0
 			parsed = true
 			blocks = BlockCoverages{}
+			funcs = FuncCoverages{}
 			continue
 		}
-		splitted := strings.Split(l, ":")
+		if strings.HasPrefix(l, "FN:") || strings.HasPrefix(l, "FNDA:") {
+			if err := parseLcovFunc(l, &funcs); err != nil {
+				_ = r.Close() //nostyle:handlerrors
+				return nil, "", err
+			}
+			continue
+		}
+		splitted := strings.SplitN(l, ":", 2)
 		if len(splitted) != 2 {
 			continue
 		}
@@ -71,8 +84,9 @@ func (l *Lcov) ParseReport(path string) (*Coverage, string, error) {
 			fileName = splitted[1]
 		case "DA":
 			total += 1
+			// DA:<line>,<count>[,<checksum>]
 			nums := strings.Split(splitted[1], ",")
-			if len(nums) != 2 {
+			if len(nums) < 2 {
 				_ = r.Close() //nostyle:handlerrors
 				return nil, "", fmt.Errorf("can not parse: %s", l)
 			}
@@ -108,6 +122,71 @@ func (l *Lcov) ParseReport(path string) (*Coverage, string, error) {
 	return cov, rp, nil
 }
 
+// mergeLcovBlocks merges the line coverages into the file coverage, and returns the increase of the total and covered lines.
+func mergeLcovBlocks(fcov *FileCoverage, blocks BlockCoverages) (int, int) {
+	var total, covered int
+	lines := map[int]*BlockCoverage{}
+	for _, b := range fcov.Blocks {
+		lines[*b.StartLine] = b
+	}
+	for _, b := range blocks {
+		eb, ok := lines[*b.StartLine]
+		if !ok {
+			fcov.Blocks = append(fcov.Blocks, b)
+			lines[*b.StartLine] = b
+			total += 1
+			if *b.Count > 0 {
+				covered += 1
+			}
+			continue
+		}
+		if *eb.Count == 0 && *b.Count > 0 {
+			covered += 1
+		}
+		c := *eb.Count + *b.Count
+		eb.Count = &c
+	}
+	fcov.Total += total
+	fcov.Covered += covered
+	return total, covered
+}
+
+// parseLcovFunc parses FN:<line>[,<end line>],<name> and FNDA:<count>,<name>.
+func parseLcovFunc(l string, funcs *FuncCoverages) error {
+	k, v, _ := strings.Cut(l, ":")
+	n, name, ok := strings.Cut(v, ",")
+	if !ok {
+		return fmt.Errorf("can not parse: %s", l)
+	}
+	num, err := strconv.Atoi(n)
+	if err != nil {
+		return err
+	}
+	find := func(name string) *FuncCoverage {
+		for _, f := range *funcs {
+			if f.Name == name {
+				return f
+			}
+		}
+		f := &FuncCoverage{Name: name}
+		*funcs = append(*funcs, f)
+		return f
+	}
+	switch k {
+	case "FN":
+		// LCOV 2.x: FN:<line>,<end line>,<name>
+		if e, rest, ok := strings.Cut(name, ","); ok {
+			if _, err := strconv.Atoi(e); err == nil {
+				name = rest
+			}
+		}
+		find(name).Line = num
+	case "FNDA":
+		find(name).Count += num
+	}
+	return nil
+}
+
 func (l *Lcov) detectReportPath(path string) (string, error) {
 	p, err := os.Stat(path)
 	if err != nil {
@@ -3,6 +3,8 @@ package coverage
 import (
 	"path/filepath"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestLcov(t *testing.T) {
@@ -44,6 +46,65 @@ func TestLcov(t *testing.T) {
 	}
 }
 
+func TestLcovFuncs(t *testing.T) {
+	path := filepath.Join(testdataDir(t), "lcov_funcs")
+	got, _, err := NewLcov().ParseReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsMeasuredFuncs() {
+		t.Error("function-level coverage should be measured")
+	}
+	want := FuncCoverages{
+		{Name: "add", Line: 1, Count: 3},
+		{Name: "sub", Line: 5, Count: 0},
+		{Name: "mul", Line: 9, Count: 0},
+	}
+	if diff := cmp.Diff(got.Files[0].Funcs, want, nil); diff != "" {
+		t.Error(diff)
+	}
+	if got := got.UncoveredFuncs(); got != 2 {
+		t.Errorf("got %v\nwant %v", got, 2)
+	}
+}
+
+func TestLcovMultipleSections(t *testing.T) {
+	path := filepath.Join(testdataDir(t), "lcov_multi")
+	got, _, err := NewLcov().ParseReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Total != 6 || got.Covered != 4 {
+		t.Errorf("got %v/%v\nwant %v/%v", got.Covered, got.Total, 4, 6)
+	}
+	if len(got.Files) != 2 {
+		t.Fatalf("got %v\nwant %v", len(got.Files), 2)
+	}
+	tests := []struct {
+		file    string
+		total   int
+		covered int
+	}{
+		{"src/app.ts", 4, 2},
+		{"C:\\work\\server\\main.go", 2, 2},
+	}
+	for _, tt := range tests {
+		fc, err := got.Files.FindByFile(tt.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fc.Total != tt.total || fc.Covered != tt.covered {
+			t.Errorf("%s: got %v/%v\nwant %v/%v", tt.file, fc.Covered, fc.Total, tt.covered, tt.total)
+		}
+		if got := len(fc.Blocks); got != tt.total {
+			t.Errorf("%s: got %v\nwant %v", tt.file, got, tt.total)
+		}
+	}
+	if got.IsMeasuredFuncs() {
+		t.Error("function-level coverage should not be measured")
+	}
+}
+
 func TestLcovParseAllFormat(t *testing.T) {
 	tests := []struct {
 		path    string
@@ -1,9 +1,19 @@
 package coverage
 
+import "fmt"
+
 func (c *Coverage) Merge(c2 *Coverage) error {
 	if c2 == nil {
 		c2 = &Coverage{}
 	}
+	// Mode
+	switch {
+	case c2.Mode == "":
+	case c.Mode == "":
+		c.Mode = c2.Mode
+	case c.Mode != c2.Mode:
+		return fmt.Errorf("can not merge Go coverage profiles of different modes (%s, %s)", c.Mode, c2.Mode)
+	}
 	// Type
 	switch {
 	case c2.Type == "":
@@ -12,12 +22,28 @@ func (c *Coverage) Merge(c2 *Coverage) error {
 	}
 	// Files
 	for _, fc2 := range c2.Files {
-		fc, err := c.Files.FindByFile(fc2.File)
+		var (
+			fc  *FileCoverage
+			err error
+		)
+		if c.CaseInsensitive {
+			fc, err = c.Files.findByFileFold(fc2.File)
+		} else {
+			fc, err = c.Files.FindByFile(fc2.File)
+		}
 		if err == nil {
+			if err := compareLines(fc, fc2); err != nil {
+				return err
+			}
 			if fc2.Type != fc.Type {
 				fc.Type = TypeMerged
 			}
-			fc.Blocks = append(fc.Blocks, fc2.Blocks...)
+			blocks, err := mergeBlocks(fc.File, c.Mode, fc.Blocks, fc2.Blocks)
+			if err != nil {
+				return err
+			}
+			fc.Blocks = blocks
+			fc.Funcs = fc.Funcs.merge(fc2.Funcs)
 		} else {
 			c.Files = append(c.Files, fc2)
 		}
@@ -29,6 +55,12 @@ func (c *Coverage) reCalc() error {
 	total := 0
 	covered := 0
 	for _, f := range c.Files {
+		if len(f.Blocks) == 0 && f.Total > 0 {
+			// The file coverage of the shrunk or truncated report (e.g. stored in datastores) has no blocks to recalculate
+			total += f.Total
+			covered += f.Covered
+			continue
+		}
 		var fileTotal, fileCovered int
 
 		switch f.Type {
@@ -56,3 +88,121 @@ func (c *Coverage) reCalc() error {
 
 	return nil
 }
+
+type blockKey struct {
+	typ                                  Type
+	startLine, startCol, endLine, endCol int
+}
+
+func (b *BlockCoverage) key() blockKey {
+	k := blockKey{typ: b.Type, startCol: -1, endCol: -1}
+	if b.StartLine != nil {
+		k.startLine = *b.StartLine
+	}
+	if b.EndLine != nil {
+		k.endLine = *b.EndLine
+	}
+	if b.StartCol != nil {
+		k.startCol = *b.StartCol
+	}
+	if b.EndCol != nil {
+		k.endCol = *b.EndCol
+	}
+	return k
+}
+
+// compareLines returns an error if the coverages of the same file disagree on the lines (or the statements) to be covered.
+func compareLines(fc, fc2 *FileCoverage) error {
+	if fc.Type == TypeStmt && fc2.Type == TypeStmt {
+		stmts, stmts2 := fc.Blocks.numStmt(), fc2.Blocks.numStmt()
+		if stmts != stmts2 {
+			return fmt.Errorf("can not merge coverages of %s: the total number of statements differs (%d, %d)", fc.File, stmts, stmts2)
+		}
+		return nil
+	}
+	if fc.Type != TypeLOC || fc2.Type != TypeLOC {
+		return nil
+	}
+	lines, lines2 := fc.Blocks.lines(), fc2.Blocks.lines()
+	if len(lines) != len(lines2) {
+		return fmt.Errorf("can not merge coverages of %s: the total number of lines differs (%d, %d)", fc.File, len(lines), len(lines2))
+	}
+	for l := range lines2 {
+		if _, ok := lines[l]; !ok {
+			return fmt.Errorf("can not merge coverages of %s: the line %d is not in both coverages", fc.File, l)
+		}
+	}
+	return nil
+}
+
+// numStmt returns the total number of the statements of the block coverages.
+func (bc BlockCoverages) numStmt() int { //nostyle:recvtype
+	n := 0
+	for _, b := range bc {
+		if b.NumStmt != nil {
+			n += *b.NumStmt
+		}
+	}
+	return n
+}
+
+// lines returns the set of the lines of the block coverages.
+func (bc BlockCoverages) lines() map[int]struct{} { //nostyle:recvtype
+	lines := map[int]struct{}{}
+	for _, b := range bc {
+		if b.StartLine == nil || b.EndLine == nil {
+			continue
+		}
+		for l := *b.StartLine; l <= *b.EndLine; l++ {
+			lines[l] = struct{}{}
+		}
+	}
+	return lines
+}
+
+// mergeBlocks merges the block coverages of the same file.
+// Blocks at the same position are deduplicated by taking the max count (not summing),
+// and it returns an error if they disagree on the number of statements.
+// The counts of the statement blocks of Go coverage profiles follow the mode instead:
+// OR (0 or 1) for set, and sum for count and atomic, in the same way as the go tool merges profiles.
+func mergeBlocks(file, mode string, bcs, bcs2 BlockCoverages) (BlockCoverages, error) {
+	m := map[blockKey]*BlockCoverage{}
+	for _, b := range bcs {
+		m[b.key()] = b
+	}
+	for _, b2 := range bcs2 {
+		k := b2.key()
+		b, ok := m[k]
+		if !ok {
+			bcs = append(bcs, b2)
+			m[k] = b2
+			continue
+		}
+		if b.NumStmt != nil && b2.NumStmt != nil && *b.NumStmt != *b2.NumStmt {
+			return nil, fmt.Errorf("can not merge coverages of %s: the number of statements of the block %d.%d,%d.%d differs (%d, %d)", file, k.startLine, k.startCol, k.endLine, k.endCol, *b.NumStmt, *b2.NumStmt)
+		}
+		if b.Count != nil && b2.Count != nil {
+			bm := ""
+			if b.Type == TypeStmt {
+				bm = mode
+			}
+			c := mergeCount(bm, *b.Count, *b2.Count)
+			b.Count = &c
+		}
+	}
+	return bcs, nil
+}
+
+func mergeCount(mode string, c, c2 int) int {
+	switch mode {
+	case GocoverModeSet:
+		if c > 0 || c2 > 0 {
+			return 1
+		}
+		return 0
+	case GocoverModeCount, GocoverModeAtomic:
+		return c + c2
+	default:
+		return max(c, c2)
+	}
+}
@@ -152,12 +152,9 @@ func TestMerge(t *testing.T) {
 						Total:   3,
 						Covered: 3,
 						Blocks: BlockCoverages{
-							newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 0),
-							newBlockCoverage(TypeLOC, 2, -1, 2, -1, -1, 1),
-							newBlockCoverage(TypeLOC, 3, -1, 3, -1, -1, 1),
 							newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 1),
 							newBlockCoverage(TypeLOC, 2, -1, 2, -1, -1, 1),
-							newBlockCoverage(TypeLOC, 3, -1, 3, -1, -1, 0),
+							newBlockCoverage(TypeLOC, 3, -1, 3, -1, -1, 1),
 						},
 					},
 				},
@@ -206,9 +203,6 @@ func TestMerge(t *testing.T) {
 							newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 1),
 							newBlockCoverage(TypeLOC, 2, -1, 2, -1, -1, 0),
 							newBlockCoverage(TypeLOC, 3, -1, 3, -1, -1, 1),
-							newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 1),
-							newBlockCoverage(TypeLOC, 2, -1, 2, -1, -1, 0),
-							newBlockCoverage(TypeLOC, 3, -1, 3, -1, -1, 1),
 						},
 					},
 				},
@@ -545,3 +539,203 @@ func TestMerge(t *testing.T) {
 		}
 	}
 }
+
+func TestMergeCaseInsensitive(t *testing.T) {
+	tests := []struct {
+		caseInsensitive bool
+		wantFiles       int
+		wantTotal       int
+	}{
+		{false, 2, 4},
+		{true, 1, 2},
+	}
+	for _, tt := range tests {
+		c1 := &Coverage{
+			Type: TypeLOC,
+			Files: FileCoverages{
+				&FileCoverage{
+					File:   "Pkg/file_a.go",
+					Type:   TypeLOC,
+					Blocks: BlockCoverages{newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 1), newBlockCoverage(TypeLOC, 2, -1, 2, -1, -1, 0)},
+				},
+			},
+			CaseInsensitive: tt.caseInsensitive,
+		}
+		c2 := &Coverage{
+			Type: TypeLOC,
+			Files: FileCoverages{
+				&FileCoverage{
+					File:   "pkg/file_a.go",
+					Type:   TypeLOC,
+					Blocks: BlockCoverages{newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 0), newBlockCoverage(TypeLOC, 2, -1, 2, -1, -1, 1)},
+				},
+			},
+		}
+		if err := c1.Merge(c2); err != nil {
+			t.Fatal(err)
+		}
+		if got := len(c1.Files); got != tt.wantFiles {
+			t.Errorf("got %v\nwant %v", got, tt.wantFiles)
+		}
+		if got := c1.Total; got != tt.wantTotal {
+			t.Errorf("got %v\nwant %v", got, tt.wantTotal)
+		}
+	}
+}
+
+func TestMergeSameStmtBlocks(t *testing.T) {
+	newCov := func(bcs ...*BlockCoverage) *Coverage {
+		return &Coverage{
+			Type: TypeStmt,
+			Files: FileCoverages{
+				&FileCoverage{File: "file_a.go", Type: TypeStmt, Blocks: bcs},
+			},
+		}
+	}
+	t.Run("shards", func(t *testing.T) {
+		c := newCov(
+			newBlockCoverage(TypeStmt, 1, 1, 3, 1, 2, 3),
+			newBlockCoverage(TypeStmt, 4, 1, 6, 1, 3, 0),
+		)
+		c2 := newCov(
+			newBlockCoverage(TypeStmt, 1, 1, 3, 1, 2, 1),
+			newBlockCoverage(TypeStmt, 4, 1, 6, 1, 3, 2),
+		)
+		if err := c.Merge(c2); err != nil {
+			t.Fatal(err)
+		}
+		if c.Total != 5 || c.Covered != 5 {
+			t.Errorf("got %v/%v\nwant %v/%v", c.Covered, c.Total, 5, 5)
+		}
+		want := BlockCoverages{
+			newBlockCoverage(TypeStmt, 1, 1, 3, 1, 2, 3),
+			newBlockCoverage(TypeStmt, 4, 1, 6, 1, 3, 2),
+		}
+		if diff := cmp.Diff(c.Files[0].Blocks, want, nil); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("mismatch", func(t *testing.T) {
+		c := newCov(newBlockCoverage(TypeStmt, 1, 1, 3, 1, 2, 1))
+		c2 := newCov(newBlockCoverage(TypeStmt, 1, 1, 3, 1, 3, 1))
+		if err := c.Merge(c2); err == nil {
+			t.Error("want error")
+		}
+	})
+	t.Run("modes", func(t *testing.T) {
+		tests := []struct {
+			mode  string
+			mode2 string
+			want  []int
+		}{
+			{"", "", []int{3, 2}},
+			{GocoverModeSet, GocoverModeSet, []int{1, 1}},
+			{GocoverModeCount, GocoverModeCount, []int{4, 2}},
+			{GocoverModeAtomic, GocoverModeAtomic, []int{4, 2}},
+			{"", GocoverModeCount, []int{4, 2}},
+		}
+		for _, tt := range tests {
+			c := newCov(
+				newBlockCoverage(TypeStmt, 1, 1, 3, 1, 2, 3),
+				newBlockCoverage(TypeStmt, 4, 1, 6, 1, 3, 0),
+			)
+			c.Mode = tt.mode
+			c2 := newCov(
+				newBlockCoverage(TypeStmt, 1, 1, 3, 1, 2, 1),
+				newBlockCoverage(TypeStmt, 4, 1, 6, 1, 3, 2),
+			)
+			c2.Mode = tt.mode2
+			if tt.mode == GocoverModeSet {
+				*c.Files[0].Blocks[0].Count = 1
+				*c2.Files[0].Blocks[1].Count = 1
+			}
+			if err := c.Merge(c2); err != nil {
+				t.Fatal(err)
+			}
+			var got []int
+			for _, b := range c.Files[0].Blocks {
+				got = append(got, *b.Count)
+			}
+			if diff := cmp.Diff(got, tt.want, nil); diff != "" {
+				t.Errorf("%s, %s: %s", tt.mode, tt.mode2, diff)
+			}
+			if c.Covered != 5 {
+				t.Errorf("got %v\nwant %v", c.Covered, 5)
+			}
+		}
+	})
+	t.Run("mode mismatch", func(t *testing.T) {
+		c := newCov(newBlockCoverage(TypeStmt, 1, 1, 3, 1, 2, 1))
+		c.Mode = GocoverModeSet
+		c2 := newCov(newBlockCoverage(TypeStmt, 1, 1, 3, 1, 2, 1))
+		c2.Mode = GocoverModeCount
+		if err := c.Merge(c2); err == nil {
+			t.Error("want error")
+		}
+	})
+	t.Run("statements mismatch", func(t *testing.T) {
+		c := newCov(
+			newBlockCoverage(TypeStmt, 1, 1, 3, 1, 2, 1),
+			newBlockCoverage(TypeStmt, 4, 1, 6, 1, 3, 0),
+		)
+		c2 := newCov(newBlockCoverage(TypeStmt, 1, 1, 3, 1, 2, 1))
+		if err := c.Merge(c2); err == nil {
+			t.Error("want error")
+		}
+	})
+	newLOCCov := func(bcs ...*BlockCoverage) *Coverage {
+		return &Coverage{
+			Type: TypeLOC,
+			Files: FileCoverages{
+				&FileCoverage{File: "file_a.js", Type: TypeLOC, Blocks: bcs},
+			},
+		}
+	}
+	t.Run("lines", func(t *testing.T) {
+		c := newLOCCov(
+			newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 2),
+			newBlockCoverage(TypeLOC, 2, -1, 2, -1, -1, 0),
+		)
+		c2 := newLOCCov(
+			newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 1),
+			newBlockCoverage(TypeLOC, 2, -1, 2, -1, -1, 3),
+		)
+		if err := c.Merge(c2); err != nil {
+			t.Fatal(err)
+		}
+		if c.Total != 2 || c.Covered != 2 {
+			t.Errorf("got %v/%v\nwant %v/%v", c.Covered, c.Total, 2, 2)
+		}
+	})
+	t.Run("lines mismatch", func(t *testing.T) {
+		tests := []struct {
+			name string
+			bcs2 BlockCoverages
+		}{
+			{
+				"total",
+				BlockCoverages{
+					newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 1),
+					newBlockCoverage(TypeLOC, 2, -1, 2, -1, -1, 1),
+					newBlockCoverage(TypeLOC, 3, -1, 3, -1, -1, 1),
+				},
+			},
+			{
+				"line",
+				BlockCoverages{
+					newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 1),
+					newBlockCoverage(TypeLOC, 3, -1, 3, -1, -1, 1),
+				},
+			},
+		}
+		for _, tt := range tests {
+			c := newLOCCov(
+				newBlockCoverage(TypeLOC, 1, -1, 1, -1, -1, 2),
+				newBlockCoverage(TypeLOC, 2, -1, 2, -1, -1, 0),
+			)
+			if err := c.Merge(newLOCCov(tt.bcs2...)); err == nil {
+				t.Errorf("%s: want error", tt.name)
+			}
+		}
+	})
+}
@@ -0,0 +1,164 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+var _ Processor = (*Opencover)(nil)
+
+const OpencoverDefaultPath = "coverage.opencover.xml"
+
+type Opencover struct{}
+
+type OpencoverReport struct {
+	XMLName xml.Name                `xml:"CoverageSession"`
+	Summary *OpencoverReportSummary `xml:"Summary"`
+	Modules []OpencoverReportModule `xml:"Modules>Module"`
+}
+
+type OpencoverReportSummary struct {
+	NumSequencePoints     int     `xml:"numSequencePoints,attr"`
+	VisitedSequencePoints int     `xml:"visitedSequencePoints,attr"`
+	NumBranchPoints       int     `xml:"numBranchPoints,attr"`
+	VisitedBranchPoints   int     `xml:"visitedBranchPoints,attr"`
+	SequenceCoverage      float64 `xml:"sequenceCoverage,attr"`
+	BranchCoverage        float64 `xml:"branchCoverage,attr"`
+}
+
+type OpencoverReportModule struct {
+	SkippedDueTo string `xml:"skippedDueTo,attr"`
+	ModuleName   string `xml:"ModuleName"`
+	Files        []struct {
+		UID      string `xml:"uid,attr"`
+		FullPath string `xml:"fullPath,attr"`
+	} `xml:"Files>File"`
+	Classes []struct {
+		FullName string `xml:"FullName"`
+		Methods  []struct {
+			FileRef *struct {
+				UID string `xml:"uid,attr"`
+			} `xml:"FileRef"`
+			SequencePoints []OpencoverReportSequencePoint `xml:"SequencePoints>SequencePoint"`
+		} `xml:"Methods>Method"`
+	} `xml:"Classes>Class"`
+}
+
+type OpencoverReportSequencePoint struct {
+	Vc     int    `xml:"vc,attr"`
+	Sl     int    `xml:"sl,attr"`
+	Sc     int    `xml:"sc,attr"`
+	El     int    `xml:"el,attr"`
+	Ec     int    `xml:"ec,attr"`
+	Fileid string `xml:"fileid,attr"`
+}
+
+func NewOpencover() *Opencover {
+	return &Opencover{}
+}
+
+func (c *Opencover) Name() string {
+	return "OpenCover"
+}
+
+func (c *Opencover) ParseReport(path string) (*Coverage, string, error) {
+	rp, err := c.detectReportPath(path)
+	if err != nil {
+		return nil, "", err
+	}
+	b, err := os.ReadFile(filepath.Clean(rp))
+	if err != nil {
+		return nil, "", err
+	}
+	r := OpencoverReport{}
+	if err := xml.Unmarshal(b, &r); err != nil {
+		return nil, "", err
+	}
+	if r.Summary == nil {
+		return nil, "", fmt.Errorf("%s is not OpenCover format", filepath.Clean(rp))
+	}
+
+	cov := New()
+	// A sequence point is the smallest unit of executable code (similar to a statement).
+	cov.Type = TypeStmt
+	cov.Format = c.Name()
+
+	flm := map[string]BlockCoverages{}
+	for _, m := range r.Modules {
+		if m.SkippedDueTo != "" {
+			continue
+		}
+		files := map[string]string{}
+		for _, f := range m.Files {
+			files[f.UID] = f.FullPath
+		}
+		for _, cl := range m.Classes {
+			for _, me := range cl.Methods {
+				for _, sp := range me.SequencePoints {
+					uid := sp.Fileid
+					if uid == "" && me.FileRef != nil {
+						uid = me.FileRef.UID
+					}
+					n, ok := files[uid]
+					if !ok {
+						continue
+					}
+					sl := sp.Sl
+					sc := sp.Sc
+					el := sp.El
+					ec := sp.Ec
+					ns := 1
+					vc := sp.Vc
+					flm[n] = append(flm[n], &BlockCoverage{
+						Type:      TypeStmt,
+						StartLine: &sl,
+						StartCol:  &sc,
+						EndLine:   &el,
+						EndCol:    &ec,
+						NumStmt:   &ns,
+						Count:     &vc,
+					})
+				}
+			}
+		}
+	}
+
+	var names []string
+	for n := range flm {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		blocks := flm[n]
+		fcov := NewFileCoverage(n, TypeStmt)
+		for _, b := range blocks {
+			fcov.Total += *b.NumStmt
+			if *b.Count > 0 {
+				fcov.Covered += *b.NumStmt
+			}
+		}
+		fcov.Blocks = blocks
+		cov.Total += fcov.Total
+		cov.Covered += fcov.Covered
+		cov.Files = append(cov.Files, fcov)
+	}
+
+	return cov, rp, nil
+}
+
+func (c *Opencover) detectReportPath(path string) (string, error) {
+	p, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if p.IsDir() {
+		path = filepath.Join(path, OpencoverDefaultPath)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
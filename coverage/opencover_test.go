@@ -0,0 +1,59 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpencover(t *testing.T) {
+	path := filepath.Join(testdataDir(t), "opencover")
+	opencover := NewOpencover()
+	got, _, err := opencover.ParseReport(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Files) != 2 {
+		t.Errorf("got %v\nwant %v", len(got.Files), 2)
+	}
+
+	b, err := os.ReadFile(filepath.Join(path, OpencoverDefaultPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := OpencoverReport{}
+	if err := xml.Unmarshal(b, &r); err != nil {
+		t.Fatal(err)
+	}
+	if got.Total != r.Summary.NumSequencePoints {
+		t.Errorf("got %v\nwant %v", got.Total, r.Summary.NumSequencePoints)
+	}
+	if got.Covered != r.Summary.VisitedSequencePoints {
+		t.Errorf("got %v\nwant %v", got.Covered, r.Summary.VisitedSequencePoints)
+	}
+	for _, f := range got.Files {
+		total := 0
+		covered := 0
+		for _, b := range f.Blocks {
+			total += *b.NumStmt
+			if *b.Count > 0 {
+				covered += *b.NumStmt
+			}
+		}
+		if got := f.Total; got != total {
+			t.Errorf("got %v\nwant %v", got, total)
+		}
+		if got := f.Covered; got != covered {
+			t.Errorf("got %v\nwant %v", got, covered)
+		}
+	}
+}
+
+func TestOpencoverNotOpencover(t *testing.T) {
+	path := filepath.Join(testdataDir(t), "clover", "coverage.xml")
+	opencover := NewOpencover()
+	if _, _, err := opencover.ParseReport(path); err == nil {
+		t.Error("want error")
+	}
+}
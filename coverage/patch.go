@@ -0,0 +1,167 @@
+package coverage
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var hunkRe = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// AddedLines is the line numbers (in the new file) added or changed by a patch, by file path.
+type AddedLines map[string][]int
+
+// ParseUnifiedDiff parses the unified diff (e.g. `git diff` or the diff of the pull request) and returns the added lines.
+// Renamed files are keyed by the new path, and deleted files are ignored.
+func ParseUnifiedDiff(r io.Reader) (AddedLines, error) {
+	added := AddedLines{}
+	var (
+		file         string
+		line, remain int
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSrcSize)
+	for scanner.Scan() {
+		l := scanner.Text()
+		if remain > 0 {
+			switch {
+			case strings.HasPrefix(l, "+"):
+				if file != "" {
+					added[file] = append(added[file], line)
+				}
+				line++
+				remain--
+			case strings.HasPrefix(l, "-"):
+			case strings.HasPrefix(l, `\`):
+				// \ No newline at end of file
+			default:
+				line++
+				remain--
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(l, "diff --git "):
+			file = ""
+		case strings.HasPrefix(l, "+++ "):
+			p := strings.TrimPrefix(l, "+++ ")
+			if i := strings.Index(p, "\t"); i >= 0 {
+				p = p[:i]
+			}
+			if p == "/dev/null" {
+				file = ""
+				continue
+			}
+			file = strings.TrimPrefix(p, "b/")
+		case strings.HasPrefix(l, "@@ "):
+			m := hunkRe.FindStringSubmatch(l)
+			if m == nil {
+				return nil, fmt.Errorf("invalid hunk header: %s", l)
+			}
+			line, _ = strconv.Atoi(m[1]) //nostyle:handlerrors
+			remain = 1
+			if m[2] != "" {
+				remain, _ = strconv.Atoi(m[2]) //nostyle:handlerrors
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	for f, lines := range added {
+		if len(lines) == 0 {
+			delete(added, f)
+		}
+	}
+	return added, nil
+}
+
+// PatchCoverage is the code coverage of the lines added by a patch.
+type PatchCoverage struct {
+	Total   int                  `json:"total"`
+	Covered int                  `json:"covered"`
+	Files   []*PatchFileCoverage `json:"files"`
+}
+
+type PatchFileCoverage struct {
+	File    string `json:"file"`
+	Total   int    `json:"total"`
+	Covered int    `json:"covered"`
+	// InReport is whether the file is found in the coverage report
+	InReport bool `json:"in_report"`
+}
+
+// Percent returns the patch coverage. It returns 100 if the patch has no lines to be covered.
+func (pc *PatchCoverage) Percent() float64 {
+	if pc == nil || pc.Total == 0 {
+		return 100
+	}
+	return float64(pc.Covered) / float64(pc.Total) * 100
+}
+
+// PatchCoverage returns the code coverage of the added lines.
+// Only the added lines that are measurable in the coverage report (e.g. statements) are counted.
+// The added lines of the files not found in the coverage report are skipped, or counted as uncovered if countMissingFiles is true.
+// Only the missing files with the same extension as a file in the coverage report are counted, so that added lines of non-code files (e.g. README.md) are not.
+func (c *Coverage) PatchCoverage(added AddedLines, countMissingFiles bool) (*PatchCoverage, error) {
+	if c == nil {
+		return nil, errors.New("code coverage is not measured")
+	}
+	files := make([]string, 0, len(added))
+	for f := range added {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	exts := map[string]struct{}{}
+	if countMissingFiles {
+		for _, fc := range c.Files {
+			if ext := filepath.Ext(fc.File); ext != "" {
+				exts[ext] = struct{}{}
+			}
+		}
+	}
+	pc := &PatchCoverage{Files: []*PatchFileCoverage{}}
+	for _, f := range files {
+		pfc := &PatchFileCoverage{File: f}
+		fc, err := c.Files.FuzzyFindByFile(f)
+		if err != nil {
+			if !countMissingFiles {
+				continue
+			}
+			if _, ok := exts[filepath.Ext(f)]; !ok {
+				continue
+			}
+			pfc.Total = len(added[f])
+		} else {
+			pfc.InReport = true
+			lcs := fc.Blocks.ToLineCoverages()
+			counts := make(map[int]int, len(lcs))
+			for _, lc := range lcs {
+				counts[lc.Line] = lc.Count
+			}
+			for _, l := range added[f] {
+				cnt, ok := counts[l]
+				if !ok {
+					continue
+				}
+				pfc.Total++
+				if cnt > 0 {
+					pfc.Covered++
+				}
+			}
+		}
+		if pfc.Total == 0 {
+			continue
+		}
+		pc.Total += pfc.Total
+		pc.Covered += pfc.Covered
+		pc.Files = append(pc.Files, pfc)
+	}
+	return pc, nil
+}
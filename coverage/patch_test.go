@@ -0,0 +1,132 @@
+package coverage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testPatch = `diff --git a/pkg/a.go b/pkg/a.go
+index 1111111..2222222 100644
+--- a/pkg/a.go
++++ b/pkg/a.go
+@@ -1,3 +1,4 @@
+ package pkg
+-var a = 1
++var a = 2
++var b = 3
+ 
+@@ -10 +11,2 @@ func A() {
++	b++
++	return
+diff --git a/old.go b/new.go
+similarity index 90%
+rename from old.go
+rename to new.go
+--- a/old.go
++++ b/new.go
+@@ -5,0 +6 @@
++	c := 1
+diff --git a/deleted.go b/deleted.go
+deleted file mode 100644
+--- a/deleted.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package pkg
+-var d = 1
+diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1 +1 @@
+-# title
++# Title
+\ No newline at end of file
+`
+
+func TestParseUnifiedDiff(t *testing.T) {
+	got, err := ParseUnifiedDiff(strings.NewReader(testPatch))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := AddedLines{
+		"pkg/a.go":  []int{2, 3, 11, 12},
+		"new.go":    []int{6},
+		"README.md": []int{1},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestPatchCoverage(t *testing.T) {
+	c := &Coverage{
+		Files: FileCoverages{
+			&FileCoverage{
+				File: "github.com/owner/repo/pkg/a.go",
+				Blocks: BlockCoverages{
+					newBlockCoverage(TypeLOC, 2, -1, 2, -1, -1, 1),
+					newBlockCoverage(TypeLOC, 11, -1, 11, -1, -1, 0),
+					newBlockCoverage(TypeLOC, 12, -1, 12, -1, -1, 0),
+				},
+			},
+		},
+	}
+	added := AddedLines{
+		"pkg/a.go":                 []int{2, 3, 11, 12},
+		"new.go":                   []int{6},
+		"README.md":                []int{1, 2},
+		".github/workflows/ci.yml": []int{3},
+	}
+	tests := []struct {
+		countMissingFiles bool
+		want              *PatchCoverage
+	}{
+		{
+			false,
+			&PatchCoverage{
+				Total:   3,
+				Covered: 1,
+				Files: []*PatchFileCoverage{
+					{File: "pkg/a.go", Total: 3, Covered: 1, InReport: true},
+				},
+			},
+		},
+		{
+			true,
+			&PatchCoverage{
+				Total:   4,
+				Covered: 1,
+				Files: []*PatchFileCoverage{
+					{File: "new.go", Total: 1, Covered: 0, InReport: false},
+					{File: "pkg/a.go", Total: 3, Covered: 1, InReport: true},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		got, err := c.PatchCoverage(added, tt.countMissingFiles)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestPatchCoveragePercent(t *testing.T) {
+	tests := []struct {
+		pc   *PatchCoverage
+		want float64
+	}{
+		{nil, 100},
+		{&PatchCoverage{}, 100},
+		{&PatchCoverage{Total: 4, Covered: 1}, 25},
+	}
+	for _, tt := range tests {
+		if got := tt.pc.Percent(); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
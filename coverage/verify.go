@@ -0,0 +1,61 @@
+package coverage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// VerifySource compares the file coverages with the source files under root,
+// and returns the mismatches such as blocks beyond the end of the source file.
+// Files that cannot be found under root are skipped.
+func (c *Coverage) VerifySource(root string) ([]string, error) {
+	var mismatches []string
+	for _, fc := range c.Files {
+		maxLine := 0
+		for _, b := range fc.Blocks {
+			if b.EndLine != nil && *b.EndLine > maxLine {
+				maxLine = *b.EndLine
+			}
+		}
+		if maxLine == 0 {
+			continue
+		}
+		p, ok := FindSourceFile(root, fc.File)
+		if !ok {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Clean(p))
+		if err != nil {
+			return nil, err
+		}
+		lines := bytes.Count(b, []byte("\n"))
+		if len(b) > 0 && !bytes.HasSuffix(b, []byte("\n")) {
+			lines += 1
+		}
+		if maxLine > lines {
+			mismatches = append(mismatches, fmt.Sprintf("%s: coverage refers to line %d but the source file has %d lines", fc.File, maxLine, lines))
+		}
+	}
+	return mismatches, nil
+}
+
+// FindSourceFile finds the source file of the file coverage under root.
+// When the file is recorded as a package path (ex. github.com/owner/repo/path/to/file.go), leading elements are trimmed until the file is found.
+func FindSourceFile(root, file string) (string, bool) {
+	if filepath.IsAbs(file) {
+		if fi, err := os.Stat(file); err == nil && !fi.IsDir() {
+			return file, true
+		}
+	}
+	elems := strings.Split(strings.TrimPrefix(filepath.ToSlash(file), "./"), "/")
+	for i := range elems {
+		p := filepath.Join(root, filepath.FromSlash(strings.Join(elems[i:], "/")))
+		if fi, err := os.Stat(p); err == nil && !fi.IsDir() {
+			return p, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,81 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVerifySource(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "pkg"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "pkg", "a.go"), []byte("package pkg\n\nfunc A() {\n}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		file    string
+		endLine int
+		want    []string
+	}{
+		{"pkg/a.go", 4, nil},
+		{"github.com/owner/repo/pkg/a.go", 4, nil},
+		{"github.com/owner/repo/pkg/a.go", 10, []string{"github.com/owner/repo/pkg/a.go: coverage refers to line 10 but the source file has 4 lines"}},
+		{filepath.Join(root, "pkg", "a.go"), 5, []string{filepath.Join(root, "pkg", "a.go") + ": coverage refers to line 5 but the source file has 4 lines"}},
+		{"pkg/notexist.go", 10, nil},
+	}
+	for _, tt := range tests {
+		c := &Coverage{
+			Files: FileCoverages{
+				&FileCoverage{
+					File:   tt.file,
+					Type:   TypeLOC,
+					Blocks: BlockCoverages{newBlockCoverage(TypeLOC, 1, -1, tt.endLine, -1, -1, 1)},
+				},
+			},
+		}
+		got, err := c.VerifySource(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestFindSourceFile(t *testing.T) {
+	root := t.TempDir()
+	for _, p := range []string{filepath.Join("pkg", "a.go"), filepath.Join(".github", "a.go"), filepath.Join("github", "b.go")} {
+		if err := os.MkdirAll(filepath.Join(root, filepath.Dir(p)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, p), []byte("package pkg\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		file   string
+		want   string
+		wantOK bool
+	}{
+		{"pkg/a.go", filepath.Join(root, "pkg", "a.go"), true},
+		{"./pkg/a.go", filepath.Join(root, "pkg", "a.go"), true},
+		{"github.com/owner/repo/pkg/a.go", filepath.Join(root, "pkg", "a.go"), true},
+		{".github/a.go", filepath.Join(root, ".github", "a.go"), true},
+		{".github/b.go", "", false},
+		{"pkg/notexist.go", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := FindSourceFile(root, tt.file)
+		if ok != tt.wantOK {
+			t.Errorf("%s: got %v\nwant %v", tt.file, ok, tt.wantOK)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %v\nwant %v", tt.file, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package artifact
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
@@ -53,32 +54,49 @@ func (a *Artifact) Put(ctx context.Context, path string, content []byte) error {
 	return a.gh.PutArtifact(ctx, a.name, path, content)
 }
 
-func (a *Artifact) FS() (fs.FS, error) {
-	ctx := context.Background()
-	var (
-		path, name string
-		r          *gh.Repository
-		err        error
-	)
-	if a.r == nil {
-		r, err = gh.Parse(a.repository)
-		if err != nil {
-			return nil, err
-		}
-		path = fmt.Sprintf("%s/%s/%s", r.Owner, r.Repo, reportFilename)
-		name = a.name
-	} else {
-		r, err = gh.Parse(a.r.Repository)
-		if err != nil {
+// FetchReportOfRef fetches the report uploaded by the workflow run for the ref (commit SHA, branch or tag).
+func (a *Artifact) FetchReportOfRef(ctx context.Context, ref string) (*report.Report, error) {
+	r, err := gh.Parse(a.repository)
+	if err != nil {
+		return nil, err
+	}
+	af, err := a.gh.FetchArtifactOfRef(ctx, r.Owner, r.Repo, a.name, reportFilename, ref)
+	if err != nil {
+		return nil, err
+	}
+	rt := &report.Report{}
+	if err := json.Unmarshal(af.Content, rt); err != nil {
+		return nil, err
+	}
+	return rt, nil
+}
+
+// FetchRecentReports fetches up to n latest reports uploaded by workflow runs.
+func (a *Artifact) FetchRecentReports(ctx context.Context, n int) ([]*report.Report, error) {
+	r, _, name, err := a.location()
+	if err != nil {
+		return nil, err
+	}
+	afs, err := a.gh.FetchRecentArtifacts(ctx, r.Owner, r.Repo, name, reportFilename, n)
+	if err != nil {
+		return nil, err
+	}
+	var reports []*report.Report
+	for _, af := range afs {
+		rt := &report.Report{}
+		if err := json.Unmarshal(af.Content, rt); err != nil {
 			return nil, err
 		}
-		path = fmt.Sprintf("%s/%s/%s", r.Owner, r.Reponame(), reportFilename)
-		key := keyRep.Replace(a.r.Key())
-		if key == "" {
-			name = a.name
-		} else {
-			name = fmt.Sprintf("%s-%s", a.name, keyRep.Replace(a.r.Key()))
-		}
+		reports = append(reports, rt)
+	}
+	return reports, nil
+}
+
+func (a *Artifact) FS() (fs.FS, error) {
+	ctx := context.Background()
+	r, path, name, err := a.location()
+	if err != nil {
+		return nil, err
 	}
 	log.Printf("artifact name: %s", name)
 	af, err := a.gh.FetchLatestArtifact(ctx, r.Owner, r.Repo, name, reportFilename)
@@ -92,3 +110,24 @@ func (a *Artifact) FS() (fs.FS, error) {
 	}
 	return &fsys, nil
 }
+
+// location returns the repository, the report path and the artifact name of the report.
+func (a *Artifact) location() (*gh.Repository, string, string, error) {
+	if a.r == nil {
+		r, err := gh.Parse(a.repository)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return r, fmt.Sprintf("%s/%s/%s", r.Owner, r.Repo, reportFilename), a.name, nil
+	}
+	r, err := gh.Parse(a.r.Repository)
+	if err != nil {
+		return nil, "", "", err
+	}
+	path := fmt.Sprintf("%s/%s/%s", r.Owner, r.Reponame(), reportFilename)
+	key := keyRep.Replace(a.r.Key())
+	if key == "" {
+		return r, path, a.name, nil
+	}
+	return r, path, fmt.Sprintf("%s-%s", a.name, key), nil
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"net/http"
 	"testing/fstest"
 	"time"
 
@@ -13,9 +14,15 @@ import (
 	"github.com/k1LoW/octocov/gh"
 	"github.com/k1LoW/octocov/report"
 	"github.com/oklog/ulid/v2"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 )
 
+const (
+	createdTableRetries       = 5
+	createdTableRetryInterval = 3 * time.Second
+)
+
 type BQ struct {
 	client  *bigquery.Client
 	dataset string
@@ -106,7 +113,43 @@ func (b *BQ) StoreReport(ctx context.Context, r *report.Report) error {
 			Valid:   true,
 		}
 	}
-	return u.Put(ctx, []*ReportRecord{rr})
+	if err := u.Put(ctx, []*ReportRecord{rr}); err != nil {
+		if !isNotFound(err) {
+			return err
+		}
+		// Create the table with the reports schema when it does not exist yet
+		if err := b.CreateTable(ctx); err != nil && !isAlreadyExists(err) {
+			return err
+		}
+		return b.putWithRetry(ctx, u, rr)
+	}
+	return nil
+}
+
+// putWithRetry retries inserting the record, because streaming inserts into a newly created table may fail for a while.
+func (b *BQ) putWithRetry(ctx context.Context, u *bigquery.Inserter, rr *ReportRecord) error {
+	var err error
+	for i := 0; i < createdTableRetries; i++ {
+		if err = u.Put(ctx, []*ReportRecord{rr}); err == nil || !isNotFound(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(createdTableRetryInterval):
+		}
+	}
+	return err
+}
+
+func isNotFound(err error) bool {
+	var gerr *googleapi.Error
+	return errors.As(err, &gerr) && gerr.Code == http.StatusNotFound
+}
+
+func isAlreadyExists(err error) bool {
+	var gerr *googleapi.Error
+	return errors.As(err, &gerr) && gerr.Code == http.StatusConflict
 }
 
 func (b *BQ) Put(ctx context.Context, path string, context []byte) error {
@@ -57,6 +57,14 @@ type Datastore interface {
 	FS() (fs.FS, error)
 }
 
+var _ Versioned = (*github.Github)(nil)
+
+// Versioned is a datastore that can list the versions (e.g. blob SHAs) of the stored files without reading them.
+type Versioned interface {
+	// Versions returns the versions of the files keyed by the path in FS().
+	Versions(ctx context.Context) (map[string]string, error)
+}
+
 func New(ctx context.Context, u string, hints ...HintFunc) (Datastore, error) {
 	h := &hint{}
 	for _, hf := range hints {
@@ -87,7 +95,15 @@ func New(ctx context.Context, u string, hints ...HintFunc) (Datastore, error) {
 				return nil, err
 			}
 		}
-		return github.New(g, ownerrepo, branch, prefix)
+		gd, err := github.New(g, ownerrepo, branch, prefix)
+		if err != nil {
+			return nil, err
+		}
+		if h.githubRetries != nil {
+			gd.SetRetries(*h.githubRetries)
+		}
+		gd.SetGzip(h.gzip)
+		return gd, nil
 	case Artifact:
 		ownerrepo := args[0]
 		name := args[1]
@@ -109,7 +125,12 @@ func New(ctx context.Context, u string, hints ...HintFunc) (Datastore, error) {
 		}
 		cfg.Region = region
 		sc := s3.NewFromConfig(cfg)
-		return s3d.New(sc, bucket, prefix)
+		sd, err := s3d.New(sc, bucket, prefix)
+		if err != nil {
+			return nil, err
+		}
+		sd.SetGzip(h.gzip)
+		return sd, nil
 	case GCS:
 		bucket := args[0]
 		prefix := args[1]
@@ -122,7 +143,12 @@ func New(ctx context.Context, u string, hints ...HintFunc) (Datastore, error) {
 		if err != nil {
 			return nil, err
 		}
-		return gcs.New(client, bucket, prefix)
+		gd, err := gcs.New(client, bucket, prefix)
+		if err != nil {
+			return nil, err
+		}
+		gd.SetGzip(h.gzip)
+		return gd, nil
 	case BigQuery:
 		project := args[0]
 		dataset := args[1]
@@ -143,11 +169,22 @@ func New(ctx context.Context, u string, hints ...HintFunc) (Datastore, error) {
 		return mackerel.New(client, service)
 	case Local:
 		root := args[0]
-		return local.New(root)
+		ld, err := local.New(root)
+		if err != nil {
+			return nil, err
+		}
+		ld.SetGzip(h.gzip)
+		return ld, nil
 	}
 	return nil, fmt.Errorf("invalid datastore: %s", u)
 }
 
+// Validate checks the format of the datastore URL without connecting to the datastore.
+func Validate(u string) error {
+	_, _, err := parse(u, "")
+	return err
+}
+
 func parse(u, root string) (Type, []string, error) {
 	switch {
 	case strings.HasPrefix(u, "github://"):
@@ -232,3 +269,26 @@ func parse(u, root string) (Type, []string, error) {
 func NeedToShrink(u string) bool {
 	return strings.HasPrefix(u, "bq://")
 }
+
+// CanStoreByRef reports whether the datastore stores files by path, so that the report can also be stored keyed by the ref (report.storeByRef).
+func CanStoreByRef(u string) bool {
+	d, _, err := parse(u, "")
+	if err != nil {
+		return false
+	}
+	switch d {
+	case GitHub, S3, GCS, Local:
+		return true
+	default:
+		return false
+	}
+}
+
+// RefReportPath returns the path of the report keyed by the ref (e.g. owner/repo/refs/tags/v1.2.0/report.json).
+// A ref without the refs/ prefix is regarded as a branch.
+func RefReportPath(repository, ref string) string {
+	if !strings.HasPrefix(ref, "refs/") {
+		ref = "refs/heads/" + ref
+	}
+	return fmt.Sprintf("%s/%s/report.json", repository, ref)
+}
@@ -77,3 +77,40 @@ func testdataDir(t *testing.T) string {
 	}
 	return dir
 }
+
+func TestCanStoreByRef(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"github://owner/repo/reports", true},
+		{"s3://bucket/reports", true},
+		{"gs://bucket/reports", true},
+		{"local://reports", true},
+		{"artifact://owner/repo", false},
+		{"bq://project/dataset/table", false},
+		{"mackerel://service", false},
+		{"github://owner", false},
+	}
+	for _, tt := range tests {
+		if got := CanStoreByRef(tt.in); got != tt.want {
+			t.Errorf("%s: got %v\nwant %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRefReportPath(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{"refs/tags/v1.2.0", "owner/repo/refs/tags/v1.2.0/report.json"},
+		{"refs/heads/release", "owner/repo/refs/heads/release/report.json"},
+		{"release", "owner/repo/refs/heads/release/report.json"},
+	}
+	for _, tt := range tests {
+		if got := RefReportPath("owner/repo", tt.ref); got != tt.want {
+			t.Errorf("%s: got %v\nwant %v", tt.ref, got, tt.want)
+		}
+	}
+}
@@ -2,11 +2,11 @@ package gcs
 
 import (
 	"context"
-	"fmt"
 	"io/fs"
 	"path/filepath"
 
 	"cloud.google.com/go/storage"
+	"github.com/k1LoW/octocov/internal"
 	"github.com/k1LoW/octocov/report"
 	"github.com/mauri870/gcsfs"
 )
@@ -15,6 +15,7 @@ type GCS struct {
 	client *storage.Client
 	bucket string
 	prefix string
+	gzip   bool
 }
 
 func New(client *storage.Client, bucket, prefix string) (*GCS, error) {
@@ -25,13 +26,22 @@ func New(client *storage.Client, bucket, prefix string) (*GCS, error) {
 	}, nil
 }
 
+// SetGzip sets whether to store the report gzip-compressed (report.json.gz).
+func (g *GCS) SetGzip(gz bool) {
+	g.gzip = gz
+}
+
 func (g *GCS) StoreReport(ctx context.Context, r *report.Report) error {
-	path := fmt.Sprintf("%s/report.json", r.Repository)
+	path := internal.ReportPath(r.Repository, g.gzip)
 	return g.Put(ctx, path, r.Bytes())
 }
 
 func (g *GCS) Put(ctx context.Context, path string, content []byte) error {
 	o := filepath.Join(g.prefix, path)
+	content, err := internal.GzipIfNeeded(path, content)
+	if err != nil {
+		return err
+	}
 	w := g.client.Bucket(g.bucket).Object(o).NewWriter(ctx)
 	if _, err := w.Write(content); err != nil {
 		return err
@@ -52,8 +62,8 @@ func (fsys *FS) Open(name string) (fs.File, error) { //nostyle:recvnames
 }
 
 func (g *GCS) FS() (fs.FS, error) {
-	return &FS{
+	return internal.GzipFS(&FS{
 		prefix: g.prefix,
 		gscfs:  gcsfs.NewWithClient(g.client, g.bucket),
-	}, nil
+	}), nil
 }
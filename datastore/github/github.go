@@ -2,12 +2,17 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/fs"
+	"path"
 	"path/filepath"
+	"strings"
 
+	"github.com/google/go-github/v58/github"
 	"github.com/k1LoW/ghfs"
 	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/internal"
 	"github.com/k1LoW/octocov/report"
 )
 
@@ -17,6 +22,8 @@ type Github struct {
 	branch     string
 	prefix     string
 	from       string
+	retries    int
+	gzip       bool
 }
 
 func New(gh *gh.Gh, r, b, prefix string) (*Github, error) {
@@ -25,11 +32,22 @@ func New(gh *gh.Gh, r, b, prefix string) (*Github, error) {
 		repository: r,
 		branch:     b,
 		prefix:     prefix,
+		retries:    DefaultRetries,
 	}, nil
 }
 
+// SetRetries sets the number of retries of the GitHub API calls on transient errors (secondary rate limit, 429 and 5xx).
+func (g *Github) SetRetries(retries int) {
+	g.retries = retries
+}
+
+// SetGzip sets whether to store the report gzip-compressed (report.json.gz).
+func (g *Github) SetGzip(gz bool) {
+	g.gzip = gz
+}
+
 func (g *Github) StoreReport(ctx context.Context, r *report.Report) error {
-	path := fmt.Sprintf("%s/report.json", r.Repository)
+	path := internal.ReportPath(r.Repository, g.gzip)
 	g.from = r.Repository
 	return g.Put(ctx, path, r.Bytes())
 }
@@ -42,7 +60,16 @@ func (g *Github) Put(ctx context.Context, path string, content []byte) error {
 		return err
 	}
 	cp := filepath.Join(g.prefix, path)
-	return g.gh.PushContent(ctx, repo.Owner, repo.Repo, branch, string(content), cp, message)
+	if hasGlob(g.prefix) {
+		return fmt.Errorf("failed to store %s to %s: a glob pattern in the path is supported only for reading", cp, g.repository)
+	}
+	content, err = internal.GzipIfNeeded(path, content)
+	if err != nil {
+		return err
+	}
+	return retry(ctx, g.retries, fmt.Sprintf("store %s to %s", cp, g.repository), func() error {
+		return g.gh.PushContent(ctx, repo.Owner, repo.Repo, branch, string(content), cp, message)
+	})
 }
 
 func (g *Github) FS() (fs.FS, error) {
@@ -50,9 +77,183 @@ func (g *Github) FS() (fs.FS, error) {
 	if err != nil {
 		return nil, err
 	}
-	fsys, err := ghfs.New(r.Owner, r.Repo, ghfs.Client(g.gh.Client()), ghfs.Branch(g.branch))
+	var fsys fs.FS
+	if err := retry(context.Background(), g.retries, fmt.Sprintf("read %s", g.repository), func() error {
+		var err error
+		fsys, err = ghfs.New(r.Owner, r.Repo, ghfs.Client(g.gh.Client()), ghfs.Branch(g.branch))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	rfs := &retryFS{fsys: fsys, retries: g.retries}
+	prefix := g.prefix
+	var files map[string]string
+	if hasGlob(prefix) {
+		tree, err := g.tree(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		prefix, files, err = g.resolvePrefix(tree)
+		if err != nil {
+			return nil, err
+		}
+	}
+	sub, err := fs.Sub(rfs, prefix)
+	if err != nil {
+		return nil, err
+	}
+	if files != nil {
+		sub = &globFilesFS{FS: sub, root: rfs, prefix: prefix, files: files}
+	}
+	return internal.GzipFS(sub), nil
+}
+
+// Versions returns the blob SHAs of the files under the prefix, keyed by the path relative to the prefix.
+func (g *Github) Versions(ctx context.Context) (map[string]string, error) {
+	tree, err := g.tree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prefix, _, err := g.resolvePrefix(tree)
+	if err != nil {
+		return nil, err
+	}
+	versions := map[string]string{}
+	for _, e := range tree.Entries {
+		if e.GetType() != "blob" {
+			continue
+		}
+		p := e.GetPath()
+		if prefix != "" && prefix != "." {
+			if !strings.HasPrefix(p, prefix+"/") {
+				continue
+			}
+			p = strings.TrimPrefix(p, prefix+"/")
+		}
+		versions[p] = e.GetSHA()
+	}
+	return versions, nil
+}
+
+// tree returns the entries of all files and directories of the branch.
+func (g *Github) tree(ctx context.Context) (*github.Tree, error) {
+	r, err := gh.Parse(g.repository)
 	if err != nil {
 		return nil, err
 	}
-	return fs.Sub(fsys, g.prefix)
+	var tree *github.Tree
+	if err := retry(ctx, g.retries, fmt.Sprintf("list files of %s", g.repository), func() error {
+		var err error
+		tree, _, err = g.gh.Client().Git.GetTree(ctx, r.Owner, r.Repo, g.branch, true)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if tree.GetTruncated() {
+		return nil, errors.New("the tree of the repository is too large to list")
+	}
+	return tree, nil
+}
+
+// resolvePrefix returns the prefix in the slash-separated form.
+// A glob pattern in the prefix is resolved to the lexically last directory matching the pattern.
+// If no directory matches, the pattern is matched against the files instead: the prefix is resolved to the leading directories without a glob pattern,
+// and the lexically last matching file of each directory is returned keyed by the path of the report.json (or report.json.gz) of the directory.
+func (g *Github) resolvePrefix(tree *github.Tree) (string, map[string]string, error) {
+	prefix := strings.Trim(filepath.ToSlash(g.prefix), "/")
+	if !hasGlob(prefix) {
+		return prefix, nil, nil
+	}
+	var latest string
+	files := map[string]string{}
+	for _, e := range tree.Entries {
+		p := e.GetPath()
+		ok, err := path.Match(prefix, p)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid glob pattern in the path: %s: %w", prefix, err)
+		}
+		if !ok {
+			continue
+		}
+		switch e.GetType() {
+		case "tree":
+			if p > latest {
+				latest = p
+			}
+		case "blob":
+			key := path.Join(path.Dir(p), "report.json")
+			if strings.HasSuffix(p, internal.GzipExt) {
+				key += internal.GzipExt
+			}
+			if p > files[key] {
+				files[key] = p
+			}
+		}
+	}
+	if latest != "" {
+		return latest, nil, nil
+	}
+	if len(files) == 0 {
+		return "", nil, fmt.Errorf("no directory or file matches %s in %s@%s", prefix, g.repository, g.branch)
+	}
+	return globBase(prefix), files, nil
+}
+
+// globBase returns the leading directories of the pattern without a glob pattern.
+func globBase(pattern string) string {
+	var base []string
+	for _, s := range strings.Split(path.Dir(pattern), "/") {
+		if hasGlob(s) {
+			break
+		}
+		base = append(base, s)
+	}
+	if len(base) == 0 {
+		return "."
+	}
+	return path.Join(base...)
+}
+
+// globFilesFS is the fs.FS reading the files matching the glob pattern as the report.json of their directories.
+type globFilesFS struct {
+	fs.FS
+	// root is the fs.FS of the whole branch to read the files
+	root   fs.FS
+	prefix string
+	// files are the paths of the files keyed by the path of the report.json of their directories
+	files map[string]string
+}
+
+func (g *globFilesFS) Open(name string) (fs.File, error) {
+	if p, ok := g.lookup(name); ok {
+		return g.root.Open(p)
+	}
+	return g.FS.Open(name)
+}
+
+func (g *globFilesFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(g.FS, name)
+}
+
+// lookup returns the path of the file to be read as name.
+// The pattern may contain the directory of the repository (e.g. reports/owner/repo/*-report.json),
+// so name (e.g. owner/repo/report.json) is also looked up by the suffix.
+func (g *globFilesFS) lookup(name string) (string, bool) {
+	if p, ok := g.files[path.Join(g.prefix, name)]; ok {
+		return p, true
+	}
+	if path.Dir(name) == "." {
+		return "", false
+	}
+	var found string
+	for k, p := range g.files {
+		if strings.HasSuffix(k, "/"+name) && p > found {
+			found = p
+		}
+	}
+	return found, found != ""
+}
+
+func hasGlob(p string) bool {
+	return strings.ContainsAny(p, "*?[")
 }
@@ -0,0 +1,132 @@
+package github
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-github/v58/github"
+)
+
+func TestResolvePrefix(t *testing.T) {
+	tree := &github.Tree{
+		Entries: []*github.TreeEntry{
+			{Path: github.String("reports"), Type: github.String("tree")},
+			{Path: github.String("reports/2023-01"), Type: github.String("tree")},
+			{Path: github.String("reports/2023-12"), Type: github.String("tree")},
+			{Path: github.String("reports/2023-02"), Type: github.String("tree")},
+			{Path: github.String("reports/2023-12/k1LoW/octocov/report.json"), Type: github.String("blob")},
+			{Path: github.String("reports/2024-01.json"), Type: github.String("blob")},
+			{Path: github.String("dated"), Type: github.String("tree")},
+			{Path: github.String("dated/owner"), Type: github.String("tree")},
+			{Path: github.String("dated/owner/repo"), Type: github.String("tree")},
+			{Path: github.String("dated/owner/repo/2023-01-report.json"), Type: github.String("blob")},
+			{Path: github.String("dated/owner/repo/2023-12-report.json"), Type: github.String("blob")},
+			{Path: github.String("dated/owner/repo/2023-02-report.json"), Type: github.String("blob")},
+			{Path: github.String("dated/owner/other"), Type: github.String("tree")},
+			{Path: github.String("dated/owner/other/2023-03-report.json.gz"), Type: github.String("blob")},
+		},
+	}
+	tests := []struct {
+		prefix    string
+		want      string
+		wantFiles map[string]string
+		wantErr   bool
+	}{
+		{"reports", "reports", nil, false},
+		{"/reports/2023-01/", "reports/2023-01", nil, false},
+		{"reports/2023-*", "reports/2023-12", nil, false},
+		{"reports/202?-0?", "reports/2023-02", nil, false},
+		{"reports/*", "reports/2023-12", nil, false},
+		{"reports/2022-*", "", nil, true},
+		{"reports/[", "", nil, true},
+		{
+			"dated/owner/repo/*-report.json",
+			"dated/owner/repo",
+			map[string]string{"dated/owner/repo/report.json": "dated/owner/repo/2023-12-report.json"},
+			false,
+		},
+		{
+			"dated/*/*/*-report.json*",
+			"dated",
+			map[string]string{
+				"dated/owner/repo/report.json":     "dated/owner/repo/2023-12-report.json",
+				"dated/owner/other/report.json.gz": "dated/owner/other/2023-03-report.json.gz",
+			},
+			false,
+		},
+		{"dated/*/*/2022-*-report.json", "", nil, true},
+	}
+	for _, tt := range tests {
+		g := &Github{repository: "owner/repo", branch: "main", prefix: tt.prefix}
+		got, gotFiles, err := g.resolvePrefix(tree)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: %v", tt.prefix, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%s: want error", tt.prefix)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %v\nwant %v", tt.prefix, got, tt.want)
+		}
+		if diff := cmp.Diff(gotFiles, tt.wantFiles); diff != "" {
+			t.Errorf("%s: %s", tt.prefix, diff)
+		}
+	}
+}
+
+func TestGlobFilesFS(t *testing.T) {
+	root := fstest.MapFS{
+		"dated/owner/repo/2023-01-report.json": &fstest.MapFile{Data: []byte("old")},
+		"dated/owner/repo/2023-12-report.json": &fstest.MapFile{Data: []byte("latest")},
+	}
+	files := map[string]string{"dated/owner/repo/report.json": "dated/owner/repo/2023-12-report.json"}
+	tests := []struct {
+		prefix  string
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"dated", "owner/repo/report.json", "latest", false},
+		{"dated/owner/repo", "owner/repo/report.json", "latest", false},
+		{"dated/owner/repo", "report.json", "latest", false},
+		{"dated", "owner/other/report.json", "", true},
+		{"dated", "owner/repo/2023-01-report.json", "old", false},
+	}
+	for _, tt := range tests {
+		sub, err := fs.Sub(root, tt.prefix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fsys := &globFilesFS{FS: sub, root: root, prefix: tt.prefix, files: files}
+		b, err := fs.ReadFile(fsys, tt.name)
+		if err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s %s: %v", tt.prefix, tt.name, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%s %s: want error", tt.prefix, tt.name)
+			continue
+		}
+		if got := string(b); got != tt.want {
+			t.Errorf("%s %s: got %v\nwant %v", tt.prefix, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPutWithGlob(t *testing.T) {
+	g := &Github{repository: "owner/repo", branch: "main", prefix: "reports/2023-*"}
+	err := g.Put(context.Background(), "owner/repo/report.json", []byte("{}"))
+	if err == nil || !strings.Contains(err.Error(), "supported only for reading") {
+		t.Errorf("got %v\nwant the error of the glob pattern", err)
+	}
+}
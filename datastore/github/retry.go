@@ -0,0 +1,99 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"github.com/google/go-github/v58/github"
+)
+
+// DefaultRetries is the default number of retries of the GitHub API calls on transient errors.
+const DefaultRetries = 3
+
+var (
+	retryMinInterval = time.Second
+	retryMaxInterval = 30 * time.Second
+)
+
+// retry calls fn until it succeeds, retrying up to retries times with exponential backoff while the error is transient.
+func retry(ctx context.Context, retries int, op string, fn func() error) error {
+	interval := retryMinInterval
+	var err error
+	for i := 1; ; i++ {
+		err = fn()
+		if err == nil {
+			return nil
+		}
+		if !isTransient(err) || i > retries {
+			return fmt.Errorf("failed to %s (attempts: %d): %w", op, i, err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to %s (attempts: %d): %w", op, i, errors.Join(err, ctx.Err()))
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, retryMaxInterval)
+	}
+}
+
+// isTransient reports whether the error of the GitHub API is worth retrying (secondary rate limit, 429 and 5xx).
+func isTransient(err error) bool {
+	var aerr *github.AbuseRateLimitError
+	if errors.As(err, &aerr) {
+		return true
+	}
+	var rerr *github.ErrorResponse
+	if errors.As(err, &rerr) && rerr.Response != nil {
+		return rerr.Response.StatusCode == http.StatusTooManyRequests || rerr.Response.StatusCode >= http.StatusInternalServerError
+	}
+	return false
+}
+
+// retryFS is a fs.FS retrying the reads of the GitHub API on transient errors.
+type retryFS struct {
+	fsys    fs.FS
+	retries int
+}
+
+func (r *retryFS) Open(name string) (fs.File, error) {
+	var f fs.File
+	err := retry(context.Background(), r.retries, fmt.Sprintf("open %s", name), func() error {
+		var err error
+		f, err = r.fsys.Open(name)
+		return err
+	})
+	return f, unwrapNotExist(err)
+}
+
+func (r *retryFS) ReadFile(name string) ([]byte, error) {
+	var b []byte
+	err := retry(context.Background(), r.retries, fmt.Sprintf("read %s", name), func() error {
+		var err error
+		b, err = fs.ReadFile(r.fsys, name)
+		return err
+	})
+	return b, unwrapNotExist(err)
+}
+
+func (r *retryFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	var entries []fs.DirEntry
+	err := retry(context.Background(), r.retries, fmt.Sprintf("read directory %s", name), func() error {
+		var err error
+		entries, err = fs.ReadDir(r.fsys, name)
+		return err
+	})
+	return entries, unwrapNotExist(err)
+}
+
+// unwrapNotExist returns the *fs.PathError of a missing file as it is, because callers of fs.FS may compare it directly.
+func unwrapNotExist(err error) error {
+	var perr *fs.PathError
+	if errors.Is(err, fs.ErrNotExist) && errors.As(err, &perr) {
+		return perr
+	}
+	return err
+}
@@ -0,0 +1,62 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v58/github"
+)
+
+func TestRetry(t *testing.T) {
+	retryMinInterval = time.Millisecond
+	t.Cleanup(func() {
+		retryMinInterval = time.Second
+	})
+	errorResponse := func(status int) error {
+		return &github.ErrorResponse{Response: &http.Response{StatusCode: status}}
+	}
+	tests := []struct {
+		name         string
+		errs         []error
+		retries      int
+		wantAttempts int
+		wantErr      string
+	}{
+		{"success", nil, 3, 1, ""},
+		{"success after 5xx", []error{errorResponse(http.StatusBadGateway), errorResponse(http.StatusServiceUnavailable)}, 3, 3, ""},
+		{"success after secondary rate limit", []error{&github.AbuseRateLimitError{}}, 3, 2, ""},
+		{"give up", []error{errorResponse(500), errorResponse(500), errorResponse(500), errorResponse(500), errorResponse(500)}, 3, 4, "failed to store (attempts: 4)"},
+		{"429", []error{errorResponse(http.StatusTooManyRequests)}, 0, 1, "failed to store (attempts: 1)"},
+		{"404", []error{errorResponse(http.StatusNotFound)}, 3, 1, "failed to store (attempts: 1)"},
+		{"403", []error{errorResponse(http.StatusForbidden)}, 3, 1, "failed to store (attempts: 1)"},
+		{"other error", []error{errors.New("invalid")}, 3, 1, "failed to store (attempts: 1)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := retry(context.Background(), tt.retries, "store", func() error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			})
+			if attempts != tt.wantAttempts {
+				t.Errorf("got %v\nwant %v", attempts, tt.wantAttempts)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("got %v\nwant nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got %v\nwant containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,8 +3,10 @@ package datastore
 import "github.com/k1LoW/octocov/report"
 
 type hint struct {
-	root   string
-	report *report.Report
+	root          string
+	report        *report.Report
+	githubRetries *int
+	gzip          bool
 }
 
 type HintFunc func(*hint) error
@@ -24,3 +26,19 @@ func Report(r *report.Report) HintFunc {
 		return nil
 	}
 }
+
+// GitHubRetries hint for github datastore.
+func GitHubRetries(n int) HintFunc {
+	return func(h *hint) error {
+		h.githubRetries = &n
+		return nil
+	}
+}
+
+// Gzip hint for github, s3, gs and local datastores to store the report gzip-compressed (report.json.gz).
+func Gzip(gz bool) HintFunc {
+	return func(h *hint) error {
+		h.gzip = gz
+		return nil
+	}
+}
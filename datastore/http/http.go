@@ -0,0 +1,69 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	nethttp "net/http"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/octocov/report"
+)
+
+const maxErrorBodySize = 1024
+
+type HTTP struct {
+	url     string
+	method  string
+	headers map[string]string
+	client  *nethttp.Client
+}
+
+func New(u, method string, headers map[string]string) (*HTTP, error) {
+	if u == "" {
+		return nil, errors.New("http endpoint url is not set")
+	}
+	if method == "" {
+		method = nethttp.MethodPost
+	}
+	return &HTTP{
+		url:     u,
+		method:  strings.ToUpper(method),
+		headers: headers,
+		client:  &nethttp.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// StoreReport sends the report ( report.json ) to the endpoint. A non-2xx response is an error including the response body.
+func (h *HTTP) StoreReport(ctx context.Context, r *report.Report) error {
+	req, err := nethttp.NewRequestWithContext(ctx, h.method, h.url, bytes.NewReader(r.Bytes()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range h.headers {
+		req.Header.Set(k, v)
+	}
+	res, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize)) //nostyle:handlerrors
+		return fmt.Errorf("failed to send report to %s: %s: %s", req.URL.Redacted(), res.Status, strings.TrimSpace(string(b)))
+	}
+	return nil
+}
+
+func (h *HTTP) Put(ctx context.Context, path string, content []byte) error {
+	return errors.New("not implemented")
+}
+
+func (h *HTTP) FS() (fs.FS, error) {
+	return nil, errors.New("not implemented")
+}
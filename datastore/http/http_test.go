@@ -0,0 +1,75 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/octocov/coverage"
+	"github.com/k1LoW/octocov/report"
+)
+
+func TestStoreReport(t *testing.T) {
+	r := &report.Report{
+		Repository: "owner/repo",
+		Coverage:   &coverage.Coverage{Total: 8, Covered: 6},
+	}
+	tests := []struct {
+		method     string
+		status     int
+		wantMethod string
+		wantErr    string
+	}{
+		{"", nethttp.StatusCreated, nethttp.MethodPost, ""},
+		{"put", nethttp.StatusNoContent, nethttp.MethodPut, ""},
+		{"", nethttp.StatusBadRequest, nethttp.MethodPost, "400 Bad Request: invalid report"},
+	}
+	for _, tt := range tests {
+		var (
+			gotMethod, gotAuth, gotContentType string
+			got                                *report.Report
+		)
+		ts := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, req *nethttp.Request) {
+			gotMethod = req.Method
+			gotAuth = req.Header.Get("Authorization")
+			gotContentType = req.Header.Get("Content-Type")
+			got = &report.Report{}
+			_ = json.NewDecoder(req.Body).Decode(got)
+			w.WriteHeader(tt.status)
+			if tt.status >= 300 {
+				_, _ = fmt.Fprintln(w, "invalid report")
+			}
+		}))
+		h, err := New(ts.URL, tt.method, map[string]string{"Authorization": "Bearer token"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = h.StoreReport(context.Background(), r)
+		ts.Close()
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got %v\nwant %v", err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotMethod != tt.wantMethod {
+			t.Errorf("got %v\nwant %v", gotMethod, tt.wantMethod)
+		}
+		if gotAuth != "Bearer token" {
+			t.Errorf("got %v\nwant %v", gotAuth, "Bearer token")
+		}
+		if gotContentType != "application/json" {
+			t.Errorf("got %v\nwant %v", gotContentType, "application/json")
+		}
+		if got.Repository != r.Repository || got.CoveragePercent() != r.CoveragePercent() {
+			t.Errorf("got %v\nwant %v", got, r)
+		}
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/k1LoW/octocov/internal"
 	"github.com/k1LoW/octocov/report"
 )
 
 type Local struct {
 	root string
+	gzip bool
 }
 
 func New(root string) (*Local, error) {
@@ -35,8 +37,13 @@ func (l *Local) Root() string {
 	return l.root
 }
 
+// SetGzip sets whether to store the report gzip-compressed (report.json.gz).
+func (l *Local) SetGzip(gz bool) {
+	l.gzip = gz
+}
+
 func (l *Local) StoreReport(ctx context.Context, r *report.Report) error {
-	path := fmt.Sprintf("%s/report.json", r.Repository)
+	path := internal.ReportPath(r.Repository, l.gzip)
 	return l.Put(ctx, path, r.Bytes())
 }
 
@@ -49,9 +56,13 @@ func (l *Local) Put(ctx context.Context, path string, content []byte) error {
 			return err
 		}
 	}
+	content, err := internal.GzipIfNeeded(path, content)
+	if err != nil {
+		return err
+	}
 	return os.WriteFile(p, content, os.ModePerm)
 }
 
 func (l *Local) FS() (fs.FS, error) {
-	return os.DirFS(l.root), nil
+	return internal.GzipFS(os.DirFS(l.root)), nil
 }
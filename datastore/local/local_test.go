@@ -1,7 +1,9 @@
 package local
 
 import (
+	"bytes"
 	"context"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
@@ -57,3 +59,39 @@ func TestStoreReport(t *testing.T) {
 		t.Errorf("%s does not exist", want)
 	}
 }
+
+func TestStoreReportGzip(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	l, err := New(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.SetGzip(true)
+	r := &report.Report{
+		Repository: "owner/repo",
+	}
+	if err := l.StoreReport(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	p := filepath.Join(l.Root(), "owner", "repo", "report.json.gz")
+	b, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b) < 2 || b[0] != 0x1f || b[1] != 0x8b {
+		t.Errorf("%s is not gzip-compressed", p)
+	}
+
+	fsys, err := l.FS()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := fs.ReadFile(fsys, "owner/repo/report.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := r.Bytes(); !bytes.Equal(got, want) {
+		t.Errorf("got %s\nwant %s", got, want)
+	}
+}
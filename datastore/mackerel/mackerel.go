@@ -68,6 +68,15 @@ func (m *Mackerel) StoreReport(ctx context.Context, r *report.Report) error {
 		}
 		values = append(values, v)
 	}
+	if r.DocCoverage != nil {
+		name := fmt.Sprintf("doc-coverage.%s", repo)
+		v := &mkr.MetricValue{
+			Name:  name,
+			Time:  t,
+			Value: r.DocCoveragePercent(),
+		}
+		values = append(values, v)
+	}
 
 	if err := m.client.PostServiceMetricValues(sn, values); err != nil {
 		return err
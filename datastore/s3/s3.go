@@ -3,13 +3,13 @@ package s3
 import (
 	"bytes"
 	"context"
-	"fmt"
 	"io/fs"
 	"path/filepath"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/jszwec/s3fs/v2"
+	"github.com/k1LoW/octocov/internal"
 	"github.com/k1LoW/octocov/report"
 )
 
@@ -27,6 +27,7 @@ type S3 struct {
 	client Client
 	bucket string
 	prefix string
+	gzip   bool
 }
 
 func New(client Client, bucket, prefix string) (*S3, error) {
@@ -37,14 +38,23 @@ func New(client Client, bucket, prefix string) (*S3, error) {
 	}, nil
 }
 
+// SetGzip sets whether to store the report gzip-compressed (report.json.gz).
+func (s *S3) SetGzip(gz bool) {
+	s.gzip = gz
+}
+
 func (s *S3) StoreReport(ctx context.Context, r *report.Report) error {
-	path := fmt.Sprintf("%s/report.json", r.Repository)
+	path := internal.ReportPath(r.Repository, s.gzip)
 	return s.Put(ctx, path, r.Bytes())
 }
 
 func (s *S3) Put(ctx context.Context, path string, content []byte) error {
 	key := filepath.Join(s.prefix, path)
-	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+	content, err := internal.GzipIfNeeded(path, content)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
 		Bucket:        &s.bucket,
 		Key:           &key,
 		Body:          bytes.NewReader(content),
@@ -57,5 +67,9 @@ func (s *S3) Put(ctx context.Context, path string, content []byte) error {
 }
 
 func (s *S3) FS() (fs.FS, error) {
-	return fs.Sub(s3fs.New(s.client, s.bucket), s.prefix)
+	fsys, err := fs.Sub(s3fs.New(s.client, s.bucket), s.prefix)
+	if err != nil {
+		return nil, err
+	}
+	return internal.GzipFS(fsys), nil
 }
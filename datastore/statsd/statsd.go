@@ -0,0 +1,78 @@
+package statsd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/octocov/report"
+)
+
+const prefix = "octocov"
+
+type Statsd struct {
+	addr string
+}
+
+func New(addr string) (*Statsd, error) {
+	if addr == "" {
+		return nil, errors.New("statsd address is not set")
+	}
+	return &Statsd{
+		addr: addr,
+	}, nil
+}
+
+// StoreReport sends the code metrics of the report to StatsD as gauges tagged with the repository (DogStatsD format).
+func (s *Statsd) StoreReport(ctx context.Context, r *report.Report) error {
+	lines := Gauges(r)
+	if len(lines) == 0 {
+		return nil
+	}
+	d := net.Dialer{Timeout: 5 * time.Second}
+	conn, err := d.DialContext(ctx, "udp", s.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	for _, l := range lines {
+		if _, err := conn.Write([]byte(l)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Statsd) Put(ctx context.Context, path string, content []byte) error {
+	return errors.New("not implemented")
+}
+
+func (s *Statsd) FS() (fs.FS, error) {
+	return nil, errors.New("not implemented")
+}
+
+// Gauges returns the gauge lines of the measured code metrics of the report.
+func Gauges(r *report.Report) []string {
+	tag := fmt.Sprintf("repository:%s", strings.ReplaceAll(r.Repository, ",", "_"))
+	var lines []string
+	gauge := func(name string, v float64) {
+		lines = append(lines, fmt.Sprintf("%s.%s:%g|g|#%s", prefix, name, v, tag))
+	}
+	if r.IsMeasuredCoverage() {
+		gauge("coverage", r.CoveragePercent())
+	}
+	if r.IsMeasuredCodeToTestRatio() {
+		gauge("code_to_test_ratio", r.CodeToTestRatioRatio())
+	}
+	if r.IsMeasuredTestExecutionTime() {
+		gauge("test_execution_time", r.TestExecutionTimeNano()/float64(time.Second)) // seconds
+	}
+	if r.IsMeasuredDocCoverage() {
+		gauge("doc_coverage", r.DocCoveragePercent())
+	}
+	return lines
+}
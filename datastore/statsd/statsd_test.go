@@ -0,0 +1,80 @@
+package statsd
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/octocov/coverage"
+	"github.com/k1LoW/octocov/ratio"
+	"github.com/k1LoW/octocov/report"
+)
+
+func TestGauges(t *testing.T) {
+	tests := []struct {
+		r    *report.Report
+		want []string
+	}{
+		{
+			&report.Report{Repository: "owner/repo"},
+			nil,
+		},
+		{
+			&report.Report{
+				Repository: "owner/repo",
+				Coverage:   &coverage.Coverage{Total: 8, Covered: 6},
+			},
+			[]string{"octocov.coverage:75|g|#repository:owner/repo"},
+		},
+		{
+			&report.Report{
+				Repository:      "owner/repo",
+				Coverage:        &coverage.Coverage{Total: 8, Covered: 6},
+				CodeToTestRatio: &ratio.Ratio{Code: 100, Test: 150},
+			},
+			[]string{
+				"octocov.coverage:75|g|#repository:owner/repo",
+				"octocov.code_to_test_ratio:1.5|g|#repository:owner/repo",
+			},
+		},
+	}
+	for _, tt := range tests {
+		got := Gauges(tt.r)
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestStoreReport(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	s, err := New(conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &report.Report{
+		Repository: "owner/repo",
+		Coverage:   &coverage.Coverage{Total: 8, Covered: 6},
+	}
+	if err := s.StoreReport(context.Background(), r); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "octocov.coverage:75|g|#repository:owner/repo"
+	if got := string(buf[:n]); got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
@@ -0,0 +1,224 @@
+package doccov
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+type File struct {
+	Path    string `json:"path"`
+	Total   int    `json:"total"`
+	Covered int    `json:"covered"`
+}
+
+type Files []*File
+
+type DocCoverage struct {
+	Total   int   `json:"total"`
+	Covered int   `json:"covered"`
+	Files   Files `json:"files"`
+}
+
+type DiffDocCoverage struct {
+	A            float64      `json:"a"`
+	B            float64      `json:"b"`
+	Diff         float64      `json:"diff"`
+	DocCoverageA *DocCoverage `json:"-"`
+	DocCoverageB *DocCoverage `json:"-"`
+}
+
+func New() *DocCoverage {
+	return &DocCoverage{
+		Files: Files{},
+	}
+}
+
+func (d *DocCoverage) Percent() float64 {
+	if d == nil || d.Total == 0 {
+		return 0.0
+	}
+	return float64(d.Covered) / float64(d.Total) * 100
+}
+
+func (d *DocCoverage) Compare(d2 *DocCoverage) *DiffDocCoverage {
+	dd := &DiffDocCoverage{
+		DocCoverageA: d,
+		DocCoverageB: d2,
+	}
+	dd.A = d.Percent()
+	dd.B = d2.Percent()
+	dd.Diff = dd.A - dd.B
+	return dd
+}
+
+func (d *DocCoverage) DeleteFiles() {
+	d.Files = Files{}
+}
+
+// Measure counts exported Go identifiers and how many of them have doc comments.
+func Measure(root string, include, exclude []string) (*DocCoverage, error) {
+	log.Printf("root: %s", root)
+	d := New()
+	fset := token.NewFileSet()
+	if err := filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			if ignore(path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		ok, err := match(filepath.ToSlash(rel), include, exclude)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			if _, err := fmt.Fprintf(os.Stderr, "could not parse: %s\n", path); err != nil {
+				return err
+			}
+			return nil
+		}
+		total, covered := countFile(f)
+		if total == 0 {
+			return nil
+		}
+		log.Printf("doc: %s,%d/%d", rel, covered, total)
+		d.Total += total
+		d.Covered += covered
+		d.Files = append(d.Files, &File{
+			Path:    rel,
+			Total:   total,
+			Covered: covered,
+		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if d.Total == 0 {
+		return nil, fmt.Errorf("could not find exported identifiers: %s", root)
+	}
+	return d, nil
+}
+
+func match(rel string, include, exclude []string) (bool, error) {
+	matched := len(include) == 0
+	for _, p := range include {
+		ok, err := doublestar.Match(p, rel)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return false, nil
+	}
+	for _, p := range exclude {
+		ok, err := doublestar.Match(p, rel)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func countFile(f *ast.File) (int, int) {
+	var total, covered int
+	count := func(documented bool) {
+		total += 1
+		if documented {
+			covered += 1
+		}
+	}
+	for _, decl := range f.Decls {
+		switch v := decl.(type) {
+		case *ast.FuncDecl:
+			if !v.Name.IsExported() || !exportedRecv(v) {
+				continue
+			}
+			count(v.Doc != nil)
+		case *ast.GenDecl:
+			if v.Tok == token.IMPORT {
+				continue
+			}
+			// Doc comments on the grouped declaration apply to all of its specs.
+			for _, s := range v.Specs {
+				switch vv := s.(type) {
+				case *ast.TypeSpec:
+					if vv.Name.IsExported() {
+						count(vv.Doc != nil || v.Doc != nil)
+					}
+				case *ast.ValueSpec:
+					for _, n := range vv.Names {
+						if n.IsExported() {
+							count(vv.Doc != nil || v.Doc != nil)
+						}
+					}
+				}
+			}
+		}
+	}
+	return total, covered
+}
+
+func exportedRecv(fn *ast.FuncDecl) bool {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return true
+	}
+	t := fn.Recv.List[0].Type
+	for {
+		switch v := t.(type) {
+		case *ast.StarExpr:
+			t = v.X
+		case *ast.IndexExpr:
+			t = v.X
+		case *ast.IndexListExpr:
+			t = v.X
+		case *ast.Ident:
+			return v.IsExported()
+		default:
+			return false
+		}
+	}
+}
+
+var ignores = []string{
+	".bzr", ".cvs", ".hg", ".git", ".svn",
+	"vendor", "testdata",
+}
+
+func ignore(path string) bool {
+	b := filepath.Base(path)
+	for _, v := range ignores {
+		if b == v {
+			return true
+		}
+	}
+	return false
+}
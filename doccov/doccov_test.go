@@ -0,0 +1,126 @@
+package doccov
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestCompare(t *testing.T) {
+	a := &DocCoverage{
+		Total:   10,
+		Covered: 5,
+	}
+	tests := []struct {
+		b    *DocCoverage
+		want *DiffDocCoverage
+	}{
+		{
+			&DocCoverage{
+				Total:   10,
+				Covered: 5,
+			},
+			&DiffDocCoverage{
+				A:    50.0,
+				B:    50.0,
+				Diff: 0.0,
+			},
+		},
+		{
+			nil,
+			&DiffDocCoverage{
+				A:    50.0,
+				B:    0.0,
+				Diff: 50.0,
+			},
+		},
+		{
+			&DocCoverage{
+				Total:   10,
+				Covered: 8,
+			},
+			&DiffDocCoverage{
+				A:    50.0,
+				B:    80.0,
+				Diff: -30.0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		got := a.Compare(tt.b)
+
+		opts := []cmp.Option{
+			cmpopts.IgnoreFields(DiffDocCoverage{}, "DocCoverageA", "DocCoverageB"),
+		}
+
+		if diff := cmp.Diff(got, tt.want, opts...); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	tests := []struct {
+		include     []string
+		exclude     []string
+		wantTotal   int
+		wantCovered int
+		wantErr     bool
+	}{
+		{nil, nil, 9, 5, false},
+		{[]string{"sub/**"}, nil, 2, 1, false},
+		{nil, []string{"sub/**"}, 7, 4, false},
+		{[]string{"**/*.ts"}, nil, 0, 0, true},
+	}
+	root := filepath.Join(testdataDir(t), "doccov")
+	for _, tt := range tests {
+		got, err := Measure(root, tt.include, tt.exclude)
+		if err != nil {
+			if !tt.wantErr {
+				t.Error(err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("got %v\nwant err", got)
+			continue
+		}
+		if got.Total != tt.wantTotal {
+			t.Errorf("got %v\nwant %v", got.Total, tt.wantTotal)
+		}
+		if got.Covered != tt.wantCovered {
+			t.Errorf("got %v\nwant %v", got.Covered, tt.wantCovered)
+		}
+	}
+}
+
+func TestDeleteFiles(t *testing.T) {
+	root := filepath.Join(testdataDir(t), "doccov")
+	got, err := Measure(root, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Files) == 0 {
+		t.Errorf("got %v\nwant >0", len(got.Files))
+	}
+	got.DeleteFiles()
+	if len(got.Files) > 0 {
+		t.Errorf("got %v\nwant 0", len(got.Files))
+	}
+}
+
+func testdataDir(t *testing.T) string {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := filepath.Abs(filepath.Join(filepath.Dir(wd), "testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
@@ -4,6 +4,7 @@ import (
 	"archive/zip"
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -18,6 +19,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing/object"
@@ -25,6 +27,7 @@ import (
 	"github.com/google/go-github/v58/github"
 	"github.com/k1LoW/go-github-actions/artifact"
 	"github.com/k1LoW/go-github-client/v58/factory"
+	"github.com/k1LoW/octocov/internal"
 	"github.com/k1LoW/repin"
 	"github.com/lestrrat-go/backoff/v2"
 	"github.com/shurcooL/githubv4"
@@ -34,6 +37,15 @@ import (
 const DefaultGithubServerURL = "https://github.com"
 const maxCopySize = 1073741824 //1GB
 
+// States of the commit status.
+const (
+	CommitStatusSuccess = "success"
+	CommitStatusFailure = "failure"
+)
+
+// maxCommitStatusDescription is the maximum length of the description of the commit status.
+const maxCommitStatusDescription = 140
+
 var octocovNameRe = regexp.MustCompile(`(?i)(octocov|coverage)`)
 
 type Gh struct {
@@ -84,6 +96,11 @@ func (g *Gh) PushContent(ctx context.Context, owner, repo, branch, content, cp,
 			Encoding: github.String("utf-8"),
 			Size:     github.Int(len(content)),
 		}
+		if !utf8.ValidString(content) {
+			// binary content (e.g. report.json.gz)
+			blob.Content = github.String(base64.StdEncoding.EncodeToString([]byte(content)))
+			blob.Encoding = github.String("base64")
+		}
 
 		resB, _, err := srv.CreateBlob(ctx, owner, repo, blob)
 		if err != nil {
@@ -231,6 +248,18 @@ func (g *Gh) DetectCurrentBranch(ctx context.Context) (string, error) {
 	return os.Getenv("GITHUB_HEAD_REF"), nil
 }
 
+// DetectCurrentTag detects the tag of the current tag push event.
+func (g *Gh) DetectCurrentTag(ctx context.Context) (string, error) {
+	ref := os.Getenv("GITHUB_REF")
+	if ref == "" {
+		return "", fmt.Errorf("env %s is not set", "GITHUB_REF")
+	}
+	if !strings.HasPrefix(ref, "refs/tags/") {
+		return "", fmt.Errorf("env %s is not a tag: %s", "GITHUB_REF", ref)
+	}
+	return strings.TrimPrefix(ref, "refs/tags/"), nil
+}
+
 func (g *Gh) DetectCurrentPullRequestNumber(ctx context.Context, owner, repo string) (int, error) {
 	if os.Getenv("GITHUB_PULL_REQUEST_NUMBER") != "" {
 		return strconv.Atoi(os.Getenv("GITHUB_PULL_REQUEST_NUMBER"))
@@ -266,7 +295,7 @@ func (g *Gh) DetectCurrentPullRequestNumber(ctx context.Context, owner, repo str
 }
 
 func (g *Gh) ReplaceInsertToBody(ctx context.Context, owner, repo string, number int, content, key string) error {
-	sig := generateSig(key)
+	sig := internal.CommentSig(key)
 	pr, _, err := g.client.PullRequests.Get(ctx, owner, repo, number)
 	if err != nil {
 		return err
@@ -294,9 +323,13 @@ func (g *Gh) ReplaceInsertToBody(ctx context.Context, owner, repo string, number
 }
 
 type PullRequest struct {
-	Number  int
-	IsDraft bool
-	Labels  []string
+	Number    int
+	IsDraft   bool
+	Labels    []string
+	Additions int
+	Deletions int
+	BaseRef   string
+	HeadSHA   string
 }
 
 func (g *Gh) FetchPullRequest(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
@@ -309,12 +342,51 @@ func (g *Gh) FetchPullRequest(ctx context.Context, owner, repo string, number in
 		labels = append(labels, l.GetName())
 	}
 	return &PullRequest{
-		Number:  pr.GetNumber(),
-		IsDraft: pr.GetDraft(),
-		Labels:  labels,
+		Number:    pr.GetNumber(),
+		IsDraft:   pr.GetDraft(),
+		Labels:    labels,
+		Additions: pr.GetAdditions(),
+		Deletions: pr.GetDeletions(),
+		BaseRef:   pr.GetBase().GetRef(),
+		HeadSHA:   pr.GetHead().GetSHA(),
+	}, nil
+}
+
+// Issue is an issue of the repository.
+type Issue struct {
+	Number        int
+	Title         string
+	State         string
+	IsPullRequest bool
+}
+
+// FetchIssue fetches the issue of the number.
+func (g *Gh) FetchIssue(ctx context.Context, owner, repo string, number int) (*Issue, error) {
+	i, _, err := g.client.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	return &Issue{
+		Number:        i.GetNumber(),
+		Title:         i.GetTitle(),
+		State:         i.GetState(),
+		IsPullRequest: i.IsPullRequest(),
 	}, nil
 }
 
+// FetchMergeBase fetches the SHA of the merge-base commit of base and head.
+func (g *Gh) FetchMergeBase(ctx context.Context, owner, repo, base, head string) (string, error) {
+	comp, _, err := g.client.Repositories.CompareCommits(ctx, owner, repo, base, head, nil)
+	if err != nil {
+		return "", err
+	}
+	sha := comp.GetMergeBaseCommit().GetSHA()
+	if sha == "" {
+		return "", fmt.Errorf("could not detect the merge-base of %s and %s", base, head)
+	}
+	return sha, nil
+}
+
 type PullRequestFile struct {
 	Filename string
 	BlobURL  string
@@ -345,6 +417,15 @@ func (g *Gh) FetchPullRequestFiles(ctx context.Context, owner, repo string, numb
 	return files, nil
 }
 
+// FetchPullRequestDiff returns the unified diff of the pull request.
+func (g *Gh) FetchPullRequestDiff(ctx context.Context, owner, repo string, number int) (string, error) {
+	diff, _, err := g.client.PullRequests.GetRaw(ctx, owner, repo, number, github.RawOptions{Type: github.Diff})
+	if err != nil {
+		return "", err
+	}
+	return diff, nil
+}
+
 func (g *Gh) FetchChangedFiles(ctx context.Context, owner, repo string) ([]*PullRequestFile, error) {
 	base, err := g.FetchDefaultBranch(ctx, owner, repo)
 	if err != nil {
@@ -494,7 +575,7 @@ L:
 }
 
 func (g *Gh) PutComment(ctx context.Context, owner, repo string, n int, comment, key string) error {
-	sig := generateSig(key)
+	sig := internal.CommentSig(key)
 	if err := g.minimizePreviousComments(ctx, owner, repo, n, sig); err != nil {
 		return err
 	}
@@ -506,7 +587,7 @@ func (g *Gh) PutComment(ctx context.Context, owner, repo string, n int, comment,
 }
 
 func (g *Gh) PutCommentWithDeletion(ctx context.Context, owner, repo string, n int, comment, key string) error {
-	sig := generateSig(key)
+	sig := internal.CommentSig(key)
 	if err := g.deletePreviousComments(ctx, owner, repo, n, sig); err != nil {
 		return err
 	}
@@ -517,6 +598,26 @@ func (g *Gh) PutCommentWithDeletion(ctx context.Context, owner, repo string, n i
 	return nil
 }
 
+// PutCommentWithUpdate edits the latest previous comment with the signature of the key in place, or creates a new comment if there is none.
+func (g *Gh) PutCommentWithUpdate(ctx context.Context, owner, repo string, n int, comment, key string) error {
+	sig := internal.CommentSig(key)
+	c := strings.Join([]string{comment, sig}, "\n")
+	prev, err := g.findLatestComment(ctx, owner, repo, n, sig)
+	if err != nil {
+		return err
+	}
+	if prev == nil {
+		if _, _, err := g.client.Issues.CreateComment(ctx, owner, repo, n, &github.IssueComment{Body: &c}); err != nil {
+			return err
+		}
+		return nil
+	}
+	if _, _, err := g.client.Issues.EditComment(ctx, owner, repo, prev.GetID(), &github.IssueComment{Body: &c}); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (g *Gh) PutArtifact(ctx context.Context, name, fp string, content []byte) error {
 	return artifact.Upload(ctx, name, fp, bytes.NewReader(content))
 }
@@ -528,7 +629,28 @@ type ArtifactFile struct {
 }
 
 func (g *Gh) FetchLatestArtifact(ctx context.Context, owner, repo, name, fp string) (*ArtifactFile, error) {
-	const maxRedirect = 5
+	return g.fetchArtifact(ctx, owner, repo, name, fp, func(a *github.Artifact) bool {
+		return true
+	})
+}
+
+// FetchArtifactOfRef fetches the latest artifact uploaded by the workflow run for the ref (commit SHA, branch or tag).
+func (g *Gh) FetchArtifactOfRef(ctx context.Context, owner, repo, name, fp, ref string) (*ArtifactFile, error) {
+	branch := strings.TrimPrefix(strings.TrimPrefix(ref, "refs/heads/"), "refs/tags/")
+	return g.fetchArtifact(ctx, owner, repo, name, fp, func(a *github.Artifact) bool {
+		if ref == "" {
+			return false
+		}
+		return strings.HasPrefix(a.GetWorkflowRun().GetHeadSHA(), ref) || a.GetWorkflowRun().GetHeadBranch() == branch
+	})
+}
+
+// FetchRecentArtifacts fetches up to n latest artifacts.
+func (g *Gh) FetchRecentArtifacts(ctx context.Context, owner, repo, name, fp string, n int) ([]*ArtifactFile, error) {
+	var afs []*ArtifactFile
+	if n <= 0 {
+		return afs, nil
+	}
 	page := 1
 	for {
 		l, res, err := g.client.Actions.ListArtifacts(ctx, owner, repo, &github.ListOptions{
@@ -543,52 +665,46 @@ func (g *Gh) FetchLatestArtifact(ctx context.Context, owner, repo, name, fp stri
 			if a.GetName() != name {
 				continue
 			}
-			u, _, err := g.client.Actions.DownloadArtifact(ctx, owner, repo, a.GetID(), maxRedirect)
+			af, err := g.downloadArtifactFile(ctx, owner, repo, a, fp)
 			if err != nil {
 				return nil, err
 			}
-			resp, err := http.Get(u.String())
-			if err != nil {
-				return nil, err
+			if af == nil {
+				continue
 			}
-			buf := new(bytes.Buffer)
-			size, err := io.CopyN(buf, resp.Body, maxCopySize)
-			if !errors.Is(err, io.EOF) {
-				return nil, err
+			afs = append(afs, af)
+			if len(afs) >= n {
+				return afs, nil
 			}
-			if size >= maxCopySize {
-				return nil, fmt.Errorf("too large file size to copy: %d >= %d", size, maxCopySize)
+		}
+		if res.NextPage == 0 {
+			break
+		}
+	}
+	return afs, nil
+}
+
+func (g *Gh) fetchArtifact(ctx context.Context, owner, repo, name, fp string, match func(a *github.Artifact) bool) (*ArtifactFile, error) {
+	page := 1
+	for {
+		l, res, err := g.client.Actions.ListArtifacts(ctx, owner, repo, &github.ListOptions{
+			Page:    page,
+			PerPage: 100,
+		})
+		if err != nil {
+			return nil, err
+		}
+		page += 1
+		for _, a := range l.Artifacts {
+			if a.GetName() != name || !match(a) {
+				continue
 			}
-			reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			af, err := g.downloadArtifactFile(ctx, owner, repo, a, fp)
 			if err != nil {
 				return nil, err
 			}
-			for _, file := range reader.File {
-				if file.Name != fp {
-					continue
-				}
-				in, err := file.Open()
-				if err != nil {
-					return nil, err
-				}
-				out := new(bytes.Buffer)
-				size, err := io.CopyN(out, in, maxCopySize)
-				if !errors.Is(err, io.EOF) {
-					_ = in.Close() //nostyle:handlerrors
-					return nil, err
-				}
-				if size >= maxCopySize {
-					_ = in.Close() //nostyle:handlerrors
-					return nil, fmt.Errorf("too large file size to copy: %d >= %d", size, maxCopySize)
-				}
-				if err := in.Close(); err != nil {
-					return nil, err
-				}
-				return &ArtifactFile{
-					Name:      file.Name,
-					Content:   out.Bytes(),
-					CreatedAt: a.CreatedAt.Time,
-				}, nil
+			if af != nil {
+				return af, nil
 			}
 		}
 		if res.NextPage == 0 {
@@ -598,6 +714,124 @@ func (g *Gh) FetchLatestArtifact(ctx context.Context, owner, repo, name, fp stri
 	return nil, errors.New("artifact not found")
 }
 
+// downloadArtifactFile downloads the artifact and returns the file fp in it. It returns nil if the file is not found.
+func (g *Gh) downloadArtifactFile(ctx context.Context, owner, repo string, a *github.Artifact, fp string) (*ArtifactFile, error) {
+	const maxRedirect = 5
+	u, _, err := g.client.Actions.DownloadArtifact(ctx, owner, repo, a.GetID(), maxRedirect)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	size, err := io.CopyN(buf, resp.Body, maxCopySize)
+	if !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if size >= maxCopySize {
+		return nil, fmt.Errorf("too large file size to copy: %d >= %d", size, maxCopySize)
+	}
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range reader.File {
+		if file.Name != fp {
+			continue
+		}
+		in, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		out := new(bytes.Buffer)
+		size, err := io.CopyN(out, in, maxCopySize)
+		if !errors.Is(err, io.EOF) {
+			_ = in.Close() //nostyle:handlerrors
+			return nil, err
+		}
+		if size >= maxCopySize {
+			_ = in.Close() //nostyle:handlerrors
+			return nil, fmt.Errorf("too large file size to copy: %d >= %d", size, maxCopySize)
+		}
+		if err := in.Close(); err != nil {
+			return nil, err
+		}
+		return &ArtifactFile{
+			Name:      file.Name,
+			Content:   out.Bytes(),
+			CreatedAt: a.CreatedAt.Time,
+		}, nil
+	}
+	return nil, nil
+}
+
+// PutReleaseAsset uploads the content as the asset of the release of the tag. An asset with the same name is replaced.
+func (g *Gh) PutReleaseAsset(ctx context.Context, owner, repo, tag, name string, content []byte) error {
+	rel, _, err := g.client.Repositories.GetReleaseByTag(ctx, owner, repo, tag)
+	if err != nil {
+		return err
+	}
+	page := 1
+	for {
+		l, res, err := g.client.Repositories.ListReleaseAssets(ctx, owner, repo, rel.GetID(), &github.ListOptions{
+			Page:    page,
+			PerPage: 100,
+		})
+		if err != nil {
+			return err
+		}
+		page += 1
+		for _, a := range l {
+			if a.GetName() != name {
+				continue
+			}
+			if _, err := g.client.Repositories.DeleteReleaseAsset(ctx, owner, repo, a.GetID()); err != nil {
+				return err
+			}
+		}
+		if res.NextPage == 0 {
+			break
+		}
+	}
+	f, err := os.CreateTemp("", "octocov-asset-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	if _, err := f.Write(content); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, _, err := g.client.Repositories.UploadReleaseAsset(ctx, owner, repo, rel.GetID(), &github.UploadOptions{Name: name}, f); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateCommitStatus creates the commit status of the commit (sha) with the context (e.g. octocov/coverage).
+func (g *Gh) CreateCommitStatus(ctx context.Context, owner, repo, sha, state, statusContext, description, targetURL string) error {
+	if len(description) > maxCommitStatusDescription {
+		description = description[:maxCommitStatusDescription-3] + "..."
+	}
+	status := &github.RepoStatus{
+		State:       github.String(state),
+		Context:     github.String(statusContext),
+		Description: github.String(description),
+	}
+	if targetURL != "" {
+		status.TargetURL = github.String(targetURL)
+	}
+	if _, _, err := g.client.Repositories.CreateStatus(ctx, owner, repo, sha, status); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (g *Gh) IsPrivate(ctx context.Context, owner, repo string) (bool, error) {
 	r, _, err := g.client.Repositories.Get(ctx, owner, repo)
 	if err != nil {
@@ -648,6 +882,33 @@ func (g *Gh) minimizePreviousComments(ctx context.Context, owner, repo string, n
 	return nil
 }
 
+func (g *Gh) findLatestComment(ctx context.Context, owner, repo string, n int, sig string) (*github.IssueComment, error) {
+	var latest *github.IssueComment
+	page := 1
+	for {
+		opts := &github.IssueListCommentsOptions{
+			ListOptions: github.ListOptions{
+				Page:    page,
+				PerPage: 100,
+			},
+		}
+		comments, res, err := g.client.Issues.ListComments(ctx, owner, repo, n, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range comments {
+			if strings.Contains(c.GetBody(), sig) {
+				latest = c
+			}
+		}
+		if res.NextPage == 0 {
+			break
+		}
+		page = res.NextPage
+	}
+	return latest, nil
+}
+
 func (g *Gh) deletePreviousComments(ctx context.Context, owner, repo string, n int, sig string) error {
 	page := 1
 	for {
@@ -742,9 +1003,11 @@ func PushUsingLocalGit(ctx context.Context, gitRoot string, addPaths []string, m
 }
 
 type GitHubEvent struct {
-	Name    string
-	Number  int
-	State   string
+	Name   string
+	Number int
+	State  string
+	// BaseRef is the base branch of the pull request (pull_request.base.ref). It is empty if the event is not of a pull request.
+	BaseRef string
 	Payload any
 }
 
@@ -767,6 +1030,9 @@ func DecodeGitHubEvent() (*GitHubEvent, error) {
 		PullRequest struct {
 			Number int    `json:"number,omitempty"`
 			State  string `json:"state,omitempty"`
+			Base   struct {
+				Ref string `json:"ref,omitempty"`
+			} `json:"base,omitempty"`
 		} `json:"pull_request,omitempty"`
 		Issue struct {
 			Number int    `json:"number,omitempty"`
@@ -780,6 +1046,7 @@ func DecodeGitHubEvent() (*GitHubEvent, error) {
 	case s.PullRequest.Number > 0:
 		i.Number = s.PullRequest.Number
 		i.State = s.PullRequest.State
+		i.BaseRef = s.PullRequest.Base.Ref
 	case s.Issue.Number > 0:
 		i.Number = s.Issue.Number
 		i.State = s.Issue.State
@@ -842,10 +1109,3 @@ func trimContentURL(u, p string) (string, error) {
 	}
 	return strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(parsed.String(), fmt.Sprintf("?%s", parsed.RawQuery)), p), "/"), nil
 }
-
-func generateSig(key string) string {
-	if key == "" {
-		return "<!-- octocov -->"
-	}
-	return fmt.Sprintf("<!-- octocov:%s -->", key)
-}
@@ -2,12 +2,18 @@ package gh
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-github/v58/github"
 	"github.com/k1LoW/go-github-client/v58/factory"
+	"github.com/k1LoW/octocov/internal"
 	"github.com/migueleliasweb/go-github-mock/src/mock"
 )
 
@@ -119,6 +125,39 @@ func TestDetectCurrentBranch(t *testing.T) {
 	}
 }
 
+func TestDetectCurrentTag(t *testing.T) {
+	tests := []struct {
+		GITHUB_REF string
+		want       string
+		wantErr    bool
+	}{
+		{"", "", true},
+		{"refs/heads/main", "", true},
+		{"refs/pull/8/head", "", true},
+		{"refs/tags/v1.0.0", "v1.0.0", false},
+	}
+	ctx := context.TODO()
+	mg := mockedGh(t)
+	for _, tt := range tests {
+		t.Run(tt.GITHUB_REF, func(t *testing.T) {
+			t.Setenv("GITHUB_REF", tt.GITHUB_REF)
+			got, err := mg.DetectCurrentTag(ctx)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("got err: %v", err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Error("want err")
+			}
+			if got != tt.want {
+				t.Errorf("got %v\nwant %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestDetectCurrentPullRequestNumber(t *testing.T) {
 	tests := []struct {
 		GITHUB_PULL_REQUEST_NUMBER string
@@ -155,21 +194,150 @@ func TestDetectCurrentPullRequestNumber(t *testing.T) {
 	}
 }
 
-func TestGenerateSig(t *testing.T) {
+func TestFetchMergeBase(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "dummy")
+	mockedHTTPClient := mock.NewMockedHTTPClient( //nostyle:funcfmt
+		mock.WithRequestMatch( //nostyle:funcfmt
+			mock.GetReposCompareByOwnerByRepoByBasehead,
+			github.CommitsComparison{
+				MergeBaseCommit: &github.RepositoryCommit{
+					SHA: github.String("a1b2c3d"),
+				},
+			},
+		),
+	)
+	client, err := factory.NewGithubClient(factory.HTTPClient(mockedHTTPClient), factory.Timeout(10*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetClient(client)
+	got, err := g.FetchMergeBase(context.TODO(), "owner", "repo", "main", "e4f5a6b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "a1b2c3d"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestCreateCommitStatus(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "dummy")
+	var got *github.RepoStatus
+	mockedHTTPClient := mock.NewMockedHTTPClient( //nostyle:funcfmt
+		mock.WithRequestMatchHandler( //nostyle:funcfmt
+			mock.PostReposStatusesByOwnerByRepoBySha,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = &github.RepoStatus{}
+				if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+					t.Error(err)
+				}
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write(mock.MustMarshal(got))
+			}),
+		),
+	)
+	client, err := factory.NewGithubClient(factory.HTTPClient(mockedHTTPClient), factory.Timeout(10*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetClient(client)
+	description := strings.Repeat("x", 200)
+	if err := g.CreateCommitStatus(context.TODO(), "owner", "repo", "a1b2c3d", CommitStatusFailure, "octocov/coverage", description, ""); err != nil {
+		t.Fatal(err)
+	}
+	want := &github.RepoStatus{
+		State:       github.String(CommitStatusFailure),
+		Context:     github.String("octocov/coverage"),
+		Description: github.String(strings.Repeat("x", 137) + "..."),
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestDecodeGitHubEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		payload     string
+		wantNumber  int
+		wantBaseRef string
+	}{
+		{"pull_request", `{"pull_request": {"number": 3, "state": "open", "base": {"ref": "develop"}}}`, 3, "develop"},
+		{"issue_comment", `{"issue": {"number": 4, "state": "open"}}`, 4, ""},
+		{"push", `{"ref": "refs/heads/main"}`, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "event.json")
+			if err := os.WriteFile(p, []byte(tt.payload), 0600); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("GITHUB_EVENT_NAME", tt.name)
+			t.Setenv("GITHUB_EVENT_PATH", p)
+			got, err := DecodeGitHubEvent()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Number != tt.wantNumber {
+				t.Errorf("got %v\nwant %v", got.Number, tt.wantNumber)
+			}
+			if got.BaseRef != tt.wantBaseRef {
+				t.Errorf("got %v\nwant %v", got.BaseRef, tt.wantBaseRef)
+			}
+		})
+	}
+}
+
+func TestFindLatestComment(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "dummy")
+	comments := []*github.IssueComment{
+		{ID: github.Int64(1), Body: github.String("report\n<!-- octocov -->")},
+		{ID: github.Int64(2), Body: github.String("report\n<!-- octocov:linux -->")},
+		{ID: github.Int64(3), Body: github.String("LGTM")},
+		{ID: github.Int64(4), Body: github.String("report\n<!-- octocov -->")},
+	}
+	mockedHTTPClient := mock.NewMockedHTTPClient( //nostyle:funcfmt
+		mock.WithRequestMatch( //nostyle:funcfmt
+			mock.GetReposIssuesCommentsByOwnerByRepoByIssueNumber,
+			comments, comments, comments,
+		),
+	)
+	client, err := factory.NewGithubClient(factory.HTTPClient(mockedHTTPClient), factory.Timeout(10*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.SetClient(client)
 	tests := []struct {
 		key  string
-		want string
+		want int64
 	}{
-		{"", "<!-- octocov -->"},
-		{"foo", "<!-- octocov:foo -->"},
+		{"", 4},
+		{"linux", 2},
+		{"windows", 0},
 	}
 	for _, tt := range tests {
-		got := generateSig(tt.key)
-		if got != tt.want {
-			t.Errorf("got %v\nwant %v", got, tt.want)
+		got, err := g.findLatestComment(context.TODO(), "owner", "repo", 1, internal.CommentSig(tt.key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.GetID() != tt.want {
+			t.Errorf("%q: got %v\nwant %v", tt.key, got.GetID(), tt.want)
 		}
 	}
 }
+
 func mockedGh(t *testing.T) *Gh {
 	t.Setenv("GITHUB_TOKEN", "dummy")
 	mockedHTTPClient := mock.NewMockedHTTPClient( //nostyle:funcfmt
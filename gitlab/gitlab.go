@@ -0,0 +1,192 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/octocov/internal"
+)
+
+const DefaultAPIURL = "https://gitlab.com/api/v4"
+
+type GitLab struct {
+	client *http.Client
+	apiURL string
+	token  string
+}
+
+// New returns a GitLab API client using the token of env GITLAB_TOKEN and the endpoint of env CI_API_V4_URL.
+func New() (*GitLab, error) {
+	token := os.Getenv("GITLAB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("env %s is not set", "GITLAB_TOKEN")
+	}
+	apiURL := os.Getenv("CI_API_V4_URL")
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	return &GitLab{
+		client: &http.Client{Timeout: 10 * time.Second},
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		token:  token,
+	}, nil
+}
+
+func (g *GitLab) SetAPIURL(apiURL string) {
+	g.apiURL = strings.TrimSuffix(apiURL, "/")
+}
+
+type GitLabCIEnv struct {
+	ProjectID       string
+	ProjectURL      string
+	MergeRequestIID int
+	CommitSHA       string
+}
+
+// DecodeGitLabCIEnv detects the project and the merge request of the current GitLab CI pipeline.
+func DecodeGitLabCIEnv() (*GitLabCIEnv, error) {
+	e := &GitLabCIEnv{
+		ProjectID:  os.Getenv("CI_PROJECT_ID"),
+		ProjectURL: os.Getenv("CI_PROJECT_URL"),
+		CommitSHA:  os.Getenv("CI_COMMIT_SHA"),
+	}
+	iid := os.Getenv("CI_MERGE_REQUEST_IID")
+	if iid == "" {
+		return e, fmt.Errorf("env %s is not set", "CI_MERGE_REQUEST_IID")
+	}
+	n, err := strconv.Atoi(iid)
+	if err != nil {
+		return e, fmt.Errorf("invalid env %s: %w", "CI_MERGE_REQUEST_IID", err)
+	}
+	e.MergeRequestIID = n
+	return e, nil
+}
+
+type note struct {
+	ID   int    `json:"id"`
+	Body string `json:"body"`
+}
+
+// PutNote edits the latest previous note with the signature of the key in place, or creates a new note to the merge request if there is none.
+func (g *GitLab) PutNote(ctx context.Context, project string, iid int, comment, key string) error {
+	sig := internal.CommentSig(key)
+	c := strings.Join([]string{comment, sig}, "\n")
+	prev, err := g.findLatestNote(ctx, project, iid, sig)
+	if err != nil {
+		return err
+	}
+	p := fmt.Sprintf("/projects/%s/merge_requests/%d/notes", url.PathEscape(project), iid)
+	if prev == nil {
+		return g.do(ctx, http.MethodPost, p, map[string]string{"body": c}, nil, nil)
+	}
+	return g.do(ctx, http.MethodPut, fmt.Sprintf("%s/%d", p, prev.ID), map[string]string{"body": c}, nil, nil)
+}
+
+type MergeRequestFile struct {
+	Filename string
+	BlobURL  string
+}
+
+// FetchMergeRequestFiles returns the files changed in the merge request. BlobURL is set if projectURL and sha are not empty.
+func (g *GitLab) FetchMergeRequestFiles(ctx context.Context, project string, iid int, projectURL, sha string) ([]*MergeRequestFile, error) {
+	var files []*MergeRequestFile
+	page := 1
+	for page > 0 {
+		var diffs []struct {
+			NewPath     string `json:"new_path"`
+			DeletedFile bool   `json:"deleted_file"`
+		}
+		p := fmt.Sprintf("/projects/%s/merge_requests/%d/diffs?per_page=100&page=%d", url.PathEscape(project), iid, page)
+		next := 0
+		if err := g.do(ctx, http.MethodGet, p, nil, &diffs, &next); err != nil {
+			return nil, err
+		}
+		for _, d := range diffs {
+			if d.DeletedFile {
+				continue
+			}
+			f := &MergeRequestFile{Filename: d.NewPath}
+			if projectURL != "" && sha != "" {
+				f.BlobURL = fmt.Sprintf("%s/-/blob/%s/%s", strings.TrimSuffix(projectURL, "/"), sha, d.NewPath)
+			}
+			files = append(files, f)
+		}
+		page = next
+	}
+	return files, nil
+}
+
+func (g *GitLab) findLatestNote(ctx context.Context, project string, iid int, sig string) (*note, error) {
+	var latest *note
+	page := 1
+	for page > 0 {
+		var notes []*note
+		p := fmt.Sprintf("/projects/%s/merge_requests/%d/notes?sort=asc&order_by=created_at&per_page=100&page=%d", url.PathEscape(project), iid, page)
+		next := 0
+		if err := g.do(ctx, http.MethodGet, p, nil, &notes, &next); err != nil {
+			return nil, err
+		}
+		for _, n := range notes {
+			if strings.Contains(n.Body, sig) {
+				latest = n
+			}
+		}
+		page = next
+	}
+	return latest, nil
+}
+
+// do sends the request to the GitLab API. If nextPage is not nil, the value of the X-Next-Page header is set to it.
+func (g *GitLab) do(ctx context.Context, method, p string, in, out any, nextPage *int) error {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.apiURL+p, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("PRIVATE-TOKEN", g.token)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("%s %s: %s: %s", method, req.URL.Path, res.Status, strings.TrimSpace(string(b)))
+	}
+	if nextPage != nil {
+		if n := res.Header.Get("X-Next-Page"); n != "" {
+			v, err := strconv.Atoi(n)
+			if err != nil {
+				return fmt.Errorf("invalid X-Next-Page header: %w", err)
+			}
+			*nextPage = v
+		}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
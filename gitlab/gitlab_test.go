@@ -0,0 +1,132 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecodeGitLabCIEnv(t *testing.T) {
+	tests := []struct {
+		iid     string
+		want    int
+		wantErr bool
+	}{
+		{"", 0, true},
+		{"x", 0, true},
+		{"12", 12, false},
+	}
+	for _, tt := range tests {
+		t.Setenv("CI_PROJECT_ID", "123")
+		t.Setenv("CI_MERGE_REQUEST_IID", tt.iid)
+		got, err := DecodeGitLabCIEnv()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwant error %v", err, tt.wantErr)
+			continue
+		}
+		if got.ProjectID != "123" {
+			t.Errorf("got %v\nwant %v", got.ProjectID, "123")
+		}
+		if got.MergeRequestIID != tt.want {
+			t.Errorf("got %v\nwant %v", got.MergeRequestIID, tt.want)
+		}
+	}
+}
+
+func TestPutNote(t *testing.T) {
+	tests := []struct {
+		notes      []*note
+		wantMethod string
+		wantPath   string
+	}{
+		{
+			[]*note{{ID: 1, Body: "LGTM"}},
+			http.MethodPost,
+			"/projects/group/project/merge_requests/2/notes",
+		},
+		{
+			[]*note{{ID: 1, Body: "old\n<!-- octocov:key -->"}, {ID: 3, Body: "LGTM"}, {ID: 4, Body: "old\n<!-- octocov:key -->"}},
+			http.MethodPut,
+			"/projects/group/project/merge_requests/2/notes/4",
+		},
+	}
+	for _, tt := range tests {
+		var gotMethod, gotPath, gotBody string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("PRIVATE-TOKEN") != "token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if !strings.HasPrefix(r.URL.EscapedPath(), "/projects/group%2Fproject/") {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if r.Method == http.MethodGet {
+				_ = json.NewEncoder(w).Encode(tt.notes)
+				return
+			}
+			gotMethod = r.Method
+			gotPath = r.URL.Path
+			b := map[string]string{}
+			_ = json.NewDecoder(r.Body).Decode(&b)
+			gotBody = b["body"]
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprint(w, "{}")
+		}))
+		t.Setenv("GITLAB_TOKEN", "token")
+		t.Setenv("CI_API_V4_URL", ts.URL)
+		g, err := New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := g.PutNote(context.Background(), "group/project", 2, "report", "key"); err != nil {
+			t.Fatal(err)
+		}
+		ts.Close()
+		if gotMethod != tt.wantMethod {
+			t.Errorf("got %v\nwant %v", gotMethod, tt.wantMethod)
+		}
+		if gotPath != tt.wantPath {
+			t.Errorf("got %v\nwant %v", gotPath, tt.wantPath)
+		}
+		if want := "report\n<!-- octocov:key -->"; gotBody != want {
+			t.Errorf("got %v\nwant %v", gotBody, want)
+		}
+	}
+}
+
+func TestFetchMergeRequestFiles(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Header().Set("X-Next-Page", "2")
+			_, _ = fmt.Fprint(w, `[{"new_path":"a.go"},{"new_path":"b.go","deleted_file":true}]`)
+		default:
+			_, _ = fmt.Fprint(w, `[{"new_path":"pkg/c.go"}]`)
+		}
+	}))
+	defer ts.Close()
+	t.Setenv("GITLAB_TOKEN", "token")
+	t.Setenv("CI_API_V4_URL", ts.URL)
+	g, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := g.FetchMergeRequestFiles(context.Background(), "123", 2, "https://gitlab.com/group/project", "abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*MergeRequestFile{
+		{Filename: "a.go", BlobURL: "https://gitlab.com/group/project/-/blob/abcdef/a.go"},
+		{Filename: "pkg/c.go", BlobURL: "https://gitlab.com/group/project/-/blob/abcdef/pkg/c.go"},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,136 @@
+// Package gotest parses the output of `go test -json` (the test2json event stream).
+package gotest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Actions of the events.
+const (
+	ActionPass = "pass"
+	ActionFail = "fail"
+	ActionSkip = "skip"
+)
+
+// Event is an event of `go test -json`.
+type Event struct {
+	Time    time.Time `json:"Time"`
+	Action  string    `json:"Action"`
+	Package string    `json:"Package"`
+	Test    string    `json:"Test"`
+	Elapsed float64   `json:"Elapsed"`
+	Output  string    `json:"Output"`
+}
+
+// Package is the result of the tests of a package.
+type Package struct {
+	Name    string
+	Action  string
+	Elapsed time.Duration
+	Passed  int
+	Failed  int
+	Skipped int
+}
+
+// Result is the result of `go test -json`.
+type Result struct {
+	// Elapsed is the wall-clock time from the first event to the last event.
+	// If the events have no time, it is the sum of the elapsed time of the packages.
+	Elapsed  time.Duration
+	Packages []*Package
+}
+
+// ParseFile parses the output of `go test -json` saved in path.
+func ParseFile(path string) (*Result, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse parses the output of `go test -json`.
+// Lines that are not JSON (e.g. build errors mixed by `2>&1`) are ignored.
+func Parse(r io.Reader) (*Result, error) {
+	var (
+		first, last time.Time
+		n           int
+	)
+	pkgs := map[string]*Package{}
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for s.Scan() {
+		line := bytes.TrimSpace(s.Bytes())
+		if !bytes.HasPrefix(line, []byte("{")) {
+			continue
+		}
+		e := &Event{}
+		if err := json.Unmarshal(line, e); err != nil {
+			return nil, fmt.Errorf("invalid event of go test -json: %w", err)
+		}
+		n++
+		if !e.Time.IsZero() {
+			if first.IsZero() || e.Time.Before(first) {
+				first = e.Time
+			}
+			if e.Time.After(last) {
+				last = e.Time
+			}
+		}
+		if e.Package == "" {
+			continue
+		}
+		p, ok := pkgs[e.Package]
+		if !ok {
+			p = &Package{Name: e.Package}
+			pkgs[e.Package] = p
+		}
+		switch e.Action {
+		case ActionPass, ActionFail, ActionSkip:
+		default:
+			continue
+		}
+		if e.Test == "" {
+			p.Action = e.Action
+			p.Elapsed = time.Duration(e.Elapsed * float64(time.Second))
+			continue
+		}
+		switch e.Action {
+		case ActionPass:
+			p.Passed++
+		case ActionFail:
+			p.Failed++
+		case ActionSkip:
+			p.Skipped++
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, errors.New("no events of go test -json")
+	}
+	res := &Result{}
+	for _, p := range pkgs {
+		res.Packages = append(res.Packages, p)
+	}
+	sort.Slice(res.Packages, func(i, j int) bool { return res.Packages[i].Name < res.Packages[j].Name })
+	if !first.IsZero() {
+		res.Elapsed = last.Sub(first)
+	} else {
+		for _, p := range res.Packages {
+			res.Elapsed += p.Elapsed
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,63 @@
+package gotest
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseFile(t *testing.T) {
+	tests := []struct {
+		path        string
+		wantElapsed time.Duration
+		want        []*Package
+	}{
+		{
+			"pass.json",
+			2560 * time.Millisecond,
+			[]*Package{
+				{Name: "example.com/a", Action: ActionPass, Elapsed: 1200 * time.Millisecond, Passed: 1, Skipped: 1},
+				{Name: "example.com/b", Action: ActionPass, Elapsed: 2500 * time.Millisecond, Passed: 1},
+				{Name: "example.com/c", Action: ActionSkip},
+			},
+		},
+		{
+			"fail_with_build_output.json",
+			1000 * time.Millisecond,
+			[]*Package{
+				{Name: "example.com/a", Action: ActionFail, Elapsed: 600 * time.Millisecond, Failed: 1},
+				{Name: "example.com/d", Action: ActionFail, Elapsed: 400 * time.Millisecond},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ParseFile(filepath.Join("testdata", tt.path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Elapsed != tt.wantElapsed {
+				t.Errorf("got %v\nwant %v", got.Elapsed, tt.wantElapsed)
+			}
+			if diff := cmp.Diff(got.Packages, tt.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"ok  \texample.com/a\t0.1s\n",
+		"{\"Action\":\n",
+	}
+	for _, tt := range tests {
+		if _, err := Parse(strings.NewReader(tt)); err == nil {
+			t.Errorf("got nil\nwant error: %q", tt)
+		}
+	}
+}
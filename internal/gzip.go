@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+)
+
+// GzipExt is the extension of the gzip-compressed files in the datastores.
+const GzipExt = ".gz"
+
+// ReportPath returns the path of the report of the repository in the datastores.
+// If gz is true, it is the path of the gzip-compressed report (owner/repo/report.json.gz).
+func ReportPath(repository string, gz bool) string {
+	p := fmt.Sprintf("%s/report.json", repository)
+	if gz {
+		p += GzipExt
+	}
+	return p
+}
+
+// GzipIfNeeded returns the content gzip-compressed if the path ends with .gz, otherwise the content as it is.
+func GzipIfNeeded(path string, content []byte) ([]byte, error) {
+	if !strings.HasSuffix(path, GzipExt) {
+		return content, nil
+	}
+	buf := new(bytes.Buffer)
+	w := gzip.NewWriter(buf)
+	if _, err := w.Write(content); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GzipFS returns the fs.FS decompressing the files ending with .gz transparently.
+// The file with .gz appended is also looked up (e.g. report.json.gz for report.json).
+// If both exist (e.g. report.gzip: was toggled), the one modified more recently is opened, and the gzip-compressed one if they can not be told apart.
+func GzipFS(fsys fs.FS) fs.FS {
+	return &gzipFS{fsys: fsys}
+}
+
+type gzipFS struct {
+	fsys fs.FS
+}
+
+func (g *gzipFS) Open(name string) (fs.File, error) {
+	if strings.HasSuffix(name, GzipExt) {
+		f, err := g.fsys.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		fi, err := f.Stat()
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		if fi.IsDir() {
+			return f, nil
+		}
+		return gunzipFile(f, name)
+	}
+	f, err := g.fsys.Open(name)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	gf, gerr := g.fsys.Open(name + GzipExt)
+	if gerr != nil {
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	}
+	if err != nil {
+		return gunzipFile(gf, name)
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		_ = gf.Close()
+		return nil, err
+	}
+	gfi, err := gf.Stat()
+	if err != nil {
+		_ = f.Close()
+		_ = gf.Close()
+		return nil, err
+	}
+	if fi.IsDir() || fi.ModTime().After(gfi.ModTime()) {
+		_ = gf.Close()
+		return f, nil
+	}
+	_ = f.Close()
+	return gunzipFile(gf, name)
+}
+
+func (g *gzipFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(g.fsys, name)
+}
+
+// gunzipFile reads and decompresses the whole file f, and closes it.
+func gunzipFile(f fs.File, name string) (fs.File, error) {
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	b, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &gunzippedFile{Reader: bytes.NewReader(b), info: &gunzippedFileInfo{FileInfo: fi, size: int64(len(b))}}, nil
+}
+
+type gunzippedFile struct {
+	*bytes.Reader
+	info fs.FileInfo
+}
+
+func (f *gunzippedFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+func (f *gunzippedFile) Close() error {
+	return nil
+}
+
+// gunzippedFileInfo is the fs.FileInfo of the original file with the decompressed size.
+type gunzippedFileInfo struct {
+	fs.FileInfo
+	size int64
+}
+
+func (fi *gunzippedFileInfo) Size() int64 {
+	return fi.size
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestGzipFS(t *testing.T) {
+	gz, err := GzipIfNeeded("report.json.gz", []byte(`{"repository":"owner/repo"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	plain, err := GzipIfNeeded("plain.json", []byte(`{"repository":"owner/plain"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale, err := GzipIfNeeded("stale.json", []byte(`{"repository":"owner/stale"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	fsys := GzipFS(fstest.MapFS{
+		"owner/repo/report.json.gz":   {Data: gz},
+		"owner/plain/report.json":     {Data: plain},
+		"owner/broken/report.json.gz": {Data: []byte("not gzip")},
+		"owner/both/report.json.gz":   {Data: gz, ModTime: now},
+		"owner/both/report.json":      {Data: stale, ModTime: now.Add(-time.Hour)},
+		"owner/newer/report.json.gz":  {Data: gz, ModTime: now.Add(-time.Hour)},
+		"owner/newer/report.json":     {Data: plain, ModTime: now},
+		"owner/same/report.json.gz":   {Data: gz},
+		"owner/same/report.json":      {Data: stale},
+	})
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"owner/repo/report.json.gz", `{"repository":"owner/repo"}`, false},
+		{"owner/repo/report.json", `{"repository":"owner/repo"}`, false},
+		{"owner/plain/report.json", `{"repository":"owner/plain"}`, false},
+		{"owner/missing/report.json", "", true},
+		{"owner/broken/report.json", "", true},
+		{"owner/both/report.json", `{"repository":"owner/repo"}`, false},
+		{"owner/newer/report.json", `{"repository":"owner/plain"}`, false},
+		{"owner/same/report.json", `{"repository":"owner/repo"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fs.ReadFile(fsys, tt.name)
+			if err != nil {
+				if !tt.wantErr {
+					t.Error(err)
+				}
+				return
+			}
+			if tt.wantErr {
+				t.Error("want error")
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s\nwant %s", got, tt.want)
+			}
+			fi, err := fs.Stat(fsys, tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fi.Size() != int64(len(tt.want)) {
+				t.Errorf("got %v\nwant %v", fi.Size(), len(tt.want))
+			}
+		})
+	}
+
+	entries, err := fs.ReadDir(fsys, "owner/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "report.json.gz" {
+		t.Errorf("got %v", entries)
+	}
+}
+
+func TestReportPath(t *testing.T) {
+	if got, want := ReportPath("owner/repo", false), "owner/repo/report.json"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := ReportPath("owner/repo", true), "owner/repo/report.json.gz"; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
@@ -0,0 +1,72 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+)
+
+const defaultGitHubHost = "github.com"
+
+// RepositoryFromGitRemote returns owner/repo detected from the URL of the origin remote of the Git repository.
+func RepositoryFromGitRemote(gitRoot string) (string, error) {
+	if gitRoot == "" {
+		return "", errors.New("git root path is not set")
+	}
+	r, err := git.PlainOpen(gitRoot)
+	if err != nil {
+		return "", err
+	}
+	remote, err := r.Remote("origin")
+	if err != nil {
+		return "", err
+	}
+	urls := remote.Config().URLs
+	if len(urls) == 0 {
+		return "", errors.New("URL of the origin remote is not set")
+	}
+	return parseGitHubRemoteURL(urls[0], gitHubHost())
+}
+
+func gitHubHost() string {
+	if u, err := url.Parse(os.Getenv("GITHUB_SERVER_URL")); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return defaultGitHubHost
+}
+
+// parseGitHubRemoteURL parses the SSH ( git@github.com:owner/repo.git ) or HTTPS ( https://github.com/owner/repo.git ) URL of the GitHub remote.
+func parseGitHubRemoteURL(u, host string) (string, error) {
+	var h, p string
+	if strings.Contains(u, "://") {
+		pu, err := url.Parse(u)
+		if err != nil {
+			return "", err
+		}
+		h = pu.Hostname()
+		p = pu.Path
+	} else {
+		// scp-like syntax
+		hp, path, ok := strings.Cut(u, ":")
+		if !ok {
+			return "", fmt.Errorf("unsupported remote URL: %s", u)
+		}
+		_, h, ok = strings.Cut(hp, "@")
+		if !ok {
+			h = hp
+		}
+		p = path
+	}
+	if !strings.EqualFold(h, host) && !strings.EqualFold(h, defaultGitHubHost) {
+		return "", fmt.Errorf("not a GitHub remote URL: %s", u)
+	}
+	splitted := strings.Split(strings.TrimSuffix(strings.Trim(p, "/"), ".git"), "/")
+	if len(splitted) != 2 || splitted[0] == "" || splitted[1] == "" {
+		return "", fmt.Errorf("could not detect owner/repo from the remote URL: %s", u)
+	}
+	return fmt.Sprintf("%s/%s", splitted[0], splitted[1]), nil
+}
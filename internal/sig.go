@@ -0,0 +1,11 @@
+package internal
+
+import "fmt"
+
+// CommentSig returns the signature embedded in the comments posted by octocov, to find and update the previous comment of the key.
+func CommentSig(key string) string {
+	if key == "" {
+		return "<!-- octocov -->"
+	}
+	return fmt.Sprintf("<!-- octocov:%s -->", key)
+}
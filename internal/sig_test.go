@@ -0,0 +1,19 @@
+package internal
+
+import "testing"
+
+func TestCommentSig(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"", "<!-- octocov -->"},
+		{"foo", "<!-- octocov:foo -->"},
+	}
+	for _, tt := range tests {
+		got := CommentSig(tt.key)
+		if got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package ratio
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Group is the code to test ratio of the files grouped by directory or by Pattern.
+type Group struct {
+	Name string `json:"name"`
+	Code int    `json:"code"`
+	Test int    `json:"test"`
+}
+
+// Ratio returns the code to test ratio of the group. It returns 0 if the group has no code.
+func (g *Group) Ratio() float64 {
+	if g.Code == 0 {
+		return 0
+	}
+	return float64(g.Test) / float64(g.Code)
+}
+
+// Groups returns the code to test ratio grouped by the leading depth directories of the file paths, sorted by worst ratio.
+// Files directly under the root are grouped into ".". Groups without code (e.g. directories only for tests) are listed last.
+func (r *Ratio) Groups(depth int) []*Group {
+	if r == nil || depth <= 0 {
+		return nil
+	}
+	gm := map[string]*Group{}
+	group := func(p string) *Group {
+		n := GroupName(p, depth)
+		g, ok := gm[n]
+		if !ok {
+			g = &Group{Name: n}
+			gm[n] = g
+		}
+		return g
+	}
+	for _, f := range r.CodeFiles {
+		group(f.Path).Code += f.Code
+	}
+	for _, f := range r.TestFiles {
+		group(f.Path).Test += f.Code
+	}
+	return sortGroups(gm)
+}
+
+// NamedGroups returns the code to test ratio of each group of Pattern (codeToTestRatio.groups), sorted by worst ratio.
+// It returns nil if the files are not grouped by Pattern.
+func (r *Ratio) NamedGroups() []*Group {
+	if r == nil {
+		return nil
+	}
+	gm := map[string]*Group{}
+	group := func(n string) *Group {
+		g, ok := gm[n]
+		if !ok {
+			g = &Group{Name: n}
+			gm[n] = g
+		}
+		return g
+	}
+	for _, f := range r.CodeFiles {
+		if f.Group != "" {
+			group(f.Group).Code += f.Code
+		}
+	}
+	for _, f := range r.TestFiles {
+		if f.Group != "" {
+			group(f.Group).Test += f.Code
+		}
+	}
+	if len(gm) == 0 {
+		return nil
+	}
+	return sortGroups(gm)
+}
+
+// sortGroups returns the groups sorted by worst ratio. Groups without code are listed last.
+func sortGroups(gm map[string]*Group) []*Group {
+	groups := make([]*Group, 0, len(gm))
+	for _, g := range gm {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i].Code == 0) != (groups[j].Code == 0) {
+			return groups[j].Code == 0
+		}
+		if groups[i].Ratio() != groups[j].Ratio() {
+			return groups[i].Ratio() < groups[j].Ratio()
+		}
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// GroupName returns the leading depth directories of the file path.
+func GroupName(p string, depth int) string {
+	dir := path.Dir(filepath.ToSlash(p))
+	if dir == "." || dir == "/" {
+		return "."
+	}
+	parts := strings.Split(strings.TrimPrefix(dir, "/"), "/")
+	if len(parts) > depth {
+		parts = parts[:depth]
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,73 @@
+package ratio
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGroupName(t *testing.T) {
+	tests := []struct {
+		path  string
+		depth int
+		want  string
+	}{
+		{"main.go", 1, "."},
+		{"pkg/a/a.go", 1, "pkg"},
+		{"pkg/a/a.go", 2, "pkg/a"},
+		{"pkg/a/a.go", 3, "pkg/a"},
+	}
+	for _, tt := range tests {
+		if got := GroupName(tt.path, tt.depth); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
+
+func TestGroups(t *testing.T) {
+	r := &Ratio{
+		CodeFiles: Files{
+			{Path: "main.go", Code: 10},
+			{Path: "pkg/a/a.go", Code: 100},
+			{Path: "pkg/b/b.go", Code: 100},
+			{Path: "cmd/root.go", Code: 50},
+		},
+		TestFiles: Files{
+			{Path: "pkg/a/a_test.go", Code: 150},
+			{Path: "pkg/b/b_test.go", Code: 10},
+			{Path: "cmd/root_test.go", Code: 100},
+			{Path: "testdata/helper_test.go", Code: 30},
+		},
+	}
+	tests := []struct {
+		depth int
+		want  []*Group
+	}{
+		{0, nil},
+		{
+			1,
+			[]*Group{
+				{Name: ".", Code: 10, Test: 0},
+				{Name: "pkg", Code: 200, Test: 160},
+				{Name: "cmd", Code: 50, Test: 100},
+				{Name: "testdata", Code: 0, Test: 30},
+			},
+		},
+		{
+			2,
+			[]*Group{
+				{Name: ".", Code: 10, Test: 0},
+				{Name: "pkg/b", Code: 100, Test: 10},
+				{Name: "pkg/a", Code: 100, Test: 150},
+				{Name: "cmd", Code: 50, Test: 100},
+				{Name: "testdata", Code: 0, Test: 30},
+			},
+		},
+	}
+	for _, tt := range tests {
+		got := r.Groups(tt.depth)
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
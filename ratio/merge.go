@@ -33,6 +33,7 @@ func uniqueFiles(in Files) Files {
 			v.Code = f.Code
 			v.Comments = f.Comments
 			v.Lang = f.Lang
+			v.Group = f.Group
 			v.Path = f.Path
 			continue
 		}
@@ -17,6 +17,8 @@ type File struct {
 	Blanks   int    `json:"blank"`
 	Path     string `json:"path"`
 	Lang     string `json:"language"`
+	// Group is the name of the Pattern the file matched (codeToTestRatio.groups)
+	Group string `json:"group,omitempty"`
 }
 
 type Files []*File
@@ -63,16 +65,33 @@ func (r *Ratio) DeleteFiles() {
 	r.TestFiles = Files{}
 }
 
+// Pattern is the glob patterns of the code files and the test files of a named group (e.g. a language).
+type Pattern struct {
+	Name string
+	Code []string
+	Test []string
+}
+
 func Measure(root string, code, test []string) (*Ratio, error) {
+	return MeasureGroups(root, []*Pattern{{Code: code, Test: test}})
+}
+
+// MeasureGroups measures the code to test ratio of the files matching the patterns, and the combined total of them.
+// A file matching the code or test patterns of more than one group is counted only in the first declared group.
+func MeasureGroups(root string, patterns []*Pattern) (*Ratio, error) {
 	log.Printf("root: %s", root)
 	ratio := New()
 	defined := gocloc.NewDefinedLanguages()
 	opts := gocloc.NewClocOptions()
-	for i, p := range code {
-		code[i] = filepath.FromSlash(p)
-	}
-	for i, p := range test {
-		test[i] = filepath.FromSlash(p)
+	var all []string
+	for _, pt := range patterns {
+		for i, p := range pt.Code {
+			pt.Code[i] = filepath.FromSlash(p)
+		}
+		for i, p := range pt.Test {
+			pt.Test[i] = filepath.FromSlash(p)
+		}
+		all = append(all, pt.Code...)
 	}
 
 	if err := filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
@@ -89,53 +108,28 @@ func Measure(root string, code, test []string) (*Ratio, error) {
 			return nil
 		}
 
-		isCode := false
-		isTest := false
-
 		rel, err := filepath.Rel(root, path)
 		if err != nil {
 			return err
 		}
 
-		// check path
-		if len(code) == 0 {
-			isCode = true
-		}
-		for _, p := range code {
-			not := false
-			if strings.HasPrefix(p, "!") {
-				p = strings.TrimPrefix(p, "!")
-				not = true
-			}
-			match, err := doublestar.PathMatch(p, rel)
+		var (
+			isCode, isTest bool
+			group          string
+		)
+		for _, pt := range patterns {
+			// check path
+			isCode, err = matchPatterns(pt.Code, rel, len(pt.Code) == 0)
 			if err != nil {
 				return err
 			}
-			if match {
-				if not {
-					isCode = false
-				} else {
-					isCode = true
-				}
-			}
-		}
-		// test
-		for _, p := range test {
-			not := false
-			if strings.HasPrefix(p, "!") {
-				p = strings.TrimPrefix(p, "!")
-				not = true
-			}
-			match, err := doublestar.PathMatch(p, rel)
+			isTest, err = matchPatterns(pt.Test, rel, false)
 			if err != nil {
 				return err
 			}
-			if match {
-				if not {
-					isTest = false
-				} else {
-					isTest = true
-				}
+			if isCode || isTest {
+				group = pt.Name
+				break
 			}
 		}
 		if !isCode && !isTest {
@@ -165,6 +159,7 @@ func Measure(root string, code, test []string) (*Ratio, error) {
 				Blanks:   int(cf.Blanks),
 				Path:     rel,
 				Lang:     cf.Lang,
+				Group:    group,
 			})
 		}
 		if isTest {
@@ -176,6 +171,7 @@ func Measure(root string, code, test []string) (*Ratio, error) {
 				Blanks:   int(cf.Blanks),
 				Path:     rel,
 				Lang:     cf.Lang,
+				Group:    group,
 			})
 		}
 		return nil
@@ -183,11 +179,32 @@ func Measure(root string, code, test []string) (*Ratio, error) {
 		return nil, err
 	}
 	if ratio.Code == 0 {
-		return nil, fmt.Errorf("could not count code: %s", code)
+		return nil, fmt.Errorf("could not count code: %s", all)
 	}
 	return ratio, nil
 }
 
+// matchPatterns reports whether the path matches the glob patterns. The last matching pattern wins, and a pattern prefixed with "!" excludes the path.
+// It returns def if no pattern matches.
+func matchPatterns(patterns []string, path string, def bool) (bool, error) {
+	matched := def
+	for _, p := range patterns {
+		not := false
+		if strings.HasPrefix(p, "!") {
+			p = strings.TrimPrefix(p, "!")
+			not = true
+		}
+		match, err := doublestar.PathMatch(p, path)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			matched = !not
+		}
+	}
+	return matched, nil
+}
+
 var ignores = []string{
 	".bzr", ".cvs", ".hg", ".git", ".svn",
 	".github", ".gitignore", ".gitkeep",
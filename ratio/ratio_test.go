@@ -89,6 +89,49 @@ func TestMeasure(t *testing.T) {
 	}
 }
 
+func TestMeasureGroups(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"main.go":          "package main\n\nfunc main() {\n}\n",
+		"main_test.go":     "package main\n\nimport \"testing\"\n\nfunc TestMain(t *testing.T) {\n}\n",
+		"web/app.ts":       "export const a = 1;\nexport const b = 2;\n",
+		"web/app.test.ts":  "import { a } from './app';\n",
+		"tools/gen/gen.go": "package gen\n",
+	}
+	for p, content := range files {
+		fp := filepath.Join(root, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(fp), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fp, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	patterns := []*Pattern{
+		{Name: "go", Code: []string{"**/*.go", "!**/*_test.go"}, Test: []string{"**/*_test.go"}},
+		{Name: "typescript", Code: []string{"**/*.ts", "!**/*.test.ts"}, Test: []string{"**/*.test.ts"}},
+		// Files matching the former groups (tools/gen/gen.go and web/app.ts) are not counted again
+		{Name: "tools", Code: []string{"tools/**/*.go", "**/*.ts"}, Test: []string{}},
+	}
+	got, err := MeasureGroups(root, patterns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 6; got.Code != want {
+		t.Errorf("got %v code\nwant %v", got.Code, want)
+	}
+	if want := 5; got.Test != want {
+		t.Errorf("got %v test\nwant %v", got.Test, want)
+	}
+	want := []*Group{
+		{Name: "typescript", Code: 2, Test: 1},
+		{Name: "go", Code: 4, Test: 4},
+	}
+	if diff := cmp.Diff(got.NamedGroups(), want); diff != "" {
+		t.Error(diff)
+	}
+}
+
 func TestPathMatch(t *testing.T) {
 	root := filepath.Join(testdataDir(t), "..")
 	tests := []struct {
@@ -0,0 +1,126 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/olekukonko/tablewriter"
+)
+
+// BranchReport is the report stored for the branch. Report is nil when the report of the branch is not found.
+type BranchReport struct {
+	Branch string
+	Report *Report
+}
+
+// BranchReports is the reports of the branches to compare.
+type BranchReports []*BranchReport
+
+type branchMetrics struct {
+	Branch            string     `json:"branch"`
+	Found             bool       `json:"found"`
+	Commit            string     `json:"commit,omitempty"`
+	Timestamp         *time.Time `json:"timestamp,omitempty"`
+	Coverage          *float64   `json:"coverage,omitempty"`
+	CodeToTestRatio   *float64   `json:"code_to_test_ratio,omitempty"`
+	TestExecutionTime *float64   `json:"test_execution_time,omitempty"`
+	DocCoverage       *float64   `json:"doc_coverage,omitempty"`
+}
+
+// Out writes the code metrics of the branches side by side.
+func (brs BranchReports) Out(w io.Writer) error {
+	h := []string{""}
+	for _, br := range brs {
+		h = append(h, br.Branch)
+	}
+	rows := []struct {
+		title    string
+		measured func(r *Report) bool
+		value    func(r *Report) string
+	}{
+		{"Coverage", (*Report).IsMeasuredCoverage, func(r *Report) string { return fmt.Sprintf("%.1f%%", r.CoveragePercent()) }},
+		{"Code to Test Ratio", (*Report).IsMeasuredCodeToTestRatio, func(r *Report) string { return fmt.Sprintf("1:%.1f", r.CodeToTestRatioRatio()) }},
+		{"Test Execution Time", (*Report).IsMeasuredTestExecutionTime, func(r *Report) string { return time.Duration(*r.TestExecutionTime).String() }},
+		{"Doc Coverage", (*Report).IsMeasuredDocCoverage, func(r *Report) string { return fmt.Sprintf("%.1f%%", r.DocCoveragePercent()) }},
+		{"Commit", func(r *Report) bool { return r.Commit != "" }, func(r *Report) string { return shortCommit(r.Commit) }},
+		{"Timestamp", func(r *Report) bool { return !r.Timestamp.IsZero() }, func(r *Report) string { return r.Timestamp.UTC().Format(time.RFC3339) }},
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader(h)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("-")
+	table.SetHeaderLine(true)
+	table.SetBorder(false)
+	alignments := []int{tablewriter.ALIGN_LEFT}
+	for range brs {
+		alignments = append(alignments, tablewriter.ALIGN_RIGHT)
+	}
+	table.SetColumnAlignment(alignments)
+	for _, row := range rows {
+		values := []string{row.title}
+		colors := []tablewriter.Colors{{tablewriter.Bold}}
+		measured := false
+		for _, br := range brs {
+			v := "-"
+			if br.Report != nil && row.measured(br.Report) {
+				v = row.value(br.Report)
+				measured = true
+			}
+			values = append(values, v)
+			colors = append(colors, tablewriter.Colors{})
+		}
+		if !measured {
+			continue
+		}
+		table.Rich(values, colors)
+	}
+	table.Render()
+	return nil
+}
+
+// JSON returns the code metrics of the branches as JSON.
+func (brs BranchReports) JSON() ([]byte, error) {
+	ms := []*branchMetrics{}
+	for _, br := range brs {
+		m := &branchMetrics{Branch: br.Branch}
+		if r := br.Report; r != nil {
+			m.Found = true
+			m.Commit = r.Commit
+			if !r.Timestamp.IsZero() {
+				ts := r.Timestamp
+				m.Timestamp = &ts
+			}
+			if r.IsMeasuredCoverage() {
+				v := r.CoveragePercent()
+				m.Coverage = &v
+			}
+			if r.IsMeasuredCodeToTestRatio() {
+				v := r.CodeToTestRatioRatio()
+				m.CodeToTestRatio = &v
+			}
+			if r.IsMeasuredTestExecutionTime() {
+				v := r.TestExecutionTimeNano()
+				m.TestExecutionTime = &v
+			}
+			if r.IsMeasuredDocCoverage() {
+				v := r.DocCoveragePercent()
+				m.DocCoverage = &v
+			}
+		}
+		ms = append(ms, m)
+	}
+	return json.MarshalIndent(ms, "", "  ")
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
@@ -0,0 +1,68 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/octocov/coverage"
+)
+
+func TestBranchReportsOut(t *testing.T) {
+	brs := BranchReports{
+		{Branch: "main", Report: &Report{
+			Commit:    "1234567890abcdef",
+			Coverage:  &coverage.Coverage{Total: 100, Covered: 80},
+			Timestamp: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		}},
+		{Branch: "release-1.x", Report: &Report{
+			Commit:    "fedcba0987654321",
+			Coverage:  &coverage.Coverage{Total: 100, Covered: 65},
+			Timestamp: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		}},
+		{Branch: "release-2.x"},
+	}
+	buf := new(bytes.Buffer)
+	if err := brs.Out(buf); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	for _, want := range []string{"release-1.x", "80.0%", "65.0%", "1234567", "2024-02-01T00:00:00Z"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("got %v\nwant to contain %v", got, want)
+		}
+	}
+	if strings.Contains(got, "Code to Test Ratio") {
+		t.Errorf("got %v\nwant not to contain %v", got, "Code to Test Ratio")
+	}
+}
+
+func TestBranchReportsJSON(t *testing.T) {
+	tet := float64(3 * time.Second)
+	brs := BranchReports{
+		{Branch: "main", Report: &Report{
+			Commit:            "1234567890abcdef",
+			Coverage:          &coverage.Coverage{Total: 100, Covered: 80},
+			TestExecutionTime: &tet,
+		}},
+		{Branch: "release-2.x"},
+	}
+	b, err := brs.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string]any{
+		{"branch": "main", "found": true, "commit": "1234567890abcdef", "coverage": 80.0, "test_execution_time": tet},
+		{"branch": "release-2.x", "found": false},
+	}
+	if diff := cmp.Diff(got, want, nil); diff != "" {
+		t.Error(diff)
+	}
+}
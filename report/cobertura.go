@@ -0,0 +1,113 @@
+package report
+
+import (
+	"encoding/xml"
+	"errors"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// coberturaReport is a report in the Cobertura XML format.
+// https://github.com/cobertura/web/blob/master/htdocs/xml/coverage-04.dtd
+type coberturaReport struct {
+	XMLName         xml.Name           `xml:"coverage"`
+	LineRate        float64            `xml:"line-rate,attr"`
+	BranchRate      float64            `xml:"branch-rate,attr"`
+	LinesCovered    int                `xml:"lines-covered,attr"`
+	LinesValid      int                `xml:"lines-valid,attr"`
+	BranchesCovered int                `xml:"branches-covered,attr"`
+	BranchesValid   int                `xml:"branches-valid,attr"`
+	Complexity      int                `xml:"complexity,attr"`
+	Version         string             `xml:"version,attr"`
+	Timestamp       string             `xml:"timestamp,attr"`
+	Sources         []string           `xml:"sources>source"`
+	Packages        []coberturaPackage `xml:"packages>package"`
+}
+
+type coberturaPackage struct {
+	Name       string           `xml:"name,attr"`
+	LineRate   float64          `xml:"line-rate,attr"`
+	BranchRate float64          `xml:"branch-rate,attr"`
+	Complexity int              `xml:"complexity,attr"`
+	Classes    []coberturaClass `xml:"classes>class"`
+}
+
+type coberturaClass struct {
+	Name       string          `xml:"name,attr"`
+	Filename   string          `xml:"filename,attr"`
+	LineRate   float64         `xml:"line-rate,attr"`
+	BranchRate float64         `xml:"branch-rate,attr"`
+	Complexity int             `xml:"complexity,attr"`
+	Methods    struct{}        `xml:"methods"`
+	Lines      []coberturaLine `xml:"lines>line"`
+}
+
+type coberturaLine struct {
+	Number int `xml:"number,attr"`
+	Hits   int `xml:"hits,attr"`
+}
+
+// CoberturaXML returns the code coverage of the report in the Cobertura XML format.
+// Files are grouped into packages by directory, and only line hit counts of each file are populated.
+func (r *Report) CoberturaXML() ([]byte, error) {
+	if r.Coverage == nil {
+		return nil, errors.New("coverage is not measured")
+	}
+	cr := &coberturaReport{
+		Version:   "octocov",
+		Timestamp: strconv.FormatInt(r.Timestamp.UnixMilli(), 10),
+		Sources:   []string{"."},
+	}
+	pkgs := map[string]*coberturaPackage{}
+	pkgCounts := map[string][2]int{}
+	for _, fc := range r.Coverage.Files {
+		cl := coberturaClass{
+			Name:     fc.File,
+			Filename: fc.File,
+		}
+		covered := 0
+		for _, lc := range fc.Blocks.ToLineCoverages() {
+			cl.Lines = append(cl.Lines, coberturaLine{Number: lc.Line, Hits: lc.Count})
+			if lc.Count > 0 {
+				covered++
+			}
+		}
+		cl.LineRate = lineRate(covered, len(cl.Lines))
+		dir := filepath.ToSlash(filepath.Dir(fc.File))
+		p, ok := pkgs[dir]
+		if !ok {
+			p = &coberturaPackage{Name: dir}
+			pkgs[dir] = p
+		}
+		p.Classes = append(p.Classes, cl)
+		c := pkgCounts[dir]
+		pkgCounts[dir] = [2]int{c[0] + covered, c[1] + len(cl.Lines)}
+		cr.LinesCovered += covered
+		cr.LinesValid += len(cl.Lines)
+	}
+	var dirs []string
+	for dir := range pkgs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		p := pkgs[dir]
+		p.LineRate = lineRate(pkgCounts[dir][0], pkgCounts[dir][1])
+		sort.Slice(p.Classes, func(i, j int) bool { return p.Classes[i].Filename < p.Classes[j].Filename })
+		cr.Packages = append(cr.Packages, *p)
+	}
+	cr.LineRate = lineRate(cr.LinesCovered, cr.LinesValid)
+	b, err := xml.MarshalIndent(cr, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+func lineRate(covered, valid int) float64 {
+	if valid == 0 {
+		return 0
+	}
+	return float64(covered) / float64(valid)
+}
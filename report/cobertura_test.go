@@ -0,0 +1,93 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k1LoW/octocov/coverage"
+)
+
+func TestCoberturaXML(t *testing.T) {
+	log.SetOutput(io.Discard) // Disable log in challengeParseReport()
+
+	tests := []struct {
+		path string
+	}{
+		{filepath.Join(coverageTestdataDir(t), "lcov")},
+		{filepath.Join(coverageTestdataDir(t), "gocover")},
+	}
+	for _, tt := range tests {
+		t.Run(filepath.Base(tt.path), func(t *testing.T) {
+			r := &Report{}
+			if err := r.MeasureCoverage([]string{tt.path}, nil); err != nil {
+				t.Fatal(err)
+			}
+			b, err := r.CoberturaXML()
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := filepath.Join(t.TempDir(), coverage.CoberturaDefaultPath)
+			if err := os.WriteFile(p, b, 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			// Round-trip with the Cobertura reader
+			got, _, err := coverage.NewCobertura().ParseReport(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.Files) != len(r.Coverage.Files) {
+				t.Errorf("got %v\nwant %v", len(got.Files), len(r.Coverage.Files))
+			}
+			total, covered := 0, 0
+			for _, fc := range r.Coverage.Files {
+				gf, err := got.Files.FindByFile(fc.File)
+				if err != nil {
+					t.Errorf("file not found: %s", fc.File)
+					continue
+				}
+				wantCovered := 0
+				lcs := fc.Blocks.ToLineCoverages()
+				for _, lc := range lcs {
+					if lc.Count > 0 {
+						wantCovered++
+					}
+				}
+				if gf.Total != len(lcs) {
+					t.Errorf("%s: got %v\nwant %v", fc.File, gf.Total, len(lcs))
+				}
+				if gf.Covered != wantCovered {
+					t.Errorf("%s: got %v\nwant %v", fc.File, gf.Covered, wantCovered)
+				}
+				total += len(lcs)
+				covered += wantCovered
+			}
+
+			root := struct {
+				LineRate     float64 `xml:"line-rate,attr"`
+				LinesValid   int     `xml:"lines-valid,attr"`
+				LinesCovered int     `xml:"lines-covered,attr"`
+			}{}
+			if err := xml.Unmarshal(b, &root); err != nil {
+				t.Fatal(err)
+			}
+			if root.LinesValid != total || root.LinesCovered != covered {
+				t.Errorf("got %v/%v\nwant %v/%v", root.LinesCovered, root.LinesValid, covered, total)
+			}
+			if want := float64(covered) / float64(total); root.LineRate != want {
+				t.Errorf("got %v\nwant %v", root.LineRate, want)
+			}
+		})
+	}
+}
+
+func TestCoberturaXMLWithoutCoverage(t *testing.T) {
+	r := &Report{}
+	if _, err := r.CoberturaXML(); err == nil {
+		t.Error("want error")
+	}
+}
@@ -0,0 +1,33 @@
+package report
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/goccy/go-json"
+)
+
+// codecovReport is a report in the Codecov custom coverage format.
+// https://docs.codecov.com/docs/codecov-custom-coverage-format
+type codecovReport struct {
+	Coverage map[string]map[string]int `json:"coverage"`
+}
+
+// CodecovJSON returns the code coverage of the report in the Codecov custom coverage format.
+// Only line hit counts of each file are populated.
+func (r *Report) CodecovJSON() ([]byte, error) {
+	if r.Coverage == nil {
+		return nil, errors.New("coverage is not measured")
+	}
+	cr := &codecovReport{
+		Coverage: map[string]map[string]int{},
+	}
+	for _, fc := range r.Coverage.Files {
+		lines := map[string]int{}
+		for _, lc := range fc.Blocks.ToLineCoverages() {
+			lines[strconv.Itoa(lc.Line)] = lc.Count
+		}
+		cr.Coverage[fc.File] = lines
+	}
+	return json.MarshalIndent(cr, "", "  ")
+}
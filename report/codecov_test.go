@@ -0,0 +1,57 @@
+package report
+
+import (
+	"io"
+	"log"
+	"path/filepath"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+func TestCodecovJSON(t *testing.T) {
+	log.SetOutput(io.Discard) // Disable log in challengeParseReport()
+
+	r := &Report{}
+	if err := r.MeasureCoverage([]string{filepath.Join(coverageTestdataDir(t), "lcov")}, nil); err != nil {
+		t.Fatal(err)
+	}
+	b, err := r.CodecovJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]map[string]map[string]int{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	files := got["coverage"]
+	if len(files) != len(r.Coverage.Files) {
+		t.Errorf("got %v\nwant %v", len(files), len(r.Coverage.Files))
+	}
+	for _, fc := range r.Coverage.Files {
+		lines, ok := files[fc.File]
+		if !ok {
+			t.Errorf("file not found: %s", fc.File)
+			continue
+		}
+		covered := 0
+		for _, c := range lines {
+			if c > 0 {
+				covered++
+			}
+		}
+		if len(lines) != fc.Total {
+			t.Errorf("got %v\nwant %v", len(lines), fc.Total)
+		}
+		if covered != fc.Covered {
+			t.Errorf("got %v\nwant %v", covered, fc.Covered)
+		}
+	}
+}
+
+func TestCodecovJSONWithoutCoverage(t *testing.T) {
+	r := &Report{}
+	if _, err := r.CodecovJSON(); err == nil {
+		t.Error("want error")
+	}
+}
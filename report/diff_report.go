@@ -5,30 +5,33 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/k1LoW/octocov/coverage"
+	"github.com/k1LoW/octocov/doccov"
 	"github.com/k1LoW/octocov/gh"
 	"github.com/k1LoW/octocov/ratio"
 	"github.com/olekukonko/tablewriter"
 )
 
 type DiffReport struct {
-	RepositoryA       string                 `json:"repository_a"`
-	RepositoryB       string                 `json:"repository_b"`
-	RefA              string                 `json:"ref_a"`
-	RefB              string                 `json:"ref_b"`
-	CommitA           string                 `json:"commit_a"`
-	CommitB           string                 `json:"commit_b"`
-	Coverage          *coverage.DiffCoverage `json:"coverage,omitempty"`
-	CodeToTestRatio   *ratio.DiffRatio       `json:"code_to_test_ratio,omitempty"`
-	TestExecutionTime *DiffTestExecutionTime `json:"test_execution_time,omitempty"`
-	CustomMetrics     []*DiffCustomMetricSet `json:"custom_metrics,omitempty"`
-	TimestampA        time.Time              `json:"timestamp_a"`
-	TimestampB        time.Time              `json:"timestamp_b"`
-	ReportA           *Report                `json:"-"`
-	ReportB           *Report                `json:"-"`
+	RepositoryA       string                  `json:"repository_a"`
+	RepositoryB       string                  `json:"repository_b"`
+	RefA              string                  `json:"ref_a"`
+	RefB              string                  `json:"ref_b"`
+	CommitA           string                  `json:"commit_a"`
+	CommitB           string                  `json:"commit_b"`
+	Coverage          *coverage.DiffCoverage  `json:"coverage,omitempty"`
+	CodeToTestRatio   *ratio.DiffRatio        `json:"code_to_test_ratio,omitempty"`
+	TestExecutionTime *DiffTestExecutionTime  `json:"test_execution_time,omitempty"`
+	DocCoverage       *doccov.DiffDocCoverage `json:"doc_coverage,omitempty"`
+	CustomMetrics     []*DiffCustomMetricSet  `json:"custom_metrics,omitempty"`
+	TimestampA        time.Time               `json:"timestamp_a"`
+	TimestampB        time.Time               `json:"timestamp_b"`
+	ReportA           *Report                 `json:"-"`
+	ReportB           *Report                 `json:"-"`
 }
 
 type DiffTestExecutionTime struct {
@@ -58,6 +61,55 @@ func (d *DiffReport) Out(w io.Writer) {
 	table.Render()
 }
 
+// RegressedFiles returns the file coverages whose code coverage decreased, in the order of the largest decrease.
+// Files that do not exist in ReportA (e.g. deleted files) are not included.
+func (d *DiffReport) RegressedFiles() coverage.DiffFileCoverages {
+	if d.Coverage == nil {
+		return nil
+	}
+	var files coverage.DiffFileCoverages
+	for _, fc := range d.Coverage.Files {
+		if fc.FileCoverageA == nil || fc.Diff >= 0 {
+			continue
+		}
+		files = append(files, fc)
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].Diff != files[j].Diff {
+			return files[i].Diff < files[j].Diff
+		}
+		return files[i].File < files[j].File
+	})
+	return files
+}
+
+// OutRegressedFiles prints the table of the files whose code coverage decreased.
+func (d *DiffReport) OutRegressedFiles(w io.Writer) {
+	files := d.RegressedFiles()
+	if len(files) == 0 {
+		return
+	}
+	table := tablewriter.NewWriter(w)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("-")
+	table.SetHeaderLine(true)
+	table.SetBorder(false)
+	table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT, tablewriter.ALIGN_RIGHT})
+	table.SetHeader([]string{"Regressed Files", makeHeadTitle(d.RefB, d.CommitB, d.ReportB.covPaths), makeHeadTitle(d.RefA, d.CommitA, d.ReportA.covPaths), "+/-"})
+	r := tablewriter.Colors{tablewriter.Bold, tablewriter.FgRedColor}
+	for _, fc := range files {
+		covB := "-"
+		if fc.FileCoverageB != nil {
+			covB = fmt.Sprintf("%.1f%%", fc.B)
+		}
+		table.Rich([]string{fc.File, covB, fmt.Sprintf("%.1f%%", fc.A), fmt.Sprintf("%.1f%%", fc.Diff)}, []tablewriter.Colors{tablewriter.Colors{}, tablewriter.Colors{}, tablewriter.Colors{}, r})
+	}
+	table.Render()
+}
+
 var leftSepRe = regexp.MustCompile(`(?m)^\|`)
 
 func (d *DiffReport) Table() string {
@@ -113,6 +165,13 @@ func (d *DiffReport) Table() string {
 			}
 		}
 	}
+	if d.Coverage != nil && (d.Coverage.CoverageA.IsMeasuredFuncs() || d.Coverage.CoverageB.IsMeasuredFuncs()) {
+		if dd := d.Coverage.CoverageA.UncoveredFuncs() - d.Coverage.CoverageB.UncoveredFuncs(); dd > 0 {
+			t2 = strings.Replace(t2, "  | Uncovered Functions", "- | Uncovered Functions", 1)
+		} else if dd < 0 {
+			t2 = strings.Replace(t2, "  | Uncovered Functions", "+ | Uncovered Functions", 1)
+		}
+	}
 	if d.TestExecutionTime != nil {
 		if d.TestExecutionTime.Diff > 0 {
 			t2 = strings.Replace(t2, "  | Test Execution", "- | Test Execution", 1)
@@ -120,6 +179,13 @@ func (d *DiffReport) Table() string {
 			t2 = strings.Replace(t2, "  | Test Execution", "+ | Test Execution", 1)
 		}
 	}
+	if d.DocCoverage != nil {
+		if d.DocCoverage.Diff > 0 {
+			t2 = strings.Replace(t2, "  | Doc Coverage", "+ | Doc Coverage", 1)
+		} else if d.DocCoverage.Diff < 0 {
+			t2 = strings.Replace(t2, "  | Doc Coverage", "- | Doc Coverage", 1)
+		}
+	}
 	out = append(out, fmt.Sprintf("<details>\n\n<summary>Details</summary>\n\n``` diff\n%s```\n\n</details>\n", t2))
 
 	return strings.Join(out, "\n")
@@ -178,6 +244,30 @@ func (d *DiffReport) renderTable(table *tablewriter.Table, g, r, b tablewriter.C
 			}
 		}
 
+		if d.Coverage.CoverageA.IsMeasuredFuncs() || d.Coverage.CoverageB.IsMeasuredFuncs() {
+			a := "-"
+			bb := "-"
+			if d.Coverage.CoverageA.IsMeasuredFuncs() {
+				a = fmt.Sprintf("%d", d.Coverage.CoverageA.UncoveredFuncs())
+			}
+			if d.Coverage.CoverageB.IsMeasuredFuncs() {
+				bb = fmt.Sprintf("%d", d.Coverage.CoverageB.UncoveredFuncs())
+			}
+			dd := d.Coverage.CoverageA.UncoveredFuncs() - d.Coverage.CoverageB.UncoveredFuncs()
+			ds := fmt.Sprintf("%d", dd)
+			cc := tablewriter.Colors{}
+			if dd > 0 {
+				ds = fmt.Sprintf("+%d", dd)
+				cc = r
+			} else if dd < 0 {
+				cc = g
+			}
+			t := "Uncovered Functions"
+			if !detail {
+				t = "**Uncovered Functions**"
+			}
+			table.Rich([]string{t, bb, a, ds}, []tablewriter.Colors{b, tablewriter.Colors{}, tablewriter.Colors{}, cc})
+		}
 	}
 	if d.CodeToTestRatio != nil {
 		dd := d.CodeToTestRatio.Diff
@@ -248,6 +338,31 @@ func (d *DiffReport) renderTable(table *tablewriter.Table, g, r, b tablewriter.C
 		}
 		table.Rich([]string{t, tb, ta, ds}, []tablewriter.Colors{b, tablewriter.Colors{}, tablewriter.Colors{}, cc})
 	}
+	if d.DocCoverage != nil {
+		dd := d.DocCoverage.Diff
+		ds := fmt.Sprintf("%.1f%%", dd)
+		cc := tablewriter.Colors{}
+		if dd > 0 {
+			ds = fmt.Sprintf("+%.1f%%", dd)
+			cc = g
+		} else if dd < 0 {
+			ds = fmt.Sprintf("%.1f%%", dd)
+			cc = r
+		}
+		covA := "-"
+		covB := "-"
+		if d.DocCoverage.DocCoverageA != nil {
+			covA = fmt.Sprintf("%.1f%%", d.DocCoverage.A)
+		}
+		if d.DocCoverage.DocCoverageB != nil {
+			covB = fmt.Sprintf("%.1f%%", d.DocCoverage.B)
+		}
+		t := "Doc Coverage"
+		if !detail {
+			t = "**Doc Coverage**"
+		}
+		table.Rich([]string{t, covB, covA, ds}, []tablewriter.Colors{b, tablewriter.Colors{}, tablewriter.Colors{}, cc})
+	}
 }
 
 func (d *DiffReport) FileCoveragesTable(files []*gh.PullRequestFile) string {
@@ -257,9 +372,16 @@ func (d *DiffReport) FileCoveragesTable(files []*gh.PullRequestFile) string {
 	if len(files) == 0 {
 		return ""
 	}
+	if d.ReportB.IsFilesTruncated() {
+		// The file coverages of the previous report other than the worst covered ones are not available to compare.
+		return d.ReportA.FileCoveragesTable(files)
+	}
 	var t, c, pt, pc int
 	exist := false
-	var rows [][]string
+	var (
+		rows   [][]string
+		covers []float64
+	)
 	for _, f := range files {
 		fc, err := d.Coverage.Files.FuzzyFindByFile(f.Filename)
 		if err != nil {
@@ -279,6 +401,7 @@ func (d *DiffReport) FileCoveragesTable(files []*gh.PullRequestFile) string {
 			pt += fc.FileCoverageB.Total
 		}
 		rows = append(rows, []string{fmt.Sprintf("[%s](%s)", f.Filename, f.BlobURL), fmt.Sprintf("%.1f%%", fc.A), diff})
+		covers = append(covers, fc.A)
 	}
 	if !exist {
 		return ""
@@ -296,6 +419,8 @@ func (d *DiffReport) FileCoveragesTable(files []*gh.PullRequestFile) string {
 	buf := new(bytes.Buffer)
 	buf.WriteString(fmt.Sprintf("%s\n\n", title))
 
+	rows, more := worstFileRows(rows, covers, d.ReportA.filesTableMax())
+
 	if len(rows) > filesSkipMax {
 		buf.WriteString(fmt.Sprintf("Skip file coverages because there are too many files (%d)\n", len(rows)))
 		return buf.String()
@@ -316,6 +441,9 @@ func (d *DiffReport) FileCoveragesTable(files []*gh.PullRequestFile) string {
 		table.Append(v)
 	}
 	table.Render()
+	if more > 0 {
+		buf.WriteString(fmt.Sprintf("\n+%d more files\n", more))
+	}
 
 	if len(rows) > filesHideMin {
 		buf.WriteString("\n</details>\n")
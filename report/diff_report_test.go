@@ -2,10 +2,15 @@ package report
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/octocov/coverage"
+	"github.com/k1LoW/octocov/gh"
 	"github.com/tenntenn/golden"
 )
 
@@ -54,3 +59,73 @@ func TestDiffTable(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestRegressedFiles(t *testing.T) {
+	fc := func(file string, total, covered int) *coverage.FileCoverage {
+		f := coverage.NewFileCoverage(file, coverage.TypeLOC)
+		f.Total = total
+		f.Covered = covered
+		return f
+	}
+	newReport := func(files ...*coverage.FileCoverage) *Report {
+		cov := coverage.New()
+		for _, f := range files {
+			cov.Total += f.Total
+			cov.Covered += f.Covered
+			cov.Files = append(cov.Files, f)
+		}
+		return &Report{Coverage: cov}
+	}
+	a := newReport(fc("a.go", 10, 5), fc("b.go", 10, 8), fc("c.go", 10, 10), fc("new.go", 10, 0))
+	b := newReport(fc("a.go", 10, 10), fc("b.go", 10, 9), fc("c.go", 10, 10), fc("deleted.go", 10, 10))
+	d := a.Compare(b)
+
+	var got []string
+	for _, f := range d.RegressedFiles() {
+		got = append(got, f.File)
+	}
+	want := []string{"a.go", "b.go"}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+
+	buf := new(bytes.Buffer)
+	d.OutRegressedFiles(buf)
+	for _, w := range []string{"Regressed Files", "a.go", "-50.0%", "b.go", "-10.0%"} {
+		if !strings.Contains(buf.String(), w) {
+			t.Errorf("got %v\nwant containing %q", buf.String(), w)
+		}
+	}
+}
+
+func TestDiffFileCoveragesTableWithTruncatedPrev(t *testing.T) {
+	newReport := func(covered ...int) *Report {
+		cov := coverage.New()
+		for i, c := range covered {
+			f := coverage.NewFileCoverage(fmt.Sprintf("%c.go", 'a'+i), coverage.TypeLOC)
+			f.Total = 10
+			f.Covered = c
+			cov.Total += f.Total
+			cov.Covered += f.Covered
+			cov.Files = append(cov.Files, f)
+		}
+		return &Report{Coverage: cov}
+	}
+	files := []*gh.PullRequestFile{
+		{Filename: "a.go", BlobURL: "https://github.com/owner/repo/blob/xxx/a.go"},
+		{Filename: "b.go", BlobURL: "https://github.com/owner/repo/blob/xxx/b.go"},
+	}
+	a := newReport(9, 9)
+	b := newReport(8, 2)
+
+	got := a.Compare(b).FileCoveragesTable(files)
+	if !strings.Contains(got, "+10.0%") {
+		t.Errorf("got\n%v\nwant the diff of a.go", got)
+	}
+
+	// a.go is aggregated into OtherFiles of the truncated previous report, so it is not compared
+	got = a.Compare(b.TruncateFiles(1)).FileCoveragesTable(files)
+	if want := a.FileCoveragesTable(files); got != want {
+		t.Errorf("got\n%v\nwant\n%v", got, want)
+	}
+}
@@ -0,0 +1,263 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/k1LoW/octocov/coverage"
+	"github.com/k1LoW/octocov/ratio"
+)
+
+//go:embed html_index.html.tmpl
+var htmlIndexTmpl []byte
+
+//go:embed html_file.html.tmpl
+var htmlFileTmpl []byte
+
+//go:embed html_summary.html.tmpl
+var htmlSummaryTmpl []byte
+
+const htmlFilesDir = "files"
+
+var htmlColorRe = regexp.MustCompile(`^#[0-9A-Fa-f]{3,8}$`)
+
+var htmlFilenameRep = strings.NewReplacer("/", "_", "\\", "_", ":", "_")
+
+type htmlDir struct {
+	Name    string
+	Total   int
+	Covered int
+	Files   []*htmlFile
+}
+
+type htmlFile struct {
+	Name    string
+	File    string
+	Href    string
+	Total   int
+	Covered int
+}
+
+type htmlSummaryFile struct {
+	File    string
+	Total   int
+	Covered int
+	Percent float64
+	Color   template.CSS
+}
+
+type htmlSummaryRatio struct {
+	Value  float64
+	Code   int
+	Test   int
+	Groups []*ratio.Group
+}
+
+type htmlLine struct {
+	Number int
+	Text   string
+	Count  int
+	Class  string
+}
+
+// WriteHTML writes a static HTML coverage report browsable per file into dir, and returns the paths of the written files.
+// Source files are looked up under root; files without sources are listed without line highlighting.
+func (r *Report) WriteHTML(dir, root string) ([]string, error) {
+	if r.Coverage == nil {
+		return nil, errors.New("coverage is not measured")
+	}
+	if err := os.MkdirAll(filepath.Join(dir, htmlFilesDir), 0755); err != nil {
+		return nil, err
+	}
+	var (
+		written []string
+		dirs    []*htmlDir
+	)
+	fcs := make(coverage.FileCoverages, len(r.Coverage.Files))
+	copy(fcs, r.Coverage.Files)
+	sort.SliceStable(fcs, func(i, j int) bool {
+		return fcs[i].File < fcs[j].File
+	})
+	used := map[string]int{}
+	ftmpl := template.Must(template.New("file").Funcs(htmlFuncs()).Parse(string(htmlFileTmpl)))
+	for _, fc := range fcs {
+		name := htmlFilenameRep.Replace(strings.TrimLeft(filepath.ToSlash(fc.File), "/"))
+		if n, ok := used[name]; ok {
+			used[name] = n + 1
+			name = fmt.Sprintf("%s-%d", name, n+1)
+		} else {
+			used[name] = 0
+		}
+		href := path.Join(htmlFilesDir, fmt.Sprintf("%s.html", name))
+		lines, err := htmlLines(root, fc)
+		if err != nil {
+			return nil, err
+		}
+		buf := new(bytes.Buffer)
+		if err := ftmpl.Execute(buf, map[string]any{
+			"Title":   r.Title(),
+			"File":    fc.File,
+			"Total":   fc.Total,
+			"Covered": fc.Covered,
+			"Lines":   lines,
+		}); err != nil {
+			return nil, err
+		}
+		p := filepath.Join(dir, filepath.FromSlash(href))
+		if err := os.WriteFile(p, buf.Bytes(), 0600); err != nil {
+			return nil, err
+		}
+		written = append(written, p)
+
+		d := path.Dir(filepath.ToSlash(fc.File))
+		if len(dirs) == 0 || dirs[len(dirs)-1].Name != d {
+			dirs = append(dirs, &htmlDir{Name: d})
+		}
+		hd := dirs[len(dirs)-1]
+		hd.Total += fc.Total
+		hd.Covered += fc.Covered
+		hd.Files = append(hd.Files, &htmlFile{
+			Name:    path.Base(filepath.ToSlash(fc.File)),
+			File:    fc.File,
+			Href:    href,
+			Total:   fc.Total,
+			Covered: fc.Covered,
+		})
+	}
+	itmpl := template.Must(template.New("index").Funcs(htmlFuncs()).Parse(string(htmlIndexTmpl)))
+	buf := new(bytes.Buffer)
+	if err := itmpl.Execute(buf, map[string]any{
+		"Title":   r.Title(),
+		"Total":   r.Coverage.Total,
+		"Covered": r.Coverage.Covered,
+		"Dirs":    dirs,
+	}); err != nil {
+		return nil, err
+	}
+	p := filepath.Join(dir, "index.html")
+	if err := os.WriteFile(p, buf.Bytes(), 0600); err != nil {
+		return nil, err
+	}
+	written = append(written, p)
+	return written, nil
+}
+
+// SummaryHTML returns a self-contained HTML page summarizing the report (the code coverage with the coverage of each file and the code to test ratio).
+// color returns the color of the bar of the code coverage (e.g. (*config.Config).CoverageColor).
+func (r *Report) SummaryHTML(color func(cover float64) string) ([]byte, error) {
+	if r.Coverage == nil {
+		return nil, errors.New("coverage is not measured")
+	}
+	fcs := make(coverage.FileCoverages, len(r.Coverage.Files))
+	copy(fcs, r.Coverage.Files)
+	sort.SliceStable(fcs, func(i, j int) bool {
+		return fcs[i].File < fcs[j].File
+	})
+	var files []*htmlSummaryFile
+	for _, fc := range fcs {
+		p := htmlPercent(fc.Covered, fc.Total)
+		files = append(files, &htmlSummaryFile{
+			File:    fc.File,
+			Total:   fc.Total,
+			Covered: fc.Covered,
+			Percent: p,
+			Color:   htmlColor(color(p)),
+		})
+	}
+	var rt *htmlSummaryRatio
+	if r.IsMeasuredCodeToTestRatio() {
+		rt = &htmlSummaryRatio{
+			Value:  r.CodeToTestRatioRatio(),
+			Code:   r.CodeToTestRatio.Code,
+			Test:   r.CodeToTestRatio.Test,
+			Groups: r.CodeToTestRatio.NamedGroups(),
+		}
+	}
+	cp := htmlPercent(r.Coverage.Covered, r.Coverage.Total)
+	tmpl := template.Must(template.New("summary").Funcs(htmlFuncs()).Parse(string(htmlSummaryTmpl)))
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, map[string]any{
+		"Title":   r.Title(),
+		"Total":   r.Coverage.Total,
+		"Covered": r.Coverage.Covered,
+		"Percent": cp,
+		"Color":   htmlColor(color(cp)),
+		"Files":   files,
+		"Ratio":   rt,
+	}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func htmlPercent(covered, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(covered) / float64(total) * 100
+}
+
+// htmlColor returns the color as CSS only if it is a hex RGB color, because html/template does not trust the value in the style attribute.
+func htmlColor(c string) template.CSS {
+	if !htmlColorRe.MatchString(c) {
+		return ""
+	}
+	return template.CSS(c) // #nosec
+}
+
+func htmlLines(root string, fc *coverage.FileCoverage) ([]*htmlLine, error) {
+	sp, ok := coverage.FindSourceFile(root, fc.File)
+	if !ok {
+		return nil, nil
+	}
+	b, err := os.ReadFile(filepath.Clean(sp))
+	if err != nil {
+		return nil, err
+	}
+	lcs := fc.Blocks.ToLineCoverages()
+	var lines []*htmlLine
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(b)+1)
+	n := 1
+	for scanner.Scan() {
+		l := &htmlLine{
+			Number: n,
+			Text:   scanner.Text(),
+		}
+		if lc, err := lcs.FindByLine(n); err == nil {
+			l.Count = lc.Count
+			if lc.Count > 0 {
+				l.Class = "covered"
+			} else {
+				l.Class = "uncovered"
+			}
+		}
+		lines = append(lines, l)
+		n += 1
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+func htmlFuncs() template.FuncMap {
+	return template.FuncMap{
+		"percent": func(covered, total int) string {
+			if total == 0 {
+				return "-"
+			}
+			return fmt.Sprintf("%.1f%%", float64(covered)/float64(total)*100)
+		},
+	}
+}
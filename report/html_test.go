@@ -0,0 +1,151 @@
+package report
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/k1LoW/octocov/ratio"
+)
+
+func TestWriteHTML(t *testing.T) {
+	log.SetOutput(io.Discard) // Disable log in challengeParseReport()
+
+	root := t.TempDir()
+	src := "package main\n\nfunc main() {\n\tprintln(\"<covered>\")\n}\n\nfunc unused() {\n\tprintln(\"uncovered\")\n}\n"
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	profile := "mode: count\nexample.com/app/main.go:3.13,5.2 1 2\nexample.com/app/main.go:7.15,9.2 1 0\nexample.com/app/missing.go:1.1,2.2 1 1\n"
+	pp := filepath.Join(root, "coverage.out")
+	if err := os.WriteFile(pp, []byte(profile), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r := &Report{}
+	if err := r.MeasureCoverage([]string{pp}, nil); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(t.TempDir(), "html")
+	written, err := r.WriteHTML(dir, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(written), len(r.Coverage.Files)+1; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := string(b)
+	for _, want := range []string{`href="files/example.com_app_main.go.html"`, `href="files/example.com_app_missing.go.html"`, "example.com/app/"} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index.html does not contain %q", want)
+		}
+	}
+
+	b, err = os.ReadFile(filepath.Join(dir, "files", "example.com_app_main.go.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := string(b)
+	for _, want := range []string{`<tr class="covered"><td class="num">4</td>`, `<tr class="uncovered"><td class="num">8</td>`, "&lt;covered&gt;"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("main.go page does not contain %q", want)
+		}
+	}
+
+	b, err = os.ReadFile(filepath.Join(dir, "files", "example.com_app_missing.go.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "Source file is not found.") {
+		t.Error("missing.go page should note that the source file is not found")
+	}
+}
+
+func TestWriteHTMLWithoutCoverage(t *testing.T) {
+	r := &Report{}
+	if _, err := r.WriteHTML(t.TempDir(), t.TempDir()); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestSummaryHTML(t *testing.T) {
+	log.SetOutput(io.Discard) // Disable log in challengeParseReport()
+
+	root := t.TempDir()
+	profile := "mode: count\nexample.com/app/main.go:3.13,5.2 1 2\nexample.com/app/main.go:7.15,9.2 1 0\nexample.com/app/sub/<sub>.go:1.1,2.2 1 1\n"
+	pp := filepath.Join(root, "coverage.out")
+	if err := os.WriteFile(pp, []byte(profile), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r := &Report{}
+	if err := r.MeasureCoverage([]string{pp}, nil); err != nil {
+		t.Fatal(err)
+	}
+	r.CodeToTestRatio = &ratio.Ratio{
+		Code: 100,
+		Test: 50,
+		CodeFiles: ratio.Files{
+			{Path: "main.go", Code: 100, Group: "app"},
+		},
+		TestFiles: ratio.Files{
+			{Path: "main_test.go", Code: 50, Group: "app"},
+		},
+	}
+	color := func(cover float64) string {
+		if cover >= 80 {
+			return "#97CA00"
+		}
+		return "#E05D44"
+	}
+	b, err := r.SummaryHTML(color)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(b)
+	for _, want := range []string{
+		"66.7% (2/3)",
+		`<div style="width: 66.7%; background: #E05D44;">`,
+		`<tr class="file"><td>example.com/app/main.go</td><td class="num">50.0%</td><td><div class="bar"><div style="width: 50.0%; background: #E05D44;"></div></div></td>`,
+		`<tr class="file"><td>example.com/app/sub/&lt;sub&gt;.go</td><td class="num">100.0%</td><td><div class="bar"><div style="width: 100.0%; background: #97CA00;"></div></div></td>`,
+		"1:0.5 (Code: 100 / Test: 50)",
+		`<tr class="group"><td>app</td><td class="num">1:0.5</td>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary does not contain %q\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "<link") || strings.Contains(got, "<script") {
+		t.Error("summary should be self-contained")
+	}
+}
+
+func TestSummaryHTMLWithoutCoverage(t *testing.T) {
+	r := &Report{}
+	if _, err := r.SummaryHTML(func(float64) string { return "#97CA00" }); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestHTMLColor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"#97CA00", "#97CA00"},
+		{"#fff", "#fff"},
+		{"red; background-image: url(x)", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := string(htmlColor(tt.in)); got != tt.want {
+			t.Errorf("got %v\nwant %v", got, tt.want)
+		}
+	}
+}
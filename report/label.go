@@ -0,0 +1,127 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/k1LoW/octocov/coverage"
+	"github.com/olekukonko/tablewriter"
+)
+
+// CoveragePercentOf returns the code coverage of files matching the pattern.
+func (r *Report) CoveragePercentOf(pattern string) float64 {
+	if r == nil || r.Coverage == nil {
+		return 0.0
+	}
+	lc, err := r.Coverage.CoverageOfLabel("", pattern)
+	if err != nil {
+		return 0.0
+	}
+	return lc.Percent()
+}
+
+// FileCoveragePercentsOf returns the code coverage of each file matching any of the patterns.
+func (r *Report) FileCoveragePercentsOf(patterns []string) map[string]float64 {
+	percents := map[string]float64{}
+	if r == nil || r.Coverage == nil {
+		return percents
+	}
+	files, err := r.Coverage.FilesOf(patterns)
+	if err != nil {
+		return percents
+	}
+	for _, f := range files {
+		p := 0.0
+		if f.Total > 0 {
+			p = float64(f.Covered) / float64(f.Total) * 100
+		}
+		percents[f.File] = p
+	}
+	return percents
+}
+
+// LabelCoverages returns the code coverages of coverage labels.
+func (r *Report) LabelCoverages() (coverage.LabelCoverages, error) {
+	if r.Coverage == nil || r.opts == nil || len(r.opts.CoverageLabels) == 0 {
+		return nil, nil
+	}
+	var labels []string
+	for l := range r.opts.CoverageLabels {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+	var lcs coverage.LabelCoverages
+	for _, l := range labels {
+		lc, err := r.Coverage.CoverageOfLabel(l, r.opts.CoverageLabels[l])
+		if err != nil {
+			return nil, err
+		}
+		lcs = append(lcs, lc)
+	}
+	return lcs, nil
+}
+
+// LabelCoveragesTable returns the Markdown table of code coverages of coverage labels.
+func (r *Report) LabelCoveragesTable(rPrev *Report) string {
+	lcs, err := r.LabelCoverages()
+	if err != nil || len(lcs) == 0 {
+		return ""
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteString("### Code coverage by label\n\n")
+	table := tablewriter.NewWriter(buf)
+	h := []string{"Label", "Coverage"}
+	if rPrev != nil {
+		h = append(h, "+/-")
+	}
+	table.SetHeader(h)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	for _, lc := range lcs {
+		row := []string{lc.Label, fmt.Sprintf("%.1f%%", lc.Percent())}
+		if rPrev != nil {
+			diff := lc.Percent() - rPrev.CoveragePercentOf(r.opts.CoverageLabels[lc.Label])
+			ds := fmt.Sprintf("%.1f%%", diff)
+			if diff > 0 {
+				ds = fmt.Sprintf("+%.1f%%", diff)
+			}
+			row = append(row, ds)
+		}
+		table.Append(row)
+	}
+	table.Render()
+	return strings.Replace(strings.Replace(buf.String(), "---|", "--:|", len(h)), "--:|", "---|", 1)
+}
+
+func (r *Report) outLabelCoverages(w io.Writer) error {
+	lcs, err := r.LabelCoverages()
+	if err != nil {
+		return err
+	}
+	if len(lcs) == 0 {
+		return nil
+	}
+	if _, err := w.Write([]byte("\n")); err != nil {
+		return err
+	}
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Label", "Coverage"})
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("-")
+	table.SetHeaderLine(true)
+	table.SetBorder(false)
+	table.SetColumnAlignment([]int{tablewriter.ALIGN_LEFT, tablewriter.ALIGN_RIGHT})
+	for _, lc := range lcs {
+		table.Rich([]string{lc.Label, fmt.Sprintf("%.1f%%", lc.Percent())}, []tablewriter.Colors{tablewriter.Colors{tablewriter.Bold}, tablewriter.Colors{}})
+	}
+	table.Render()
+	return nil
+}
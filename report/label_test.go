@@ -0,0 +1,62 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/k1LoW/octocov/coverage"
+)
+
+func TestLabelCoveragesTable(t *testing.T) {
+	r := &Report{
+		Coverage: &coverage.Coverage{
+			Files: coverage.FileCoverages{
+				&coverage.FileCoverage{File: "services/api/a.go", Total: 10, Covered: 5},
+				&coverage.FileCoverage{File: "web/b.ts", Total: 4, Covered: 3},
+			},
+		},
+		opts: &Options{
+			CoverageLabels: map[string]string{
+				"web": "web/**",
+				"api": "services/api/**",
+			},
+		},
+	}
+	rPrev := &Report{
+		Coverage: &coverage.Coverage{
+			Files: coverage.FileCoverages{
+				&coverage.FileCoverage{File: "services/api/a.go", Total: 10, Covered: 4},
+				&coverage.FileCoverage{File: "web/b.ts", Total: 4, Covered: 3},
+			},
+		},
+	}
+	tests := []struct {
+		rPrev *Report
+		want  string
+	}{
+		{
+			nil,
+			`### Code coverage by label
+
+| Label | Coverage |
+|-------|---------:|
+| api   | 50.0%    |
+| web   | 75.0%    |
+`,
+		},
+		{
+			rPrev,
+			`### Code coverage by label
+
+| Label | Coverage |  +/-   |
+|-------|---------:|-------:|
+| api   | 50.0%    | +10.0% |
+| web   | 75.0%    | 0.0%   |
+`,
+		},
+	}
+	for _, tt := range tests {
+		if got := r.LabelCoveragesTable(tt.rPrev); got != tt.want {
+			t.Errorf("got\n%v\nwant\n%v", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/k1LoW/octocov/config"
+	"github.com/k1LoW/octocov/ratio"
+)
+
+// Measure measures the code metrics set in the config and returns the validated report.
+// c has to be loaded and built ( (*config.Config).Load and (*config.Config).Build ).
+// The metrics that can not be measured are skipped with a message to stderr, and it returns an error if nothing could be measured.
+// It does not access GitHub unless testExecutionTime.steps: is used, so the report can be evaluated with (*config.Config).Acceptable in any Go program.
+// If coverage: is a list of named coverage configs, measure each of (*config.Config).CoverageConfigs with MeasureSharing instead.
+func Measure(ctx context.Context, c *config.Config) (*Report, error) {
+	if len(c.Coverages) > 0 {
+		return nil, errors.New("coverage: is a list of named coverage configs, measure each of the coverage configs")
+	}
+	return MeasureSharing(ctx, c, nil)
+}
+
+// MeasureSharing measures the code metrics like Measure, but takes over the code metrics that do not depend on the coverage config
+// (code to test ratio, test execution time and doc coverage) from shared if it is not nil, instead of measuring them again.
+// It is used to measure these code metrics once for the named coverage configs of coverage: given as a list.
+func MeasureSharing(ctx context.Context, c *config.Config, shared *Report) (*Report, error) {
+	r, err := New(c.Repository, Locale(c.Locale), CoverageLabels(c.CoverageLabelPaths()), CaseInsensitive(c.Coverage.CaseInsensitive), CoverageCacheDir(c.Coverage.CacheDir), ParserCommand(c.CoverageParserCommand()), CoverageFormat(c.Coverage.Format), CoveragePattern(c.Coverage.Pattern), FilesTableMax(c.CoverageReportFiles()), UncoveredFiles(c.Coverage.AcceptableMaxUncoveredFiles != nil))
+	if err != nil {
+		return nil, err
+	}
+	if c.Report != nil {
+		switch c.Report.Timestamp {
+		case "", config.ReportTimestampNow:
+		case config.ReportTimestampCommit:
+			if err := r.UseCommitTimestamp(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("invalid report.timestamp: %s", c.Report.Timestamp)
+		}
+	}
+
+	if err := c.CoverageConfigReady(); err != nil {
+		skipf("Skip measuring code coverage: %v\n", err)
+	} else {
+		if err := r.MeasureCoverage(c.Coverage.Paths, c.Coverage.Exclude); err != nil {
+			if c.Coverage.Required {
+				return nil, fmt.Errorf("failed to measure code coverage (coverage.required: true): %w", err)
+			}
+			skipf("Skip measuring code coverage: %v\n", err)
+		} else if c.Coverage.Required && r.Coverage != nil && r.Coverage.Total == 0 {
+			return nil, fmt.Errorf("the coverage report has no lines to be covered (coverage.required: true): %s", strings.Join(c.Coverage.Paths, ", "))
+		} else if err := r.verifyCoverageSource(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if shared != nil {
+		r.TakeOverSharedMetrics(shared)
+	} else {
+		r.measureSharedMetrics(ctx, c)
+	}
+
+	if err := r.CollectCustomMetrics(); err != nil {
+		skipf("Skip collecting custom metrics: %v\n", err)
+	}
+
+	if r.CountMeasured() == 0 {
+		return nil, errors.New("nothing could be measured")
+	}
+
+	if err := r.Validate(); err != nil {
+		return nil, fmt.Errorf("validation error: %w", err)
+	}
+	return r, nil
+}
+
+// measureSharedMetrics measures the code metrics that do not depend on the coverage config.
+func (r *Report) measureSharedMetrics(ctx context.Context, c *config.Config) {
+	if err := c.CodeToTestRatioConfigReady(); err != nil {
+		skipf("Skip measuring code to test ratio: %v\n", err)
+	} else {
+		if err := r.MeasureCodeToTestRatioByConfig(c); err != nil {
+			skipf("Skip measuring code to test ratio: %v\n", err)
+		}
+	}
+
+	if err := c.TestExecutionTimeConfigReady(); err != nil {
+		skipf("Skip measuring test execution time: %v\n", err)
+	} else {
+		if err := r.MeasureTestExecutionTimeByConfig(ctx, c); err != nil {
+			skipf("Skip measuring test execution time: %v\n", err)
+		}
+	}
+
+	if err := c.DocCoverageConfigReady(); err != nil {
+		skipf("Skip measuring doc coverage: %v\n", err)
+	} else {
+		if err := r.MeasureDocCoverage(c.Root(), c.DocCoverage.Include, c.DocCoverage.Exclude); err != nil {
+			skipf("Skip measuring doc coverage: %v\n", err)
+		}
+	}
+}
+
+// TakeOverSharedMetrics takes over the code metrics that do not depend on the coverage config from shared.
+func (r *Report) TakeOverSharedMetrics(shared *Report) {
+	r.CodeToTestRatio = shared.CodeToTestRatio
+	r.TestExecutionTime = shared.TestExecutionTime
+	r.DocCoverage = shared.DocCoverage
+}
+
+// MeasureCodeToTestRatioByConfig measures the code to test ratio of codeToTestRatio.groups: if set, otherwise of codeToTestRatio.code: and codeToTestRatio.test:.
+func (r *Report) MeasureCodeToTestRatioByConfig(c *config.Config) error {
+	if len(c.CodeToTestRatio.Groups) == 0 {
+		return r.MeasureCodeToTestRatio(c.Root(), c.CodeToTestRatio.Code, c.CodeToTestRatio.Test)
+	}
+	var patterns []*ratio.Pattern
+	for _, g := range c.CodeToTestRatio.Groups {
+		patterns = append(patterns, &ratio.Pattern{Name: g.Name, Code: g.Code, Test: g.Test})
+	}
+	return r.MeasureCodeToTestRatioGroups(c.Root(), patterns)
+}
+
+// MeasureTestExecutionTimeByConfig measures the test execution time from the output of `go test -json` if testExecutionTime.goTestJSON: is set, otherwise from the steps of the GitHub Actions job.
+func (r *Report) MeasureTestExecutionTimeByConfig(ctx context.Context, c *config.Config) error {
+	if c.TestExecutionTime.GoTestJSON != "" {
+		return r.MeasureTestExecutionTimeFromGoTestJSON(c.TestExecutionTime.GoTestJSON)
+	}
+	return r.MeasureTestExecutionTime(ctx, c.TestExecutionTime.Steps)
+}
+
+func (r *Report) verifyCoverageSource(c *config.Config) error {
+	switch c.Coverage.VerifySource {
+	case "", config.VerifySourceOff:
+		return nil
+	case config.VerifySourceWarn, config.VerifySourceError:
+	default:
+		return fmt.Errorf("invalid coverage.verifySource: %s", c.Coverage.VerifySource)
+	}
+	if r.Coverage == nil {
+		return nil
+	}
+	mismatches, err := r.Coverage.VerifySource(c.Root())
+	if err != nil {
+		return err
+	}
+	if len(mismatches) == 0 {
+		return nil
+	}
+	for _, m := range mismatches {
+		skipf("Coverage report does not match the source: %s\n", m)
+	}
+	if c.Coverage.VerifySource == config.VerifySourceError {
+		return fmt.Errorf("coverage report does not match the source (%d files). the coverage report may be stale", len(mismatches))
+	}
+	return nil
+}
+
+func skipf(format string, a ...any) {
+	_, _ = fmt.Fprintf(os.Stderr, format, a...) //nostyle:handlerrors
+}
@@ -0,0 +1,118 @@
+package report
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/k1LoW/octocov/config"
+)
+
+func TestMeasure(t *testing.T) {
+	log.SetOutput(io.Discard) // Disable log in challengeParseReport()
+
+	tests := []struct {
+		name           string
+		acceptable     string
+		wantCoverage   float64
+		wantAcceptable bool
+	}{
+		{"acceptable", "current >= 60%", 66.7, true},
+		{"not acceptable", "current >= 70%", 66.7, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			profile := "mode: count\nexample.com/app/main.go:1.1,2.2 1 1\nexample.com/app/main.go:3.1,4.2 1 1\nexample.com/app/main.go:5.1,6.2 1 0\n"
+			if err := os.WriteFile(filepath.Join(dir, "coverage.out"), []byte(profile), 0600); err != nil {
+				t.Fatal(err)
+			}
+			yml := "repository: owner/repo\ncoverage:\n  paths:\n    - coverage.out\n  acceptable: " + tt.acceptable + "\n"
+			p := filepath.Join(dir, ".octocov.yml")
+			if err := os.WriteFile(p, []byte(yml), 0600); err != nil {
+				t.Fatal(err)
+			}
+			c := config.New()
+			if err := c.Load(p); err != nil {
+				t.Fatal(err)
+			}
+			c.Build()
+			r, err := Measure(context.Background(), c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := float64(int(r.CoveragePercent()*10+0.5)) / 10; got != tt.wantCoverage {
+				t.Errorf("got %v\nwant %v", got, tt.wantCoverage)
+			}
+			if err := c.Acceptable(context.Background(), r, (*Report)(nil)); (err == nil) != tt.wantAcceptable {
+				t.Errorf("got %v\nwant acceptable %v", err, tt.wantAcceptable)
+			}
+		})
+	}
+}
+
+func TestMeasureNothing(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, ".octocov.yml")
+	if err := os.WriteFile(p, []byte("repository: owner/repo\ncoverage:\n  paths:\n    - missing.out\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := config.New()
+	if err := c.Load(p); err != nil {
+		t.Fatal(err)
+	}
+	c.Build()
+	if _, err := Measure(context.Background(), c); err == nil {
+		t.Error("want error")
+	}
+}
+
+func TestMeasureSharing(t *testing.T) {
+	dir := t.TempDir()
+	profile := "mode: count\nexample.com/app/main.go:1.1,2.2 1 1\n"
+	for _, f := range []string{"unit.out", "integration.out"} {
+		if err := os.WriteFile(filepath.Join(dir, f), []byte(profile), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main_test.go"), []byte("package main\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	yml := "repository: owner/repo\ncoverage:\n  - name: unit\n    paths:\n      - unit.out\n  - name: integration\n    paths:\n      - integration.out\ncodeToTestRatio:\n  code:\n    - '**/*.go'\n    - '!**/*_test.go'\n  test:\n    - '**/*_test.go'\n"
+	p := filepath.Join(dir, ".octocov.yml")
+	if err := os.WriteFile(p, []byte(yml), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := config.New()
+	if err := c.Load(p); err != nil {
+		t.Fatal(err)
+	}
+	c.Build()
+	configs, err := c.CoverageConfigs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var shared *Report
+	for _, cc := range configs {
+		r, err := MeasureSharing(context.Background(), cc, shared)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.CodeToTestRatio == nil {
+			t.Fatalf("%s: got nil\nwant code to test ratio", cc.Coverage.Name)
+		}
+		if shared == nil {
+			shared = r
+			continue
+		}
+		if r.CodeToTestRatio != shared.CodeToTestRatio {
+			t.Errorf("%s: the code to test ratio is measured again", cc.Coverage.Name)
+		}
+	}
+}
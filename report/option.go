@@ -3,7 +3,16 @@ package report
 import "golang.org/x/text/language"
 
 type Options struct {
-	Locale *language.Tag
+	Locale           *language.Tag
+	CoverageLabels   map[string]string
+	CaseInsensitive  bool
+	CoverageCacheDir string
+	ParserCommand    string
+	CoverageFormat   string
+	CoveragePattern  string
+	FilesTableMax    int
+	// UncoveredFiles shows the number of the files with 0% coverage in the report
+	UncoveredFiles bool
 }
 
 type Option func(*Options)
@@ -13,3 +22,59 @@ func Locale(locale *language.Tag) Option {
 		args.Locale = locale
 	}
 }
+
+// CoverageLabels sets the mapping from label to path pattern for per-label coverage.
+func CoverageLabels(labels map[string]string) Option {
+	return func(args *Options) {
+		args.CoverageLabels = labels
+	}
+}
+
+// CaseInsensitive sets whether to match coverage file paths case-insensitively.
+func CaseInsensitive(enable bool) Option {
+	return func(args *Options) {
+		args.CaseInsensitive = enable
+	}
+}
+
+// CoverageCacheDir sets the directory for caching parsed Go coverage per package.
+func CoverageCacheDir(dir string) Option {
+	return func(args *Options) {
+		args.CoverageCacheDir = dir
+	}
+}
+
+// ParserCommand sets the external command to parse coverage reports.
+func ParserCommand(command string) Option {
+	return func(args *Options) {
+		args.ParserCommand = command
+	}
+}
+
+// CoverageFormat sets the format of coverage reports. If it is empty, the format is detected automatically.
+func CoverageFormat(format string) Option {
+	return func(args *Options) {
+		args.CoverageFormat = format
+	}
+}
+
+// CoveragePattern sets the glob pattern of the coverage report files searched recursively in the directories of the coverage report paths.
+func CoveragePattern(pattern string) Option {
+	return func(args *Options) {
+		args.CoveragePattern = pattern
+	}
+}
+
+// FilesTableMax sets the maximum number of rows in the file coverages table. The worst covered files are shown.
+func FilesTableMax(n int) Option {
+	return func(args *Options) {
+		args.FilesTableMax = n
+	}
+}
+
+// UncoveredFiles sets whether to show the number of the files with 0% coverage in the report (coverage.acceptableMaxUncoveredFiles).
+func UncoveredFiles(enable bool) Option {
+	return func(args *Options) {
+		args.UncoveredFiles = enable
+	}
+}
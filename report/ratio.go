@@ -0,0 +1,51 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/k1LoW/octocov/ratio"
+	"github.com/olekukonko/tablewriter"
+)
+
+// CodeToTestRatioGroupsTable returns the Markdown table of code to test ratios grouped by the leading depth directories, sorted by worst ratio.
+func (r *Report) CodeToTestRatioGroupsTable(depth int) string {
+	if !r.IsMeasuredCodeToTestRatio() {
+		return ""
+	}
+	return ratioGroupsTable("Code to test ratio by directory", "Directory", r.CodeToTestRatio.Groups(depth))
+}
+
+// CodeToTestRatioNamedGroupsTable returns the Markdown table of code to test ratios of the named groups (codeToTestRatio.groups), sorted by worst ratio.
+func (r *Report) CodeToTestRatioNamedGroupsTable() string {
+	if !r.IsMeasuredCodeToTestRatio() {
+		return ""
+	}
+	return ratioGroupsTable("Code to test ratio by group", "Group", r.CodeToTestRatio.NamedGroups())
+}
+
+func ratioGroupsTable(title, name string, groups []*ratio.Group) string {
+	if len(groups) == 0 {
+		return ""
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteString(fmt.Sprintf("### %s\n\n", title))
+	table := tablewriter.NewWriter(buf)
+	h := []string{name, "Code", "Test", "Ratio"}
+	table.SetHeader(h)
+	table.SetAutoFormatHeaders(false)
+	table.SetAutoWrapText(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: false, Right: true, Bottom: false})
+	table.SetCenterSeparator("|")
+	for _, g := range groups {
+		ratio := "-"
+		if g.Code > 0 {
+			ratio = fmt.Sprintf("1:%.1f", g.Ratio())
+		}
+		table.Append([]string{g.Name, strconv.Itoa(g.Code), strconv.Itoa(g.Test), ratio})
+	}
+	table.Render()
+	return strings.Replace(strings.Replace(buf.String(), "---|", "--:|", len(h)), "--:|", "---|", 1)
+}
@@ -0,0 +1,72 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/k1LoW/octocov/ratio"
+)
+
+func TestCodeToTestRatioGroupsTable(t *testing.T) {
+	r := &Report{
+		CodeToTestRatio: &ratio.Ratio{
+			Code: 200,
+			Test: 150,
+			CodeFiles: ratio.Files{
+				{Path: "cmd/root.go", Code: 50},
+				{Path: "pkg/a.go", Code: 150},
+			},
+			TestFiles: ratio.Files{
+				{Path: "cmd/root_test.go", Code: 100},
+				{Path: "pkg/a_test.go", Code: 30},
+				{Path: "testdata/helper_test.go", Code: 20},
+			},
+		},
+	}
+	want := `### Code to test ratio by directory
+
+| Directory | Code | Test | Ratio |
+|-----------|-----:|-----:|------:|
+| pkg       |  150 |   30 | 1:0.2 |
+| cmd       |   50 |  100 | 1:2.0 |
+| testdata  |    0 |   20 | -     |
+`
+	if got := r.CodeToTestRatioGroupsTable(1); got != want {
+		t.Errorf("got\n%v\nwant\n%v", got, want)
+	}
+	if got := r.CodeToTestRatioGroupsTable(0); got != "" {
+		t.Errorf("got %v\nwant empty", got)
+	}
+	if got := (&Report{}).CodeToTestRatioGroupsTable(1); got != "" {
+		t.Errorf("got %v\nwant empty", got)
+	}
+}
+
+func TestCodeToTestRatioNamedGroupsTable(t *testing.T) {
+	r := &Report{
+		CodeToTestRatio: &ratio.Ratio{
+			Code: 200,
+			Test: 150,
+			CodeFiles: ratio.Files{
+				{Path: "main.go", Code: 150, Group: "go"},
+				{Path: "web/app.ts", Code: 50, Group: "typescript"},
+			},
+			TestFiles: ratio.Files{
+				{Path: "main_test.go", Code: 120, Group: "go"},
+				{Path: "web/app.test.ts", Code: 30, Group: "typescript"},
+			},
+		},
+	}
+	want := `### Code to test ratio by group
+
+|   Group    | Code | Test | Ratio |
+|------------|-----:|-----:|------:|
+| typescript |   50 |   30 | 1:0.6 |
+| go         |  150 |  120 | 1:0.8 |
+`
+	if got := r.CodeToTestRatioNamedGroupsTable(); got != want {
+		t.Errorf("got\n%v\nwant\n%v", got, want)
+	}
+	if got := (&Report{CodeToTestRatio: &ratio.Ratio{Code: 1, CodeFiles: ratio.Files{{Path: "main.go", Code: 1}}}}).CodeToTestRatioNamedGroupsTable(); got != "" {
+		t.Errorf("got %v\nwant empty", got)
+	}
+}
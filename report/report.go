@@ -6,19 +6,24 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/goccy/go-json"
 	"github.com/hashicorp/go-multierror"
 	"github.com/k1LoW/octocov/config"
 	"github.com/k1LoW/octocov/coverage"
+	"github.com/k1LoW/octocov/doccov"
 	"github.com/k1LoW/octocov/gh"
+	"github.com/k1LoW/octocov/gotest"
 	"github.com/k1LoW/octocov/ratio"
 	"github.com/olekukonko/tablewriter"
 	"github.com/samber/lo"
@@ -34,14 +39,19 @@ var (
 )
 
 type Report struct {
-	Repository        string             `json:"repository"`
-	Ref               string             `json:"ref"`
-	Commit            string             `json:"commit"`
-	Coverage          *coverage.Coverage `json:"coverage,omitempty"`
-	CodeToTestRatio   *ratio.Ratio       `json:"code_to_test_ratio,omitempty"`
-	TestExecutionTime *float64           `json:"test_execution_time,omitempty"`
-	Timestamp         time.Time          `json:"timestamp"`
-	CustomMetrics     []*CustomMetricSet `json:"custom_metrics,omitempty"`
+	Repository        string              `json:"repository"`
+	Ref               string              `json:"ref"`
+	Commit            string              `json:"commit"`
+	Coverage          *coverage.Coverage  `json:"coverage,omitempty"`
+	CodeToTestRatio   *ratio.Ratio        `json:"code_to_test_ratio,omitempty"`
+	TestExecutionTime *float64            `json:"test_execution_time,omitempty"`
+	DocCoverage       *doccov.DocCoverage `json:"doc_coverage,omitempty"`
+	Timestamp         time.Time           `json:"timestamp"`
+	CustomMetrics     []*CustomMetricSet  `json:"custom_metrics,omitempty"`
+	// PatchCoverage is the code coverage of the lines added by the pull request
+	PatchCoverage *coverage.PatchCoverage `json:"patch_coverage,omitempty"`
+	// CoverageHighWaterMark is the highest code coverage ever recorded up to the report (coverage.acceptable.ratchet)
+	CoverageHighWaterMark *float64 `json:"coverage_high_water_mark,omitempty"`
 
 	// coverage report paths
 	covPaths []string
@@ -82,6 +92,25 @@ func New(ownerrepo string, opts ...Option) (*Report, error) {
 	}, nil
 }
 
+// UseCommitTimestamp sets the timestamp of the report to the committer date of the commit of the report,
+// so that re-running on the same commit produces the same report timestamp.
+func (r *Report) UseCommitTimestamp() error {
+	if r.Commit == "" {
+		return fmt.Errorf("coverage report %q (env %s) is not set", "commit", "GITHUB_SHA")
+	}
+	cmd := exec.Command("git", "show", "-s", "--format=%ct", r.Commit) //#nosec G204
+	b, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("failed to get the timestamp of commit %s: %w", r.Commit, err)
+	}
+	sec, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to get the timestamp of commit %s: %w", r.Commit, err)
+	}
+	r.Timestamp = time.Unix(sec, 0).UTC()
+	return nil
+}
+
 func (r *Report) Title() string {
 	key := r.Key()
 	if key == "" {
@@ -101,6 +130,20 @@ func (r *Report) Key() string {
 	return strings.TrimPrefix(r.Repository, fmt.Sprintf("%s/", repo))
 }
 
+// TruncateFiles returns a shallow copy of the report whose coverage keeps the file coverages of only the n worst covered files.
+func (r *Report) TruncateFiles(n int) *Report {
+	tr := *r
+	if r.Coverage != nil {
+		tr.Coverage = r.Coverage.TruncateFiles(n)
+	}
+	return &tr
+}
+
+// IsFilesTruncated returns true if the file coverages of the report are truncated by TruncateFiles (report.maxFiles).
+func (r *Report) IsFilesTruncated() bool {
+	return r != nil && r.Coverage.IsTruncated()
+}
+
 func (r *Report) String() string {
 	return string(r.Bytes())
 }
@@ -122,6 +165,14 @@ func (r *Report) Table() string {
 		h = append(h, "Coverage")
 		m = append(m, fmt.Sprintf("%.1f%%", r.CoveragePercent()))
 	}
+	if r.IsMeasuredUncoveredFuncs() {
+		h = append(h, "Uncovered Functions")
+		m = append(m, fmt.Sprintf("%d", r.UncoveredFuncs()))
+	}
+	if r.IsMeasuredUncoveredFiles() {
+		h = append(h, "Uncovered Files")
+		m = append(m, fmt.Sprintf("%d", len(r.UncoveredFiles())))
+	}
 	if r.IsMeasuredCodeToTestRatio() {
 		h = append(h, "Code to Test Ratio")
 		m = append(m, fmt.Sprintf("1:%.1f", r.CodeToTestRatioRatio()))
@@ -131,6 +182,10 @@ func (r *Report) Table() string {
 		d := time.Duration(r.TestExecutionTimeNano())
 		m = append(m, d.String())
 	}
+	if r.IsMeasuredDocCoverage() {
+		h = append(h, "Doc Coverage")
+		m = append(m, fmt.Sprintf("%.1f%%", r.DocCoveragePercent()))
+	}
 	buf := new(bytes.Buffer)
 	table := tablewriter.NewWriter(buf)
 	table.SetHeader(h)
@@ -159,6 +214,14 @@ func (r *Report) Out(w io.Writer) error {
 		table.Rich([]string{"Coverage", fmt.Sprintf("%.1f%%", r.CoveragePercent())}, []tablewriter.Colors{tablewriter.Colors{tablewriter.Bold}, tablewriter.Colors{}})
 	}
 
+	if r.IsMeasuredUncoveredFuncs() {
+		table.Rich([]string{"Uncovered Functions", fmt.Sprintf("%d", r.UncoveredFuncs())}, []tablewriter.Colors{tablewriter.Colors{tablewriter.Bold}, tablewriter.Colors{}})
+	}
+
+	if r.IsMeasuredUncoveredFiles() {
+		table.Rich([]string{"Uncovered Files", fmt.Sprintf("%d", len(r.UncoveredFiles()))}, []tablewriter.Colors{tablewriter.Colors{tablewriter.Bold}, tablewriter.Colors{}})
+	}
+
 	if r.IsMeasuredCodeToTestRatio() {
 		table.Rich([]string{"Code to Test Ratio", fmt.Sprintf("1:%.1f", r.CodeToTestRatioRatio())}, []tablewriter.Colors{tablewriter.Colors{tablewriter.Bold}, tablewriter.Colors{}})
 	}
@@ -167,8 +230,16 @@ func (r *Report) Out(w io.Writer) error {
 		table.Rich([]string{"Test Execution Time", time.Duration(*r.TestExecutionTime).String()}, []tablewriter.Colors{tablewriter.Colors{tablewriter.Bold}, tablewriter.Colors{}})
 	}
 
+	if r.IsMeasuredDocCoverage() {
+		table.Rich([]string{"Doc Coverage", fmt.Sprintf("%.1f%%", r.DocCoveragePercent())}, []tablewriter.Colors{tablewriter.Colors{tablewriter.Bold}, tablewriter.Colors{}})
+	}
+
 	table.Render()
 
+	if err := r.outLabelCoverages(w); err != nil {
+		return err
+	}
+
 	if r.IsCollectedCustomMetrics() {
 		for _, m := range r.CustomMetrics {
 			if _, err := w.Write([]byte("\n")); err != nil {
@@ -192,7 +263,10 @@ func (r *Report) FileCoveragesTable(files []*gh.PullRequestFile) string {
 	}
 	var t, c int
 	exist := false
-	var rows [][]string
+	var (
+		rows   [][]string
+		covers []float64
+	)
 	for _, f := range files {
 		fc, err := r.Coverage.Files.FuzzyFindByFile(f.Filename)
 		if err != nil {
@@ -206,6 +280,7 @@ func (r *Report) FileCoveragesTable(files []*gh.PullRequestFile) string {
 			cover = 0.0
 		}
 		rows = append(rows, []string{fmt.Sprintf("[%s](%s)", f.Filename, f.BlobURL), fmt.Sprintf("%.1f%%", cover)})
+		covers = append(covers, cover)
 	}
 	if !exist {
 		return ""
@@ -219,6 +294,8 @@ func (r *Report) FileCoveragesTable(files []*gh.PullRequestFile) string {
 	buf := new(bytes.Buffer)
 	buf.WriteString(fmt.Sprintf("%s\n\n", title))
 
+	rows, more := worstFileRows(rows, covers, r.filesTableMax())
+
 	if len(rows) > filesSkipMax {
 		buf.WriteString(fmt.Sprintf("Skip file coverages because there are too many files (%d)\n", len(rows)))
 		return buf.String()
@@ -239,6 +316,9 @@ func (r *Report) FileCoveragesTable(files []*gh.PullRequestFile) string {
 		table.Append(v)
 	}
 	table.Render()
+	if more > 0 {
+		buf.WriteString(fmt.Sprintf("\n+%d more files\n", more))
+	}
 
 	if len(rows) > filesHideMin {
 		buf.WriteString("\n</details>\n")
@@ -247,6 +327,36 @@ func (r *Report) FileCoveragesTable(files []*gh.PullRequestFile) string {
 	return strings.Replace(strings.Replace(buf.String(), "---|", "--:|", len(h)), "--:|", "---|", 1)
 }
 
+func (r *Report) filesTableMax() int {
+	if r == nil || r.opts == nil {
+		return 0
+	}
+	return r.opts.FilesTableMax
+}
+
+// worstFileRows sorts the rows of the file coverages table by lowest coverage and keeps the n worst rows.
+// It returns the kept rows and the number of omitted rows. If n is 0, the rows are returned as they are.
+func worstFileRows(rows [][]string, covers []float64, n int) ([][]string, int) {
+	if n <= 0 {
+		return rows, 0
+	}
+	idx := make([]int, len(rows))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return covers[idx[i]] < covers[idx[j]]
+	})
+	sorted := make([][]string, 0, len(rows))
+	for _, i := range idx {
+		sorted = append(sorted, rows[i])
+	}
+	if len(sorted) <= n {
+		return sorted, 0
+	}
+	return sorted[:n], len(sorted) - n
+}
+
 func (r *Report) CountMeasured() int {
 	c := 0
 	if r.IsMeasuredCoverage() {
@@ -258,14 +368,59 @@ func (r *Report) CountMeasured() int {
 	if r.IsMeasuredTestExecutionTime() {
 		c += 1
 	}
+	if r.IsMeasuredDocCoverage() {
+		c += 1
+	}
 	c += len(r.CustomMetrics)
 	return c
 }
 
 func (r *Report) IsMeasuredCoverage() bool {
+	if r == nil {
+		return false
+	}
 	return r.Coverage != nil
 }
 
+// IsMeasuredUncoveredFuncs reports whether the code coverage contains function-level coverage.
+func (r *Report) IsMeasuredUncoveredFuncs() bool {
+	if r == nil {
+		return false
+	}
+	return r.Coverage.IsMeasuredFuncs()
+}
+
+// UncoveredFuncs returns the number of functions that are never executed.
+func (r *Report) UncoveredFuncs() int {
+	if r == nil {
+		return 0
+	}
+	return r.Coverage.UncoveredFuncs()
+}
+
+// IsMeasuredUncoveredFiles reports whether the number of the uncovered files is shown in the report (report.UncoveredFiles).
+func (r *Report) IsMeasuredUncoveredFiles() bool {
+	if r == nil || r.opts == nil {
+		return false
+	}
+	return r.opts.UncoveredFiles && r.IsMeasuredCoverage()
+}
+
+// UncoveredFiles returns the sorted files with 0% coverage. Files without lines to be covered are not counted.
+func (r *Report) UncoveredFiles() []string {
+	var files []string
+	if r == nil || r.Coverage == nil {
+		return files
+	}
+	for _, fc := range r.Coverage.Files {
+		if fc.Total > 0 && fc.Covered == 0 {
+			files = append(files, fc.File)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
 func (r *Report) IsMeasuredCodeToTestRatio() bool {
 	return r.CodeToTestRatio != nil
 }
@@ -277,6 +432,10 @@ func (r *Report) IsMeasuredTestExecutionTime() bool {
 	return r.TestExecutionTime != nil
 }
 
+func (r *Report) IsMeasuredDocCoverage() bool {
+	return r.DocCoverage != nil
+}
+
 func (r *Report) IsCollectedCustomMetrics() bool {
 	return len(r.CustomMetrics) > 0
 }
@@ -297,20 +456,80 @@ func (r *Report) Load(path string) error {
 	return nil
 }
 
+// isOctocovReport reports whether path is a report.json of octocov containing the code coverage.
+func isOctocovReport(path string) bool {
+	if !strings.EqualFold(filepath.Ext(path), ".json") {
+		return false
+	}
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return false
+	}
+	s := struct {
+		Repository *string    `json:"repository"`
+		Timestamp  *time.Time `json:"timestamp"`
+		Coverage   *struct {
+			Format *string          `json:"format"`
+			Files  *json.RawMessage `json:"files"`
+		} `json:"coverage"`
+	}{}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return false
+	}
+	return s.Repository != nil && s.Timestamp != nil && s.Coverage != nil && s.Coverage.Format != nil && s.Coverage.Files != nil
+}
+
+// MeasureCoverage measures the code coverage from the coverage reports of paths.
+// StdinPath ( "-" ) reads the coverage report from stdin.
 func (r *Report) MeasureCoverage(paths, exclude []string) error {
 	if len(paths) == 0 {
 		return fmt.Errorf("coverage report not found: %s", paths)
 	}
 
-	var cerr *multierror.Error
-	for _, path := range paths {
-		cov, rp, err := challengeParseReport(path)
+	var (
+		cerr      *multierror.Error
+		stdinFile string
+	)
+	resolved := make([]string, len(paths))
+	copy(resolved, paths)
+	for i, path := range resolved {
+		if path != StdinPath {
+			continue
+		}
+		p, err := readStdinToFile()
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(filepath.Dir(p))
+		resolved[i] = p
+		stdinFile = p
+	}
+	if r.opts != nil && r.opts.CoveragePattern != "" {
+		var err error
+		resolved, err = findCoverageReports(resolved, r.opts.CoveragePattern)
+		if err != nil {
+			cerr = multierror.Append(cerr, err)
+		}
+	}
+	// octocov report.json (e.g. report.path of a prior run) is loaded as it is by the fallback below, without trying the parsers
+	loadReport := len(resolved) == 1 && isOctocovReport(resolved[0])
+	for _, path := range resolved {
+		if loadReport {
+			break
+		}
+		cov, rp, err := r.challengeParseReport(path)
 		if err != nil {
 			cerr = multierror.Append(cerr, err)
 			continue
 		}
+		if stdinFile != "" && path == stdinFile {
+			rp = StdinPath
+		}
 		if r.Coverage == nil {
 			r.Coverage = cov
+			if r.opts != nil {
+				r.Coverage.CaseInsensitive = r.opts.CaseInsensitive
+			}
 		} else {
 			if err := r.Coverage.Merge(cov); err != nil {
 				cerr = multierror.Append(cerr, err)
@@ -321,8 +540,8 @@ func (r *Report) MeasureCoverage(paths, exclude []string) error {
 	}
 
 	// fallback load report.json
-	if r.Coverage == nil && len(paths) == 1 {
-		path := paths[0]
+	if r.Coverage == nil && len(resolved) == 1 {
+		path := resolved[0]
 		if err := r.Load(path); err != nil {
 			cerr = multierror.Append(cerr, err)
 			return cerr
@@ -332,8 +551,14 @@ func (r *Report) MeasureCoverage(paths, exclude []string) error {
 	if r.Coverage == nil {
 		return cerr
 	}
+	if r.opts != nil {
+		r.Coverage.CaseInsensitive = r.opts.CaseInsensitive
+	}
 
 	if err := r.Coverage.Exclude(exclude); err != nil {
+		// The coverage of the unexcluded files must not be reported as measured
+		r.Coverage = nil
+		r.covPaths = nil
 		cerr = multierror.Append(cerr, err)
 		return cerr
 	}
@@ -341,6 +566,51 @@ func (r *Report) MeasureCoverage(paths, exclude []string) error {
 	return nil
 }
 
+// findCoverageReports replaces the directories in paths with the files matching the glob pattern (e.g. **/*.out) found recursively in them.
+// The pattern is matched against the slash-separated path relative to the directory.
+func findCoverageReports(paths []string, pattern string) ([]string, error) {
+	if !doublestar.ValidatePattern(pattern) {
+		return paths, fmt.Errorf("invalid coverage.pattern: %s", pattern)
+	}
+	var (
+		found []string
+		merr  *multierror.Error
+	)
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil || !fi.IsDir() {
+			found = append(found, path)
+			continue
+		}
+		var matched []string
+		if err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(path, p)
+			if err != nil {
+				return err
+			}
+			if ok, _ := doublestar.Match(pattern, filepath.ToSlash(rel)); ok {
+				matched = append(matched, p)
+			}
+			return nil
+		}); err != nil {
+			merr = multierror.Append(merr, err)
+			continue
+		}
+		if len(matched) == 0 {
+			merr = multierror.Append(merr, fmt.Errorf("coverage report matching %s not found in %s", pattern, path))
+			continue
+		}
+		found = append(found, matched...)
+	}
+	return found, merr.ErrorOrNil()
+}
+
 func (r *Report) MeasureCodeToTestRatio(root string, code, test []string) error {
 	ratio, err := ratio.Measure(root, code, test)
 	if err != nil {
@@ -350,6 +620,54 @@ func (r *Report) MeasureCodeToTestRatio(root string, code, test []string) error
 	return nil
 }
 
+// MeasureCodeToTestRatioGroups measures the code to test ratio of each named group of the patterns and the combined total.
+func (r *Report) MeasureCodeToTestRatioGroups(root string, patterns []*ratio.Pattern) error {
+	ratio, err := ratio.MeasureGroups(root, patterns)
+	if err != nil {
+		return err
+	}
+	r.CodeToTestRatio = ratio
+	return nil
+}
+
+// MeasurePatchCoverage measures the code coverage of the lines added by the unified diff.
+// If countMissingFiles is true, the added lines of the files not found in the coverage report are counted as uncovered.
+func (r *Report) MeasurePatchCoverage(diff io.Reader, countMissingFiles bool) error {
+	if !r.IsMeasuredCoverage() {
+		return errors.New("code coverage is not measured")
+	}
+	added, err := coverage.ParseUnifiedDiff(diff)
+	if err != nil {
+		return err
+	}
+	pc, err := r.Coverage.PatchCoverage(added, countMissingFiles)
+	if err != nil {
+		return err
+	}
+	r.PatchCoverage = pc
+	return nil
+}
+
+func (r *Report) MeasureDocCoverage(root string, include, exclude []string) error {
+	d, err := doccov.Measure(root, include, exclude)
+	if err != nil {
+		return err
+	}
+	r.DocCoverage = d
+	return nil
+}
+
+// MeasureTestExecutionTimeFromGoTestJSON measures the test execution time from the output of `go test -json` saved in path.
+func (r *Report) MeasureTestExecutionTimeFromGoTestJSON(path string) error {
+	res, err := gotest.ParseFile(path)
+	if err != nil {
+		return err
+	}
+	t := float64(res.Elapsed)
+	r.TestExecutionTime = &t
+	return nil
+}
+
 func (r *Report) MeasureTestExecutionTime(ctx context.Context, stepNames []string) error {
 	if r.Repository == "" {
 		return fmt.Errorf("env %s is not set", "GITHUB_REPOSITORY")
@@ -467,6 +785,14 @@ func (r *Report) CollectCustomMetrics() error {
 	return nil
 }
 
+// RepositoryName returns the repository (owner/repo) of the report.
+func (r *Report) RepositoryName() string {
+	if r == nil {
+		return ""
+	}
+	return r.Repository
+}
+
 func (r *Report) CoveragePercent() float64 {
 	if r == nil || r.Coverage == nil || r.Coverage.Total == 0 {
 		return 0.0
@@ -474,6 +800,56 @@ func (r *Report) CoveragePercent() float64 {
 	return float64(r.Coverage.Covered) / float64(r.Coverage.Total) * 100
 }
 
+// CoverageTotal returns the total number of the lines (or statements) to be covered.
+func (r *Report) CoverageTotal() int {
+	if r == nil || r.Coverage == nil {
+		return 0
+	}
+	return r.Coverage.Total
+}
+
+// CoverageHighWaterMarkPercent returns the highest code coverage ever recorded up to the report.
+// It is the code coverage of the report if the high-water mark is not recorded, and false is returned if neither is available.
+func (r *Report) CoverageHighWaterMarkPercent() (float64, bool) {
+	if r == nil {
+		return 0, false
+	}
+	hwm, ok := 0.0, false
+	if r.CoverageHighWaterMark != nil {
+		hwm, ok = *r.CoverageHighWaterMark, true
+	}
+	if r.IsMeasuredCoverage() {
+		hwm, ok = max(hwm, r.CoveragePercent()), true
+	}
+	return hwm, ok
+}
+
+// UpdateCoverageHighWaterMark records the high-water mark of the report from the high-water mark of the previous report.
+// The recorded value changes only when the code coverage of the report exceeds it.
+func (r *Report) UpdateCoverageHighWaterMark(rPrev *Report) {
+	hwm, ok := rPrev.CoverageHighWaterMarkPercent()
+	if r.IsMeasuredCoverage() {
+		hwm, ok = max(hwm, r.CoveragePercent()), true
+	}
+	if !ok {
+		return
+	}
+	r.CoverageHighWaterMark = &hwm
+}
+
+// IsMeasuredPatchCoverage returns true if the patch coverage is measured.
+func (r *Report) IsMeasuredPatchCoverage() bool {
+	return r != nil && r.PatchCoverage != nil
+}
+
+// PatchCoveragePercent returns the patch coverage. It returns 100 if the pull request adds no lines to be covered.
+func (r *Report) PatchCoveragePercent() float64 {
+	if r == nil {
+		return 0.0
+	}
+	return r.PatchCoverage.Percent()
+}
+
 func (r *Report) CodeToTestRatioRatio() float64 {
 	if r == nil || r.CodeToTestRatio == nil || r.CodeToTestRatio.Code == 0 {
 		return 0.0
@@ -481,6 +857,14 @@ func (r *Report) CodeToTestRatioRatio() float64 {
 	return float64(r.CodeToTestRatio.Test) / float64(r.CodeToTestRatio.Code)
 }
 
+// CodeToTestRatioCodeFiles returns the number of code files measured for the code to test ratio.
+func (r *Report) CodeToTestRatioCodeFiles() int {
+	if r == nil || r.CodeToTestRatio == nil {
+		return 0
+	}
+	return len(r.CodeToTestRatio.CodeFiles)
+}
+
 func (r *Report) TestExecutionTimeNano() float64 {
 	if r == nil || r.TestExecutionTime == nil {
 		return 0.0
@@ -488,6 +872,13 @@ func (r *Report) TestExecutionTimeNano() float64 {
 	return *r.TestExecutionTime
 }
 
+func (r *Report) DocCoveragePercent() float64 {
+	if r == nil {
+		return 0.0
+	}
+	return r.DocCoverage.Percent()
+}
+
 func (r *Report) Validate() error {
 	if r.Repository == "" {
 		return fmt.Errorf("coverage report %q (env %s) is not set", "repository", "GITHUB_REPOSITORY")
@@ -541,6 +932,9 @@ func (r *Report) Compare(r2 *Report) *DiffReport {
 		dt.Diff = t1 - t2
 		d.TestExecutionTime = dt
 	}
+	if r.IsMeasuredDocCoverage() {
+		d.DocCoverage = r.DocCoverage.Compare(r2.DocCoverage)
+	}
 	if r.IsCollectedCustomMetrics() {
 		for _, set := range r.CustomMetrics {
 			set2 := r2.findCustomMetricSetByKey(set.Key)
@@ -648,9 +1042,33 @@ func mergeExecutionTimes(steps []gh.Step) time.Duration {
 	return d
 }
 
-func challengeParseReport(path string) (*coverage.Coverage, string, error) {
+func (r *Report) challengeParseReport(path string) (*coverage.Coverage, string, error) {
+	// explicitly specified format
+	if r.opts != nil && r.opts.CoverageFormat != "" {
+		p, err := r.coverageProcessor(r.opts.CoverageFormat)
+		if err != nil {
+			return nil, "", err
+		}
+		cov, rp, err := p.ParseReport(path)
+		if err != nil {
+			return nil, "", fmt.Errorf("parse as %s: %w", p.Name(), err)
+		}
+		return cov, rp, nil
+	}
+	// external parser command
+	if r.opts != nil && r.opts.ParserCommand != "" {
+		if cov, rp, err := coverage.NewExternal(r.opts.ParserCommand).ParseReport(path); err == nil {
+			return cov, rp, nil
+		} else {
+			log.Printf("parse using external parser command: %s", err)
+		}
+	}
 	// gocover
-	if cov, rp, err := coverage.NewGocover().ParseReport(path); err == nil {
+	gcov := coverage.NewGocover()
+	if r.opts != nil && r.opts.CoverageCacheDir != "" {
+		gcov = coverage.NewGocoverWithCache(r.opts.CoverageCacheDir)
+	}
+	if cov, rp, err := gcov.ParseReport(path); err == nil {
 		return cov, rp, nil
 	} else {
 		log.Printf("parse as Go coverage: %s", err)
@@ -661,6 +1079,12 @@ func challengeParseReport(path string) (*coverage.Coverage, string, error) {
 	} else {
 		log.Printf("parse as LCOV: %s", err)
 	}
+	// coverage.py (before SimpleCov, which also parses JSON loosely)
+	if cov, rp, err := coverage.NewCoveragePy().ParseReport(path); err == nil {
+		return cov, rp, nil
+	} else {
+		log.Printf("parse as coverage.py: %s", err)
+	}
 	// simplecov
 	if cov, rp, err := coverage.NewSimplecov().ParseReport(path); err == nil {
 		return cov, rp, nil
@@ -685,9 +1109,53 @@ func challengeParseReport(path string) (*coverage.Coverage, string, error) {
 	} else {
 		log.Printf("parse as JaCoCo: %s", err)
 	}
+	// opencover
+	if cov, rp, err := coverage.NewOpencover().ParseReport(path); err == nil {
+		return cov, rp, nil
+	} else {
+		log.Printf("parse as OpenCover: %s", err)
+	}
+
+	// coverage.py data file is not parsable, but it is worth telling how to convert it
+	if err := coverage.DetectCoveragePyDataFile(path); err != nil {
+		log.Println(err)
+		return nil, "", err
+	}
 
 	msg := fmt.Sprintf("parsable coverage report not found: %s", path)
 	log.Println(msg)
 
 	return nil, "", errors.New(msg)
 }
+
+// coverageProcessor returns the parser of the coverage report format specified by coverage.format.
+func (r *Report) coverageProcessor(format string) (coverage.Processor, error) {
+	switch strings.ToLower(format) {
+	case "go", "gocover":
+		if r.opts != nil && r.opts.CoverageCacheDir != "" {
+			return coverage.NewGocoverWithCache(r.opts.CoverageCacheDir), nil
+		}
+		return coverage.NewGocover(), nil
+	case "lcov":
+		return coverage.NewLcov(), nil
+	case "simplecov":
+		return coverage.NewSimplecov(), nil
+	case "coveragepy":
+		return coverage.NewCoveragePy(), nil
+	case "clover":
+		return coverage.NewClover(), nil
+	case "cobertura":
+		return coverage.NewCobertura(), nil
+	case "jacoco":
+		return coverage.NewJacoco(), nil
+	case "opencover":
+		return coverage.NewOpencover(), nil
+	case "external":
+		if r.opts == nil || r.opts.ParserCommand == "" {
+			return nil, errors.New("coverage.parser.command: is not set")
+		}
+		return coverage.NewExternal(r.opts.ParserCommand), nil
+	default:
+		return nil, fmt.Errorf("unsupported coverage format: %s", format)
+	}
+}
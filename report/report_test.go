@@ -67,6 +67,28 @@ func TestNewWithOptions(t *testing.T) {
 	}
 }
 
+func TestUseCommitTimestamp(t *testing.T) {
+	r := &Report{Commit: "HEAD", Timestamp: time.Now().UTC()}
+	if err := r.UseCommitTimestamp(); err != nil {
+		t.Fatal(err)
+	}
+	if r.Timestamp.IsZero() || r.Timestamp.After(time.Now()) {
+		t.Errorf("invalid commit timestamp: %v", r.Timestamp)
+	}
+	want := r.Timestamp
+	if err := r.UseCommitTimestamp(); err != nil {
+		t.Fatal(err)
+	}
+	if !r.Timestamp.Equal(want) {
+		t.Errorf("got %v\nwant %v", r.Timestamp, want)
+	}
+
+	r = &Report{Commit: "0000000000000000000000000000000000000000"}
+	if err := r.UseCommitTimestamp(); err == nil {
+		t.Error("want error")
+	}
+}
+
 func TestMeasureCoverage(t *testing.T) {
 	log.SetOutput(io.Discard) // Disable log in challengeParseReport()
 
@@ -125,6 +147,171 @@ func TestMeasureCoverage(t *testing.T) {
 	}
 }
 
+func TestMeasureTestExecutionTimeFromGoTestJSON(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "test.json")
+	b := `{"Time":"2026-01-01T00:00:00Z","Action":"start","Package":"example.com/a"}
+{"Time":"2026-01-01T00:01:30Z","Action":"pass","Package":"example.com/a","Elapsed":90}
+`
+	if err := os.WriteFile(p, []byte(b), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r, err := New("owner/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.MeasureTestExecutionTimeFromGoTestJSON(p); err != nil {
+		t.Fatal(err)
+	}
+	if !r.IsMeasuredTestExecutionTime() {
+		t.Fatal("test execution time is not measured")
+	}
+	if got, want := time.Duration(r.TestExecutionTimeNano()), 90*time.Second; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestMeasureCoverageWithFormat(t *testing.T) {
+	log.SetOutput(io.Discard) // Disable log in challengeParseReport()
+
+	tests := []struct {
+		format     string
+		path       string
+		wantFormat string
+		wantErr    bool
+	}{
+		{"lcov", filepath.Join(coverageTestdataDir(t), "lcov"), "LCOV", false},
+		{"LCOV", filepath.Join(coverageTestdataDir(t), "lcov"), "LCOV", false},
+		{"go", filepath.Join(coverageTestdataDir(t), "gocover"), "Go coverage", false},
+		{"lcov", filepath.Join(coverageTestdataDir(t), "gocover"), "", true},
+		{"unknown", filepath.Join(coverageTestdataDir(t), "lcov"), "", true},
+	}
+	for _, tt := range tests {
+		r, err := New("owner/repo", CoverageFormat(tt.format))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.MeasureCoverage([]string{tt.path}, nil); err != nil {
+			if !tt.wantErr {
+				t.Error(err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Error("want error")
+			continue
+		}
+		if got := r.Coverage.Format; got != tt.wantFormat {
+			t.Errorf("got %v\nwant %v", got, tt.wantFormat)
+		}
+	}
+}
+
+func TestMeasureCoverageAllExcluded(t *testing.T) {
+	log.SetOutput(io.Discard) // Disable log in challengeParseReport()
+
+	r, err := New("owner/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.MeasureCoverage([]string{filepath.Join(coverageTestdataDir(t), "gocover")}, []string{"**"}); err == nil {
+		t.Error("want error")
+	}
+	if r.IsMeasuredCoverage() {
+		t.Errorf("got coverage %.1f%%\nwant not measured", r.CoveragePercent())
+	}
+}
+
+func TestMeasureCoverageFromOctocovReport(t *testing.T) {
+	log.SetOutput(io.Discard) // Disable log in challengeParseReport()
+
+	r, err := New("owner/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.MeasureCoverage([]string{filepath.Join(coverageTestdataDir(t), "gocover")}, nil); err != nil {
+		t.Fatal(err)
+	}
+	p := filepath.Join(t.TempDir(), "report.json")
+	if err := os.WriteFile(p, r.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if !isOctocovReport(p) {
+		t.Fatalf("%s is not detected as octocov report.json", p)
+	}
+	if isOctocovReport(filepath.Join(coverageTestdataDir(t), "coveragepy", "coverage.json")) {
+		t.Error("coverage.py JSON report is detected as octocov report.json")
+	}
+
+	got, err := New("owner/repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := got.MeasureCoverage([]string{p}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got.Coverage, r.Coverage, cmpopts.IgnoreUnexported(coverage.FileCoverage{})); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(got.covPaths, []string{p}); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestMeasureCoverageWithPattern(t *testing.T) {
+	log.SetOutput(io.Discard) // Disable log in challengeParseReport()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"pkg/a/coverage.out":  "mode: set\nexample.com/app/a/a.go:1.1,3.2 2 1\nexample.com/app/a/a.go:5.1,7.2 2 0\n",
+		"pkg/b/c/profile.out": "mode: set\nexample.com/app/b/c/c.go:1.1,3.2 1 1\n",
+		"pkg/b/broken.out":    "not a coverage profile\n",
+		"pkg/b/README.md":     "# b\n",
+	}
+	for p, content := range files {
+		fp := filepath.Join(dir, filepath.FromSlash(p))
+		if err := os.MkdirAll(filepath.Dir(fp), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fp, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		pattern     string
+		wantFiles   int
+		wantTotal   int
+		wantCovered int
+		wantErr     bool
+	}{
+		{"**/*.out", 2, 5, 3, false},
+		{"pkg/a/*.out", 1, 4, 2, false},
+		{"**/*.cov", 0, 0, 0, true},
+	}
+	for _, tt := range tests {
+		r, err := New("owner/repo", CoveragePattern(tt.pattern))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.MeasureCoverage([]string{dir}, nil); err != nil {
+			if !tt.wantErr {
+				t.Errorf("%s: %v", tt.pattern, err)
+			}
+			continue
+		}
+		if tt.wantErr {
+			t.Errorf("%s: want error", tt.pattern)
+			continue
+		}
+		if got := len(r.Coverage.Files); got != tt.wantFiles {
+			t.Errorf("%s: got %v files\nwant %v", tt.pattern, got, tt.wantFiles)
+		}
+		if r.Coverage.Total != tt.wantTotal || r.Coverage.Covered != tt.wantCovered {
+			t.Errorf("%s: got %d/%d\nwant %d/%d", tt.pattern, r.Coverage.Covered, r.Coverage.Total, tt.wantCovered, tt.wantTotal)
+		}
+	}
+}
+
 func TestCollectCustomMetrics(t *testing.T) {
 	tests := []struct {
 		envs    map[string]string
@@ -341,6 +528,49 @@ func TestTable(t *testing.T) {
 	}
 }
 
+func TestTableWithUncoveredFuncs(t *testing.T) {
+	log.SetOutput(io.Discard) // Disable log in challengeParseReport()
+
+	r := &Report{}
+	if err := r.MeasureCoverage([]string{filepath.Join(coverageTestdataDir(t), "lcov_funcs")}, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := `| Coverage | Uncovered Functions |
+|---------:|--------------------:|
+| 50.0%    |                   2 |
+`
+	if got := r.Table(); got != want {
+		t.Errorf("got\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestTableWithUncoveredFiles(t *testing.T) {
+	log.SetOutput(io.Discard) // Disable log in challengeParseReport()
+
+	pp := filepath.Join(t.TempDir(), "coverage.out")
+	profile := "mode: count\nexample.com/app/a.go:1.1,2.2 1 1\nexample.com/app/b.go:1.1,2.2 1 0\nexample.com/app/c.go:1.1,2.2 1 0\n"
+	if err := os.WriteFile(pp, []byte(profile), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r, err := New("owner/repo", UncoveredFiles(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.MeasureCoverage([]string{pp}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(r.UncoveredFiles(), []string{"example.com/app/b.go", "example.com/app/c.go"}); diff != "" {
+		t.Error(diff)
+	}
+	want := `| Coverage | Uncovered Files |
+|---------:|----------------:|
+| 33.3%    |               2 |
+`
+	if got := r.Table(); got != want {
+		t.Errorf("got\n%v\nwant\n%v", got, want)
+	}
+}
+
 func TestOut(t *testing.T) {
 	tests := []struct {
 		path string
@@ -401,6 +631,37 @@ func TestFileCoveragesTable(t *testing.T) {
 	}
 }
 
+func TestFileCoveragesTableWithFilesTableMax(t *testing.T) {
+	r, err := New("owner/repo", FilesTableMax(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Coverage = &coverage.Coverage{
+		Files: coverage.FileCoverages{
+			&coverage.FileCoverage{File: "a.go", Total: 10, Covered: 9},
+			&coverage.FileCoverage{File: "b.go", Total: 10, Covered: 2},
+			&coverage.FileCoverage{File: "c.go", Total: 10, Covered: 5},
+		},
+	}
+	files := []*gh.PullRequestFile{
+		{Filename: "a.go", BlobURL: "https://github.com/owner/repo/blob/xxx/a.go"},
+		{Filename: "b.go", BlobURL: "https://github.com/owner/repo/blob/xxx/b.go"},
+		{Filename: "c.go", BlobURL: "https://github.com/owner/repo/blob/xxx/c.go"},
+	}
+	want := `### Code coverage of files in pull request scope (53.3%)
+
+|                        Files                        | Coverage |
+|-----------------------------------------------------|---------:|
+| [b.go](https://github.com/owner/repo/blob/xxx/b.go) | 20.0%    |
+| [c.go](https://github.com/owner/repo/blob/xxx/c.go) | 50.0%    |
+
++1 more files
+`
+	if got := r.FileCoveragesTable(files); got != want {
+		t.Errorf("got\n%v\nwant\n%v", got, want)
+	}
+}
+
 func TestMergeExecutionTimes(t *testing.T) {
 	tests := []struct {
 		steps []gh.Step
@@ -481,6 +742,31 @@ func TestMergeExecutionTimes(t *testing.T) {
 	}
 }
 
+func TestUpdateCoverageHighWaterMark(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	cov := func(covered int) *coverage.Coverage { return &coverage.Coverage{Total: 100, Covered: covered} }
+	tests := []struct {
+		name  string
+		r     *Report
+		rPrev *Report
+		want  *float64
+	}{
+		{"first run", &Report{Coverage: cov(70)}, nil, f(70.0)},
+		{"baseline without high-water mark", &Report{Coverage: cov(70)}, &Report{Coverage: cov(75)}, f(75.0)},
+		{"not improved", &Report{Coverage: cov(70)}, &Report{Coverage: cov(75), CoverageHighWaterMark: f(80.0)}, f(80.0)},
+		{"improved", &Report{Coverage: cov(85)}, &Report{Coverage: cov(75), CoverageHighWaterMark: f(80.0)}, f(85.0)},
+		{"coverage not measured", &Report{}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.r.UpdateCoverageHighWaterMark(tt.rPrev)
+			if diff := cmp.Diff(tt.r.CoverageHighWaterMark, tt.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
 func TestCompare(t *testing.T) {
 	a := &Report{}
 	if err := a.Load(filepath.Join(testdataDir(t), "reports", "k1LoW", "tbls", "report.json")); err != nil {
@@ -0,0 +1,39 @@
+package report
+
+import (
+	"math"
+	"strings"
+)
+
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline returns a unicode sparkline of the values (oldest first) scaled between their minimum and maximum.
+// NaN values (e.g. missing reports) are drawn as spaces. It returns an empty string when there are fewer than 2 values.
+func Sparkline(values []float64) string {
+	minV, maxV := math.Inf(1), math.Inf(-1)
+	n := 0
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		minV = math.Min(minV, v)
+		maxV = math.Max(maxV, v)
+		n++
+	}
+	if n < 2 {
+		return ""
+	}
+	var b strings.Builder
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			b.WriteRune(' ')
+			continue
+		}
+		i := len(sparkBars) / 2
+		if maxV > minV {
+			i = int(math.Round((v - minV) / (maxV - minV) * float64(len(sparkBars)-1)))
+		}
+		b.WriteRune(sparkBars[i])
+	}
+	return b.String()
+}
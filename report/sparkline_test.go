@@ -0,0 +1,26 @@
+package report
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []float64
+		want   string
+	}{
+		{nil, ""},
+		{[]float64{80.0}, ""},
+		{[]float64{math.NaN(), 80.0}, ""},
+		{[]float64{70.0, 80.0}, "▁█"},
+		{[]float64{70.0, 75.0, 80.0}, "▁▅█"},
+		{[]float64{80.0, 80.0, 80.0}, "▅▅▅"},
+		{[]float64{70.0, math.NaN(), 80.0}, "▁ █"},
+	}
+	for _, tt := range tests {
+		if got := Sparkline(tt.values); got != tt.want {
+			t.Errorf("got %q\nwant %q", got, tt.want)
+		}
+	}
+}
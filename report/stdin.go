@@ -0,0 +1,50 @@
+package report
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// StdinPath is the coverage report path to read the coverage report from stdin.
+const StdinPath = "-"
+
+var stdin = os.Stdin
+
+// readStdinToFile writes the coverage report piped to stdin into a file in a temporary directory, and returns the path of the file.
+func readStdinToFile() (string, error) {
+	fi, err := stdin.Stat()
+	if err != nil {
+		return "", err
+	}
+	if fi.Mode()&os.ModeCharDevice != 0 {
+		return "", errors.New("coverage report path is - but no coverage report is piped to stdin")
+	}
+	dir, err := os.MkdirTemp("", "octocov-stdin")
+	if err != nil {
+		return "", err
+	}
+	p := filepath.Join(dir, "coverage")
+	if err := copyStdin(p); err != nil {
+		_ = os.RemoveAll(dir) //nostyle:handlerrors
+		return "", err
+	}
+	return p, nil
+}
+
+func copyStdin(p string) error {
+	f, err := os.Create(filepath.Clean(p))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	n, err := io.Copy(f, stdin)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.New("coverage report path is - but stdin is empty")
+	}
+	return nil
+}
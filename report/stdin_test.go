@@ -0,0 +1,58 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMeasureCoverageFromStdin(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "lcov.info")
+	if err := os.WriteFile(p, []byte("SF:main.go\nDA:1,1\nDA:2,0\nend_of_record\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	orig := stdin
+	stdin = f
+	t.Cleanup(func() {
+		stdin = orig
+	})
+
+	r, err := New("owner/repo", CoverageFormat("lcov"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.MeasureCoverage([]string{StdinPath}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := r.CoveragePercent(), 50.0; got != want {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+	if got, want := r.covPaths, []string{StdinPath}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestReadStdinToFileEmpty(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "empty")
+	if err := os.WriteFile(p, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	orig := stdin
+	stdin = f
+	t.Cleanup(func() {
+		stdin = orig
+	})
+	if _, err := readStdinToFile(); err == nil {
+		t.Error("want error")
+	}
+}
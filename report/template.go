@@ -0,0 +1,66 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// TemplateData is the data passed to the custom template of the report comment (comment.template).
+// It is a stable interface for custom templates: fields are only added, never renamed or removed.
+type TemplateData struct {
+	// Repository is the repository of the report (owner/repo).
+	Repository string
+	// Title is the title of the report.
+	Title string
+	// Report is the current report.
+	Report *Report
+	// Prev is the report to compare with. It is nil if there is no baseline report.
+	Prev *Report
+	// Diff is the comparison of Report with Prev. It is nil if there is no baseline report.
+	Diff *DiffReport
+	// CoverageDelta is the difference of the code coverage (percentage point) from Prev. It is 0 if there is no baseline report.
+	CoverageDelta float64
+	// Errors are the unacceptable conditions of the report.
+	Errors []string
+	// Warnings are the unacceptable conditions of the report which are only warned (e.g. coverage.acceptable.mode: warn).
+	Warnings []string
+	// Table is the table of the metrics.
+	Table string
+	// Note is the note about the comparison (e.g. no baseline report).
+	Note string
+	// Trend is the sparkline of the code coverage of recent reports.
+	Trend string
+	// PatchCoverage is the line of the code coverage of the lines added by the pull request.
+	PatchCoverage string
+	// FileTable is the table of the code coverage of the changed files.
+	FileTable string
+	// LabelTable is the table of the code coverage by label.
+	LabelTable string
+	// GroupRatioTable is the table of the code to test ratio by group (codeToTestRatio.groups).
+	GroupRatioTable string
+	// RatioTable is the table of the code to test ratio by directory.
+	RatioTable string
+	// CustomTables are the tables of the custom metrics.
+	CustomTables []string
+	// Footer is the footer of the report.
+	Footer string
+}
+
+// RenderTemplate renders the report comment using the Go text/template file at path.
+func RenderTemplate(wr io.Writer, path string, d *TemplateData) error {
+	b, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	tmpl, err := template.New(filepath.Base(path)).Parse(string(b))
+	if err != nil {
+		return fmt.Errorf("invalid comment template: %w", err)
+	}
+	if err := tmpl.Execute(wr, d); err != nil {
+		return fmt.Errorf("failed to render comment template: %w", err)
+	}
+	return nil
+}
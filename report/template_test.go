@@ -0,0 +1,57 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	tests := []struct {
+		tmpl    string
+		d       *TemplateData
+		want    string
+		wantErr bool
+	}{
+		{
+			"## {{ .Title }} ({{ .Repository }})\n{{ range .Errors }}- {{ . }}\n{{ end }}{{ .Footer }}",
+			&TemplateData{Repository: "owner/repo", Title: "Code Metrics Report", Errors: []string{"Code coverage is 50.0%"}, Footer: "Reported by octocov"},
+			"## Code Metrics Report (owner/repo)\n- Code coverage is 50.0%\nReported by octocov",
+			false,
+		},
+		{
+			`{{ if .Diff }}{{ printf "%.1f" .CoverageDelta }}{{ else }}no baseline{{ end }}`,
+			&TemplateData{},
+			"no baseline",
+			false,
+		},
+		{
+			"{{ .Title ",
+			&TemplateData{},
+			"",
+			true,
+		},
+		{
+			"{{ .Unknown }}",
+			&TemplateData{},
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		p := filepath.Join(t.TempDir(), "comment.md.tmpl")
+		if err := os.WriteFile(p, []byte(tt.tmpl), 0600); err != nil {
+			t.Fatal(err)
+		}
+		got := new(strings.Builder)
+		err := RenderTemplate(got, p, tt.d)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("got %v\nwant error %v", err, tt.wantErr)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("got %v\nwant %v", got.String(), tt.want)
+		}
+	}
+}
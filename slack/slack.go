@@ -0,0 +1,130 @@
+// Package slack posts the summary of the report to Slack via an incoming webhook.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/k1LoW/octocov/report"
+)
+
+const maxErrorBodySize = 1024
+
+type Slack struct {
+	webhookURL string
+	client     *http.Client
+}
+
+func New(webhookURL string) (*Slack, error) {
+	if webhookURL == "" {
+		return nil, errors.New("slack webhook url is not set")
+	}
+	return &Slack{
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Message is the payload of the incoming webhook.
+type Message struct {
+	Text   string   `json:"text"`
+	Blocks []*Block `json:"blocks,omitempty"`
+}
+
+type Block struct {
+	Type string `json:"type"`
+	Text *Text  `json:"text,omitempty"`
+}
+
+type Text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// NewMessage returns the message of the code metrics of the report, the delta from the previous report (if rPrev is not nil) and the failures of the acceptable conditions.
+// If link is not empty, the repository is linked to it (e.g. the URL of the workflow run).
+func NewMessage(r, rPrev *report.Report, failures []string, link string) *Message {
+	repo := r.Repository
+	if link != "" {
+		repo = fmt.Sprintf("<%s|%s>", link, r.Repository)
+	}
+	var metrics []string
+	if r.IsMeasuredCoverage() {
+		m := fmt.Sprintf("Code Coverage: *%.1f%%*", r.CoveragePercent())
+		if rPrev.IsMeasuredCoverage() {
+			m += fmt.Sprintf(" (%+.1f%%)", r.CoveragePercent()-rPrev.CoveragePercent())
+		}
+		metrics = append(metrics, m)
+	}
+	if r.IsMeasuredCodeToTestRatio() {
+		metrics = append(metrics, fmt.Sprintf("Code to Test Ratio: *1:%.1f*", r.CodeToTestRatioRatio()))
+	}
+	if r.IsMeasuredTestExecutionTime() {
+		metrics = append(metrics, fmt.Sprintf("Test Execution Time: *%s*", time.Duration(r.TestExecutionTimeNano())))
+	}
+	status := "Code metrics are acceptable"
+	icon := ":white_check_mark:"
+	if len(failures) > 0 {
+		status = "Code metrics are not acceptable"
+		icon = ":no_entry_sign:"
+	}
+	title := fmt.Sprintf("*%s*", repo)
+	if r.Ref != "" {
+		title = fmt.Sprintf("*%s* (%s)", repo, r.Ref)
+	}
+	lines := []string{title, fmt.Sprintf("%s %s", icon, status)}
+	lines = append(lines, metrics...)
+	for _, f := range failures {
+		lines = append(lines, fmt.Sprintf("• %s", f))
+	}
+	// text is the fallback of the blocks (e.g. notifications)
+	text := fmt.Sprintf("%s: %s", r.Repository, status)
+	if r.IsMeasuredCoverage() {
+		text = fmt.Sprintf("%s (Code Coverage: %.1f%%)", text, r.CoveragePercent())
+	}
+	return &Message{
+		Text: text,
+		Blocks: []*Block{
+			{
+				Type: "section",
+				Text: &Text{Type: "mrkdwn", Text: strings.Join(lines, "\n")},
+			},
+		},
+	}
+}
+
+// Post posts the message to the incoming webhook. A non-2xx response is an error including the response body.
+func (s *Slack) Post(ctx context.Context, m *Message) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhookURL, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := s.client.Do(req)
+	if err != nil {
+		// The webhook URL is a secret, so it is not included in the error
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("failed to post message to Slack: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize)) //nostyle:handlerrors
+		return fmt.Errorf("failed to post message to Slack: %s: %s", res.Status, strings.TrimSpace(string(b)))
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/k1LoW/octocov/coverage"
+	"github.com/k1LoW/octocov/report"
+)
+
+func TestNewMessage(t *testing.T) {
+	r := &report.Report{
+		Repository: "owner/repo",
+		Ref:        "refs/heads/main",
+		Coverage:   &coverage.Coverage{Total: 1000, Covered: 785},
+	}
+	rPrev := &report.Report{
+		Repository: "owner/repo",
+		Coverage:   &coverage.Coverage{Total: 1000, Covered: 800},
+	}
+	tests := []struct {
+		name     string
+		rPrev    *report.Report
+		failures []string
+		url      string
+		want     *Message
+	}{
+		{
+			"acceptable without previous report",
+			nil,
+			nil,
+			"",
+			&Message{
+				Text:   "owner/repo: Code metrics are acceptable (Code Coverage: 78.5%)",
+				Blocks: []*Block{{Type: "section", Text: &Text{Type: "mrkdwn", Text: "*owner/repo* (refs/heads/main)\n:white_check_mark: Code metrics are acceptable\nCode Coverage: *78.5%*"}}},
+			},
+		},
+		{
+			"not acceptable with delta",
+			rPrev,
+			[]string{"Code coverage is 78.5%. the condition in the `coverage.acceptable:` section is not met (`diff >= 0%`)"},
+			"https://github.com/owner/repo/actions/runs/1",
+			&Message{
+				Text:   "owner/repo: Code metrics are not acceptable (Code Coverage: 78.5%)",
+				Blocks: []*Block{{Type: "section", Text: &Text{Type: "mrkdwn", Text: "*<https://github.com/owner/repo/actions/runs/1|owner/repo>* (refs/heads/main)\n:no_entry_sign: Code metrics are not acceptable\nCode Coverage: *78.5%* (-1.5%)\n• Code coverage is 78.5%. the condition in the `coverage.acceptable:` section is not met (`diff >= 0%`)"}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewMessage(r, tt.rPrev, tt.failures, tt.url)
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestPost(t *testing.T) {
+	var got *Message
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("got %v\nwant %v", r.Method, http.MethodPost)
+		}
+		if r.URL.Path == "/invalid" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("no_service\n"))
+			return
+		}
+		got = &Message{}
+		if err := json.NewDecoder(r.Body).Decode(got); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	m := &Message{Text: "owner/repo: Code metrics are acceptable"}
+	s, err := New(ts.URL + "/services/T000/B000/XXX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Post(context.Background(), m); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, m); diff != "" {
+		t.Error(diff)
+	}
+
+	s, err = New(ts.URL + "/invalid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Post(context.Background(), m)
+	if err == nil || !strings.Contains(err.Error(), "404 Not Found: no_service") || strings.Contains(err.Error(), "/invalid") {
+		t.Errorf("got %v\nwant the error without the webhook URL", err)
+	}
+
+	if _, err := New(""); err == nil {
+		t.Error("want error")
+	}
+}
@@ -0,0 +1,25 @@
+package a
+
+// Documented is documented.
+type Documented struct{}
+
+type Undocumented struct{}
+
+// Do is documented.
+func (d *Documented) Do() {}
+
+func (d *Documented) Undo() {}
+
+func (u undocumented) Method() {}
+
+type undocumented struct{}
+
+// Values are documented as a group.
+const (
+	A = 1
+	B = 2
+)
+
+var C = 3
+
+func helper() {}
@@ -0,0 +1,6 @@
+package sub
+
+// Func is documented.
+func Func() {}
+
+func Other() {}